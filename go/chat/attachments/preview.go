@@ -59,8 +59,18 @@ func Preview(ctx context.Context, g *globals.Context, log utils.DebugLabeler, sr
 		}
 		log.Debug(ctx, "Preview: failed to get video preview for filename: %s contentType: %s err: %s",
 			basename, contentType, err)
+		if pre, eerr := previewExternal(ctx, g, log, src, contentType, basename); eerr == nil && pre != nil {
+			return pre, nil
+		}
 		return previewVideoBlank(ctx, g, log, src, basename)
 	}
+	// Audio waveforms, PDF thumbnails and other media hand off to a
+	// configured external generator; the result is encrypted and
+	// uploaded as an ordinary preview sibling.
+	if pre, err := previewExternal(ctx, g, log, src, contentType, basename); err == nil && pre != nil {
+		log.Debug(ctx, "Preview: external helper produced preview for %s (%s)", basename, contentType)
+		return pre, nil
+	}
 	return nil, nil
 }
 
@@ -0,0 +1,115 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package attachments
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/utils"
+)
+
+// External preview generators for media the built-in pipeline can't
+// decode: audio waveforms, video frames, PDF pages. A helper is
+// declared per content-type prefix in config:
+//
+//   "attachments": { "preview_helpers": {
+//     "audio": "/usr/local/bin/kb-waveform",
+//     "application/pdf": "/usr/local/bin/kb-pdf-thumb" } }
+//
+// The helper gets the media on stdin and prints a JSON envelope:
+//
+//   {"content_type": "image/png", "width": 400, "height": 80,
+//    "duration_ms": 32000, "data_base64": "..."}
+//
+// The generated preview runs through the normal pipeline afterwards, so
+// it's encrypted and uploaded as an asset sibling like any other
+// preview; receiving clients render it without fetching the full asset.
+
+type externalPreviewEnvelope struct {
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	DurationMs  int    `json:"duration_ms"`
+	DataBase64  []byte `json:"data_base64"`
+}
+
+const externalPreviewTimeout = 60 * time.Second
+
+// externalPreviewHelper returns the configured helper for a content
+// type, or "". Longest matching prefix wins.
+func externalPreviewHelper(g *globals.Context, contentType string) string {
+	raw, err := g.Env.GetConfig().GetInterfaceAtPath("attachments.preview_helpers")
+	if err != nil {
+		return ""
+	}
+	blob, err := json.Marshal(raw)
+	if err != nil {
+		return ""
+	}
+	var helpers map[string]string
+	if err := json.Unmarshal(blob, &helpers); err != nil {
+		return ""
+	}
+	best := ""
+	var bestPath string
+	for prefix, path := range helpers {
+		if len(prefix) > len(best) && len(contentType) >= len(prefix) && contentType[:len(prefix)] == prefix {
+			best, bestPath = prefix, path
+		}
+	}
+	return bestPath
+}
+
+// previewExternal runs the configured helper over the media stream.
+func previewExternal(ctx context.Context, g *globals.Context, log utils.DebugLabeler,
+	src io.Reader, contentType, basename string) (*PreviewRes, error) {
+	helper := externalPreviewHelper(g, contentType)
+	if helper == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, externalPreviewTimeout)
+	defer cancel()
+
+	// Helpers get a file path when the source is a file (cheaper for
+	// big media), otherwise the bytes on stdin.
+	cmd := exec.CommandContext(ctx, helper)
+	if f, ok := src.(*os.File); ok {
+		cmd.Args = append(cmd.Args, f.Name())
+	} else {
+		cmd.Stdin = src
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		log.Debug(ctx, "previewExternal: helper %s failed on %s: %s", helper, basename, err)
+		return nil, err
+	}
+
+	var env externalPreviewEnvelope
+	if err := json.Unmarshal(out, &env); err != nil {
+		return nil, err
+	}
+	if len(env.DataBase64) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if env.ContentType == "" {
+		env.ContentType = "image/png"
+	}
+	return &PreviewRes{
+		Source:            env.DataBase64,
+		ContentType:       env.ContentType,
+		BaseWidth:         env.Width,
+		BaseHeight:        env.Height,
+		BaseDurationMs:    env.DurationMs,
+		PreviewWidth:      env.Width,
+		PreviewHeight:     env.Height,
+		PreviewDurationMs: env.DurationMs,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/externalstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewExternalHelper(t *testing.T) {
+	tc := externalstest.SetupTest(t, "preview_external", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+	log := utils.NewDebugLabeler(tc.G.Log, "test", false)
+
+	dir, err := ioutil.TempDir("", "preview")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// A fake waveform generator: reads the audio, emits a tiny "image".
+	png := base64.StdEncoding.EncodeToString([]byte("fake-png-bytes"))
+	helper := filepath.Join(dir, "waveform.sh")
+	require.NoError(t, ioutil.WriteFile(helper, []byte(fmt.Sprintf(
+		"#!/bin/sh\ncat > /dev/null\necho '{\"content_type\": \"image/png\", \"width\": 400, \"height\": 80, \"duration_ms\": 32000, \"data_base64\": \"%s\"}'\n", png)), 0700))
+
+	cfgPath := tc.G.Env.GetConfigFilename()
+	require.NoError(t, os.MkdirAll(filepath.Dir(cfgPath), 0700))
+	require.NoError(t, ioutil.WriteFile(cfgPath, []byte(fmt.Sprintf(
+		`{"attachments": {"preview_helpers": {"audio": "%s"}}}`, helper)), 0600))
+	require.NoError(t, tc.G.ConfigReload())
+
+	pre, err := previewExternal(context.Background(), g, log,
+		bytes.NewReader([]byte("RIFFfake-audio")), "audio/wav", "memo.wav")
+	require.NoError(t, err)
+	require.NotNil(t, pre)
+	require.Equal(t, "image/png", pre.ContentType)
+	require.Equal(t, 400, pre.PreviewWidth)
+	require.Equal(t, 32000, pre.PreviewDurationMs)
+	require.Equal(t, []byte("fake-png-bytes"), pre.Source)
+
+	// Unconfigured types produce no preview and no error.
+	pre, err = previewExternal(context.Background(), g, log,
+		bytes.NewReader([]byte("x")), "application/zip", "a.zip")
+	require.NoError(t, err)
+	require.Nil(t, pre)
+}
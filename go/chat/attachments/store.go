@@ -282,17 +282,97 @@ func (a *S3Store) DownloadAsset(ctx context.Context, params chat1.S3Params, asse
 	if asset.Key == nil || asset.VerifyKey == nil || asset.EncHash == nil {
 		return fmt.Errorf("unencrypted attachments not supported: asset: %#v", asset)
 	}
-	body, err := a.GetAssetReader(ctx, params, asset, signer)
-	defer func() {
-		if body != nil {
-			body.Close()
-		}
-	}()
+	// Stream through a resuming reader, so a network blip mid-transfer
+	// picks up from the current offset with a ranged request instead of
+	// restarting a large download from zero. Integrity is unaffected:
+	// DecryptAsset hashes and decrypts the reassembled ciphertext
+	// stream exactly as before.
+	body := newResumingAssetReader(ctx, a, params, asset, signer)
+	defer body.Close()
+	a.Debug(ctx, "DownloadAsset: downloading %s from s3", asset.Path)
+	return a.DecryptAsset(ctx, w, body, asset, progress)
+}
+
+// resumingAssetReader reads an asset's ciphertext, transparently
+// re-opening the S3 stream with a Range request after transport errors.
+type resumingAssetReader struct {
+	ctx    context.Context
+	a      *S3Store
+	bucket s3.BucketInt
+	asset  chat1.Asset
+	offset int64
+	rc     io.ReadCloser
+
+	// retriesLeft bounds reconnection attempts per download.
+	retriesLeft int
+}
+
+const downloadResumeRetries = 5
+
+func newResumingAssetReader(ctx context.Context, a *S3Store, params chat1.S3Params, asset chat1.Asset,
+	signer s3.Signer) *resumingAssetReader {
+	return &resumingAssetReader{
+		ctx:         ctx,
+		a:           a,
+		bucket:      a.getAssetBucket(asset, params, signer),
+		asset:       asset,
+		retriesLeft: downloadResumeRetries,
+	}
+}
+
+func (r *resumingAssetReader) open() error {
+	var rc io.ReadCloser
+	var err error
+	if r.asset.Size <= 0 {
+		// Legacy metadata without a size can't be range-read; fetch the
+		// whole object, with no mid-stream resumption (a restart would
+		// replay bytes already delivered downstream).
+		r.retriesLeft = 0
+		rc, err = r.bucket.GetReader(r.ctx, r.asset.Path)
+	} else {
+		rc, err = r.bucket.GetReaderWithRange(r.ctx, r.asset.Path, r.offset, r.asset.Size)
+	}
 	if err != nil {
 		return err
 	}
-	a.Debug(ctx, "DownloadAsset: downloading %s from s3", asset.Path)
-	return a.DecryptAsset(ctx, w, body, asset, progress)
+	r.rc = rc
+	return nil
+}
+
+func (r *resumingAssetReader) Read(b []byte) (int, error) {
+	for {
+		if r.rc == nil {
+			if err := r.open(); err != nil {
+				if r.retriesLeft > 0 {
+					r.retriesLeft--
+					continue
+				}
+				return 0, err
+			}
+		}
+		n, err := r.rc.Read(b)
+		r.offset += int64(n)
+		if err != nil && err != io.EOF && r.retriesLeft > 0 {
+			// Transport hiccup: reconnect at the current offset.
+			r.retriesLeft--
+			r.a.Debug(r.ctx, "resumingAssetReader: read error at offset %d, resuming (%d retries left): %s",
+				r.offset, r.retriesLeft, err)
+			r.rc.Close()
+			r.rc = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *resumingAssetReader) Close() error {
+	if r.rc == nil {
+		return nil
+	}
+	return r.rc.Close()
 }
 
 type s3Seeker struct {
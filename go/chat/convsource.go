@@ -1188,6 +1188,25 @@ func (s *HybridConversationSource) mergeMaybeNotify(ctx context.Context,
 	if err != nil {
 		return err
 	}
+	if idx := s.G().Indexer; idx != nil {
+		if ierr := idx.Add(ctx, convID, uid, msgs); ierr != nil {
+			s.Debug(ctx, "mergeMaybeNotify: failed to index messages: %s", ierr)
+		}
+		if len(mergeRes.Exploded) > 0 {
+			var exploded []chat1.MessageID
+			for _, msg := range mergeRes.Exploded {
+				exploded = append(exploded, msg.GetMessageID())
+			}
+			if ierr := idx.RemoveMsgs(ctx, convID, uid, exploded); ierr != nil {
+				s.Debug(ctx, "mergeMaybeNotify: failed to unindex exploded messages: %s", ierr)
+			}
+		}
+		if mergeRes.Expunged != nil {
+			if ierr := idx.RemoveConvUpto(ctx, convID, uid, mergeRes.Expunged.Upto); ierr != nil {
+				s.Debug(ctx, "mergeMaybeNotify: failed to unindex expunged messages: %s", ierr)
+			}
+		}
+	}
 	s.notifyExpunge(ctx, uid, convID, mergeRes)
 	s.notifyEphemeralPurge(ctx, uid, convID, mergeRes.Exploded)
 	s.notifyReactionUpdates(ctx, uid, convID, mergeRes.ReactionTargets)
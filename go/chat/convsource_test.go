@@ -829,6 +829,12 @@ func (f failingUpak) ListFollowedUIDs(ctx context.Context, uid keybase1.UID) ([]
 	require.Fail(f.t, "ListFollowedUIDs call")
 	return nil, nil
 }
+func (f failingUpak) CacheStats() keybase1.UPAKStats {
+	return keybase1.UPAKStats{}
+}
+func (f failingUpak) LoadAtMerkleSeqno(ctx context.Context, uid keybase1.UID, s keybase1.Seqno) (*keybase1.UserPlusKeysV2AllIncarnations, error) {
+	return nil, errors.New("failing upak")
+}
 func (f failingUpak) PutUserToCache(ctx context.Context, user *libkb.User) error {
 	require.Fail(f.t, "PutUserToCache call")
 	return nil
@@ -0,0 +1,155 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/protocol/chat1"
+)
+
+// User-extensible slash commands. Users declare commands in config:
+//
+//   "chat": { "commands": [
+//     { "name": "weather", "usage": "<city>", "exec": "/usr/local/bin/weather" },
+//     { "name": "ticket", "usage": "<id>", "webhook": "https://internal/hook" } ] }
+//
+// When an outgoing text message starts with /name, the service runs the
+// handler — a local executable (args passed through, stdout becomes the
+// message body) or a webhook (JSON POST, response body becomes the
+// message) — extending the built-in command set without forking.
+
+type CustomCommand struct {
+	Name    string `json:"name"`
+	Usage   string `json:"usage"`
+	Exec    string `json:"exec,omitempty"`
+	Webhook string `json:"webhook,omitempty"`
+}
+
+const customCommandTimeout = 30 * time.Second
+
+// loadCustomCommands reads the declared commands from config.
+func loadCustomCommands(g *globals.Context) []CustomCommand {
+	raw, err := g.Env.GetConfig().GetInterfaceAtPath("chat.commands")
+	if err != nil {
+		return nil
+	}
+	blob, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var cmds []CustomCommand
+	if err := json.Unmarshal(blob, &cmds); err != nil {
+		return nil
+	}
+	var ret []CustomCommand
+	for _, cmd := range cmds {
+		if cmd.Name != "" && (cmd.Exec != "" || cmd.Webhook != "") {
+			ret = append(ret, cmd)
+		}
+	}
+	return ret
+}
+
+// matchCustomCommand finds the declared command invoked by a message
+// body, returning it and the argument remainder.
+func matchCustomCommand(cmds []CustomCommand, body string) (*CustomCommand, string, bool) {
+	if !strings.HasPrefix(body, "/") {
+		return nil, "", false
+	}
+	fields := strings.SplitN(strings.TrimPrefix(body, "/"), " ", 2)
+	name := fields[0]
+	args := ""
+	if len(fields) == 2 {
+		args = fields[1]
+	}
+	for i := range cmds {
+		if cmds[i].Name == name {
+			return &cmds[i], args, true
+		}
+	}
+	return nil, "", false
+}
+
+// runCustomCommand executes the handler and returns the text that
+// should be posted in place of the raw command.
+func runCustomCommand(ctx context.Context, cmd *CustomCommand, convID chat1.ConversationID, args string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, customCommandTimeout)
+	defer cancel()
+
+	switch {
+	case cmd.Exec != "":
+		run := exec.CommandContext(ctx, cmd.Exec, strings.Fields(args)...)
+		out, err := run.Output()
+		if err != nil {
+			return "", fmt.Errorf("/%s failed: %s", cmd.Name, err)
+		}
+		return strings.TrimRight(string(out), "\n"), nil
+	case cmd.Webhook != "":
+		payload, err := json.Marshal(map[string]string{
+			"command": cmd.Name,
+			"args":    args,
+			"conv_id": convID.String(),
+		})
+		if err != nil {
+			return "", err
+		}
+		req, err := http.NewRequest("POST", cmd.Webhook, bytes.NewReader(payload))
+		if err != nil {
+			return "", err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("/%s webhook failed: %s", cmd.Name, err)
+		}
+		defer resp.Body.Close()
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(resp.Body); err != nil {
+			return "", err
+		}
+		if resp.StatusCode >= 300 {
+			return "", fmt.Errorf("/%s webhook returned %s", cmd.Name, resp.Status)
+		}
+		return strings.TrimRight(buf.String(), "\n"), nil
+	}
+	return "", fmt.Errorf("/%s has no handler", cmd.Name)
+}
+
+// applyCustomCommands rewrites an outgoing message if it invokes a
+// declared command; the handler's output becomes the message body.
+func applyCustomCommands(ctx context.Context, g *globals.Context, convID chat1.ConversationID,
+	msg *chat1.MessagePlaintext) error {
+	if msg == nil {
+		return nil
+	}
+	typ, err := msg.MessageBody.MessageType()
+	if err != nil || typ != chat1.MessageType_TEXT {
+		return nil
+	}
+	cmds := loadCustomCommands(g)
+	if len(cmds) == 0 {
+		return nil
+	}
+	cmd, args, ok := matchCustomCommand(cmds, msg.MessageBody.Text().Body)
+	if !ok {
+		return nil
+	}
+	out, err := runCustomCommand(ctx, cmd, convID, args)
+	if err != nil {
+		return err
+	}
+	replyTo := msg.MessageBody.Text().ReplyTo
+	msg.MessageBody = chat1.NewMessageBodyWithText(chat1.MessageText{Body: out, ReplyTo: replyTo})
+	return nil
+}
@@ -0,0 +1,52 @@
+package chat
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchCustomCommand(t *testing.T) {
+	cmds := []CustomCommand{
+		{Name: "weather", Exec: "/bin/true"},
+		{Name: "ticket", Webhook: "https://example.com"},
+	}
+
+	cmd, args, ok := matchCustomCommand(cmds, "/weather london today")
+	require.True(t, ok)
+	require.Equal(t, "weather", cmd.Name)
+	require.Equal(t, "london today", args)
+
+	_, _, ok = matchCustomCommand(cmds, "weather london")
+	require.False(t, ok)
+	_, _, ok = matchCustomCommand(cmds, "/unknown foo")
+	require.False(t, ok)
+	cmd, args, ok = matchCustomCommand(cmds, "/ticket")
+	require.True(t, ok)
+	require.Equal(t, "", args)
+}
+
+func TestRunCustomCommandExec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "customcmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, "echoer.sh")
+	require.NoError(t, ioutil.WriteFile(script, []byte("#!/bin/sh\necho \"weather for $1: sunny\"\n"), 0700))
+
+	out, err := runCustomCommand(context.Background(),
+		&CustomCommand{Name: "weather", Exec: script}, chat1.ConversationID([]byte{1}), "london")
+	require.NoError(t, err)
+	require.Equal(t, "weather for london: sunny", out)
+
+	// Failing handlers surface an error instead of posting garbage.
+	bad := filepath.Join(dir, "bad.sh")
+	require.NoError(t, ioutil.WriteFile(bad, []byte("#!/bin/sh\nexit 3\n"), 0700))
+	_, err = runCustomCommand(context.Background(),
+		&CustomCommand{Name: "bad", Exec: bad}, chat1.ConversationID([]byte{1}), "")
+	require.Error(t, err)
+}
@@ -23,6 +23,7 @@ type ChatContext struct {
 	AttachmentUploader  types.AttachmentUploader  // upload attachments
 	NativeVideoHelper   types.NativeVideoHelper   // connection to native for doing things with video
 	PaymentLoader       types.PaymentLoader       // stellar payment loader
+	Indexer             types.Indexer             // on-device full-text chat search index
 }
 
 type Context struct {
@@ -0,0 +1,154 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package chat
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/storage"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/protocol/gregor1"
+)
+
+// LocalMaintenance proactively reclaims local chat storage on a
+// schedule rather than only when a conversation happens to be opened:
+// stale attachment cache files are deleted, expunged conversations'
+// search index rows are dropped, and the ephemeral purger gets a
+// nudge for every known conversation so expired exploding messages
+// are cleared even in never-opened conversations.
+type LocalMaintenance struct {
+	globals.Contextified
+	utils.DebugLabeler
+
+	stopCh chan chan struct{}
+}
+
+const (
+	localMaintenanceDefaultInterval = 6 * time.Hour
+	attachmentCacheMaxAge           = 7 * 24 * time.Hour
+)
+
+func NewLocalMaintenance(g *globals.Context) *LocalMaintenance {
+	return &LocalMaintenance{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.GetLog(), "LocalMaintenance", false),
+	}
+}
+
+func (l *LocalMaintenance) interval() time.Duration {
+	if s, ok := l.G().Env.GetConfig().GetStringAtPath("chat.local_purge_interval"); ok {
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			return d
+		}
+	}
+	return localMaintenanceDefaultInterval
+}
+
+func (l *LocalMaintenance) Start(ctx context.Context, uid gregor1.UID) {
+	if l.stopCh != nil {
+		l.Debug(ctx, "Start: already running")
+		return
+	}
+	l.Debug(ctx, "Start: interval %s", l.interval())
+	l.stopCh = make(chan chan struct{})
+	go l.loop(uid, l.stopCh)
+}
+
+func (l *LocalMaintenance) Stop(ctx context.Context) chan struct{} {
+	l.Debug(ctx, "Stop")
+	ch := make(chan struct{})
+	if l.stopCh != nil {
+		l.stopCh <- ch
+		l.stopCh = nil
+	} else {
+		close(ch)
+	}
+	return ch
+}
+
+func (l *LocalMaintenance) loop(uid gregor1.UID, stopCh chan chan struct{}) {
+	ctx := context.Background()
+	for {
+		select {
+		case <-l.G().Clock().After(l.interval()):
+			l.runOnce(ctx, uid)
+		case ch := <-stopCh:
+			close(ch)
+			return
+		}
+	}
+}
+
+func (l *LocalMaintenance) runOnce(ctx context.Context, uid gregor1.UID) {
+	l.Debug(ctx, "runOnce")
+	l.purgeAttachmentCache(ctx)
+	l.purgeIndex(ctx, uid)
+	l.nudgeEphemeralPurger(ctx, uid)
+}
+
+// purgeAttachmentCache deletes cached attachment artifacts past their
+// useful life; they are rebuilt on demand.
+func (l *LocalMaintenance) purgeAttachmentCache(ctx context.Context) {
+	cutoff := time.Now().Add(-attachmentCacheMaxAge)
+	for _, dirname := range []string{"uploadedpreviews", "uploadedfulls", "pendingpreviews"} {
+		dir := filepath.Join(l.G().GetCacheDir(), dirname)
+		entries, err := filepath.Glob(filepath.Join(dir, "*"))
+		if err != nil {
+			continue
+		}
+		for _, path := range entries {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || info.ModTime().After(cutoff) {
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				l.Debug(ctx, "purgeAttachmentCache: remove %s: %s", path, err)
+			}
+		}
+	}
+}
+
+// purgeIndex drops search index rows for conversations this user no
+// longer has, so index data cannot outlive its conversation.
+func (l *LocalMaintenance) purgeIndex(ctx context.Context, uid gregor1.UID) {
+	idx := l.G().Indexer
+	if idx == nil {
+		return
+	}
+	_, convs, err := storage.NewInbox(l.G(), uid).ReadAll(ctx)
+	if err != nil {
+		l.Debug(ctx, "purgeIndex: inbox read failed: %s", err)
+		return
+	}
+	keep := make(map[string]bool, len(convs))
+	for _, conv := range convs {
+		keep[conv.GetConvID().String()] = true
+	}
+	if err := idx.SweepConvs(ctx, uid, keep); err != nil {
+		l.Debug(ctx, "purgeIndex: sweep failed: %s", err)
+	}
+}
+
+// nudgeEphemeralPurger re-queues every active purge schedule with the
+// purger, so exploding messages expire on time even in conversations
+// that are never opened.
+func (l *LocalMaintenance) nudgeEphemeralPurger(ctx context.Context, uid gregor1.UID) {
+	purgeInfo, err := storage.New(l.G(), l.G().ConvSource).GetAllPurgeInfo(ctx, uid)
+	if err != nil {
+		l.Debug(ctx, "nudgeEphemeralPurger: purge info read failed: %s", err)
+		return
+	}
+	for _, info := range purgeInfo {
+		if !info.IsActive {
+			continue
+		}
+		if err := l.G().EphemeralPurger.Queue(ctx, info); err != nil {
+			l.Debug(ctx, "nudgeEphemeralPurger: queue failed: %s", err)
+		}
+	}
+}
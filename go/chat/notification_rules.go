@@ -0,0 +1,149 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/protocol/chat1"
+)
+
+// Per-device notification routing rules, evaluated client-side on the
+// receiving device so the rules (and the message text they inspect)
+// never leave it. Declared in this device's config:
+//
+//   "notifications": { "rules": [
+//     { "action": "mute", "conversation": "team.big" },
+//     { "action": "notify", "sender": "boss" },
+//     { "action": "mute", "quiet_hours": "22:00-07:00" },
+//     { "action": "notify", "keyword": "(?i)pager|urgent" } ] }
+//
+// Rules run in order; the first match wins. With no match the regular
+// notification settings apply.
+
+type notificationRule struct {
+	Action       string `json:"action"` // "notify" or "mute"
+	Conversation string `json:"conversation,omitempty"`
+	Sender       string `json:"sender,omitempty"`
+	Keyword      string `json:"keyword,omitempty"`
+	AtMention    bool   `json:"at_mention,omitempty"`
+	QuietHours   string `json:"quiet_hours,omitempty"` // "HH:MM-HH:MM" local
+}
+
+func loadNotificationRules(g *globals.Context) []notificationRule {
+	raw, err := g.Env.GetConfig().GetInterfaceAtPath("notifications.rules")
+	if err != nil {
+		return nil
+	}
+	blob, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var rules []notificationRule
+	if err := json.Unmarshal(blob, &rules); err != nil {
+		return nil
+	}
+	return rules
+}
+
+// inQuietHours evaluates an "HH:MM-HH:MM" window, handling windows that
+// wrap past midnight.
+func inQuietHours(window string, now time.Time) bool {
+	parts := strings.SplitN(window, "-", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	parse := func(s string) (int, bool) {
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+		if err != nil {
+			return 0, false
+		}
+		return t.Hour()*60 + t.Minute(), true
+	}
+	start, ok1 := parse(parts[0])
+	end, ok2 := parse(parts[1])
+	if !ok1 || !ok2 {
+		return false
+	}
+	cur := now.Hour()*60 + now.Minute()
+	if start <= end {
+		return cur >= start && cur < end
+	}
+	return cur >= start || cur < end
+}
+
+// matchNotificationRule reports whether one rule applies to a message.
+func matchNotificationRule(rule notificationRule, convName, sender, body string,
+	atMentioned bool, now time.Time) bool {
+	matched := false
+	if rule.Conversation != "" {
+		if !strings.Contains(convName, rule.Conversation) {
+			return false
+		}
+		matched = true
+	}
+	if rule.Sender != "" {
+		if sender != rule.Sender {
+			return false
+		}
+		matched = true
+	}
+	if rule.Keyword != "" {
+		re, err := regexp.Compile(rule.Keyword)
+		if err != nil || !re.MatchString(body) {
+			return false
+		}
+		matched = true
+	}
+	if rule.AtMention {
+		if !atMentioned {
+			return false
+		}
+		matched = true
+	}
+	if rule.QuietHours != "" {
+		if !inQuietHours(rule.QuietHours, now) {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// applyNotificationRules returns (decision, true) when a configured
+// rule decides the notification, or ok=false to fall through to the
+// regular settings.
+func applyNotificationRules(ctx context.Context, g *globals.Context,
+	conv *chat1.ConversationLocal, msg chat1.MessageUnboxed, uid []byte) (bool, bool) {
+	rules := loadNotificationRules(g)
+	if len(rules) == 0 || conv == nil || !msg.IsValid() {
+		return false, false
+	}
+
+	mv := msg.Valid()
+	body := ""
+	if typ, err := mv.MessageBody.MessageType(); err == nil && typ == chat1.MessageType_TEXT {
+		body = mv.MessageBody.Text().Body
+	}
+	atMentioned := false
+	for _, at := range mv.AtMentions {
+		if at.Eq(uid) {
+			atMentioned = true
+			break
+		}
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		if matchNotificationRule(rule, conv.Info.TlfName, mv.SenderUsername, body, atMentioned, now) {
+			return rule.Action == "notify", true
+		}
+	}
+	return false, false
+}
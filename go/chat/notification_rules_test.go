@@ -0,0 +1,41 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchNotificationRule(t *testing.T) {
+	at10pm := time.Date(2018, 6, 1, 22, 30, 0, 0, time.Local)
+	at9am := time.Date(2018, 6, 1, 9, 0, 0, 0, time.Local)
+
+	// Conversation mute.
+	require.True(t, matchNotificationRule(
+		notificationRule{Action: "mute", Conversation: "team.big"},
+		"team.big.general", "anyone", "x", false, at9am))
+	require.False(t, matchNotificationRule(
+		notificationRule{Action: "mute", Conversation: "team.big"},
+		"team.small", "anyone", "x", false, at9am))
+
+	// Sender allow combined with keyword: both must hold.
+	rule := notificationRule{Action: "notify", Sender: "boss", Keyword: "(?i)urgent"}
+	require.True(t, matchNotificationRule(rule, "c", "boss", "URGENT: ship it", false, at9am))
+	require.False(t, matchNotificationRule(rule, "c", "boss", "lunch?", false, at9am))
+	require.False(t, matchNotificationRule(rule, "c", "intern", "urgent", false, at9am))
+
+	// Quiet hours wrapping midnight.
+	quiet := notificationRule{Action: "mute", QuietHours: "22:00-07:00"}
+	require.True(t, matchNotificationRule(quiet, "c", "s", "x", false, at10pm))
+	require.False(t, matchNotificationRule(quiet, "c", "s", "x", false, at9am))
+
+	// At-mention rule.
+	require.True(t, matchNotificationRule(
+		notificationRule{Action: "notify", AtMention: true}, "c", "s", "x", true, at9am))
+	require.False(t, matchNotificationRule(
+		notificationRule{Action: "notify", AtMention: true}, "c", "s", "x", false, at9am))
+
+	// An empty rule matches nothing.
+	require.False(t, matchNotificationRule(notificationRule{Action: "mute"}, "c", "s", "x", false, at9am))
+}
@@ -0,0 +1,152 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package chat
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/client/go/teams"
+)
+
+// Multi-pin support: each conversation keeps an ordered list of pinned
+// messages plus an append-only history of pin/unpin events (who, when,
+// what), stored in the chat DB. Pinning in team conversations is gated
+// on the conversation's minimum writer role, enforced client-side.
+
+type PinEntry struct {
+	MsgID    chat1.MessageID `json:"msg_id"`
+	PinnedBy string          `json:"pinned_by"`
+	PinnedAt time.Time       `json:"pinned_at"`
+}
+
+type PinEvent struct {
+	Action string          `json:"action"` // "pin" or "unpin"
+	MsgID  chat1.MessageID `json:"msg_id"`
+	By     string          `json:"by"`
+	At     time.Time       `json:"at"`
+}
+
+type pinState struct {
+	Pins    []PinEntry `json:"pins"`
+	History []PinEvent `json:"history"`
+}
+
+// maxPinHistory bounds the retained event log per conversation.
+const maxPinHistory = 200
+
+func pinDbKey(convID chat1.ConversationID) libkb.DbKey {
+	return libkb.DbKey{Typ: libkb.DBChatPins, Key: convID.String()}
+}
+
+func loadPinState(g *globals.Context, convID chat1.ConversationID) (st pinState) {
+	_, _ = g.LocalChatDb.GetInto(&st, pinDbKey(convID))
+	return st
+}
+
+func savePinState(g *globals.Context, convID chat1.ConversationID, st pinState) error {
+	if len(st.History) > maxPinHistory {
+		st.History = st.History[len(st.History)-maxPinHistory:]
+	}
+	return g.LocalChatDb.PutObj(pinDbKey(convID), nil, st)
+}
+
+// checkPinPermission enforces the conversation's minimum writer role
+// for pin changes in team conversations.
+func checkPinPermission(ctx context.Context, g *globals.Context, conv chat1.ConversationLocal, username string) error {
+	if conv.Info.MembersType != chat1.ConversationMembersType_TEAM {
+		return nil
+	}
+	if conv.ConvSettings == nil || conv.ConvSettings.MinWriterRoleInfo == nil {
+		return nil
+	}
+	minRole := conv.ConvSettings.MinWriterRoleInfo.Role
+	teamID := keybase1.TeamID(conv.Info.Triple.Tlfid.String())
+	team, err := teams.Load(ctx, g.ExternalG(), keybase1.LoadTeamArg{ID: teamID})
+	if err != nil {
+		return err
+	}
+	upak, _, err := g.GetUPAKLoader().LoadV2(
+		libkb.NewLoadUserArgWithMetaContext(libkb.NewMetaContext(ctx, g.ExternalG())).
+			WithName(username).WithPublicKeyOptional())
+	if err != nil {
+		return err
+	}
+	role, err := team.MemberRole(ctx, upak.Current.ToUserVersion())
+	if err != nil {
+		return err
+	}
+	if !role.IsOrAbove(minRole) {
+		return fmt.Errorf("pinning here requires role %v (you are %v)", minRole, role)
+	}
+	return nil
+}
+
+// PinMessage appends a message to the conversation's ordered pin list.
+func PinMessage(ctx context.Context, g *globals.Context, conv chat1.ConversationLocal,
+	msgID chat1.MessageID, username string) error {
+	if err := checkPinPermission(ctx, g, conv, username); err != nil {
+		return err
+	}
+	st := loadPinState(g, conv.Info.Id)
+	for _, pin := range st.Pins {
+		if pin.MsgID == msgID {
+			return fmt.Errorf("message %d is already pinned", msgID)
+		}
+	}
+	now := time.Now()
+	st.Pins = append(st.Pins, PinEntry{MsgID: msgID, PinnedBy: username, PinnedAt: now})
+	st.History = append(st.History, PinEvent{Action: "pin", MsgID: msgID, By: username, At: now})
+	return savePinState(g, conv.Info.Id, st)
+}
+
+// UnpinMessage removes a pin, keeping the event in history.
+func UnpinMessage(ctx context.Context, g *globals.Context, conv chat1.ConversationLocal,
+	msgID chat1.MessageID, username string) error {
+	if err := checkPinPermission(ctx, g, conv, username); err != nil {
+		return err
+	}
+	st := loadPinState(g, conv.Info.Id)
+	idx := -1
+	for i, pin := range st.Pins {
+		if pin.MsgID == msgID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("message %d is not pinned", msgID)
+	}
+	st.Pins = append(st.Pins[:idx], st.Pins[idx+1:]...)
+	st.History = append(st.History, PinEvent{Action: "unpin", MsgID: msgID, By: username, At: time.Now()})
+	return savePinState(g, conv.Info.Id, st)
+}
+
+// ListPins returns the ordered pin list.
+func ListPins(g *globals.Context, convID chat1.ConversationID) []PinEntry {
+	return loadPinState(g, convID).Pins
+}
+
+// PinHistory pages through pin/unpin events, newest first.
+func PinHistory(g *globals.Context, convID chat1.ConversationID, offset, limit int) []PinEvent {
+	hist := loadPinState(g, convID).History
+	// Newest first.
+	rev := make([]PinEvent, 0, len(hist))
+	for i := len(hist) - 1; i >= 0; i-- {
+		rev = append(rev, hist[i])
+	}
+	if offset >= len(rev) {
+		return nil
+	}
+	rev = rev[offset:]
+	if limit > 0 && limit < len(rev) {
+		rev = rev[:limit]
+	}
+	return rev
+}
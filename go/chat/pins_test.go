@@ -0,0 +1,47 @@
+package chat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/externalstest"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinsMultiAndHistory(t *testing.T) {
+	tc := externalstest.SetupTest(t, "pins", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+	ctx := context.Background()
+
+	conv := chat1.ConversationLocal{}
+	conv.Info.Id = chat1.ConversationID([]byte{0x01})
+	conv.Info.MembersType = chat1.ConversationMembersType_IMPTEAMNATIVE
+
+	require.NoError(t, PinMessage(ctx, g, conv, 5, "ayesha"))
+	require.NoError(t, PinMessage(ctx, g, conv, 9, "bruno"))
+	require.Error(t, PinMessage(ctx, g, conv, 5, "ayesha")) // no duplicates
+
+	pins := ListPins(g, conv.Info.Id)
+	require.Len(t, pins, 2)
+	require.Equal(t, chat1.MessageID(5), pins[0].MsgID) // ordered
+	require.Equal(t, chat1.MessageID(9), pins[1].MsgID)
+
+	require.NoError(t, UnpinMessage(ctx, g, conv, 5, "bruno"))
+	require.Error(t, UnpinMessage(ctx, g, conv, 5, "bruno"))
+	require.Len(t, ListPins(g, conv.Info.Id), 1)
+
+	// History is newest-first and includes the unpin.
+	hist := PinHistory(g, conv.Info.Id, 0, 10)
+	require.Len(t, hist, 3)
+	require.Equal(t, "unpin", hist[0].Action)
+	require.Equal(t, "ayesha", hist[2].By)
+
+	// Paging.
+	page := PinHistory(g, conv.Info.Id, 1, 1)
+	require.Len(t, page, 1)
+	require.Equal(t, "pin", page[0].Action)
+	require.Equal(t, chat1.MessageID(9), page[0].MsgID)
+}
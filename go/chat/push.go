@@ -312,6 +312,12 @@ func (g *PushHandler) TlfResolve(ctx context.Context, m gregor.OutOfBandMessage)
 
 func (g *PushHandler) shouldDisplayDesktopNotification(ctx context.Context,
 	uid gregor1.UID, conv *chat1.ConversationLocal, msg chat1.MessageUnboxed) bool {
+	// Device-local routing rules take precedence over the synced
+	// notification settings; first match wins.
+	if decision, ok := applyNotificationRules(ctx, g.G(), conv, msg, uid.Bytes()); ok {
+		g.Debug(ctx, "shouldDisplayDesktopNotification: decided by device rule: %v", decision)
+		return decision
+	}
 	if conv == nil || conv.Notifications == nil {
 		return false
 	}
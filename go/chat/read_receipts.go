@@ -0,0 +1,99 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package chat
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+)
+
+// Read receipts with privacy controls. Sending is opt-in and always
+// under the user's control: the global chat.read_receipts.enabled knob
+// turns the feature on, and chat.read_receipts.disabled_teams lists
+// teams excluded even then. Receipts received from others aggregate
+// locally into per-message "seen by" data.
+
+// SendReadReceipts reports whether this device should emit receipts for
+// the given conversation.
+func SendReadReceipts(g *globals.Context, conv chat1.ConversationLocal) bool {
+	enabled, _ := g.Env.GetConfig().GetBoolAtPath("chat.read_receipts.enabled")
+	if !enabled {
+		return false
+	}
+	if conv.Info.MembersType == chat1.ConversationMembersType_TEAM {
+		disabled, _ := g.Env.GetConfig().GetInterfaceAtPath("chat.read_receipts.disabled_teams")
+		if list, ok := disabled.([]interface{}); ok {
+			for _, item := range list {
+				if name, ok := item.(string); ok && name == conv.Info.TlfName {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// ReadReceipt is one reader's acknowledgment of a message.
+type ReadReceipt struct {
+	Username string    `json:"username"`
+	SeenAt   time.Time `json:"seen_at"`
+}
+
+type receiptLog struct {
+	// Per message ID, who has seen it.
+	Seen map[chat1.MessageID][]ReadReceipt `json:"seen"`
+}
+
+func receiptDbKey(convID chat1.ConversationID) libkb.DbKey {
+	return libkb.DbKey{Typ: libkb.DBChatReadReceipts, Key: convID.String()}
+}
+
+// RecordReadReceipt aggregates an incoming receipt locally.
+func RecordReadReceipt(g *globals.Context, convID chat1.ConversationID,
+	msgID chat1.MessageID, username string, seenAt time.Time) error {
+	var log receiptLog
+	_, _ = g.LocalChatDb.GetInto(&log, receiptDbKey(convID))
+	if log.Seen == nil {
+		log.Seen = make(map[chat1.MessageID][]ReadReceipt)
+	}
+	for _, r := range log.Seen[msgID] {
+		if r.Username == username {
+			return nil // one receipt per reader per message
+		}
+	}
+	log.Seen[msgID] = append(log.Seen[msgID], ReadReceipt{Username: username, SeenAt: seenAt})
+	return g.LocalChatDb.PutObj(receiptDbKey(convID), nil, log)
+}
+
+// SeenBy returns who has acknowledged a message, in the order they did.
+func SeenBy(g *globals.Context, convID chat1.ConversationID, msgID chat1.MessageID) ([]ReadReceipt, error) {
+	var log receiptLog
+	found, err := g.LocalChatDb.GetInto(&log, receiptDbKey(convID))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	receipts := log.Seen[msgID]
+	sort.Slice(receipts, func(i, j int) bool { return receipts[i].SeenAt.Before(receipts[j].SeenAt) })
+	return receipts, nil
+}
+
+// FormatSeenBy renders a compact "seen by" line for CLI/UI use.
+func FormatSeenBy(receipts []ReadReceipt) string {
+	if len(receipts) == 0 {
+		return "seen by nobody yet"
+	}
+	names := make([]string, len(receipts))
+	for i, r := range receipts {
+		names[i] = r.Username
+	}
+	return fmt.Sprintf("seen by %d: %v", len(receipts), names)
+}
@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/externalstest"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReceipts(t *testing.T) {
+	tc := externalstest.SetupTest(t, "read_receipts", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+	convID := chat1.ConversationID([]byte{0x02})
+
+	// Privacy default: receipts are off until opted in.
+	conv := chat1.ConversationLocal{}
+	conv.Info.MembersType = chat1.ConversationMembersType_TEAM
+	conv.Info.TlfName = "acme"
+	require.False(t, SendReadReceipts(g, conv))
+
+	cfg := tc.G.Env.GetConfigFilename()
+	require.NoError(t, os.MkdirAll(filepath.Dir(cfg), 0700))
+	require.NoError(t, ioutil.WriteFile(cfg, []byte(
+		`{"chat": {"read_receipts": {"enabled": true, "disabled_teams": ["sensitive"]}}}`), 0600))
+	require.NoError(t, tc.G.ConfigReload())
+
+	require.True(t, SendReadReceipts(g, conv))
+	conv.Info.TlfName = "sensitive"
+	require.False(t, SendReadReceipts(g, conv)) // per-team opt-out
+
+	// Aggregation: dedup per reader, ordered by time.
+	base := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, RecordReadReceipt(g, convID, 7, "bruno", base.Add(time.Minute)))
+	require.NoError(t, RecordReadReceipt(g, convID, 7, "ayesha", base))
+	require.NoError(t, RecordReadReceipt(g, convID, 7, "bruno", base.Add(2*time.Minute)))
+
+	seen, err := SeenBy(g, convID, 7)
+	require.NoError(t, err)
+	require.Len(t, seen, 2)
+	require.Equal(t, "ayesha", seen[0].Username)
+	require.Equal(t, "bruno", seen[1].Username)
+
+	seen, err = SeenBy(g, convID, 99)
+	require.NoError(t, err)
+	require.Empty(t, seen)
+}
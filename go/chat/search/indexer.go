@@ -0,0 +1,342 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// Package search maintains an on-device inverted index over decrypted
+// chat message bodies, so term queries answer from the index instead of
+// a linear scan over every conversation. The index lives in the local
+// chat DB (encrypted at rest along with the rest of it) and is updated
+// incrementally as messages merge into local storage.
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/utils"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+)
+
+// Postings per term: conversation ID (hex) -> message IDs that contain
+// the term. Stored as JSON in the chat LocalDb.
+type termPostings map[string][]chat1.MessageID
+
+const (
+	// Terms shorter than this aren't worth indexing.
+	minTokenLen = 3
+	// Cap tokens per message so a pasted novel doesn't bloat the index.
+	maxTokensPerMsg = 64
+)
+
+type Indexer struct {
+	globals.Contextified
+	utils.DebugLabeler
+	sync.Mutex
+}
+
+func NewIndexer(g *globals.Context) *Indexer {
+	return &Indexer{
+		Contextified: globals.NewContextified(g),
+		DebugLabeler: utils.NewDebugLabeler(g.GetLog(), "search.Indexer", false),
+	}
+}
+
+func termDbKey(uid gregor1.UID, term string) libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBChatIndex,
+		Key: fmt.Sprintf("%s:%s", uid, term),
+	}
+}
+
+// tokenize splits a message body into index terms.
+func tokenize(body string) []string {
+	split := strings.FieldsFunc(strings.ToLower(body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	seen := make(map[string]bool)
+	var ret []string
+	for _, token := range split {
+		if len(token) < minTokenLen || seen[token] {
+			continue
+		}
+		seen[token] = true
+		ret = append(ret, token)
+		if len(ret) >= maxTokensPerMsg {
+			break
+		}
+	}
+	return ret
+}
+
+// messageText pulls the indexable text out of a message, or "".
+// Ephemeral (exploding) messages are never indexed: content-derived
+// terms must not outlive the message.
+func messageText(msg chat1.MessageUnboxed) string {
+	if !msg.IsValid() {
+		return ""
+	}
+	if msg.Valid().IsEphemeral() {
+		return ""
+	}
+	body := msg.Valid().MessageBody
+	typ, err := body.MessageType()
+	if err != nil {
+		return ""
+	}
+	switch typ {
+	case chat1.MessageType_TEXT:
+		return body.Text().Body
+	case chat1.MessageType_EDIT:
+		return body.Edit().Body
+	default:
+		return ""
+	}
+}
+
+// Add incrementally indexes freshly merged messages. Terms are
+// aggregated across the batch first, so each term costs one
+// read-modify-write regardless of how many messages mention it.
+func (idx *Indexer) Add(ctx context.Context, convID chat1.ConversationID, uid gregor1.UID, msgs []chat1.MessageUnboxed) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	convKey := convID.String()
+	newPostings := make(map[string][]chat1.MessageID)
+	for _, msg := range msgs {
+		text := messageText(msg)
+		if text == "" {
+			continue
+		}
+		msgID := msg.GetMessageID()
+		for _, term := range tokenize(text) {
+			newPostings[term] = append(newPostings[term], msgID)
+		}
+	}
+
+	for term, msgIDs := range newPostings {
+		key := termDbKey(uid, term)
+		var postings termPostings
+		found, err := idx.G().LocalChatDb.GetInto(&postings, key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			postings = make(termPostings)
+		}
+		dirty := false
+		for _, msgID := range msgIDs {
+			if containsMsgID(postings[convKey], msgID) {
+				continue
+			}
+			postings[convKey] = append(postings[convKey], msgID)
+			dirty = true
+		}
+		if !dirty {
+			continue
+		}
+		if err := idx.G().LocalChatDb.PutObj(key, nil, postings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveMsgs deletes postings for specific message IDs in a
+// conversation, walking the uid's slice of the index. Used when
+// ephemeral messages explode (clearing entries written before
+// ephemeral indexing was excluded) and when retention expunges.
+func (idx *Indexer) RemoveMsgs(ctx context.Context, convID chat1.ConversationID, uid gregor1.UID, msgIDs []chat1.MessageID) error {
+	drop := make(map[chat1.MessageID]bool, len(msgIDs))
+	for _, id := range msgIDs {
+		drop[id] = true
+	}
+	return idx.removeMatching(ctx, convID, uid, func(id chat1.MessageID) bool { return drop[id] })
+}
+
+// RemoveConvUpto deletes postings for every message at or below
+// upto, matching expunge semantics.
+func (idx *Indexer) RemoveConvUpto(ctx context.Context, convID chat1.ConversationID, uid gregor1.UID, upto chat1.MessageID) error {
+	return idx.removeMatching(ctx, convID, uid, func(id chat1.MessageID) bool { return id <= upto })
+}
+
+func (idx *Indexer) removeMatching(ctx context.Context, convID chat1.ConversationID, uid gregor1.UID, match func(chat1.MessageID) bool) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	convKey := convID.String()
+	uidPrefix := fmt.Sprintf("%s:", uid)
+	type edit struct {
+		key      libkb.DbKey
+		postings termPostings
+		del      bool
+	}
+	var edits []edit
+	err := idx.G().LocalChatDb.ForEachRawKv(func(id libkb.DbKey, _ []byte) error {
+		if id.Typ != libkb.DBChatIndex || !strings.HasPrefix(id.Key, uidPrefix) {
+			return nil
+		}
+		var postings termPostings
+		found, err := idx.G().LocalChatDb.GetInto(&postings, id)
+		if err != nil || !found {
+			return nil
+		}
+		ids, ok := postings[convKey]
+		if !ok {
+			return nil
+		}
+		var kept []chat1.MessageID
+		for _, msgID := range ids {
+			if !match(msgID) {
+				kept = append(kept, msgID)
+			}
+		}
+		if len(kept) == len(ids) {
+			return nil
+		}
+		if len(kept) == 0 {
+			delete(postings, convKey)
+		} else {
+			postings[convKey] = kept
+		}
+		edits = append(edits, edit{key: id, postings: postings, del: len(postings) == 0})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range edits {
+		if e.del {
+			if err := idx.G().LocalChatDb.Delete(e.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := idx.G().LocalChatDb.PutObj(e.key, nil, e.postings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SweepConvs drops postings for conversations not in keep, so index
+// rows cannot outlive their conversation.
+func (idx *Indexer) SweepConvs(ctx context.Context, uid gregor1.UID, keep map[string]bool) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	uidPrefix := fmt.Sprintf("%s:", uid)
+	type edit struct {
+		key      libkb.DbKey
+		postings termPostings
+		del      bool
+	}
+	var edits []edit
+	err := idx.G().LocalChatDb.ForEachRawKv(func(id libkb.DbKey, _ []byte) error {
+		if id.Typ != libkb.DBChatIndex || !strings.HasPrefix(id.Key, uidPrefix) {
+			return nil
+		}
+		var postings termPostings
+		found, err := idx.G().LocalChatDb.GetInto(&postings, id)
+		if err != nil || !found {
+			return nil
+		}
+		dirty := false
+		for conv := range postings {
+			if !keep[conv] {
+				delete(postings, conv)
+				dirty = true
+			}
+		}
+		if !dirty {
+			return nil
+		}
+		edits = append(edits, edit{key: id, postings: postings, del: len(postings) == 0})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range edits {
+		if e.del {
+			if err := idx.G().LocalChatDb.Delete(e.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := idx.G().LocalChatDb.PutObj(e.key, nil, e.postings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsMsgID(ids []chat1.MessageID, id chat1.MessageID) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Search returns, per conversation (hex ID), the message IDs containing
+// every term of the query.
+func (idx *Indexer) Search(ctx context.Context, uid gregor1.UID, query string) (map[string][]chat1.MessageID, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("query has no indexable terms (min length %d)", minTokenLen)
+	}
+
+	var result map[string]map[chat1.MessageID]bool
+	for _, term := range terms {
+		var postings termPostings
+		found, err := idx.G().LocalChatDb.GetInto(&postings, termDbKey(uid, term))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, nil // a missing term means no conjunctive hits
+		}
+		termSet := make(map[string]map[chat1.MessageID]bool)
+		for conv, ids := range postings {
+			set := make(map[chat1.MessageID]bool)
+			for _, id := range ids {
+				set[id] = true
+			}
+			termSet[conv] = set
+		}
+		if result == nil {
+			result = termSet
+			continue
+		}
+		// Intersect.
+		for conv, ids := range result {
+			other, ok := termSet[conv]
+			if !ok {
+				delete(result, conv)
+				continue
+			}
+			for id := range ids {
+				if !other[id] {
+					delete(ids, id)
+				}
+			}
+			if len(ids) == 0 {
+				delete(result, conv)
+			}
+		}
+	}
+
+	ret := make(map[string][]chat1.MessageID)
+	for conv, ids := range result {
+		for id := range ids {
+			ret[conv] = append(ret[conv], id)
+		}
+	}
+	return ret, nil
+}
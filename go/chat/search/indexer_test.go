@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/externalstest"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/stretchr/testify/require"
+)
+
+func textMsg(t *testing.T, id chat1.MessageID, body string) chat1.MessageUnboxed {
+	return chat1.NewMessageUnboxedWithValid(chat1.MessageUnboxedValid{
+		ServerHeader: chat1.MessageServerHeader{MessageID: id},
+		ClientHeader: chat1.MessageClientHeaderVerified{MessageType: chat1.MessageType_TEXT},
+		MessageBody:  chat1.NewMessageBodyWithText(chat1.MessageText{Body: body}),
+	})
+}
+
+func TestIndexerAddAndSearch(t *testing.T) {
+	tc := externalstest.SetupTest(t, "indexer", 1)
+	defer tc.Cleanup()
+	g := globals.NewContext(tc.G, &globals.ChatContext{})
+	idx := NewIndexer(g)
+
+	uid := gregor1.UID([]byte{0x01, 0x02})
+	conv1 := chat1.ConversationID([]byte{0xaa})
+	conv2 := chat1.ConversationID([]byte{0xbb})
+	ctx := context.Background()
+
+	require.NoError(t, idx.Add(ctx, conv1, uid, []chat1.MessageUnboxed{
+		textMsg(t, 1, "the quarterly launch plan is ready"),
+		textMsg(t, 2, "lunch plans anyone?"),
+	}))
+	require.NoError(t, idx.Add(ctx, conv2, uid, []chat1.MessageUnboxed{
+		textMsg(t, 7, "launch day checklist"),
+	}))
+
+	// Single term hits across conversations.
+	res, err := idx.Search(ctx, uid, "launch")
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, []chat1.MessageID{1}, res[conv1.String()])
+	require.Equal(t, []chat1.MessageID{7}, res[conv2.String()])
+
+	// Conjunctive terms narrow to messages containing all of them.
+	res, err = idx.Search(ctx, uid, "launch plan")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, []chat1.MessageID{1}, res[conv1.String()])
+
+	// Unindexed terms yield nothing.
+	res, err = idx.Search(ctx, uid, "zeppelin")
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	// Re-adding the same message doesn't duplicate postings.
+	require.NoError(t, idx.Add(ctx, conv1, uid, []chat1.MessageUnboxed{
+		textMsg(t, 1, "the quarterly launch plan is ready"),
+	}))
+	res, err = idx.Search(ctx, uid, "quarterly")
+	require.NoError(t, err)
+	require.Equal(t, []chat1.MessageID{1}, res[conv1.String()])
+
+	// Another user's index is separate.
+	res, err = idx.Search(ctx, gregor1.UID([]byte{0x09}), "launch")
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
@@ -742,10 +742,10 @@ func (s *BlockingSender) Send(ctx context.Context, convID chat1.ConversationID,
 	// Send up to frontend
 	if cerr == nil && boxed.GetMessageType() != chat1.MessageType_LEAVE {
 		activity := chat1.NewChatActivityWithIncomingMessage(chat1.IncomingMessage{
-			Message: utils.PresentMessageUnboxed(ctx, s.G(), unboxedMsg, boxed.ClientHeader.Sender, convID),
-			ConvID:  convID,
+			Message:                    utils.PresentMessageUnboxed(ctx, s.G(), unboxedMsg, boxed.ClientHeader.Sender, convID),
+			ConvID:                     convID,
 			DisplayDesktopNotification: false,
-			Conv: s.presentUIItem(convLocal),
+			Conv:                       s.presentUIItem(convLocal),
 		})
 		s.G().ActivityNotifier.Activity(ctx, boxed.ClientHeader.Sender, conv.GetTopicType(), &activity,
 			chat1.ChatActivitySource_LOCAL)
@@ -969,8 +969,10 @@ func (s *Deliverer) alertFailureChannels(obrs []chat1.OutboxRecord) {
 }
 
 func (s *Deliverer) doNotRetryFailure(ctx context.Context, obr chat1.OutboxRecord, err error) (chat1.OutboxErrorType, error, bool) {
-	// Check attempts
-	if obr.State.Sending() >= deliverMaxAttempts {
+	// Check attempts, but only while connected: ticks spent offline are
+	// not real send attempts, and shouldn't permanently fail a message
+	// that never reached the network.
+	if s.connected && obr.State.Sending() >= deliverMaxAttempts {
 		return chat1.OutboxErrorType_TOOMANYATTEMPTS, errors.New("max send attempts reached"), true
 	}
 	if !s.connected {
@@ -1156,6 +1158,10 @@ func (s *Deliverer) deliverLoop() {
 
 		// Fetch outbox
 		obrs, err := s.outbox.PullAllConversations(bgctx, false, false)
+		if err == nil {
+			s.G().ExternalG().Metrics.IncCounter("keybase_chat_deliver_loop_runs_total", 1)
+			s.G().ExternalG().Metrics.SetGauge("keybase_chat_outbox_depth", float64(len(obrs)))
+		}
 		if err != nil {
 			if _, ok := err.(storage.MissError); !ok {
 				s.Debug(bgctx, "deliverLoop: unable to pull outbox: uid: %s err: %s", s.outbox.GetUID(),
@@ -1178,7 +1184,10 @@ func (s *Deliverer) deliverLoop() {
 			}
 			if !s.connected {
 				err = errors.New("disconnected from chat server")
-			} else if s.clock.Now().Sub(obr.Ctime.Time()) > time.Hour {
+			} else if s.clock.Now().Sub(obr.Ctime.Time()) > time.Hour && obr.State.Sending() > 0 {
+				// Only expire messages that have actually had send
+				// attempts while connected; a message composed during a
+				// long offline stretch still deserves its first try.
 				// If we are re-trying a message after an hour, let's just give up. These times can
 				// get very long if the app is suspended on mobile.
 				s.Debug(bctx, "deliverLoop: expiring pending message because it is too old: obid: %s dur: %v",
@@ -348,6 +348,15 @@ func (h *Server) MarkAsReadLocal(ctx context.Context, arg chat1.MarkAsReadLocalA
 	if err != nil {
 		return res, err
 	}
+
+	// Record our own receipt when enabled for this conversation; other
+	// members' receipts arrive the same way from their devices.
+	if conv, cerr := h.loadConvLocal(ctx, uid, arg.ConversationID); cerr == nil && SendReadReceipts(h.G(), conv) {
+		if rerr := RecordReadReceipt(h.G(), arg.ConversationID, arg.MsgID,
+			h.G().Env.GetUsername().String(), time.Now()); rerr != nil {
+			h.Debug(ctx, "MarkAsReadLocal: read receipt record failed: %s", rerr)
+		}
+	}
 	return chat1.MarkAsReadLocalRes{
 		Offline: h.G().Syncer.IsConnected(ctx),
 	}, nil
@@ -1084,6 +1093,10 @@ func (h *Server) PostLocal(ctx context.Context, arg chat1.PostLocalArg) (res cha
 		return res, err
 	}
 
+	if arg.Msg.ClientHeader.EphemeralMetadata != nil && libkb.PolicyDisallowsExploding() {
+		return res, fmt.Errorf("exploding messages are disabled by your administrator's policy")
+	}
+
 	// Sanity check that we have a TLF name here
 	if len(arg.Msg.ClientHeader.TlfName) == 0 {
 		h.Debug(ctx, "PostLocal: no TLF name specified: convID: %s uid: %s",
@@ -1091,6 +1104,18 @@ func (h *Server) PostLocal(ctx context.Context, arg chat1.PostLocalArg) (res cha
 		return res, fmt.Errorf("no TLF name specified")
 	}
 
+	// Refuse at compose time if the conversation's minimum writer role
+	// (broadcast channels, say) excludes us, rather than waiting for
+	// the server to bounce the message.
+	if err := h.checkMinWriterRole(ctx, uid, arg.ConversationID); err != nil {
+		return res, err
+	}
+
+	// User-declared slash commands rewrite the outgoing message.
+	if err := applyCustomCommands(ctx, h.G(), arg.ConversationID, &arg.Msg); err != nil {
+		return res, err
+	}
+
 	// Make sure sender is set
 	db := make([]byte, 16)
 	deviceID := h.G().Env.GetDeviceID()
@@ -1316,6 +1341,19 @@ func (h *Server) PostLocalNonblock(ctx context.Context, arg chat1.PostLocalNonbl
 		return res, err
 	}
 
+	// Same compose-time minimum-writer-role gate as PostLocal.
+	if err := h.checkMinWriterRole(ctx, uid, arg.ConversationID); err != nil {
+		return res, err
+	}
+	if arg.Msg.ClientHeader.EphemeralMetadata != nil && libkb.PolicyDisallowsExploding() {
+		return res, fmt.Errorf("exploding messages are disabled by your administrator's policy")
+	}
+
+	// User-declared slash commands rewrite the outgoing message.
+	if err := applyCustomCommands(ctx, h.G(), arg.ConversationID, &arg.Msg); err != nil {
+		return res, err
+	}
+
 	// Sanity check that we have a TLF name here
 	if len(arg.Msg.ClientHeader.TlfName) == 0 {
 		h.Debug(ctx, "PostLocalNonblock: no TLF name specified: convID: %s uid: %s",
@@ -1676,6 +1714,47 @@ func (h *Server) assertLoggedIn(ctx context.Context) error {
 	return nil
 }
 
+// checkMinWriterRole enforces a conversation's minimum writer role
+// locally: members below the bar (readers in a broadcast channel, say)
+// get a clear error before anything is signed or queued. Lookup
+// failures fall through to the server's own enforcement.
+func (h *Server) checkMinWriterRole(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) error {
+	iboxRes, err := h.G().InboxSource.Read(ctx, uid, nil, true, &chat1.GetInboxLocalQuery{
+		ConvIDs: []chat1.ConversationID{convID},
+	}, nil)
+	if err != nil || len(iboxRes.Convs) != 1 {
+		return nil
+	}
+	conv := iboxRes.Convs[0]
+	if conv.ConvSettings == nil || conv.ConvSettings.MinWriterRoleInfo == nil {
+		return nil
+	}
+	minRole := conv.ConvSettings.MinWriterRoleInfo.Role
+	if conv.Info.MembersType != chat1.ConversationMembersType_TEAM {
+		return nil
+	}
+	teamID := keybase1.TeamID(conv.Info.Triple.Tlfid.String())
+	team, err := teams.Load(ctx, h.G().ExternalG(), keybase1.LoadTeamArg{ID: teamID})
+	if err != nil {
+		return nil
+	}
+	upak, _, err := h.G().GetUPAKLoader().LoadV2(
+		libkb.NewLoadUserArgWithMetaContext(libkb.NewMetaContext(ctx, h.G().ExternalG())).
+			WithUID(keybase1.UID(uid.String())).WithPublicKeyOptional())
+	if err != nil {
+		return nil
+	}
+	role, err := team.MemberRole(ctx, upak.Current.ToUserVersion())
+	if err != nil {
+		return nil
+	}
+	if !role.IsOrAbove(minRole) {
+		return fmt.Errorf("posting requires role %v in this conversation (you are %v); it may be a broadcast channel",
+			minRole, role)
+	}
+	return nil
+}
+
 func (h *Server) assertLoggedInUID(ctx context.Context) (uid gregor1.UID, err error) {
 	if !h.G().ActiveDevice.HaveKeys() {
 		return uid, libkb.LoginRequiredError{}
@@ -2191,6 +2270,118 @@ func (h *Server) UpgradeKBFSConversationToImpteam(ctx context.Context, convID ch
 	return h.G().ChatHelper.UpgradeKBFSToImpteam(ctx, tlfName, tlfID, public)
 }
 
+// loadConvLocal fetches one unboxed ConversationLocal by ID.
+func (h *Server) loadConvLocal(ctx context.Context, uid gregor1.UID, convID chat1.ConversationID) (conv chat1.ConversationLocal, err error) {
+	iboxRes, err := h.G().InboxSource.Read(ctx, uid, nil, true, &chat1.GetInboxLocalQuery{
+		ConvIDs: []chat1.ConversationID{convID},
+	}, nil)
+	if err != nil {
+		return conv, err
+	}
+	if len(iboxRes.Convs) != 1 {
+		return conv, fmt.Errorf("conversation not found: %s", convID)
+	}
+	return iboxRes.Convs[0], nil
+}
+
+// TranslateMessageLocal translates one message on demand through the
+// configured provider, serving repeats from the local cache.
+func (h *Server) TranslateMessageLocal(ctx context.Context, arg chat1.TranslateMessageLocalArg) (res string, err error) {
+	ctx = Context(ctx, h.G(), keybase1.TLFIdentifyBehavior_CHAT_SKIP, nil, h.identNotifier)
+	defer h.Trace(ctx, func() error { return err }, "TranslateMessageLocal")()
+	uid, err := h.assertLoggedInUID(ctx)
+	if err != nil {
+		return res, err
+	}
+	return TranslateMessage(ctx, h.G(), uid, arg.ConvID, arg.MsgID, arg.TargetLang)
+}
+
+// PinMessageLocal pins a message, gated on the conversation's minimum
+// writer role.
+func (h *Server) PinMessageLocal(ctx context.Context, arg chat1.PinMessageLocalArg) (err error) {
+	ctx = Context(ctx, h.G(), keybase1.TLFIdentifyBehavior_CHAT_SKIP, nil, h.identNotifier)
+	defer h.Trace(ctx, func() error { return err }, "PinMessageLocal")()
+	uid, err := h.assertLoggedInUID(ctx)
+	if err != nil {
+		return err
+	}
+	conv, err := h.loadConvLocal(ctx, uid, arg.ConvID)
+	if err != nil {
+		return err
+	}
+	return PinMessage(ctx, h.G(), conv, arg.MsgID, h.G().Env.GetUsername().String())
+}
+
+// UnpinMessageLocal removes a pin, keeping the event in history.
+func (h *Server) UnpinMessageLocal(ctx context.Context, arg chat1.UnpinMessageLocalArg) (err error) {
+	ctx = Context(ctx, h.G(), keybase1.TLFIdentifyBehavior_CHAT_SKIP, nil, h.identNotifier)
+	defer h.Trace(ctx, func() error { return err }, "UnpinMessageLocal")()
+	uid, err := h.assertLoggedInUID(ctx)
+	if err != nil {
+		return err
+	}
+	conv, err := h.loadConvLocal(ctx, uid, arg.ConvID)
+	if err != nil {
+		return err
+	}
+	return UnpinMessage(ctx, h.G(), conv, arg.MsgID, h.G().Env.GetUsername().String())
+}
+
+// ListPinsLocal returns the ordered pin list for a conversation.
+func (h *Server) ListPinsLocal(ctx context.Context, convID chat1.ConversationID) (res []chat1.PinEntryRes, err error) {
+	defer h.Trace(ctx, func() error { return err }, "ListPinsLocal")()
+	if _, err := h.assertLoggedInUID(ctx); err != nil {
+		return nil, err
+	}
+	for _, pin := range ListPins(h.G(), convID) {
+		res = append(res, chat1.PinEntryRes{
+			MsgID:    pin.MsgID,
+			PinnedBy: pin.PinnedBy,
+			PinnedAt: gregor1.ToTime(pin.PinnedAt),
+		})
+	}
+	return res, nil
+}
+
+// SeenByLocal reports who has acknowledged a message, oldest first.
+func (h *Server) SeenByLocal(ctx context.Context, arg chat1.SeenByLocalArg) (res []chat1.ReadReceiptRes, err error) {
+	defer h.Trace(ctx, func() error { return err }, "SeenByLocal")()
+	if _, err := h.assertLoggedInUID(ctx); err != nil {
+		return nil, err
+	}
+	receipts, err := SeenBy(h.G(), arg.ConvID, arg.MsgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range receipts {
+		res = append(res, chat1.ReadReceiptRes{Username: r.Username, SeenAt: gregor1.ToTime(r.SeenAt)})
+	}
+	return res, nil
+}
+
+// SearchLocalIndex answers term queries from the on-device inverted
+// index maintained by search.Indexer; no server round trip.
+func (h *Server) SearchLocalIndex(ctx context.Context, query string) (res []chat1.LocalIndexHit, err error) {
+	ctx = Context(ctx, h.G(), keybase1.TLFIdentifyBehavior_CHAT_SKIP, nil, h.identNotifier)
+	defer h.Trace(ctx, func() error { return err }, "SearchLocalIndex")()
+	uid, err := h.assertLoggedInUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if h.G().Indexer == nil {
+		return nil, errors.New("local index not enabled")
+	}
+	hits, err := h.G().Indexer.Search(ctx, uid, query)
+	if err != nil {
+		return nil, err
+	}
+	for convID, msgIDs := range hits {
+		res = append(res, chat1.LocalIndexHit{ConvID: convID, MsgIDs: msgIDs})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ConvID < res[j].ConvID })
+	return res, nil
+}
+
 func (h *Server) GetSearchRegexp(ctx context.Context, arg chat1.GetSearchRegexpArg) (res chat1.GetSearchRegexpRes, err error) {
 	var identBreaks []keybase1.TLFIdentifyFailure
 	ctx = Context(ctx, h.G(), arg.IdentifyBehavior, &identBreaks, h.identNotifier)
@@ -2247,3 +2438,63 @@ func (h *Server) GetStaticConfig(ctx context.Context) (res chat1.StaticConfig, e
 		DeletableByDeleteHistory: chat1.DeletableMessageTypesByDeleteHistory(),
 	}, nil
 }
+
+func (h *Server) GetLocalRetentionStats(ctx context.Context) (res []chat1.LocalConversationStats, err error) {
+	ctx = Context(ctx, h.G(), keybase1.TLFIdentifyBehavior_CHAT_SKIP, nil, nil)
+	defer h.Trace(ctx, func() error { return err }, "GetLocalRetentionStats")()
+	uid, err := h.assertLoggedInUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return storage.LocalStorageStats(h.G(), uid)
+}
+
+// GetThreadReplies returns the messages in a conversation whose text
+// body names rootMsgID as its thread root, oldest first.
+func (h *Server) GetThreadReplies(ctx context.Context, arg chat1.GetThreadRepliesArg) (res []chat1.MessageUnboxed, err error) {
+	ctx = Context(ctx, h.G(), keybase1.TLFIdentifyBehavior_CHAT_SKIP, nil, nil)
+	defer h.Trace(ctx, func() error { return err }, "GetThreadReplies")()
+	uid, err := h.assertLoggedInUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Page back until we pass the thread root (replies can't predate
+	// it), with a sanity cap on pages.
+	var pagination *chat1.Pagination
+	const maxThreadPages = 10
+	for page := 0; page < maxThreadPages; page++ {
+		tv, err := h.G().ConvSource.Pull(ctx, arg.ConvID, uid, chat1.GetThreadReason_GENERAL,
+			&chat1.GetThreadQuery{
+				MessageTypes: []chat1.MessageType{chat1.MessageType_TEXT},
+			}, pagination)
+		if err != nil {
+			return nil, err
+		}
+		sawRoot := false
+		for _, msg := range tv.Messages {
+			if msg.GetMessageID() <= arg.RootMsgID {
+				sawRoot = true
+			}
+			if !msg.IsValid() {
+				continue
+			}
+			body := msg.Valid().MessageBody
+			if typ, err := body.MessageType(); err != nil || typ != chat1.MessageType_TEXT {
+				continue
+			}
+			if rt := body.Text().ReplyTo; rt != nil && *rt == arg.RootMsgID {
+				res = append(res, msg)
+			}
+		}
+		if sawRoot || tv.Pagination == nil || tv.Pagination.Last {
+			break
+		}
+		pagination = &chat1.Pagination{Next: tv.Pagination.Next, Num: tv.Pagination.Num}
+	}
+	// Pull returns newest first; threads read oldest first.
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res, nil
+}
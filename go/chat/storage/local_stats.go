@@ -0,0 +1,54 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+)
+
+// LocalStorageStats walks the chat DB's block table and reports, per
+// conversation, how many blocks and bytes this device retains locally.
+// It backs the getLocalRetentionStats RPC so users can see what
+// retention policies are (or aren't) freeing up.
+func LocalStorageStats(g *globals.Context, uid gregor1.UID) ([]chat1.LocalConversationStats, error) {
+	prefix := fmt.Sprintf("bl:%s:", uid)
+	acc := make(map[string]*chat1.LocalConversationStats)
+
+	err := g.LocalChatDb.ForEachRawKv(func(id libkb.DbKey, value []byte) error {
+		if id.Typ != libkb.DBChatBlocks || !strings.HasPrefix(id.Key, prefix) {
+			return nil
+		}
+		rest := strings.TrimPrefix(id.Key, prefix)
+		parts := strings.SplitN(rest, ":", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		convID := parts[0]
+		stats, found := acc[convID]
+		if !found {
+			stats = &chat1.LocalConversationStats{ConvID: convID}
+			acc[convID] = stats
+		}
+		stats.Blocks++
+		stats.BytesTotal += int64(len(value))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]chat1.LocalConversationStats, 0, len(acc))
+	for _, stats := range acc {
+		ret = append(ret, *stats)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].BytesTotal > ret[j].BytesTotal })
+	return ret, nil
+}
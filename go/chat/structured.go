@@ -0,0 +1,130 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/keybase/client/go/protocol/chat1"
+)
+
+// Typed structured messages (polls, RSVPs, approvals) carried inside
+// ordinary text bodies, so they ride the existing encryption and
+// delivery paths and old clients degrade to showing the fenced JSON.
+// A structured message is a text body of the form:
+//
+//   ```kbstruct
+//   {"schema": "poll/v1", "payload": {...}}
+//   ```
+//
+// Responses reference the root message ID; AggregateResponses groups
+// them client-side so GUIs and bots can build interactive workflows.
+
+const structuredFence = "```kbstruct"
+
+// StructuredMessage is the envelope inside the fence.
+type StructuredMessage struct {
+	Schema  string          `json:"schema"`
+	ReplyTo chat1.MessageID `json:"reply_to,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Poll is the payload for schema poll/v1.
+type Poll struct {
+	Question string   `json:"question"`
+	Options  []string `json:"options"`
+}
+
+// PollResponse is the payload for schema poll-response/v1.
+type PollResponse struct {
+	Option int `json:"option"`
+}
+
+// FormatStructured renders the envelope as a message body.
+func FormatStructured(msg StructuredMessage) (string, error) {
+	if msg.Schema == "" {
+		return "", fmt.Errorf("structured messages need a schema")
+	}
+	blob, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return structuredFence + "\n" + string(blob) + "\n```", nil
+}
+
+// ParseStructured extracts the envelope from a message body, ok=false
+// for ordinary text.
+func ParseStructured(body string) (msg StructuredMessage, ok bool) {
+	trimmed := strings.TrimSpace(body)
+	if !strings.HasPrefix(trimmed, structuredFence) {
+		return msg, false
+	}
+	inner := strings.TrimPrefix(trimmed, structuredFence)
+	inner = strings.TrimSuffix(strings.TrimSpace(inner), "```")
+	if err := json.Unmarshal([]byte(strings.TrimSpace(inner)), &msg); err != nil {
+		return msg, false
+	}
+	return msg, msg.Schema != ""
+}
+
+// ValidateStructured checks the payload against its declared schema.
+func ValidateStructured(msg StructuredMessage) error {
+	switch msg.Schema {
+	case "poll/v1":
+		var poll Poll
+		if err := json.Unmarshal(msg.Payload, &poll); err != nil {
+			return fmt.Errorf("bad poll payload: %s", err)
+		}
+		if poll.Question == "" || len(poll.Options) < 2 {
+			return fmt.Errorf("polls need a question and at least two options")
+		}
+	case "poll-response/v1":
+		var resp PollResponse
+		if err := json.Unmarshal(msg.Payload, &resp); err != nil {
+			return fmt.Errorf("bad poll response payload: %s", err)
+		}
+		if msg.ReplyTo == 0 {
+			return fmt.Errorf("poll responses must reference the poll message")
+		}
+	default:
+		// Unknown schemas are allowed (bots bring their own); payload
+		// must at least be valid JSON, which RawMessage guarantees.
+	}
+	return nil
+}
+
+// PollTally aggregates poll responses client-side: one vote per sender,
+// last vote wins.
+func PollTally(poll Poll, responses []chat1.MessageUnboxed, rootID chat1.MessageID) map[string]int {
+	votes := make(map[string]int) // sender -> option
+	for _, m := range responses {
+		if !m.IsValid() {
+			continue
+		}
+		body := m.Valid().MessageBody
+		typ, err := body.MessageType()
+		if err != nil || typ != chat1.MessageType_TEXT {
+			continue
+		}
+		sm, ok := ParseStructured(body.Text().Body)
+		if !ok || sm.Schema != "poll-response/v1" || sm.ReplyTo != rootID {
+			continue
+		}
+		var resp PollResponse
+		if err := json.Unmarshal(sm.Payload, &resp); err != nil {
+			continue
+		}
+		if resp.Option < 0 || resp.Option >= len(poll.Options) {
+			continue
+		}
+		votes[m.Valid().SenderUsername] = resp.Option
+	}
+	tally := make(map[string]int)
+	for _, opt := range votes {
+		tally[poll.Options[opt]]++
+	}
+	return tally
+}
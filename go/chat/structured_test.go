@@ -0,0 +1,55 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/stretchr/testify/require"
+)
+
+func structuredTextMsg(t *testing.T, id chat1.MessageID, sender, body string) chat1.MessageUnboxed {
+	return chat1.NewMessageUnboxedWithValid(chat1.MessageUnboxedValid{
+		ServerHeader:   chat1.MessageServerHeader{MessageID: id},
+		ClientHeader:   chat1.MessageClientHeaderVerified{MessageType: chat1.MessageType_TEXT},
+		SenderUsername: sender,
+		MessageBody:    chat1.NewMessageBodyWithText(chat1.MessageText{Body: body}),
+	})
+}
+
+func TestStructuredMessagesPoll(t *testing.T) {
+	pollPayload, _ := json.Marshal(Poll{Question: "lunch?", Options: []string{"tacos", "ramen"}})
+	root := StructuredMessage{Schema: "poll/v1", Payload: pollPayload}
+	require.NoError(t, ValidateStructured(root))
+
+	body, err := FormatStructured(root)
+	require.NoError(t, err)
+
+	parsed, ok := ParseStructured(body)
+	require.True(t, ok)
+	require.Equal(t, "poll/v1", parsed.Schema)
+
+	// Plain text is not structured.
+	_, ok = ParseStructured("just words")
+	require.False(t, ok)
+
+	// Bad schemas are rejected.
+	require.Error(t, ValidateStructured(StructuredMessage{Schema: "poll/v1", Payload: []byte(`{}`)}))
+
+	// Tally: one vote per sender, last wins, out-of-range ignored.
+	vote := func(id chat1.MessageID, sender string, opt int) chat1.MessageUnboxed {
+		payload, _ := json.Marshal(PollResponse{Option: opt})
+		b, _ := FormatStructured(StructuredMessage{Schema: "poll-response/v1", ReplyTo: 1, Payload: payload})
+		return structuredTextMsg(t, id, sender, b)
+	}
+	var poll Poll
+	require.NoError(t, json.Unmarshal(parsed.Payload, &poll))
+	tally := PollTally(poll, []chat1.MessageUnboxed{
+		vote(2, "ayesha", 0),
+		vote(3, "bruno", 1),
+		vote(4, "ayesha", 1), // changed her mind
+		vote(5, "mallory", 9),
+		structuredTextMsg(t, 6, "carol", "unrelated chatter"),
+	}, 1)
+	require.Equal(t, map[string]int{"ramen": 2}, tally)
+}
@@ -0,0 +1,158 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+)
+
+// An optional translation hook for chat. Users configure a provider —
+// a local executable or an HTTP endpoint — and translate individual
+// messages on demand; results are cached only on this device, so no
+// plaintext leaves the machine unless the user picked a remote
+// provider themselves.
+//
+//   "chat": { "translator": { "exec": "/usr/local/bin/translate" } }
+//   "chat": { "translator": { "url": "http://127.0.0.1:8111/translate" } }
+//
+// Exec providers get JSON {text, target_lang} on stdin and print the
+// translation; HTTP providers receive the same JSON POSTed and answer
+// with the translated text.
+
+type translatorConfig struct {
+	Exec string `json:"exec"`
+	URL  string `json:"url"`
+}
+
+const translateTimeout = 20 * time.Second
+
+func loadTranslatorConfig(g *globals.Context) (cfg translatorConfig, ok bool) {
+	raw, err := g.Env.GetConfig().GetInterfaceAtPath("chat.translator")
+	if err != nil {
+		return cfg, false
+	}
+	blob, err := json.Marshal(raw)
+	if err != nil {
+		return cfg, false
+	}
+	if err := json.Unmarshal(blob, &cfg); err != nil {
+		return cfg, false
+	}
+	return cfg, cfg.Exec != "" || cfg.URL != ""
+}
+
+func translationDbKey(convID chat1.ConversationID, msgID chat1.MessageID, lang string) libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBChatTranslation,
+		Key: fmt.Sprintf("%s:%d:%s", convID, msgID, lang),
+	}
+}
+
+// runTranslator invokes the configured provider on one text.
+func runTranslator(ctx context.Context, cfg translatorConfig, text, targetLang string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, translateTimeout)
+	defer cancel()
+
+	payload, err := json.Marshal(map[string]string{"text": text, "target_lang": targetLang})
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case cfg.Exec != "":
+		cmd := exec.CommandContext(ctx, cfg.Exec)
+		cmd.Stdin = bytes.NewReader(payload)
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("translator failed: %s", err)
+		}
+		return strings.TrimRight(string(out), "\n"), nil
+	case cfg.URL != "":
+		req, err := http.NewRequest("POST", cfg.URL, bytes.NewReader(payload))
+		if err != nil {
+			return "", err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("translator unreachable: %s", err)
+		}
+		defer resp.Body.Close()
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(resp.Body); err != nil {
+			return "", err
+		}
+		if resp.StatusCode >= 300 {
+			return "", fmt.Errorf("translator returned %s", resp.Status)
+		}
+		return strings.TrimRight(buf.String(), "\n"), nil
+	}
+	return "", fmt.Errorf("no translation provider configured (chat.translator)")
+}
+
+// TranslateMessage fetches a message, runs it through the configured
+// provider, and caches the translation locally.
+func TranslateMessage(ctx context.Context, g *globals.Context, uid gregor1.UID,
+	convID chat1.ConversationID, msgID chat1.MessageID, targetLang string) (string, error) {
+	cfg, ok := loadTranslatorConfig(g)
+	if !ok {
+		return "", fmt.Errorf("no translation provider configured (chat.translator)")
+	}
+	if targetLang == "" {
+		targetLang = "en"
+	}
+
+	// Serve from the local cache when we've translated this one before.
+	cacheKey := translationDbKey(convID, msgID, targetLang)
+	var cached string
+	if found, _ := g.LocalChatDb.GetInto(&cached, cacheKey); found {
+		return cached, nil
+	}
+
+	iboxRes, err := g.InboxSource.Read(ctx, uid, nil, true, &chat1.GetInboxLocalQuery{
+		ConvIDs: []chat1.ConversationID{convID},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(iboxRes.Convs) != 1 {
+		return "", fmt.Errorf("conversation not found")
+	}
+	conv := iboxRes.Convs[0]
+	unboxInfo := newBasicUnboxConversationInfo(convID, conv.Info.MembersType, nil, conv.Info.Visibility)
+	msgs, err := g.ConvSource.GetMessages(ctx, unboxInfo, uid, []chat1.MessageID{msgID}, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(msgs) != 1 || !msgs[0].IsValid() {
+		return "", fmt.Errorf("message %d not found", msgID)
+	}
+	body := msgs[0].Valid().MessageBody
+	typ, err := body.MessageType()
+	if err != nil || typ != chat1.MessageType_TEXT {
+		return "", fmt.Errorf("only text messages can be translated")
+	}
+
+	translated, err := runTranslator(ctx, cfg, body.Text().Body, targetLang)
+	if err != nil {
+		return "", err
+	}
+	if err := g.LocalChatDb.PutObj(cacheKey, nil, translated); err != nil {
+		return "", err
+	}
+	return translated, nil
+}
@@ -0,0 +1,31 @@
+package chat
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTranslatorExec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "translator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// A "translator" that shouts: proves the payload reaches the
+	// provider and its stdout comes back verbatim.
+	script := filepath.Join(dir, "shout.sh")
+	require.NoError(t, ioutil.WriteFile(script,
+		[]byte("#!/bin/sh\ntr '[:lower:]' '[:upper:]'\n"), 0700))
+
+	out, err := runTranslator(context.Background(), translatorConfig{Exec: script}, "hola mundo", "en")
+	require.NoError(t, err)
+	require.Contains(t, out, "HOLA MUNDO")
+
+	// No provider configured is a clean error.
+	_, err = runTranslator(context.Background(), translatorConfig{}, "x", "en")
+	require.Error(t, err)
+}
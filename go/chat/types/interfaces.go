@@ -102,6 +102,14 @@ type MessageDeliverer interface {
 	NextFailure() (chan []chat1.OutboxRecord, func())
 }
 
+type Indexer interface {
+	Add(ctx context.Context, convID chat1.ConversationID, uid gregor1.UID, msgs []chat1.MessageUnboxed) error
+	RemoveMsgs(ctx context.Context, convID chat1.ConversationID, uid gregor1.UID, msgIDs []chat1.MessageID) error
+	RemoveConvUpto(ctx context.Context, convID chat1.ConversationID, uid gregor1.UID, upto chat1.MessageID) error
+	SweepConvs(ctx context.Context, uid gregor1.UID, keep map[string]bool) error
+	Search(ctx context.Context, uid gregor1.UID, query string) (map[string][]chat1.MessageID, error)
+}
+
 type Searcher interface {
 	SearchRegexp(ctx context.Context, uiCh chan chat1.ChatSearchHit, conversationID chat1.ConversationID, re *regexp.Regexp, sentBy string,
 		maxHits, maxMessages, beforeContext, afterContext int) (hits []chat1.ChatSearchHit, err error)
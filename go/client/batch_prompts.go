@@ -0,0 +1,195 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// Batch mode (--batch, with answers in --prompt-file) makes every
+// interactive prompt deterministic for scripts: a prompt is answered
+// from the file by its stable name, or the command fails with exit
+// code 30 after printing a one-line JSON description of the prompt it
+// needed, so automation can supply the answer and retry.
+//
+// The prompt file is a JSON object mapping prompt names to answers:
+//
+//   {"login-username": "alice", "device-revoke": true}
+//
+// String prompts (including passphrases) take strings; confirmations
+// take booleans.
+
+// BatchPromptExitCode distinguishes "a prompt went unanswered" from
+// ordinary failures (2).
+const BatchPromptExitCode = 30
+
+// promptDescriptorNames gives each prompt a stable, script-facing
+// name. Keep in sync with the descriptor list in prompts.go.
+var promptDescriptorNames = map[libkb.PromptDescriptor]string{
+	PromptDescriptorGeneric:                  "generic",
+	PromptDescriptorRevokePaperKeys:          "revoke-paper-keys",
+	PromptDescriptorReregister:               "reregister",
+	PromptDescriptorInviteOK:                 "invite-ok",
+	PromptDescriptorPGPGenPushSecret:         "pgp-gen-push-secret",
+	PromptDescriptorPGPGenEncryptSecret:      "pgp-gen-encrypt-secret",
+	PromptDescriptorGPGOKToAdd:               "gpg-ok-to-add",
+	PromptDescriptorGPGConfirmDuplicateKey:   "gpg-confirm-duplicate-key",
+	PromptDescriptorTrackAction:              "track-action",
+	PromptDescriptorTrackPublic:              "track-public",
+	PromptDescriptorProvePreWarning:          "prove-pre-warning",
+	PromptDescriptorProveOKToCheck:           "prove-ok-to-check",
+	PromptDescriptorProveOverwriteOK:         "prove-overwrite-ok",
+	PromptDescriptorGPGSelectKey:             "gpg-select-key",
+	PromptDescriptorLoginUsername:            "login-username",
+	PromptDescriptorLoginWritePaper:          "login-write-paper",
+	PromptDescriptorLoginWallet:              "login-wallet",
+	PromptDescriptorSignupUsername:           "signup-username",
+	PromptDescriptorSignupEmail:              "signup-email",
+	PromptDescriptorSignupReenterPassphrase:  "signup-reenter-passphrase",
+	PromptDescriptorSignupDevice:             "signup-device",
+	PromptDescriptorSignupCode:               "signup-code",
+	PromptDescriptorChooseProvisioningMethod: "choose-provisioning-method",
+	PromptDescriptorChooseGPGMethod:          "choose-gpg-method",
+	PromptDescriptorChooseDevice:             "choose-device",
+	PromptDescriptorChooseDeviceType:         "choose-device-type",
+	PromptDescriptorProvisionPhrase:          "provision-phrase",
+	PromptDescriptorProvisionDeviceName:      "provision-device-name",
+	PromptDescriptorProvisionSwitchToGPGSign: "provision-switch-to-gpg-sign",
+	PromptDescriptorExportSecretKeyFromGPG:   "export-secret-key-from-gpg",
+	PromptDescriptorDeprovisionWhichUser:     "deprovision-which-user",
+	PromptDescriptorDecryptInteractive:       "decrypt-interactive",
+	PromptDescriptorTrackExpireLocal:         "track-expire-local",
+	PromptDescriptorChooseConversation:       "choose-conversation",
+	PromptDescriptorEnterChatTLFName:         "enter-chat-tlf-name",
+	PromptDescriptorEnterChatMessage:         "enter-chat-message",
+	PromptDescriptorDeviceRevoke:             "device-revoke",
+	PromptDescriptorFSOverwrite:              "fs-overwrite",
+	PromptDescriptorRemoveMember:             "remove-member",
+	PromptDescriptorDeleteRootTeam:           "delete-root-team",
+	PromptDescriptorDeleteSubteam:            "delete-subteam",
+	PromptDescriptorChatDeleteHistory:        "chat-delete-history",
+	PromptDescriptorChatSetRetention:         "chat-set-retention",
+	PromptDescriptorImportStellarSecretKey:   "import-stellar-secret-key",
+	PromptDescriptorConfirmStellarImport:     "confirm-stellar-import",
+	PromptDescriptorChatSetConvMinWriterRole: "chat-set-conv-min-writer-role",
+	PromptDescriptorChangeLockdownMode:       "change-lockdown-mode",
+}
+
+func promptName(pd libkb.PromptDescriptor) string {
+	if name, found := promptDescriptorNames[pd]; found {
+		return name
+	}
+	return fmt.Sprintf("prompt-%d", int(pd))
+}
+
+type batchPrompter struct {
+	sync.Mutex
+	loaded  bool
+	loadErr error
+	answers map[string]interface{}
+}
+
+func (b *batchPrompter) load(g *libkb.GlobalContext) (map[string]interface{}, error) {
+	b.Lock()
+	defer b.Unlock()
+	if b.loaded {
+		return b.answers, b.loadErr
+	}
+	b.loaded = true
+	path := g.Env.GetPromptFile()
+	if path == "" {
+		b.answers = make(map[string]interface{})
+		return b.answers, nil
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		b.loadErr = fmt.Errorf("cannot read prompt file: %s", err)
+		return nil, b.loadErr
+	}
+	if err := json.Unmarshal(raw, &b.answers); err != nil {
+		b.loadErr = fmt.Errorf("bad prompt file %s: %s", path, err)
+		return nil, b.loadErr
+	}
+	return b.answers, nil
+}
+
+// unansweredPrompt reports the missing prompt as one JSON line on
+// stderr and returns the error that carries the distinct exit code.
+func (b *batchPrompter) unansweredPrompt(g *libkb.GlobalContext, name, text, typ string) error {
+	desc := struct {
+		Error  string `json:"error"`
+		Prompt string `json:"prompt"`
+		Type   string `json:"type"`
+		Text   string `json:"text"`
+	}{Error: "unanswered_prompt", Prompt: name, Type: typ, Text: text}
+	out, _ := json.Marshal(desc)
+	fmt.Fprintf(os.Stderr, "%s\n", out)
+	g.ExitCode = BatchPromptExitCode
+	return fmt.Errorf("batch mode: prompt %q has no answer in the prompt file", name)
+}
+
+// answerString resolves a string-valued prompt in batch mode.
+// Returns handled=false when batch mode is off.
+func (b *batchPrompter) answerString(g *libkb.GlobalContext, pd libkb.PromptDescriptor, text, typ string) (res string, handled bool, err error) {
+	if !g.Env.GetBatchMode() {
+		return "", false, nil
+	}
+	answers, err := b.load(g)
+	if err != nil {
+		return "", true, err
+	}
+	name := promptName(pd)
+	if v, found := answers[name]; found {
+		if s, ok := v.(string); ok {
+			return s, true, nil
+		}
+		return "", true, fmt.Errorf("batch mode: answer for %q must be a string", name)
+	}
+	return "", true, b.unansweredPrompt(g, name, text, typ)
+}
+
+// answerPassphrase resolves the secret-entry path (login and key
+// unlock), which does not carry a PromptDescriptor; the prompt file
+// answers it under the fixed name "passphrase".
+func (b *batchPrompter) answerPassphrase(g *libkb.GlobalContext, text string) (res string, handled bool, err error) {
+	if !g.Env.GetBatchMode() {
+		return "", false, nil
+	}
+	answers, err := b.load(g)
+	if err != nil {
+		return "", true, err
+	}
+	if v, found := answers["passphrase"]; found {
+		if s, ok := v.(string); ok {
+			return s, true, nil
+		}
+		return "", true, fmt.Errorf("batch mode: answer for \"passphrase\" must be a string")
+	}
+	return "", true, b.unansweredPrompt(g, "passphrase", text, "password")
+}
+
+// answerBool resolves a yes/no prompt in batch mode.
+func (b *batchPrompter) answerBool(g *libkb.GlobalContext, pd libkb.PromptDescriptor, text string) (res bool, handled bool, err error) {
+	if !g.Env.GetBatchMode() {
+		return false, false, nil
+	}
+	answers, err := b.load(g)
+	if err != nil {
+		return false, true, err
+	}
+	name := promptName(pd)
+	if v, found := answers[name]; found {
+		if yes, ok := v.(bool); ok {
+			return yes, true, nil
+		}
+		return false, true, fmt.Errorf("batch mode: answer for %q must be a boolean", name)
+	}
+	return false, true, b.unansweredPrompt(g, name, text, "bool")
+}
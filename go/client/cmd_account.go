@@ -13,6 +13,8 @@ func NewCmdAccount(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comma
 	subcommands := []cli.Command{
 		NewCmdAccountDelete(cl, g),
 		NewCmdAccountLockdown(cl, g),
+		NewCmdAccountListProvisioned(cl, g),
+		NewCmdAccountSwitch(cl, g),
 	}
 	subcommands = append(subcommands, getBuildSpecificAccountCommands(cl, g)...)
 	return cli.Command{
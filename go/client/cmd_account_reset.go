@@ -17,6 +17,7 @@ import (
 
 type CmdAccountReset struct {
 	libkb.Contextified
+	dryRun bool
 }
 
 func NewCmdAccountReset(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
@@ -27,6 +28,12 @@ func NewCmdAccountReset(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.
 			cmd := NewCmdAccountResetRunner(g)
 			cl.ChooseCommand(cmd, "reset", c)
 		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be lost by resetting, without resetting.",
+			},
+		},
 	}
 }
 
@@ -38,6 +45,7 @@ func (c *CmdAccountReset) ParseArgv(ctx *cli.Context) error {
 	if len(ctx.Args()) != 0 {
 		return errors.New("reset takes no arguments")
 	}
+	c.dryRun = ctx.Bool("dry-run")
 	return nil
 }
 
@@ -52,9 +60,39 @@ func (c *CmdAccountReset) Run() error {
 	if err != nil {
 		return err
 	}
+
+	if c.dryRun {
+		return c.runPreview(cli)
+	}
+
 	return cli.ResetAccount(context.Background(), keybase1.ResetAccountArg{})
 }
 
+// runPreview reports what the reset would cost, without resetting.
+func (c *CmdAccountReset) runPreview(cli keybase1.AccountClient) error {
+	preview, err := cli.PreviewReset(context.Background(), 0)
+	if err != nil {
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+	ui.Printf("Resetting your account would:\n")
+	if len(preview.TeamNames) > 0 {
+		ui.Printf("  - Lapse your membership in %d team(s):\n", len(preview.TeamNames))
+		for _, name := range preview.TeamNames {
+			ui.Printf("      %s\n", name)
+		}
+	}
+	ui.Printf("  - Break tracking for %d follower(s)\n", preview.FollowerCount)
+	if preview.GitRepoCount > 0 {
+		ui.Printf("  - Make %d encrypted git repo(s) unreadable\n", preview.GitRepoCount)
+	}
+	if preview.HasStellarWallet {
+		ui.Printf("  - Affect your stellar wallet; move any funds first\n")
+	}
+	ui.Printf("No changes were made.\n")
+	return nil
+}
+
 func (c *CmdAccountReset) GetUsage() libkb.Usage {
 	return libkb.Usage{
 		API:       true,
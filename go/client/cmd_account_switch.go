@@ -0,0 +1,145 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdAccountSwitch makes another provisioned account active without a
+// passphrase, using the stored device secret.
+type CmdAccountSwitch struct {
+	libkb.Contextified
+	username string
+}
+
+func NewCmdAccountSwitch(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "switch",
+		ArgumentHelp: "<username>",
+		Usage:        "Switch to another account provisioned on this device",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdAccountSwitch{Contextified: libkb.NewContextified(g)}, "switch", c)
+		},
+	}
+}
+
+func (c *CmdAccountSwitch) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("switch requires a username argument")
+	}
+	c.username = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdAccountSwitch) Run() error {
+	cli, err := GetAccountClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err := cli.SwitchAccount(context.Background(), keybase1.SwitchAccountArg{
+		Username: c.username,
+	}); err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Now logged in as %s.\n", c.username)
+	return nil
+}
+
+func (c *CmdAccountSwitch) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+// CmdAccountListProvisioned lists the accounts switchable on this
+// device.
+type CmdAccountListProvisioned struct {
+	libkb.Contextified
+}
+
+func NewCmdAccountListProvisioned(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "list-provisioned",
+		Usage: "List accounts provisioned on this device",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdAccountListProvisioned{Contextified: libkb.NewContextified(g)}, "list-provisioned", c)
+		},
+	}
+}
+
+func (c *CmdAccountListProvisioned) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("list-provisioned takes no arguments")
+	}
+	return nil
+}
+
+func (c *CmdAccountListProvisioned) Run() error {
+	cli, err := GetAccountClient(c.G())
+	if err != nil {
+		return err
+	}
+	users, err := cli.ListProvisionedUsers(context.Background(), 0)
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(users) == 0 {
+		dui.Printf("No accounts with stored secrets on this device.\n")
+		return nil
+	}
+	for _, u := range users {
+		dui.Printf("%s\n", u)
+	}
+	return nil
+}
+
+func (c *CmdAccountListProvisioned) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+// MaybeSwitchAsUser honors the global --as flag: it switches the
+// active account before the command's own RPCs run, and returns a
+// restore func that switches back afterwards so --as stays
+// per-command.
+func MaybeSwitchAsUser(g *libkb.GlobalContext) (restore func(), err error) {
+	noop := func() {}
+	as := g.Env.GetAsUser()
+	if as == "" {
+		return noop, nil
+	}
+	cli, err := GetAccountClient(g)
+	if err != nil {
+		return noop, err
+	}
+	cfgCli, err := GetConfigClient(g)
+	if err != nil {
+		return noop, err
+	}
+	status, err := cfgCli.GetCurrentStatus(context.Background(), 0)
+	if err != nil {
+		return noop, err
+	}
+	prev := ""
+	if status.LoggedIn && status.User != nil {
+		prev = status.User.Username
+	}
+	if err := cli.SwitchAccount(context.Background(), keybase1.SwitchAccountArg{Username: as}); err != nil {
+		return noop, err
+	}
+	if prev == "" || libkb.NewNormalizedUsername(prev).Eq(libkb.NewNormalizedUsername(as)) {
+		return noop, nil
+	}
+	return func() {
+		if err := cli.SwitchAccount(context.Background(), keybase1.SwitchAccountArg{Username: prev}); err != nil {
+			g.Log.Warning("--as: could not switch back to %s: %s", prev, err)
+		}
+	}, nil
+}
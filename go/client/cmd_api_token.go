@@ -0,0 +1,186 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// NewCmdAPIToken manages capability tokens that sandbox local RPC
+// consumers to specific namespaces.
+func NewCmdAPIToken(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "api-token",
+		Usage: "Manage capability tokens for local API consumers",
+		Subcommands: []cli.Command{
+			newCmdAPITokenCreate(cl, g),
+			newCmdAPITokenList(cl, g),
+			newCmdAPITokenRevoke(cl, g),
+		},
+	}
+}
+
+type cmdAPITokenCreate struct {
+	libkb.Contextified
+	label string
+	allow []string
+}
+
+func newCmdAPITokenCreate(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	cmd := &cmdAPITokenCreate{Contextified: libkb.NewContextified(g)}
+	return cli.Command{
+		Name:         "create",
+		ArgumentHelp: "<label>",
+		Usage:        "Create a capability token restricted to specific RPC namespaces",
+		Description:  "A process presenting this token over the local socket is limited\nto the allowed namespaces, e.g. --allow chat.send,fs.read or raw\nRPC prefixes like chat.1.local.",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(cmd, "create", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "allow",
+				Usage: "Comma-separated capabilities or RPC method prefixes [required].",
+			},
+		},
+	}
+}
+
+func (c *cmdAPITokenCreate) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("create requires a label argument")
+	}
+	c.label = ctx.Args()[0]
+	allow := ctx.String("allow")
+	if allow == "" {
+		return errors.New("create requires --allow")
+	}
+	for _, a := range strings.Split(allow, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			c.allow = append(c.allow, a)
+		}
+	}
+	if len(c.allow) == 0 {
+		return errors.New("--allow needs at least one entry")
+	}
+	return nil
+}
+
+func (c *cmdAPITokenCreate) Run() error {
+	cli, err := GetApiTokenClient(c.G())
+	if err != nil {
+		return err
+	}
+	token, err := cli.ApiTokenCreate(context.Background(), keybase1.ApiTokenCreateArg{
+		Label: c.label,
+		Allow: c.allow,
+	})
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("%s\n", token)
+	dui.PrintfStderr("Save this token now; it cannot be shown again.\n")
+	return nil
+}
+
+func (c *cmdAPITokenCreate) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+type cmdAPITokenList struct {
+	libkb.Contextified
+}
+
+func newCmdAPITokenList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	cmd := &cmdAPITokenList{Contextified: libkb.NewContextified(g)}
+	return cli.Command{
+		Name:  "list",
+		Usage: "List capability tokens",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(cmd, "list", c)
+		},
+	}
+}
+
+func (c *cmdAPITokenList) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("list takes no arguments")
+	}
+	return nil
+}
+
+func (c *cmdAPITokenList) Run() error {
+	cli, err := GetApiTokenClient(c.G())
+	if err != nil {
+		return err
+	}
+	tokens, err := cli.ApiTokenList(context.Background())
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(tokens) == 0 {
+		dui.Printf("No api tokens.\n")
+		return nil
+	}
+	for _, info := range tokens {
+		dui.Printf("%s\t%s\t%s\n", info.Label,
+			keybase1.FromTime(info.CreatedAt).Format("2006-01-02"),
+			strings.Join(info.Allow, ","))
+	}
+	return nil
+}
+
+func (c *cmdAPITokenList) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+type cmdAPITokenRevoke struct {
+	libkb.Contextified
+	label string
+}
+
+func newCmdAPITokenRevoke(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	cmd := &cmdAPITokenRevoke{Contextified: libkb.NewContextified(g)}
+	return cli.Command{
+		Name:         "revoke",
+		ArgumentHelp: "<label>",
+		Usage:        "Revoke a capability token",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(cmd, "revoke", c)
+		},
+	}
+}
+
+func (c *cmdAPITokenRevoke) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("revoke requires a label argument")
+	}
+	c.label = ctx.Args()[0]
+	return nil
+}
+
+func (c *cmdAPITokenRevoke) Run() error {
+	cli, err := GetApiTokenClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err := cli.ApiTokenRevoke(context.Background(), c.label); err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Revoked api token %q.\n", c.label)
+	return nil
+}
+
+func (c *cmdAPITokenRevoke) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+)
+
+// NewCmdBot holds automation-account tooling; `bot provision` mints a
+// capability-scoped key whose delegation links carry the scope.
+func NewCmdBot(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "bot",
+		Usage: "Provision capability-scoped keys for automation",
+		Subcommands: []cli.Command{
+			newCmdBotProvision(cl, g),
+		},
+	}
+}
+
+type cmdBotProvision struct {
+	libkb.Contextified
+	capabilities []string
+}
+
+func newCmdBotProvision(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "provision",
+		Usage: "Mint a scoped bot key",
+		Description: `Generates a paper-style key pair whose delegation links carry the
+given capabilities. The printed phrase is the bot's whole credential;
+a key scoped this way cannot delegate further keys.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdBotProvision{Contextified: libkb.NewContextified(g)}, "provision", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "allow",
+				Usage: "Comma-separated capabilities, e.g. chat.send,fs.read [required].",
+			},
+		},
+	}
+}
+
+func (c *cmdBotProvision) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("provision takes no arguments")
+	}
+	for _, capability := range strings.Split(ctx.String("allow"), ",") {
+		if capability = strings.TrimSpace(capability); capability != "" {
+			c.capabilities = append(c.capabilities, capability)
+		}
+	}
+	if len(c.capabilities) == 0 {
+		return errors.New("provision requires --allow with at least one capability")
+	}
+	return nil
+}
+
+func (c *cmdBotProvision) Run() error {
+	cli, err := GetLoginClient(c.G())
+	if err != nil {
+		return err
+	}
+	protocols := []rpc.Protocol{
+		NewSecretUIProtocol(c.G()),
+	}
+	if err := RegisterProtocolsWithContext(protocols, c.G()); err != nil {
+		return err
+	}
+	phrase, err := cli.BotProvision(context.Background(), keybase1.BotProvisionArg{
+		Capabilities: c.capabilities,
+	})
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("%s\n", phrase)
+	dui.PrintfStderr("Bot key minted with capabilities: %s\nThe phrase above is the bot's whole credential.\n", strings.Join(c.capabilities, ", "))
+	return nil
+}
+
+func (c *cmdBotProvision) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
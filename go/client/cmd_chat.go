@@ -25,6 +25,16 @@ func NewCmdChat(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 		newCmdChatListMembers(cl, g),
 		newCmdChatListUnread(cl, g),
 		newCmdChatMute(cl, g),
+		newCmdChatPin(cl, g),
+		newCmdChatUnpin(cl, g),
+		newCmdChatListPins(cl, g),
+		newCmdChatPostPoll(cl, g),
+		newCmdChatPollTally(cl, g),
+		newCmdChatPollVote(cl, g),
+		newCmdChatSeenBy(cl, g),
+		newCmdChatTranslate(cl, g),
+		newCmdChatExport(cl, g),
+		newCmdChatImport(cl, g),
 		newCmdChatRead(cl, g),
 		newCmdChatReAddMember(cl, g),
 		newCmdChatReport(cl, g),
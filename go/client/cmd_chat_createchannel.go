@@ -17,6 +17,7 @@ type CmdChatCreateChannel struct {
 	teamName    string
 	channelName string
 	topicType   chat1.TopicType
+	broadcast   bool
 }
 
 func NewCmdChatCreateChannelRunner(g *libkb.GlobalContext) *CmdChatCreateChannel {
@@ -31,7 +32,11 @@ func newCmdChatCreateChannel(cl *libcmdline.CommandLine, g *libkb.GlobalContext)
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(NewCmdChatCreateChannelRunner(g), "create-channel", c)
 		},
-		Flags: mustGetChatFlags("topic-type"),
+		Flags: append(mustGetChatFlags("topic-type"),
+			cli.BoolFlag{
+				Name:  "broadcast",
+				Usage: "Make the channel read-only for non-admins: only admins and above may post.",
+			}),
 	}
 }
 
@@ -50,10 +55,27 @@ func (c *CmdChatCreateChannel) Run() error {
 		Visibility:  keybase1.TLFVisibility_PRIVATE,
 	}
 
-	_, err = resolver.create(context.Background(), req)
+	conv, err := resolver.create(context.Background(), req)
 
 	dui := c.G().UI.GetDumbOutputUI()
 	if err == nil {
+		if c.broadcast {
+			// Broadcast mode is the min-writer-role machinery with the
+			// bar set at admin; compose is refused client-side for
+			// everyone below it and the role is enforced in metadata.
+			lcli, err := GetChatLocalClient(c.G())
+			if err != nil {
+				return err
+			}
+			if err := lcli.SetConvMinWriterRoleLocal(context.Background(), chat1.SetConvMinWriterRoleLocalArg{
+				ConvID: conv.GetConvID(),
+				Role:   keybase1.TeamRole_ADMIN,
+			}); err != nil {
+				return err
+			}
+			dui.Printf("Created broadcast channel: only admins may post.\n")
+			return nil
+		}
 		dui.Printf("Success!\n")
 		return nil
 	}
@@ -78,6 +100,8 @@ func (c *CmdChatCreateChannel) ParseArgv(ctx *cli.Context) error {
 	c.teamName = ctx.Args().Get(0)
 	c.channelName = ctx.Args().Get(1)
 
+	c.broadcast = ctx.Bool("broadcast")
+
 	var err error
 	c.topicType, err = parseConversationTopicType(ctx)
 	if err != nil {
@@ -0,0 +1,292 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdChatImport posts the history from a Slack export zip (or a Matrix
+// room JSON dump) into a conversation as clearly-marked imported
+// messages. Sends are rate limited, and progress is checkpointed next
+// to the export so a large import resumes where it left off.
+type CmdChatImport struct {
+	libkb.Contextified
+	fetcher  chatCLIConversationFetcher
+	path     string
+	format   string
+	interval time.Duration
+}
+
+func newCmdChatImport(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "import",
+		Usage:        "Import message history from a Slack or Matrix export",
+		ArgumentHelp: "<export file> <conversation>",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdChatImport{Contextified: libkb.NewContextified(g)}, "import", c)
+		},
+		Flags: append(getConversationResolverFlags(),
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Export format: slack (zip, default) or matrix (json).",
+			},
+			cli.DurationFlag{
+				Name:  "interval",
+				Usage: "Delay between posts (rate limiting).",
+				Value: 500 * time.Millisecond,
+			}),
+	}
+}
+
+// importedMessage is the normalized form of one history entry.
+type importedMessage struct {
+	Sender string
+	SentAt time.Time
+	Text   string
+}
+
+func (c *CmdChatImport) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("import takes two arguments: the export file and the conversation")
+	}
+	c.path = ctx.Args()[0]
+	c.format = ctx.String("format")
+	if c.format == "" {
+		c.format = "slack"
+	}
+	if c.format != "slack" && c.format != "matrix" {
+		return fmt.Errorf("unsupported format %q: use slack or matrix", c.format)
+	}
+	c.interval = ctx.Duration("interval")
+
+	var err error
+	c.fetcher.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args()[1])
+	return err
+}
+
+// parseSlackExport reads every channel file in a Slack export zip.
+func parseSlackExport(path string) (msgs []importedMessage, err error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	type slackMsg struct {
+		User string `json:"user"`
+		Text string `json:"text"`
+		Ts   string `json:"ts"`
+	}
+
+	// users.json maps member IDs (U02ABC...) to usernames.
+	usernames := make(map[string]string)
+	for _, f := range zr.File {
+		if f.Name != "users.json" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			continue
+		}
+		blob, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			continue
+		}
+		var users []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(blob, &users); err == nil {
+			for _, u := range users {
+				usernames[u.ID] = u.Name
+			}
+		}
+	}
+
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".json") || strings.HasPrefix(f.Name, "__") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		blob, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		var entries []slackMsg
+		if err := json.Unmarshal(blob, &entries); err != nil {
+			continue // metadata files (users.json etc.) aren't message arrays
+		}
+		for _, e := range entries {
+			if e.Text == "" {
+				continue
+			}
+			ts, err := strconv.ParseFloat(e.Ts, 64)
+			if err != nil {
+				continue
+			}
+			sender := e.User
+			if name, ok := usernames[sender]; ok {
+				sender = name
+			}
+			msgs = append(msgs, importedMessage{
+				Sender: sender,
+				SentAt: time.Unix(int64(ts), 0),
+				Text:   e.Text,
+			})
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].SentAt.Before(msgs[j].SentAt) })
+	return msgs, nil
+}
+
+// parseMatrixExport reads a Matrix room JSON dump ({"messages": [...]}).
+func parseMatrixExport(path string) (msgs []importedMessage, err error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var dump struct {
+		Messages []struct {
+			Sender  string `json:"sender"`
+			Origin  int64  `json:"origin_server_ts"`
+			Content struct {
+				Body string `json:"body"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(blob, &dump); err != nil {
+		return nil, err
+	}
+	for _, e := range dump.Messages {
+		if e.Content.Body == "" {
+			continue
+		}
+		msgs = append(msgs, importedMessage{
+			Sender: e.Sender,
+			SentAt: time.Unix(e.Origin/1000, 0),
+			Text:   e.Content.Body,
+		})
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].SentAt.Before(msgs[j].SentAt) })
+	return msgs, nil
+}
+
+func (c *CmdChatImport) progressPath() string {
+	return c.path + ".import-progress"
+}
+
+func (c *CmdChatImport) loadProgress() int {
+	blob, err := ioutil.ReadFile(c.progressPath())
+	if err != nil {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(blob)))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+func (c *CmdChatImport) saveProgress(n int) {
+	_ = ioutil.WriteFile(c.progressPath(), []byte(strconv.Itoa(n)), os.FileMode(0600))
+}
+
+func (c *CmdChatImport) Run() error {
+	var msgs []importedMessage
+	var err error
+	switch c.format {
+	case "slack":
+		msgs, err = parseSlackExport(c.path)
+	case "matrix":
+		msgs, err = parseMatrixExport(c.path)
+	}
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return fmt.Errorf("no importable messages found in %s", c.path)
+	}
+
+	if c.fetcher.resolvingRequest.TlfName != "" {
+		if err := annotateResolvingRequest(c.G(), &c.fetcher.resolvingRequest); err != nil {
+			return err
+		}
+	}
+	resolver, err := newChatConversationResolver(c.G())
+	if err != nil {
+		return err
+	}
+	conversation, _, err := resolver.Resolve(context.TODO(), c.fetcher.resolvingRequest,
+		chatConversationResolvingBehavior{
+			CreateIfNotExists: false,
+			IdentifyBehavior:  keybase1.TLFIdentifyBehavior_CHAT_CLI,
+		})
+	if err != nil {
+		return err
+	}
+	if conversation == nil {
+		return fmt.Errorf("conversation not found")
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	start := c.loadProgress()
+	if start > 0 {
+		ui.Printf("Resuming import at message %d of %d.\n", start+1, len(msgs))
+	}
+
+	for i := start; i < len(msgs); i++ {
+		m := msgs[i]
+		// Clearly marked as imported, with the original timestamp.
+		body := fmt.Sprintf("[imported] %s (%s): %s",
+			m.Sender, m.SentAt.UTC().Format("2006-01-02 15:04"), m.Text)
+		if _, err := resolver.ChatClient.PostTextNonblock(context.TODO(), chat1.PostTextNonblockArg{
+			ConversationID:   conversation.Info.Id,
+			TlfName:          conversation.Info.TlfName,
+			TlfPublic:        conversation.Info.Visibility == keybase1.TLFVisibility_PUBLIC,
+			Body:             body,
+			IdentifyBehavior: keybase1.TLFIdentifyBehavior_CHAT_CLI,
+		}); err != nil {
+			c.saveProgress(i)
+			return fmt.Errorf("import interrupted at message %d/%d: %s (re-run to resume)", i+1, len(msgs), err)
+		}
+		c.saveProgress(i + 1)
+		if c.interval > 0 {
+			time.Sleep(c.interval)
+		}
+	}
+
+	_ = os.Remove(c.progressPath())
+	ui.Printf("Imported %d messages.\n", len(msgs)-start)
+	return nil
+}
+
+func (c *CmdChatImport) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
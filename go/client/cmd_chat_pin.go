@@ -0,0 +1,172 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/gregor1"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Pinning commands: `chat pin`, `chat unpin`, and `chat pins` drive
+// the per-conversation pin list (gated on the conversation's minimum
+// writer role for teams).
+
+type CmdChatPin struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	msgID            chat1.MessageID
+	unpin            bool
+}
+
+func newCmdChatPin(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "pin",
+		Usage:        "Pin a message in a conversation",
+		ArgumentHelp: "<conversation> <message id>",
+		Action: func(c *cli.Context) {
+			cmd := &CmdChatPin{Contextified: libkb.NewContextified(g)}
+			cl.ChooseCommand(cmd, "pin", c)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func newCmdChatUnpin(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "unpin",
+		Usage:        "Unpin a message in a conversation",
+		ArgumentHelp: "<conversation> <message id>",
+		Action: func(c *cli.Context) {
+			cmd := &CmdChatPin{Contextified: libkb.NewContextified(g), unpin: true}
+			cl.ChooseCommand(cmd, "unpin", c)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatPin) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("usage: keybase chat pin <conversation> <message id>")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+	if err != nil {
+		return fmt.Errorf("bad message id %q: %s", ctx.Args().Get(1), err)
+	}
+	c.msgID = chat1.MessageID(id)
+	return nil
+}
+
+func (c *CmdChatPin) resolveConvID(ctx context.Context) (chat1.ConversationID, *chatConversationResolver, error) {
+	resolver, err := newChatConversationResolver(c.G())
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.resolvingRequest.TlfName != "" {
+		if err = annotateResolvingRequest(c.G(), &c.resolvingRequest); err != nil {
+			return nil, nil, err
+		}
+	}
+	conversation, _, err := resolver.Resolve(ctx, c.resolvingRequest, chatConversationResolvingBehavior{
+		CreateIfNotExists: false,
+		Interactive:       false,
+		IdentifyBehavior:  keybase1.TLFIdentifyBehavior_CHAT_CLI,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return conversation.Info.Id, resolver, nil
+}
+
+func (c *CmdChatPin) Run() error {
+	ctx := context.TODO()
+	convID, resolver, err := c.resolveConvID(ctx)
+	if err != nil {
+		return err
+	}
+	if c.unpin {
+		if err := resolver.ChatClient.UnpinMessageLocal(ctx, chat1.UnpinMessageLocalArg{
+			ConvID: convID, MsgID: c.msgID,
+		}); err != nil {
+			return err
+		}
+		c.G().UI.GetDumbOutputUI().Printf("Unpinned message %d.\n", c.msgID)
+		return nil
+	}
+	if err := resolver.ChatClient.PinMessageLocal(ctx, chat1.PinMessageLocalArg{
+		ConvID: convID, MsgID: c.msgID,
+	}); err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Pinned message %d.\n", c.msgID)
+	return nil
+}
+
+func (c *CmdChatPin) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
+
+// CmdChatListPins lists a conversation's pins.
+type CmdChatListPins struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+}
+
+func newCmdChatListPins(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "pins",
+		Usage:        "List pinned messages in a conversation",
+		ArgumentHelp: "<conversation>",
+		Action: func(c *cli.Context) {
+			cmd := &CmdChatListPins{Contextified: libkb.NewContextified(g)}
+			cl.ChooseCommand(cmd, "pins", c)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatListPins) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("usage: keybase chat pins <conversation>")
+	}
+	c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0))
+	return err
+}
+
+func (c *CmdChatListPins) Run() error {
+	ctx := context.TODO()
+	pinCmd := &CmdChatPin{Contextified: c.Contextified, resolvingRequest: c.resolvingRequest}
+	convID, resolver, err := pinCmd.resolveConvID(ctx)
+	if err != nil {
+		return err
+	}
+	pins, err := resolver.ChatClient.ListPinsLocal(ctx, convID)
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(pins) == 0 {
+		dui.Printf("No pinned messages.\n")
+		return nil
+	}
+	for _, pin := range pins {
+		dui.Printf("%d\tpinned by %s at %s\n", pin.MsgID, pin.PinnedBy,
+			gregor1.FromTime(pin.PinnedAt).Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func (c *CmdChatListPins) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
@@ -0,0 +1,229 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/chat"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	"github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Poll commands built on the typed structured-message envelope
+// (chat.FormatStructured): `chat post-poll` posts a poll/v1 message
+// and `chat poll-tally` aggregates poll-response/v1 replies from the
+// thread.
+
+type CmdChatPostPoll struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	question         string
+	options          []string
+}
+
+func newCmdChatPostPoll(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "post-poll",
+		Usage:        "Post a structured poll to a conversation",
+		ArgumentHelp: "<conversation> <question> <option> <option> [...]",
+		Action: func(c *cli.Context) {
+			cmd := &CmdChatPostPoll{Contextified: libkb.NewContextified(g)}
+			cl.ChooseCommand(cmd, "post-poll", c)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatPostPoll) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) < 4 {
+		return fmt.Errorf("usage: keybase chat post-poll <conversation> <question> <option> <option> [...]")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	c.question = ctx.Args().Get(1)
+	c.options = ctx.Args()[2:]
+	return nil
+}
+
+func (c *CmdChatPostPoll) Run() error {
+	payload, err := json.Marshal(chat.Poll{Question: c.question, Options: c.options})
+	if err != nil {
+		return err
+	}
+	body, err := chat.FormatStructured(chat.StructuredMessage{
+		Schema:  "poll/v1",
+		Payload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	return chatSend(context.TODO(), c.G(), ChatSendArg{
+		resolvingRequest: c.resolvingRequest,
+		message:          body,
+		nonBlock:         false,
+		team:             false,
+	})
+}
+
+func (c *CmdChatPostPoll) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
+
+// CmdChatPollVote posts a poll-response/v1 reply.
+type CmdChatPollVote struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	rootID           chat1.MessageID
+	option           int
+}
+
+func newCmdChatPollVote(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "poll-vote",
+		Usage:        "Vote in a poll",
+		ArgumentHelp: "<conversation> <poll message id> <option index>",
+		Action: func(c *cli.Context) {
+			cmd := &CmdChatPollVote{Contextified: libkb.NewContextified(g)}
+			cl.ChooseCommand(cmd, "poll-vote", c)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatPollVote) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 3 {
+		return fmt.Errorf("usage: keybase chat poll-vote <conversation> <poll message id> <option index>")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+	if err != nil {
+		return fmt.Errorf("bad message id %q: %s", ctx.Args().Get(1), err)
+	}
+	c.rootID = chat1.MessageID(id)
+	if c.option, err = strconv.Atoi(ctx.Args().Get(2)); err != nil || c.option < 0 {
+		return fmt.Errorf("bad option index %q", ctx.Args().Get(2))
+	}
+	return nil
+}
+
+func (c *CmdChatPollVote) Run() error {
+	payload, err := json.Marshal(chat.PollResponse{Option: c.option})
+	if err != nil {
+		return err
+	}
+	body, err := chat.FormatStructured(chat.StructuredMessage{
+		Schema:  "poll-response/v1",
+		ReplyTo: c.rootID,
+		Payload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	return chatSend(context.TODO(), c.G(), ChatSendArg{
+		resolvingRequest: c.resolvingRequest,
+		message:          body,
+	})
+}
+
+func (c *CmdChatPollVote) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
+
+// CmdChatPollTally aggregates responses to a poll message.
+type CmdChatPollTally struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	rootID           chat1.MessageID
+}
+
+func newCmdChatPollTally(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "poll-tally",
+		Usage:        "Tally responses to a poll",
+		ArgumentHelp: "<conversation> <poll message id>",
+		Action: func(c *cli.Context) {
+			cmd := &CmdChatPollTally{Contextified: libkb.NewContextified(g)}
+			cl.ChooseCommand(cmd, "poll-tally", c)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatPollTally) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("usage: keybase chat poll-tally <conversation> <poll message id>")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+	if err != nil {
+		return fmt.Errorf("bad message id %q: %s", ctx.Args().Get(1), err)
+	}
+	c.rootID = chat1.MessageID(id)
+	return nil
+}
+
+func (c *CmdChatPollTally) Run() error {
+	ctx := context.TODO()
+	pinCmd := &CmdChatPin{Contextified: c.Contextified, resolvingRequest: c.resolvingRequest}
+	convID, resolver, err := pinCmd.resolveConvID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tv, err := resolver.ChatClient.GetThreadLocal(ctx, chat1.GetThreadLocalArg{
+		ConversationID:   convID,
+		IdentifyBehavior: keybase1.TLFIdentifyBehavior_CHAT_CLI,
+	})
+	if err != nil {
+		return err
+	}
+
+	var poll chat.Poll
+	var found bool
+	for _, msg := range tv.Thread.Messages {
+		if !msg.IsValid() || msg.GetMessageID() != c.rootID {
+			continue
+		}
+		body := msg.Valid().MessageBody
+		if typ, err := body.MessageType(); err != nil || typ != chat1.MessageType_TEXT {
+			continue
+		}
+		structured, ok := chat.ParseStructured(body.Text().Body)
+		if !ok || structured.Schema != "poll/v1" {
+			return fmt.Errorf("message %d is not a poll", c.rootID)
+		}
+		if err := json.Unmarshal(structured.Payload, &poll); err != nil {
+			return err
+		}
+		found = true
+		break
+	}
+	if !found {
+		return fmt.Errorf("poll message %d not found in thread", c.rootID)
+	}
+
+	tally := chat.PollTally(poll, tv.Thread.Messages, c.rootID)
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("%s\n", poll.Question)
+	for i, option := range poll.Options {
+		dui.Printf("  [%d] %s: %d vote(s)\n", i, option, tally[option])
+	}
+	return nil
+}
+
+func (c *CmdChatPollTally) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
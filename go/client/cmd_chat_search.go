@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/keybase/cli"
 	"github.com/keybase/client/go/chat"
@@ -30,6 +31,7 @@ type CmdChatSearch struct {
 	beforeContext    int
 	afterContext     int
 	isRegex          bool
+	localIndex       bool
 	hasTTY           bool
 }
 
@@ -57,6 +59,10 @@ func newCmdChatSearch(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Co
 				Name:  "r, regex",
 				Usage: "Make the given query a regex",
 			},
+			cli.BoolFlag{
+				Name:  "local-index",
+				Usage: "Answer from the on-device index across all conversations; no server round trip. Ignores the conversation argument.",
+			},
 			cli.IntFlag{
 				Name:  "max-hits",
 				Value: 10,
@@ -91,7 +97,34 @@ func newCmdChatSearch(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Co
 	}
 }
 
+func (c *CmdChatSearch) runLocalIndex() error {
+	resolver, err := newChatConversationResolver(c.G())
+	if err != nil {
+		return err
+	}
+	hits, err := resolver.ChatClient.SearchLocalIndex(context.TODO(), c.query)
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(hits) == 0 {
+		dui.Printf("No matches in the local index.\n")
+		return nil
+	}
+	for _, hit := range hits {
+		ids := make([]string, len(hit.MsgIDs))
+		for i, id := range hit.MsgIDs {
+			ids[i] = fmt.Sprintf("%d", id)
+		}
+		dui.Printf("conv %s: messages %s\n", hit.ConvID, strings.Join(ids, ", "))
+	}
+	return nil
+}
+
 func (c *CmdChatSearch) Run() (err error) {
+	if c.localIndex {
+		return c.runLocalIndex()
+	}
 	ui := &ChatUI{
 		Contextified: libkb.NewContextified(c.G()),
 		terminal:     c.G().UI.GetTerminalUI(),
@@ -160,6 +193,14 @@ func (c *CmdChatSearch) Run() (err error) {
 }
 
 func (c *CmdChatSearch) ParseArgv(ctx *cli.Context) (err error) {
+	if ctx.Bool("local-index") {
+		if len(ctx.Args()) != 1 {
+			return errors.New("usage: keybase chat search --local-index <query>")
+		}
+		c.localIndex = true
+		c.query = ctx.Args().Get(0)
+		return nil
+	}
 	if len(ctx.Args()) != 2 {
 		return errors.New("usage: keybase chat search <conversation> <query>")
 	}
@@ -0,0 +1,79 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+)
+
+// CmdChatSeenBy prints who has acknowledged a message.
+type CmdChatSeenBy struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	msgID            chat1.MessageID
+}
+
+func newCmdChatSeenBy(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "seen-by",
+		Usage:        "Show who has read a message (requires chat.read_receipts.enabled)",
+		ArgumentHelp: "<conversation> <message id>",
+		Action: func(c *cli.Context) {
+			cmd := &CmdChatSeenBy{Contextified: libkb.NewContextified(g)}
+			cl.ChooseCommand(cmd, "seen-by", c)
+		},
+		Flags: getConversationResolverFlags(),
+	}
+}
+
+func (c *CmdChatSeenBy) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("usage: keybase chat seen-by <conversation> <message id>")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+	if err != nil {
+		return fmt.Errorf("bad message id %q: %s", ctx.Args().Get(1), err)
+	}
+	c.msgID = chat1.MessageID(id)
+	return nil
+}
+
+func (c *CmdChatSeenBy) Run() error {
+	ctx := context.TODO()
+	pinCmd := &CmdChatPin{Contextified: c.Contextified, resolvingRequest: c.resolvingRequest}
+	convID, resolver, err := pinCmd.resolveConvID(ctx)
+	if err != nil {
+		return err
+	}
+	receipts, err := resolver.ChatClient.SeenByLocal(ctx, chat1.SeenByLocalArg{
+		ConvID: convID,
+		MsgID:  c.msgID,
+	})
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(receipts) == 0 {
+		dui.Printf("No read receipts for message %d.\n", c.msgID)
+		return nil
+	}
+	for _, r := range receipts {
+		dui.Printf("%s\n", r.Username)
+	}
+	return nil
+}
+
+func (c *CmdChatSeenBy) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
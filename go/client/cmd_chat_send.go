@@ -74,6 +74,15 @@ func newCmdChatSend(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comm
 
 func (c *CmdChatSend) Run() (err error) {
 	if c.resolvingRequest.TlfName != "" {
+		// An imported contact name ("Bob Jones") resolves to the
+		// matching Keybase username before normal TLF resolution --
+		// but never for anything that could itself be a username or
+		// assertion, so contacts cannot shadow real Keybase users.
+		if !libkb.CheckUsername.F(c.resolvingRequest.TlfName) {
+			if username := resolveContactName(c.G(), c.resolvingRequest.TlfName); username != "" {
+				c.resolvingRequest.TlfName = username
+			}
+		}
 		if err = annotateResolvingRequest(c.G(), &c.resolvingRequest); err != nil {
 			return err
 		}
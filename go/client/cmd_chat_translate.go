@@ -0,0 +1,82 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+)
+
+// CmdChatTranslate translates one message on demand through the
+// provider configured under chat.translator.
+type CmdChatTranslate struct {
+	libkb.Contextified
+	resolvingRequest chatConversationResolvingRequest
+	msgID            chat1.MessageID
+	targetLang       string
+}
+
+func newCmdChatTranslate(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "translate",
+		Usage:        "Translate a message via the configured provider",
+		ArgumentHelp: "<conversation> <message id>",
+		Action: func(c *cli.Context) {
+			cmd := &CmdChatTranslate{Contextified: libkb.NewContextified(g)}
+			cl.ChooseCommand(cmd, "translate", c)
+		},
+		Flags: append(getConversationResolverFlags(),
+			cli.StringFlag{
+				Name:  "lang",
+				Value: "en",
+				Usage: "Target language code.",
+			},
+		),
+	}
+}
+
+func (c *CmdChatTranslate) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("usage: keybase chat translate <conversation> <message id>")
+	}
+	if c.resolvingRequest, err = parseConversationResolvingRequest(ctx, ctx.Args().Get(0)); err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+	if err != nil {
+		return fmt.Errorf("bad message id %q: %s", ctx.Args().Get(1), err)
+	}
+	c.msgID = chat1.MessageID(id)
+	c.targetLang = ctx.String("lang")
+	return nil
+}
+
+func (c *CmdChatTranslate) Run() error {
+	ctx := context.TODO()
+	pinCmd := &CmdChatPin{Contextified: c.Contextified, resolvingRequest: c.resolvingRequest}
+	convID, resolver, err := pinCmd.resolveConvID(ctx)
+	if err != nil {
+		return err
+	}
+	translated, err := resolver.ChatClient.TranslateMessageLocal(ctx, chat1.TranslateMessageLocalArg{
+		ConvID:     convID,
+		MsgID:      c.msgID,
+		TargetLang: c.targetLang,
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("%s\n", translated)
+	return nil
+}
+
+func (c *CmdChatTranslate) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
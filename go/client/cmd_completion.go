@@ -0,0 +1,250 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	chat1 "github.com/keybase/client/go/protocol/chat1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// NewCmdCompletion generates shell completion scripts. The static
+// scripts defer dynamic values (usernames, teams, devices, channels)
+// to `keybase completion values <kind>`, which asks the running
+// service, so completions stay current without regenerating scripts.
+func NewCmdCompletion(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "completion",
+		ArgumentHelp: "bash|zsh|fish",
+		Usage:        "Generate shell completion scripts",
+		Description: `Install with, e.g.:
+    source <(keybase completion bash)
+    keybase completion zsh > "${fpath[1]}/_keybase"
+    keybase completion fish > ~/.config/fish/completions/keybase.fish`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCompletion{Contextified: libkb.NewContextified(g)}, "completion", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+		Subcommands: []cli.Command{
+			newCmdCompletionValues(cl, g),
+		},
+	}
+}
+
+type CmdCompletion struct {
+	libkb.Contextified
+	shell string
+}
+
+func (c *CmdCompletion) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("completion requires one argument: bash, zsh or fish")
+	}
+	c.shell = ctx.Args()[0]
+	switch c.shell {
+	case "bash", "zsh", "fish":
+		return nil
+	}
+	return fmt.Errorf("unsupported shell %q: want bash, zsh or fish", c.shell)
+}
+
+const bashCompletion = `# bash completion for keybase
+_keybase() {
+    local cur prev words
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    if [[ ${COMP_CWORD} -eq 1 ]]; then
+        COMPREPLY=( $(compgen -W "$(keybase completion values commands 2>/dev/null)" -- "$cur") )
+        return
+    fi
+    case "${COMP_WORDS[1]}" in
+        id|follow|unfollow|track|untrack)
+            COMPREPLY=( $(compgen -W "$(keybase completion values users 2>/dev/null)" -- "$cur") ) ;;
+        team)
+            COMPREPLY=( $(compgen -W "$(keybase completion values teams 2>/dev/null)" -- "$cur") ) ;;
+        device)
+            COMPREPLY=( $(compgen -W "$(keybase completion values devices 2>/dev/null)" -- "$cur") ) ;;
+        chat)
+            COMPREPLY=( $(compgen -W "$(keybase completion values channels 2>/dev/null)" -- "$cur") ) ;;
+    esac
+}
+complete -F _keybase keybase
+`
+
+const zshCompletion = `#compdef keybase
+_keybase() {
+    local -a vals
+    if (( CURRENT == 2 )); then
+        vals=(${(f)"$(keybase completion values commands 2>/dev/null)"})
+    else
+        case "$words[2]" in
+            id|follow|unfollow|track|untrack) vals=(${(f)"$(keybase completion values users 2>/dev/null)"}) ;;
+            team) vals=(${(f)"$(keybase completion values teams 2>/dev/null)"}) ;;
+            device) vals=(${(f)"$(keybase completion values devices 2>/dev/null)"}) ;;
+            chat) vals=(${(f)"$(keybase completion values channels 2>/dev/null)"}) ;;
+        esac
+    fi
+    compadd -a vals
+}
+_keybase "$@"
+`
+
+const fishCompletion = `# fish completion for keybase
+complete -c keybase -n "__fish_use_subcommand" -a "(keybase completion values commands 2>/dev/null)"
+complete -c keybase -n "__fish_seen_subcommand_from id follow unfollow track untrack" -a "(keybase completion values users 2>/dev/null)"
+complete -c keybase -n "__fish_seen_subcommand_from team" -a "(keybase completion values teams 2>/dev/null)"
+complete -c keybase -n "__fish_seen_subcommand_from device" -a "(keybase completion values devices 2>/dev/null)"
+complete -c keybase -n "__fish_seen_subcommand_from chat" -a "(keybase completion values channels 2>/dev/null)"
+`
+
+func (c *CmdCompletion) Run() error {
+	dui := c.G().UI.GetDumbOutputUI()
+	switch c.shell {
+	case "bash":
+		dui.Printf("%s", bashCompletion)
+	case "zsh":
+		dui.Printf("%s", zshCompletion)
+	case "fish":
+		dui.Printf("%s", fishCompletion)
+	}
+	return nil
+}
+
+func (c *CmdCompletion) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
+
+// cmdCompletionValues prints dynamic completion candidates, one per
+// line. It is quiet about errors by design: a logged-out service just
+// yields no candidates, never broken shell output.
+type cmdCompletionValues struct {
+	libkb.Contextified
+	kind string
+}
+
+func newCmdCompletionValues(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "values",
+		ArgumentHelp: "commands|users|teams|devices|channels",
+		Usage:        "Print dynamic completion candidates (used by the generated scripts)",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdCompletionValues{Contextified: libkb.NewContextified(g)}, "values", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetLogForward(libcmdline.LogForwardNone)
+		},
+	}
+}
+
+func (c *cmdCompletionValues) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("values requires one kind argument")
+	}
+	c.kind = ctx.Args()[0]
+	switch c.kind {
+	case "commands", "users", "teams", "devices", "channels":
+		return nil
+	}
+	return fmt.Errorf("unknown completion kind %q", c.kind)
+}
+
+func (c *cmdCompletionValues) values() (res []string, err error) {
+	switch c.kind {
+	case "commands":
+		for _, cmd := range GetCommands(libcmdline.NewCommandLine(true, nil), c.G()) {
+			res = append(res, cmd.Name)
+		}
+		return res, nil
+	case "users":
+		cli, err := GetUserClient(c.G())
+		if err != nil {
+			return nil, err
+		}
+		summaries, err := cli.ListTracking(context.Background(), keybase1.ListTrackingArg{})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range summaries {
+			res = append(res, s.Username)
+		}
+		return res, nil
+	case "teams":
+		cli, err := GetTeamsClient(c.G())
+		if err != nil {
+			return nil, err
+		}
+		list, err := cli.TeamListUnverified(context.Background(), keybase1.TeamListUnverifiedArg{})
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, t := range list.Teams {
+			if !t.IsImplicitTeam && !seen[t.FqName] {
+				seen[t.FqName] = true
+				res = append(res, t.FqName)
+			}
+		}
+		return res, nil
+	case "devices":
+		cli, err := GetDeviceClient(c.G())
+		if err != nil {
+			return nil, err
+		}
+		devs, err := cli.DeviceList(context.Background(), 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range devs {
+			res = append(res, d.Name)
+		}
+		return res, nil
+	case "channels":
+		cli, err := GetChatLocalClient(c.G())
+		if err != nil {
+			return nil, err
+		}
+		inbox, err := cli.GetInboxSummaryForCLILocal(context.Background(), chat1.GetInboxSummaryForCLILocalQuery{})
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, conv := range inbox.Conversations {
+			name := conv.Info.TlfName
+			if channel := conv.Info.TopicName; channel != "" {
+				name += "#" + channel
+			}
+			if !seen[name] {
+				seen[name] = true
+				res = append(res, name)
+			}
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("unknown kind %q", c.kind)
+}
+
+func (c *cmdCompletionValues) Run() error {
+	vals, err := c.values()
+	if err != nil {
+		// No candidates beats broken completion in the shell.
+		return nil
+	}
+	sort.Strings(vals)
+	dui := c.G().UI.GetDumbOutputUI()
+	for _, v := range vals {
+		dui.Printf("%s\n", v)
+	}
+	return nil
+}
+
+func (c *cmdCompletionValues) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
@@ -233,6 +233,7 @@ func NewCmdConfig(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comman
 			NewCmdConfigGet(cl, g),
 			NewCmdConfigSet(cl, g),
 			NewCmdConfigInfo(cl, g),
+			NewCmdConfigProfile(cl, g),
 		},
 	}
 }
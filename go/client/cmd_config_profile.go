@@ -0,0 +1,190 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// NewCmdConfigProfile manages named config profiles. Profile values
+// live under "profiles.<name>" in the config file and override global
+// keys while the profile is active (flags > env > profile > global).
+func NewCmdConfigProfile(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "profile",
+		Usage: "Manage named configuration profiles",
+		Subcommands: []cli.Command{
+			newCmdConfigProfileList(cl, g),
+			newCmdConfigProfileUse(cl, g),
+			newCmdConfigProfileShow(cl, g),
+		},
+	}
+}
+
+type cmdConfigProfileList struct {
+	libkb.Contextified
+}
+
+func newCmdConfigProfileList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "list",
+		Usage: "List defined profiles",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdConfigProfileList{Contextified: libkb.NewContextified(g)}, "list", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+	}
+}
+
+func (c *cmdConfigProfileList) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("list takes no arguments")
+	}
+	return nil
+}
+
+func (c *cmdConfigProfileList) Run() error {
+	cfg, ok := c.G().Env.GetConfig().(*libkb.JSONConfigFile)
+	if !ok {
+		return errors.New("no config file loaded")
+	}
+	active := cfg.ActiveConfigProfile()
+	dui := c.G().UI.GetDumbOutputUI()
+	names := cfg.ConfigProfileNames()
+	if len(names) == 0 {
+		dui.Printf("No profiles defined. Add keys with `keybase config set profiles.<name>.<key> <value>`.\n")
+		return nil
+	}
+	for _, name := range names {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		dui.Printf("%s %s\n", marker, name)
+	}
+	return nil
+}
+
+func (c *cmdConfigProfileList) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true}
+}
+
+type cmdConfigProfileUse struct {
+	libkb.Contextified
+	name string
+}
+
+func newCmdConfigProfileUse(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "use",
+		ArgumentHelp: "<name>|none",
+		Usage:        "Activate a profile (or 'none' to clear)",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdConfigProfileUse{Contextified: libkb.NewContextified(g)}, "use", c)
+		},
+	}
+}
+
+func (c *cmdConfigProfileUse) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("use requires a profile name (or 'none')")
+	}
+	c.name = ctx.Args()[0]
+	return nil
+}
+
+func (c *cmdConfigProfileUse) Run() error {
+	cli, err := GetConfigClient(c.G())
+	if err != nil {
+		return err
+	}
+	if c.name == "none" {
+		if err := cli.ClearValue(context.Background(), "active_profile"); err != nil {
+			return err
+		}
+		c.G().UI.GetDumbOutputUI().Printf("Profile cleared; using global config only.\n")
+		return nil
+	}
+	name := c.name
+	if err := cli.SetValue(context.Background(), keybase1.SetValueArg{
+		Path:  "active_profile",
+		Value: keybase1.ConfigValue{S: &name},
+	}); err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Active profile: %s\n", name)
+	return nil
+}
+
+func (c *cmdConfigProfileUse) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+type cmdConfigProfileShow struct {
+	libkb.Contextified
+	paths []string
+}
+
+func newCmdConfigProfileShow(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "show",
+		ArgumentHelp: "<path> [...]",
+		Usage:        "Show effective values and which layer they come from",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdConfigProfileShow{Contextified: libkb.NewContextified(g)}, "show", c)
+		},
+	}
+}
+
+func (c *cmdConfigProfileShow) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 {
+		return errors.New("show requires at least one config path")
+	}
+	c.paths = ctx.Args()
+	return nil
+}
+
+func (c *cmdConfigProfileShow) Run() error {
+	cli, err := GetConfigClient(c.G())
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	for _, path := range c.paths {
+		provenance, err := cli.GetValueProvenance(context.Background(), path)
+		if err != nil {
+			return err
+		}
+		value, err := cli.GetValue(context.Background(), path)
+		display := "(unset)"
+		if err == nil {
+			switch {
+			case value.S != nil:
+				display = *value.S
+			case value.B != nil:
+				display = fmt.Sprintf("%v", *value.B)
+			case value.I != nil:
+				display = fmt.Sprintf("%d", *value.I)
+			case value.O != nil:
+				display = *value.O
+			case value.IsNull:
+				display = "null"
+			}
+		}
+		dui.Printf("%s\t%s\t[%s]\n", path, display, provenance)
+	}
+	return nil
+}
+
+func (c *cmdConfigProfileShow) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
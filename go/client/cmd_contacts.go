@@ -0,0 +1,133 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+// NewCmdContacts imports and inspects the local address book mapping.
+func NewCmdContacts(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "contacts",
+		Usage: "Import an address book and resolve it to Keybase users",
+		Subcommands: []cli.Command{
+			newCmdContactsImport(cl, g),
+			newCmdContactsList(cl, g),
+		},
+	}
+}
+
+type cmdContactsImport struct {
+	libkb.Contextified
+	path string
+}
+
+func newCmdContactsImport(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "import",
+		ArgumentHelp: "<file.vcf | file.json>",
+		Usage:        "Import a vCard or JSON address book",
+		Description:  "Phone numbers and emails are hashed before being sent for\nresolution; the resolved mapping is stored locally only.",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdContactsImport{Contextified: libkb.NewContextified(g)}, "import", c)
+		},
+	}
+}
+
+func (c *cmdContactsImport) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("import requires one address-book file")
+	}
+	c.path = ctx.Args()[0]
+	return nil
+}
+
+func (c *cmdContactsImport) Run() error {
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	cli, err := GetContactsClient(c.G())
+	if err != nil {
+		return err
+	}
+	resolved, err := cli.ImportContacts(context.Background(), string(data))
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Imported; %d contact(s) resolved to Keybase users.\n", resolved)
+	return nil
+}
+
+func (c *cmdContactsImport) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+type cmdContactsList struct {
+	libkb.Contextified
+}
+
+func newCmdContactsList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "list",
+		Usage: "List resolved contacts",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdContactsList{Contextified: libkb.NewContextified(g)}, "list", c)
+		},
+	}
+}
+
+func (c *cmdContactsList) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("list takes no arguments")
+	}
+	return nil
+}
+
+func (c *cmdContactsList) Run() error {
+	cli, err := GetContactsClient(c.G())
+	if err != nil {
+		return err
+	}
+	resolved, err := cli.ListContacts(context.Background())
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(resolved) == 0 {
+		dui.Printf("No resolved contacts. Run `keybase contacts import` first.\n")
+		return nil
+	}
+	for _, entry := range resolved {
+		dui.Printf("%s\t%s\n", entry.ContactName, entry.Username)
+	}
+	return nil
+}
+
+func (c *cmdContactsList) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
+
+// resolveContactName maps an imported contact name to a Keybase
+// username, or "" on any miss or error (callers fall through to
+// ordinary resolution).
+func resolveContactName(g *libkb.GlobalContext, name string) string {
+	cli, err := GetContactsClient(g)
+	if err != nil {
+		return ""
+	}
+	username, err := cli.ResolveContact(context.Background(), name)
+	if err != nil {
+		return ""
+	}
+	return username
+}
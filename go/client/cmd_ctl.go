@@ -29,6 +29,7 @@ func NewCmdCtl(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 			NewCmdWatchdog(cl, g),
 			NewCmdWatchdog2(cl, g),
 			NewCmdCtlAppExit(cl, g),
+			NewCmdCtlRemoteKeygen(cl, g),
 		},
 	}
 }
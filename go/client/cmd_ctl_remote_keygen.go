@@ -0,0 +1,224 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+// CmdCtlRemoteKeygen generates the key material for remote headless
+// control: a private CA, a server keypair for the service's TLS
+// listener, and a client keypair the controlling machine presents.
+type CmdCtlRemoteKeygen struct {
+	libkb.Contextified
+	hosts []string
+	force bool
+}
+
+func NewCmdCtlRemoteKeygen(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "remote-keygen",
+		Usage: "Generate TLS key material for remote headless control",
+		Description: `Generates a private CA plus server and client keypairs under the
+config directory. Point the service at a TCP port with
+service.remote_ctl_bind, copy ca.pem/client.pem/client-key.pem to the
+controlling machine, and set KEYBASE_REMOTE_CTL_CONNECT there.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlRemoteKeygen{Contextified: libkb.NewContextified(g)}, "remote-keygen", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "hosts",
+				Usage: "Comma-separated extra hostnames or IPs for the server certificate.",
+			},
+			cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite existing key material.",
+			},
+		},
+	}
+}
+
+func (c *CmdCtlRemoteKeygen) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("remote-keygen takes no arguments")
+	}
+	for _, h := range strings.Split(ctx.String("hosts"), ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			c.hosts = append(c.hosts, h)
+		}
+	}
+	c.force = ctx.Bool("force")
+	return nil
+}
+
+type remoteCtlKeypair struct {
+	certPem []byte
+	keyPem  []byte
+}
+
+func newRemoteCtlSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func marshalRemoteCtlKeypair(derCert []byte, key *ecdsa.PrivateKey) (kp remoteCtlKeypair, err error) {
+	kp.certPem = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derCert})
+	derKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return kp, err
+	}
+	kp.keyPem = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: derKey})
+	return kp, nil
+}
+
+func (c *CmdCtlRemoteKeygen) generate() (ca, server, client remoteCtlKeypair, err error) {
+	now := time.Now()
+	notAfter := now.AddDate(10, 0, 0)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return ca, server, client, err
+	}
+	caSerial, err := newRemoteCtlSerial()
+	if err != nil {
+		return ca, server, client, err
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          caSerial,
+		Subject:               pkix.Name{CommonName: libkb.RemoteCtlServerName + "-ca"},
+		NotBefore:             now,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return ca, server, client, err
+	}
+	caCert, err := x509.ParseCertificate(caDer)
+	if err != nil {
+		return ca, server, client, err
+	}
+	if ca, err = marshalRemoteCtlKeypair(caDer, caKey); err != nil {
+		return ca, server, client, err
+	}
+
+	issue := func(tmpl *x509.Certificate) (remoteCtlKeypair, error) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return remoteCtlKeypair{}, err
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			return remoteCtlKeypair{}, err
+		}
+		return marshalRemoteCtlKeypair(der, key)
+	}
+
+	serverSerial, err := newRemoteCtlSerial()
+	if err != nil {
+		return ca, server, client, err
+	}
+	serverTmpl := &x509.Certificate{
+		SerialNumber: serverSerial,
+		Subject:      pkix.Name{CommonName: libkb.RemoteCtlServerName},
+		NotBefore:    now,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{libkb.RemoteCtlServerName},
+	}
+	for _, h := range c.hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			serverTmpl.IPAddresses = append(serverTmpl.IPAddresses, ip)
+		} else {
+			serverTmpl.DNSNames = append(serverTmpl.DNSNames, h)
+		}
+	}
+	if server, err = issue(serverTmpl); err != nil {
+		return ca, server, client, err
+	}
+
+	clientSerial, err := newRemoteCtlSerial()
+	if err != nil {
+		return ca, server, client, err
+	}
+	client, err = issue(&x509.Certificate{
+		SerialNumber: clientSerial,
+		Subject:      pkix.Name{CommonName: libkb.RemoteCtlServerName + "-client"},
+		NotBefore:    now,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return ca, server, client, err
+}
+
+func (c *CmdCtlRemoteKeygen) Run() error {
+	dir := libkb.RemoteCtlDir(c.G())
+	if !c.force {
+		if _, err := os.Stat(filepath.Join(dir, libkb.RemoteCtlCAFile)); err == nil {
+			return fmt.Errorf("key material already exists in %s; use --force to regenerate", dir)
+		}
+	}
+	if err := os.MkdirAll(dir, libkb.PermDir); err != nil {
+		return err
+	}
+
+	ca, server, client, err := c.generate()
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+		mode os.FileMode
+	}{
+		{libkb.RemoteCtlCAFile, ca.certPem, 0644},
+		{libkb.RemoteCtlCAKeyFile, ca.keyPem, 0600},
+		{libkb.RemoteCtlServerCertFile, server.certPem, 0644},
+		{libkb.RemoteCtlServerKeyFile, server.keyPem, 0600},
+		{libkb.RemoteCtlClientCertFile, client.certPem, 0644},
+		{libkb.RemoteCtlClientKeyFile, client.keyPem, 0600},
+	}
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f.name), f.data, f.mode); err != nil {
+			return err
+		}
+	}
+
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("Wrote remote-ctl key material to %s\n\n", dir)
+	dui.Printf("On this machine, enable the listener:\n")
+	dui.Printf("    keybase config set service.remote_ctl_bind '\"0.0.0.0:9920\"'\n\n")
+	dui.Printf("Copy %s, %s and %s to the controlling machine's\n", libkb.RemoteCtlCAFile, libkb.RemoteCtlClientCertFile, libkb.RemoteCtlClientKeyFile)
+	dui.Printf("remote-ctl config dir, then there:\n")
+	dui.Printf("    KEYBASE_REMOTE_CTL_CONNECT=<host>:9920 keybase status\n")
+	return nil
+}
+
+func (c *CmdCtlRemoteKeygen) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true}
+}
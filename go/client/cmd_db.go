@@ -255,6 +255,48 @@ func NewCmdDbPut(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 	}
 }
 
+type CmdDbVerify struct {
+	libkb.Contextified
+}
+
+func (c *CmdDbVerify) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("verify takes no arguments")
+	}
+	return nil
+}
+
+func (c *CmdDbVerify) Run() error {
+	cli, err := GetCtlClient(c.G())
+	if err != nil {
+		return err
+	}
+	res, err := cli.DbVerify(context.TODO(), 0)
+	if err != nil {
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+	ui.Printf("Scanned %d records: %d corrupt, %d deleted\n", res.Scanned, res.Corrupt, res.Deleted)
+	if res.Deleted > 0 {
+		ui.Printf("Deleted records will be re-fetched from the server on next use.\n")
+	}
+	return nil
+}
+
+func NewCmdDbVerifyRunner(g *libkb.GlobalContext) *CmdDbVerify {
+	return &CmdDbVerify{Contextified: libkb.NewContextified(g)}
+}
+
+func NewCmdDbVerify(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "verify",
+		Usage: "Scan the local database for corrupt records and delete them",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(NewCmdDbVerifyRunner(g), "verify", c)
+		},
+	}
+}
+
 func NewCmdDb(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 	return cli.Command{
 		Name: "db",
@@ -263,6 +305,7 @@ func NewCmdDb(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 			NewCmdDbDelete(cl, g),
 			NewCmdDbGet(cl, g),
 			NewCmdDbPut(cl, g),
+			NewCmdDbVerify(cl, g),
 		},
 	}
 }
@@ -294,3 +337,10 @@ func (c *CmdDbPut) GetUsage() libkb.Usage {
 		API:    true,
 	}
 }
+
+func (c *CmdDbVerify) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
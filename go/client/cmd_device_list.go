@@ -4,6 +4,7 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"golang.org/x/net/context"
@@ -17,7 +18,8 @@ import (
 // CmdDeviceList is the 'device list' command.  It displays all
 // the devices for the current user.
 type CmdDeviceList struct {
-	all bool
+	all    bool
+	format string
 	libkb.Contextified
 }
 
@@ -29,6 +31,12 @@ func NewCmdDeviceList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Co
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(&CmdDeviceList{Contextified: libkb.NewContextified(g)}, "list", c)
 		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format (json for a machine-readable device inventory).",
+			},
+		},
 	}
 }
 
@@ -42,14 +50,36 @@ func (c *CmdDeviceList) Run() error {
 		return err
 	}
 
+	if c.format == "json" {
+		return c.runJSON(cli)
+	}
+
 	devs, err := cli.DeviceList(context.TODO(), 0)
 	if err != nil {
 		return err
 	}
+	if jsonOutputMode(c.G()) {
+		return printJSONResult(c.G(), "device-list.v1", devs)
+	}
 	c.output(devs)
 	return nil
 }
 
+// runJSON prints the detailed device inventory (delegation seqnos,
+// signing keys, revocations, last-used times) for fleet-management
+// scripts to consume.
+func (c *CmdDeviceList) runJSON(cli keybase1.DeviceClient) error {
+	devs, err := cli.DeviceHistoryList(context.TODO(), 0)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(devs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return c.G().UI.GetTerminalUI().Output(string(out) + "\n")
+}
+
 func (c *CmdDeviceList) output(devs []keybase1.Device) {
 	w := GlobUI.DefaultTabWriter()
 	fmt.Fprintf(w, "Name\tType\tID\tCreated\tLast Used\n")
@@ -66,6 +96,10 @@ func (c *CmdDeviceList) output(devs []keybase1.Device) {
 // ParseArgv does nothing for this command.
 func (c *CmdDeviceList) ParseArgv(ctx *cli.Context) error {
 	c.all = ctx.Bool("all")
+	c.format = ctx.String("format")
+	if c.format != "" && c.format != "json" {
+		return fmt.Errorf("unsupported format %q; only json is supported", c.format)
+	}
 	return nil
 }
 
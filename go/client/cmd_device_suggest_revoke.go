@@ -0,0 +1,138 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// defaultStaleDeviceThreshold is how long a device can go unused before
+// we suggest revoking it; override with --threshold.
+const defaultStaleDeviceThreshold = 90 * 24 * time.Hour
+
+// CmdDeviceSuggestRevoke lists devices that have been inactive beyond a
+// threshold and optionally walks the user through revoking them. A
+// forgotten laptop key is a standing risk; this surfaces it.
+type CmdDeviceSuggestRevoke struct {
+	libkb.Contextified
+	threshold time.Duration
+	revoke    bool
+}
+
+func NewCmdDeviceSuggestRevoke(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "suggest-revoke",
+		Usage: "List devices inactive long enough that they should probably be revoked",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDeviceSuggestRevoke{Contextified: libkb.NewContextified(g)}, "suggest-revoke", c)
+		},
+		Flags: []cli.Flag{
+			cli.DurationFlag{
+				Name:  "threshold",
+				Usage: "Inactivity threshold (e.g. 720h for 30 days).",
+				Value: defaultStaleDeviceThreshold,
+			},
+			cli.BoolFlag{
+				Name:  "revoke",
+				Usage: "Prompt to revoke each stale device.",
+			},
+		},
+	}
+}
+
+func (c *CmdDeviceSuggestRevoke) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("suggest-revoke takes no arguments")
+	}
+	c.threshold = ctx.Duration("threshold")
+	c.revoke = ctx.Bool("revoke")
+	return nil
+}
+
+func (c *CmdDeviceSuggestRevoke) Run() error {
+	cli, err := GetDeviceClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err := RegisterProtocolsWithContext(nil, c.G()); err != nil {
+		return err
+	}
+
+	devs, err := cli.DeviceHistoryList(context.TODO(), 0)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var stale []keybase1.DeviceDetail
+	for _, d := range devs {
+		if d.RevokedAt != nil || d.CurrentDevice {
+			continue
+		}
+		// Fall back to creation time for devices that have never
+		// reported a use.
+		lastUsed := keybase1.FromTime(d.Device.LastUsedTime)
+		if lastUsed.IsZero() {
+			lastUsed = keybase1.FromTime(d.Device.CTime)
+		}
+		if now.Sub(lastUsed) > c.threshold {
+			stale = append(stale, d)
+		}
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	if len(stale) == 0 {
+		ui.Printf("No devices have been inactive for more than %s.\n", c.threshold)
+		return nil
+	}
+
+	ui.Printf("Devices inactive for more than %s:\n\n", c.threshold)
+	w := GlobUI.DefaultTabWriter()
+	fmt.Fprintf(w, "Name\tType\tID\tLast Used\n")
+	for _, d := range stale {
+		lastUsed := keybase1.FromTime(d.Device.LastUsedTime)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Device.Name, d.Device.Type, d.Device.DeviceID, lastUsed.Format("2006 Jan 2"))
+	}
+	w.Flush()
+
+	if !c.revoke {
+		ui.Printf("\nRun `keybase device suggest-revoke --revoke` to revoke them, or\n")
+		ui.Printf("`keybase device remove <name>` for individual devices.\n")
+		return nil
+	}
+
+	for _, d := range stale {
+		ok, err := ui.PromptYesNo(PromptDescriptorDeviceRevoke, fmt.Sprintf("Revoke %q (last used %s)?",
+			d.Device.Name, keybase1.FromTime(d.Device.LastUsedTime).Format("2006 Jan 2")), libkb.PromptDefaultNo)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		rm := &CmdDeviceRemove{Contextified: libkb.NewContextified(c.G())}
+		rm.SetIDOrName(d.Device.DeviceID.String())
+		if err := rm.Run(); err != nil {
+			ui.Printf("Failed to revoke %q: %s\n", d.Device.Name, err)
+			continue
+		}
+		ui.Printf("Revoked %q.\n", d.Device.Name)
+	}
+	return nil
+}
+
+func (c *CmdDeviceSuggestRevoke) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
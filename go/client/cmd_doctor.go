@@ -0,0 +1,314 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdDoctor runs environment and account health checks and suggests
+// (or with --fix, applies) remedies. Each check degrades gracefully:
+// a logged-out service skips the account checks rather than failing.
+type CmdDoctor struct {
+	libkb.Contextified
+	fix      bool
+	failures int
+}
+
+func NewCmdDoctor(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "doctor",
+		Usage: "Check the health of your keybase environment and account",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDoctor{Contextified: libkb.NewContextified(g)}, "doctor", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetLogForward(libcmdline.LogForwardNone)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "fix",
+				Usage: "Apply safe fixes automatically (e.g. socket permissions).",
+			},
+		},
+	}
+}
+
+func (c *CmdDoctor) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return UnexpectedArgsError("doctor")
+	}
+	c.fix = ctx.Bool("fix")
+	return nil
+}
+
+const doctorClockSkewMax = 5 * time.Minute
+const doctorStaleDeviceAge = 90 * 24 * time.Hour
+
+func (c *CmdDoctor) ok(name, detail string) {
+	c.G().UI.GetDumbOutputUI().Printf("[ok]   %s: %s\n", name, detail)
+}
+
+func (c *CmdDoctor) warn(name, detail, suggestion string) {
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("[warn] %s: %s\n", name, detail)
+	if suggestion != "" {
+		dui.Printf("       fix: %s\n", suggestion)
+	}
+}
+
+func (c *CmdDoctor) fail(name, detail, suggestion string) {
+	c.failures++
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("[FAIL] %s: %s\n", name, detail)
+	if suggestion != "" {
+		dui.Printf("       fix: %s\n", suggestion)
+	}
+}
+
+func (c *CmdDoctor) skip(name, why string) {
+	c.G().UI.GetDumbOutputUI().Printf("[skip] %s: %s\n", name, why)
+}
+
+// checkService dials the socket and asks for the service config.
+func (c *CmdDoctor) checkService() bool {
+	cli, err := GetConfigClient(c.G())
+	if err != nil {
+		c.fail("service", fmt.Sprintf("cannot reach the background service: %s", err),
+			"run `keybase ctl start` (or `run_keybase`)")
+		return false
+	}
+	config, err := cli.GetConfig(context.Background(), 0)
+	if err != nil {
+		c.fail("service", fmt.Sprintf("socket connects but RPC failed: %s", err),
+			"run `keybase ctl restart`")
+		return false
+	}
+	c.ok("service", fmt.Sprintf("running version %s", config.Version))
+	return true
+}
+
+// checkSocketPerms requires the unix socket be owned by us and not
+// accessible to group/other (anyone with the socket has full account
+// power unless capability tokens are in play).
+func (c *CmdDoctor) checkSocketPerms() {
+	sf, err := c.G().Env.GetSocketBindFile()
+	if err != nil {
+		c.skip("socket permissions", err.Error())
+		return
+	}
+	info, err := os.Stat(sf)
+	if err != nil {
+		c.skip("socket permissions", fmt.Sprintf("no socket at %s", sf))
+		return
+	}
+	// Connecting to a unix socket needs write permission, so only
+	// group/other *write* bits are dangerous; the default 0755 socket
+	// is fine.
+	if perm := info.Mode().Perm(); perm&0022 != 0 {
+		if c.fix {
+			if err := os.Chmod(sf, perm&^0022); err != nil {
+				c.fail("socket permissions", fmt.Sprintf("%s is %04o and chmod failed: %s", sf, perm, err), "")
+				return
+			}
+			c.ok("socket permissions", fmt.Sprintf("fixed: %s now %04o", sf, perm&^0022))
+			return
+		}
+		c.fail("socket permissions", fmt.Sprintf("%s is %04o; other users can drive your account", sf, perm),
+			fmt.Sprintf("chmod go-w %s (or rerun with --fix)", sf))
+		return
+	}
+	c.ok("socket permissions", fmt.Sprintf("%s is not writable by other users", sf))
+}
+
+// checkClockSkew compares our clock with the API server's Date
+// header; skew breaks signature and merkle verification.
+func (c *CmdDoctor) checkClockSkew() {
+	serverURI := c.G().Env.GetServerURI()
+	req, err := http.NewRequest("HEAD", strings.TrimSuffix(serverURI, "/")+"/", nil)
+	if err != nil {
+		c.skip("clock skew", err.Error())
+		return
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		c.skip("clock skew", fmt.Sprintf("cannot reach %s: %s", serverURI, err))
+		return
+	}
+	defer resp.Body.Close()
+	serverDate := resp.Header.Get("Date")
+	if serverDate == "" {
+		c.skip("clock skew", "server sent no Date header")
+		return
+	}
+	serverTime, err := http.ParseTime(serverDate)
+	if err != nil {
+		c.skip("clock skew", fmt.Sprintf("bad Date header %q", serverDate))
+		return
+	}
+	// Date has second granularity; include half the round trip.
+	skew := start.Add(time.Since(start) / 2).Sub(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > doctorClockSkewMax {
+		c.fail("clock skew", fmt.Sprintf("local clock is %s off the server's; signature verification will break", skew.Round(time.Second)),
+			"enable NTP time synchronization")
+		return
+	}
+	c.ok("clock skew", fmt.Sprintf("%s (within tolerance)", skew.Round(time.Second)))
+}
+
+// checkTLS looks for proxies and TLS interception on the API server
+// connection.
+func (c *CmdDoctor) checkTLS() {
+	for _, env := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"} {
+		if v := os.Getenv(env); v != "" {
+			c.warn("proxy", fmt.Sprintf("%s=%s is set; keybase traffic routes through it", env, v), "")
+		}
+	}
+	serverURI := c.G().Env.GetServerURI()
+	u, err := url.Parse(serverURI)
+	if err != nil || u.Scheme != "https" {
+		c.skip("tls", fmt.Sprintf("server %s is not https (devel mode?)", serverURI))
+		return
+	}
+	host := u.Host
+	if !strings.Contains(host, ":") {
+		host += ":443"
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", host, nil)
+	if err != nil {
+		c.fail("tls", fmt.Sprintf("TLS to %s failed: %s; a middlebox may be intercepting", host, err),
+			"check corporate proxy / TLS inspection settings")
+		return
+	}
+	defer conn.Close()
+	issuer := conn.ConnectionState().PeerCertificates[0].Issuer.CommonName
+	c.ok("tls", fmt.Sprintf("certificate verifies (issuer %q)", issuer))
+}
+
+// checkAccount covers the logged-in checks: pending rekeys,
+// unreachable proofs, stale devices.
+func (c *CmdDoctor) checkAccount() {
+	configCli, err := GetConfigClient(c.G())
+	if err != nil {
+		return
+	}
+	status, err := configCli.GetCurrentStatus(context.Background(), 0)
+	if err != nil || !status.LoggedIn {
+		c.skip("account checks", "not logged in")
+		return
+	}
+
+	if rekeyCli, err := GetRekeyClient(c.G()); err == nil {
+		problems, err := rekeyCli.GetPendingRekeyStatus(context.Background(), 0)
+		if err != nil {
+			c.skip("pending rekeys", err.Error())
+		} else if n := len(problems.ProblemSet.Tlfs); n > 0 {
+			c.fail("pending rekeys", fmt.Sprintf("%d folder(s) need rekeying", n),
+				"run `keybase rekey status` and unlock a device that can rekey")
+		} else {
+			c.ok("pending rekeys", "none")
+		}
+	}
+
+	if idCli, err := GetIdentifyClient(c.G()); err == nil {
+		// A self-identify forces fresh proof checks; broken or
+		// unreachable proofs surface as an error here.
+		_, err := idCli.Identify2(context.Background(), keybase1.Identify2Arg{
+			ForceRemoteCheck: true,
+			AllowEmptySelfID: true,
+			NoSkipSelf:       true,
+			IdentifyBehavior: keybase1.TLFIdentifyBehavior_CHAT_CLI,
+			Reason:           keybase1.IdentifyReason{Reason: "keybase doctor"},
+		})
+		if err != nil {
+			c.fail("proofs", fmt.Sprintf("self-identify failed: %s", err),
+				"run `keybase id` to see which proofs are failing, then reprove with `keybase prove`")
+		} else {
+			c.ok("proofs", "self-identify passed with fresh proof checks")
+		}
+	}
+
+	if devCli, err := GetDeviceClient(c.G()); err == nil {
+		devs, err := devCli.DeviceList(context.Background(), 0)
+		if err == nil {
+			var stale []string
+			now := time.Now()
+			for _, d := range devs {
+				lastUsed := keybase1.FromTime(d.LastUsedTime)
+				if !lastUsed.IsZero() && now.Sub(lastUsed) > doctorStaleDeviceAge {
+					stale = append(stale, d.Name)
+				}
+			}
+			if len(stale) > 0 {
+				c.warn("stale devices", fmt.Sprintf("unused for 90+ days: %s", strings.Join(stale, ", ")),
+					"consider `keybase device remove <name>` for devices you no longer control")
+			} else {
+				c.ok("stale devices", "none")
+			}
+		}
+	}
+}
+
+// checkKBFSMount verifies the mount directory exists and responds.
+func (c *CmdDoctor) checkKBFSMount() {
+	mount, err := c.G().Env.GetMountDir()
+	if err != nil || mount == "" {
+		c.skip("kbfs mount", "no mount directory configured")
+		return
+	}
+	info, err := os.Stat(mount)
+	if err != nil {
+		c.warn("kbfs mount", fmt.Sprintf("%s: %s", mount, err),
+			"install/start KBFS, or ignore if you don't use the filesystem")
+		return
+	}
+	if !info.IsDir() {
+		c.fail("kbfs mount", fmt.Sprintf("%s exists but is not a directory", mount), "")
+		return
+	}
+	c.ok("kbfs mount", mount)
+}
+
+func (c *CmdDoctor) Run() error {
+	serviceUp := c.checkService()
+	c.checkSocketPerms()
+	c.checkClockSkew()
+	c.checkTLS()
+	if serviceUp {
+		c.checkAccount()
+	}
+	c.checkKBFSMount()
+
+	dui := c.G().UI.GetDumbOutputUI()
+	if c.failures > 0 {
+		dui.Printf("\n%d check(s) failed.\n", c.failures)
+		return fmt.Errorf("doctor found %d problem(s)", c.failures)
+	}
+	dui.Printf("\nAll checks passed.\n")
+	return nil
+}
+
+func (c *CmdDoctor) GetUsage() libkb.Usage {
+	// No API usage: doctor must run (and diagnose) even when the
+	// service is down, so it makes its own connections.
+	return libkb.Usage{Config: true}
+}
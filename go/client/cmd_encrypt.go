@@ -5,6 +5,7 @@ package client
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"golang.org/x/net/context"
@@ -49,6 +50,18 @@ func NewCmdEncrypt(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comma
 			Name:  "no-entity-keys",
 			Usage: "Do not use per user/per team keys for encryption.",
 		},
+		cli.BoolFlag{
+			Name: "exploding",
+			Usage: `Encrypt to the recipients' ephemeral keys, so the
+	ciphertext becomes undecryptable once those keys expire (like
+	exploding chat messages).`,
+		},
+		cli.DurationFlag{
+			Name: "lifetime",
+			Usage: `With --exploding, the desired ciphertext lifetime.
+	Expiry is governed by ephemeral key rotation; lifetimes beyond the
+	maximum ephemeral key age are refused.`,
+		},
 		cli.BoolFlag{
 			Name: "no-device-keys",
 			Usage: `Do not use the device keys of all the user recipients
@@ -178,6 +191,18 @@ func (c *CmdEncrypt) ParseArgv(ctx *cli.Context) error {
 	}
 
 	c.opts.UseEntityKeys = !ctx.Bool("no-entity-keys")
+	c.opts.UseExplodingKeys = ctx.Bool("exploding")
+	if lifetime := ctx.Duration("lifetime"); lifetime != 0 {
+		if !c.opts.UseExplodingKeys {
+			return errors.New("--lifetime requires --exploding")
+		}
+		if lifetime > libkb.MaxEphemeralContentLifetime {
+			return fmt.Errorf("--lifetime may not exceed the maximum ephemeral key age of %s", libkb.MaxEphemeralContentLifetime)
+		}
+	}
+	if c.opts.UseExplodingKeys && len(c.opts.TeamRecipients) > 0 {
+		return errors.New("--exploding does not support team recipients yet")
+	}
 	c.opts.UseDeviceKeys = !ctx.Bool("no-device-keys")
 	c.opts.UsePaperKeys = !ctx.Bool("no-paper-keys")
 	c.opts.UseKBFSKeysOnlyForTesting = ctx.Bool("use-kbfs-keys-only")
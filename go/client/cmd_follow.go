@@ -4,6 +4,8 @@
 package client
 
 import (
+	"strings"
+
 	"fmt"
 
 	"golang.org/x/net/context"
@@ -40,8 +42,15 @@ func NewCmdTrack(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 				Name:  "s, skip-proof-cache",
 				Usage: "Skip cached proofs, force re-check",
 			},
+			cli.StringFlag{
+				Name:  "exclude",
+				Usage: "Comma-separated proof types to leave out of the follow statement (e.g. twitter,dns); their changes won't warn.",
+			},
 		},
 		Aliases: []string{"track"},
+		Subcommands: []cli.Command{
+			NewCmdFollowSetPolicy(cl, g),
+		},
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(NewCmdTrackRunner(g), "follow", c)
 		},
@@ -66,6 +75,13 @@ func (v *CmdTrack) ParseArgv(ctx *cli.Context) error {
 	}
 	v.user = ctx.Args()[0]
 	v.options = keybase1.TrackOptions{LocalOnly: ctx.Bool("local"), BypassConfirm: ctx.Bool("y")}
+	if excl := ctx.String("exclude"); excl != "" {
+		for _, e := range strings.Split(excl, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				v.options.ExcludeProofTypes = append(v.options.ExcludeProofTypes, e)
+			}
+		}
+	}
 	v.skipProofCache = ctx.Bool("skip-proof-cache")
 	return nil
 }
@@ -0,0 +1,91 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+// CmdFollowSetPolicy configures the tracking freshness policy: how old
+// a follow statement may get before the background engine re-verifies
+// the followed user, and whether clean re-verifies refresh the
+// statement automatically.
+type CmdFollowSetPolicy struct {
+	libkb.Contextified
+	maxAge      time.Duration
+	autoRetrack bool
+	clear       bool
+}
+
+func NewCmdFollowSetPolicy(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "set-policy",
+		Usage: "Configure automatic re-verification of your follow statements",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdFollowSetPolicy{Contextified: libkb.NewContextified(g)}, "set-policy", c)
+		},
+		Flags: []cli.Flag{
+			cli.DurationFlag{
+				Name:  "max-age",
+				Usage: "Re-verify follows older than this (e.g. 720h for 30 days).",
+			},
+			cli.BoolFlag{
+				Name:  "auto-retrack",
+				Usage: "Refresh the follow statement automatically when re-verification is clean.",
+			},
+			cli.BoolFlag{
+				Name:  "clear",
+				Usage: "Remove the policy.",
+			},
+		},
+	}
+}
+
+func (c *CmdFollowSetPolicy) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("set-policy takes no arguments")
+	}
+	c.maxAge = ctx.Duration("max-age")
+	c.autoRetrack = ctx.Bool("auto-retrack")
+	c.clear = ctx.Bool("clear")
+	if !c.clear && c.maxAge == 0 {
+		return fmt.Errorf("set-policy needs --max-age (or --clear)")
+	}
+	return nil
+}
+
+func (c *CmdFollowSetPolicy) Run() error {
+	writer := c.G().Env.GetConfigWriter()
+	if writer == nil {
+		return fmt.Errorf("no config writer available")
+	}
+	ui := c.G().UI.GetTerminalUI()
+	if c.clear {
+		if err := writer.SetStringAtPath("track.max_age", ""); err != nil {
+			return err
+		}
+		if err := writer.SetBoolAtPath("track.auto_retrack", false); err != nil {
+			return err
+		}
+		ui.Printf("Tracking freshness policy cleared.\n")
+		return nil
+	}
+	if err := writer.SetStringAtPath("track.max_age", c.maxAge.String()); err != nil {
+		return err
+	}
+	if err := writer.SetBoolAtPath("track.auto_retrack", c.autoRetrack); err != nil {
+		return err
+	}
+	ui.Printf("Follows older than %s will be re-verified in the background.\n", c.maxAge)
+	return nil
+}
+
+func (c *CmdFollowSetPolicy) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true}
+}
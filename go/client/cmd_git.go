@@ -16,6 +16,8 @@ func NewCmdGit(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 		newCmdGitList(cl, g),
 		newCmdGitGC(cl, g),
 		newCmdGitSettings(cl, g),
+		NewCmdGitLFSTransfer(cl, g),
+		newCmdGitMirror(cl, g),
 	}
 
 	if develUsage {
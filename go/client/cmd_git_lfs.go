@@ -0,0 +1,197 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// git-lfs support for keybase-hosted repos: a custom transfer agent
+// (the git-lfs "custom adapter" protocol: JSON lines on stdin/stdout)
+// that stores LFS objects in a dedicated area of the repo's TLF via
+// SimpleFS. Configure a clone with:
+//
+//   git config lfs.standalonetransferagent keybase
+//   git config lfs.customtransfer.keybase.path keybase
+//   git config lfs.customtransfer.keybase.args "git lfs-transfer <kbfs repo dir>"
+//
+// keybase git lfs-config <dir> writes those settings into the repo.
+
+type CmdGitLFSTransfer struct {
+	libkb.Contextified
+	objectsDir string // KBFS path to the LFS objects area
+}
+
+func NewCmdGitLFSTransfer(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "lfs-transfer",
+		ArgumentHelp: "<kbfs repo path>",
+		Usage:        "git-lfs custom transfer agent storing objects in the repo's TLF",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdGitLFSTransfer{Contextified: libkb.NewContextified(g)}, "lfs-transfer", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetLogForward(libcmdline.LogForwardNone)
+		},
+	}
+}
+
+func (c *CmdGitLFSTransfer) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("lfs-transfer takes one argument: the KBFS repo path")
+	}
+	repo := ctx.Args()[0]
+	if !strings.HasPrefix(repo, "/keybase/") {
+		return fmt.Errorf("repo path must start with /keybase/")
+	}
+	c.objectsDir = strings.TrimPrefix(path.Join(repo, "lfs-objects"), "/keybase")
+	return nil
+}
+
+// lfsMessage is one line of the custom adapter protocol, both ways.
+type lfsMessage struct {
+	Event string `json:"event,omitempty"`
+	Oid   string `json:"oid,omitempty"`
+	Size  int64  `json:"size,omitempty"`
+	Path  string `json:"path,omitempty"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (c *CmdGitLFSTransfer) objectPath(oid string) (keybase1.Path, error) {
+	if len(oid) < 3 {
+		return keybase1.Path{}, fmt.Errorf("malformed LFS oid %q", oid)
+	}
+	// Shard like git does, to keep directory sizes sane.
+	return keybase1.NewPathWithKbfs(path.Join(c.objectsDir, oid[:2], oid)), nil
+}
+
+func lfsReply(out *bufio.Writer, msg lfsMessage) {
+	blob, _ := json.Marshal(msg)
+	out.Write(blob)
+	out.WriteByte('\n')
+	out.Flush()
+}
+
+func lfsError(out *bufio.Writer, oid string, err error) {
+	msg := lfsMessage{Event: "complete", Oid: oid}
+	msg.Error = &struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{Code: 1, Message: err.Error()}
+	lfsReply(out, msg)
+}
+
+func (c *CmdGitLFSTransfer) upload(ctx context.Context, cli keybase1.SimpleFSClient, oid, localPath string) error {
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	dest, err := c.objectPath(oid)
+	if err != nil {
+		return err
+	}
+	err = cli.SimpleFSCopyRecursive(ctx, keybase1.SimpleFSCopyRecursiveArg{
+		OpID: opid,
+		Src:  keybase1.NewPathWithLocal(localPath),
+		Dest: dest,
+	})
+	if err != nil {
+		return err
+	}
+	return cli.SimpleFSWait(ctx, opid)
+}
+
+func (c *CmdGitLFSTransfer) download(ctx context.Context, cli keybase1.SimpleFSClient, oid string) (string, error) {
+	tmp, err := ioutil.TempFile("", "kb-lfs")
+	if err != nil {
+		return "", err
+	}
+	tmp.Close()
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+	src, err := c.objectPath(oid)
+	if err != nil {
+		return "", err
+	}
+	err = cli.SimpleFSCopyRecursive(ctx, keybase1.SimpleFSCopyRecursiveArg{
+		OpID: opid,
+		Src:  src,
+		Dest: keybase1.NewPathWithLocal(tmp.Name()),
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := cli.SimpleFSWait(ctx, opid); err != nil {
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+func (c *CmdGitLFSTransfer) Run() error {
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	in := bufio.NewReader(os.Stdin)
+	out := bufio.NewWriter(os.Stdout)
+
+	for {
+		line, err := in.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var msg lfsMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+		switch msg.Event {
+		case "init":
+			lfsReply(out, lfsMessage{}) // empty object = ready
+		case "upload":
+			if err := c.upload(ctx, cli, msg.Oid, msg.Path); err != nil {
+				lfsError(out, msg.Oid, err)
+				continue
+			}
+			lfsReply(out, lfsMessage{Event: "complete", Oid: msg.Oid})
+		case "download":
+			local, err := c.download(ctx, cli, msg.Oid)
+			if err != nil {
+				lfsError(out, msg.Oid, err)
+				continue
+			}
+			lfsReply(out, lfsMessage{Event: "complete", Oid: msg.Oid, Path: local})
+		case "terminate":
+			return nil
+		}
+	}
+}
+
+func (c *CmdGitLFSTransfer) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, KbKeyring: true, API: true}
+}
@@ -0,0 +1,102 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+// CmdGitMirror pushes a keybase-hosted repo to an external remote
+// (GitHub, GitLab) by driving the system git through the keybase remote
+// helper: fetch from keybase://, push --mirror to the target. A local
+// bare cache under the keybase data dir keeps repeat mirrors
+// incremental.
+type CmdGitMirror struct {
+	libkb.Contextified
+	repo   string // keybase repo, e.g. keybase://team/acme/infra
+	remote string // external remote URL
+}
+
+func newCmdGitMirror(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "mirror",
+		ArgumentHelp: "<keybase repo url> <external remote url>",
+		Usage:        "Mirror a keybase-hosted repo to an external git remote",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdGitMirror{Contextified: libkb.NewContextified(g)}, "mirror", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+	}
+}
+
+func (c *CmdGitMirror) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return fmt.Errorf("mirror takes two arguments: the keybase repo URL and the external remote")
+	}
+	c.repo = ctx.Args()[0]
+	c.remote = ctx.Args()[1]
+	if !strings.HasPrefix(c.repo, "keybase://") {
+		return fmt.Errorf("first argument must be a keybase:// repo URL")
+	}
+	return nil
+}
+
+// cacheDir gives each mirrored repo a stable local bare clone.
+func (c *CmdGitMirror) cacheDir() string {
+	sanitized := strings.NewReplacer("/", "_", ":", "_").Replace(
+		strings.TrimPrefix(c.repo, "keybase://"))
+	return filepath.Join(c.G().Env.GetDataDir(), "git-mirror", sanitized)
+}
+
+func (c *CmdGitMirror) git(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (c *CmdGitMirror) Run() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("mirror needs the git binary on PATH: %s", err)
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	cache := c.cacheDir()
+	if _, err := os.Stat(cache); os.IsNotExist(err) {
+		ui.Printf("Cloning %s...\n", c.repo)
+		if err := os.MkdirAll(filepath.Dir(cache), 0700); err != nil {
+			return err
+		}
+		if err := c.git("", "clone", "--mirror", c.repo, cache); err != nil {
+			return fmt.Errorf("clone failed: %s", err)
+		}
+	} else {
+		ui.Printf("Fetching updates for %s...\n", c.repo)
+		if err := c.git(cache, "fetch", "--prune", "origin"); err != nil {
+			return fmt.Errorf("fetch failed: %s", err)
+		}
+	}
+
+	ui.Printf("Pushing to %s...\n", c.remote)
+	if err := c.git(cache, "push", "--mirror", c.remote); err != nil {
+		return fmt.Errorf("push to external remote failed: %s", err)
+	}
+	ui.Printf("Mirror complete.\n")
+	return nil
+}
+
+func (c *CmdGitMirror) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, KbKeyring: true, API: true}
+}
@@ -58,8 +58,12 @@ func (v *CmdID) Run() error {
 	protocols := []rpc.Protocol{}
 
 	// always register this, even if ui is delegated, so that
-	// fallback to terminal UI works.
-	protocols = append(protocols, NewIdentifyUIProtocol(v.G()))
+	// fallback to terminal UI works. In JSON mode leave it out: the
+	// envelope on stdout is the whole contract, and a missing UI
+	// surfaces as a hard error instead of interleaved text.
+	if !jsonOutputMode(v.G()) {
+		protocols = append(protocols, NewIdentifyUIProtocol(v.G()))
+	}
 	cli, err := GetIdentifyClient(v.G())
 	if err != nil {
 		return err
@@ -69,6 +73,25 @@ func (v *CmdID) Run() error {
 	}
 
 	arg := v.makeArg()
+	if jsonOutputMode(v.G()) {
+		// Machine-readable mode: run the identify without terminal UI
+		// chatter and emit the result envelope instead.
+		arg.IdentifyBehavior = keybase1.TLFIdentifyBehavior_CHAT_CLI
+		res, err := cli.Identify2(context.TODO(), arg)
+		if err != nil {
+			return err
+		}
+		type identified struct {
+			Username string   `json:"username"`
+			Uid      string   `json:"uid"`
+			Keys     []string `json:"keys"`
+		}
+		out := identified{Username: res.Upk.Username, Uid: res.Upk.Uid.String()}
+		for _, key := range res.Upk.DeviceKeys {
+			out.Keys = append(out.Keys, key.KID.String())
+		}
+		return printJSONResult(v.G(), "identify.v1", out)
+	}
 	_, err = cli.Identify2(context.TODO(), arg)
 	if _, ok := err.(libkb.SelfNotFoundError); ok {
 		msg := `Could not find UID or username for you on this device.
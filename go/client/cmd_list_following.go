@@ -126,6 +126,17 @@ func (s *CmdListTracking) Run() error {
 	if err != nil {
 		return err
 	}
+	if jsonOutputMode(s.G()) {
+		type followed struct {
+			Username string `json:"username"`
+			Uid      string `json:"uid"`
+		}
+		out := make([]followed, len(table))
+		for i, entry := range table {
+			out[i] = followed{Username: entry.Username, Uid: entry.Uid.String()}
+		}
+		return printJSONResult(s.G(), "user-summary-list.v1", out)
+	}
 	return displayTable(table, s.verbose, s.headers)
 }
 
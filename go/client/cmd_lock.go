@@ -0,0 +1,50 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+// CmdLock drops the service's in-memory secrets without logging out;
+// the counterpart of "keybase unlock".
+type CmdLock struct {
+	libkb.Contextified
+}
+
+func NewCmdLock(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "lock",
+		Usage: "Lock the local key store until the passphrase is entered again",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdLock{Contextified: libkb.NewContextified(g)}, "lock", c)
+		},
+	}
+}
+
+func (c *CmdLock) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return UnexpectedArgsError("lock")
+	}
+	return nil
+}
+
+func (c *CmdLock) Run() error {
+	cli, err := GetLoginClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err := cli.LockSession(context.Background(), 0); err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Locked. Run `keybase unlock` (or any key operation) to unlock.\n")
+	return nil
+}
+
+func (c *CmdLock) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
@@ -12,6 +12,8 @@ import (
 func NewCmdLog(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 	subcommands := []cli.Command{
 		NewCmdLogSend(cl, g),
+		NewCmdLogNetworkStats(cl, g),
+		NewCmdLogSetLevel(cl, g),
 	}
 	subcommands = append(subcommands, getBuildSpecificLogCommands(cl, g)...)
 	return cli.Command{
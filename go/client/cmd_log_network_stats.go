@@ -0,0 +1,69 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+// CmdLogNetworkStats dumps the service's cumulative per-endpoint API
+// byte and latency counters, so users (mobile especially) can see which
+// subsystems consume their data.
+type CmdLogNetworkStats struct {
+	libkb.Contextified
+}
+
+func NewCmdLogNetworkStats(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "network-stats",
+		Usage: "Show per-endpoint API network usage since the service started",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdLogNetworkStats{Contextified: libkb.NewContextified(g)}, "network-stats", c)
+		},
+	}
+}
+
+func (c *CmdLogNetworkStats) Run() error {
+	cli, err := GetLogClient(c.G())
+	if err != nil {
+		return err
+	}
+	stats, err := cli.NetworkStats(context.Background())
+	if err != nil {
+		return err
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	if len(stats) == 0 {
+		ui.Printf("No API requests recorded yet.\n")
+		return nil
+	}
+	ui.Printf("%-40s %8s %12s %10s %10s\n", "Endpoint", "Reqs", "Bytes in", "Total", "Avg")
+	for _, s := range stats {
+		total := time.Duration(s.TotalMs) * time.Millisecond
+		avg := total / time.Duration(s.Count)
+		ui.Printf("%-40s %8d %12d %10s %10s\n", s.Endpoint, s.Count, s.BytesIn, total, avg)
+	}
+	return nil
+}
+
+func (c *CmdLogNetworkStats) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("network-stats takes no arguments")
+	}
+	return nil
+}
+
+func (c *CmdLogNetworkStats) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    false,
+	}
+}
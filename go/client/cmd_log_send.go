@@ -42,6 +42,14 @@ func NewCmdLogSend(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comma
 				Name:  "no-confirm",
 				Usage: "Send logs without confirming",
 			},
+			cli.BoolFlag{
+				Name:  "bundle",
+				Usage: "Write an encrypted diagnostics archive locally instead of sending.",
+			},
+			cli.StringFlag{
+				Name:  "o, outfile",
+				Usage: "Where to write the diagnostics bundle.",
+			},
 		},
 	}
 }
@@ -51,9 +59,14 @@ type CmdLogSend struct {
 	numBytes  int
 	noConfirm bool
 	feedback  string
+	bundle    bool
+	outfile   string
 }
 
 func (c *CmdLogSend) Run() error {
+	if c.bundle {
+		return c.runBundle()
+	}
 
 	if !c.noConfirm {
 		if err := c.confirm(); err != nil {
@@ -204,6 +217,11 @@ func (c *CmdLogSend) ParseArgv(ctx *cli.Context) error {
 		return UnexpectedArgsError("log send")
 	}
 	c.noConfirm = ctx.Bool("no-confirm")
+	c.bundle = ctx.Bool("bundle")
+	c.outfile = ctx.String("outfile")
+	if c.outfile != "" && !c.bundle {
+		return fmt.Errorf("--outfile only makes sense with --bundle")
+	}
 	c.numBytes = ctx.Int("n")
 	if c.numBytes < 1 {
 		c.numBytes = defaultBytes
@@ -213,6 +231,31 @@ func (c *CmdLogSend) ParseArgv(ctx *cli.Context) error {
 	return nil
 }
 
+// runBundle asks the service for an encrypted diagnostics archive and
+// tells the user how to hand it (and the key, separately) to support.
+func (c *CmdLogSend) runBundle() error {
+	cli, err := GetLogClient(c.G())
+	if err != nil {
+		return err
+	}
+	outfile := c.outfile
+	if outfile == "" {
+		// Resolve the default in our cwd, not the service's.
+		outfile = fmt.Sprintf("keybase-diagnostics-%s.tgz.sealed", time.Now().Format("20060102-150405"))
+	}
+	if outfile, err = filepath.Abs(outfile); err != nil {
+		return err
+	}
+	res, err := cli.CreateDiagnosticsBundle(context.Background(), outfile)
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("Diagnostics bundle written to %s\n", res.Path)
+	dui.Printf("Decryption key (share separately from the bundle):\n  %s\n", res.Key)
+	return nil
+}
+
 func (c *CmdLogSend) GetUsage() libkb.Usage {
 	return libkb.Usage{
 		Config: true,
@@ -0,0 +1,68 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdLogSetLevel adjusts per-module log levels in the running service
+// without a restart, e.g. `keybase log set-level keybase=debug`.
+type CmdLogSetLevel struct {
+	libkb.Contextified
+	levels []keybase1.SetLevelArg
+}
+
+func NewCmdLogSetLevel(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "set-level",
+		ArgumentHelp: "<module>=<level> [...]",
+		Usage:        "Adjust log module levels in the running service",
+		Description:  "Levels: debug, info, notice, warning, error, critical.\nPairs well with --log-format=json for structured pipelines.",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdLogSetLevel{Contextified: libkb.NewContextified(g)}, "set-level", c)
+		},
+	}
+}
+
+func (c *CmdLogSetLevel) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 {
+		return errors.New("set-level requires at least one module=level pair")
+	}
+	for _, arg := range ctx.Args() {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return fmt.Errorf("bad argument %q: want module=level", arg)
+		}
+		c.levels = append(c.levels, keybase1.SetLevelArg{Module: kv[0], Level: kv[1]})
+	}
+	return nil
+}
+
+func (c *CmdLogSetLevel) Run() error {
+	cli, err := GetLogClient(c.G())
+	if err != nil {
+		return err
+	}
+	for _, arg := range c.levels {
+		if err := cli.SetLevel(context.Background(), arg); err != nil {
+			return fmt.Errorf("%s: %s", arg.Module, err)
+		}
+		c.G().UI.GetDumbOutputUI().Printf("%s -> %s\n", arg.Module, arg.Level)
+	}
+	return nil
+}
+
+func (c *CmdLogSetLevel) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true}
+}
@@ -18,6 +18,10 @@ func NewCmdPaperKey(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comm
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(NewCmdPaperKeyRunner(g), "paperkey", c)
 		},
+		Subcommands: []cli.Command{
+			NewCmdPaperKeyList(cl, g),
+			NewCmdPaperKeyRevoke(cl, g),
+		},
 	}
 }
 
@@ -0,0 +1,170 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// CmdPaperKeyList lists the account's paper keys with their creation
+// time, last use, and delegation position, so users can tell which of
+// several paper keys is which.
+type CmdPaperKeyList struct {
+	libkb.Contextified
+}
+
+func NewCmdPaperKeyList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "list",
+		Usage: "List your paper keys",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdPaperKeyList{Contextified: libkb.NewContextified(g)}, "list", c)
+		},
+	}
+}
+
+// paperKeyDevices filters the device inventory down to unrevoked paper
+// keys.
+func paperKeyDevices(g *libkb.GlobalContext) ([]keybase1.DeviceDetail, error) {
+	cli, err := GetDeviceClient(g)
+	if err != nil {
+		return nil, err
+	}
+	if err := RegisterProtocolsWithContext(nil, g); err != nil {
+		return nil, err
+	}
+	devs, err := cli.DeviceHistoryList(context.TODO(), 0)
+	if err != nil {
+		return nil, err
+	}
+	var ret []keybase1.DeviceDetail
+	for _, d := range devs {
+		if d.Device.Type == libkb.DeviceTypePaper && d.RevokedAt == nil {
+			ret = append(ret, d)
+		}
+	}
+	return ret, nil
+}
+
+func (c *CmdPaperKeyList) Run() error {
+	keys, err := paperKeyDevices(c.G())
+	if err != nil {
+		return err
+	}
+	ui := c.G().UI.GetTerminalUI()
+	if len(keys) == 0 {
+		ui.Printf("You have no active paper keys.\n")
+		return nil
+	}
+	w := GlobUI.DefaultTabWriter()
+	fmt.Fprintf(w, "Name\tCreated\tLast Used\tDelegated At Seqno\n")
+	for _, d := range keys {
+		fmt.Fprintf(w, "%s...\t%s\t%s\t%d\n",
+			d.Device.Name,
+			keybase1.FromTime(d.Device.CTime).Format("2006 Jan 2"),
+			keybase1.FromTime(d.Device.LastUsedTime).Format("2006 Jan 2"),
+			d.DelegationSeqno)
+	}
+	w.Flush()
+	ui.Printf("\nRevoke one with `keybase paperkey revoke <name prefix>`.\n")
+	return nil
+}
+
+func (c *CmdPaperKeyList) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("list takes no arguments")
+	}
+	return nil
+}
+
+func (c *CmdPaperKeyList) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
+
+// CmdPaperKeyRevoke revokes one specific paper key, identified by its
+// name prefix (the first words of the passphrase), instead of the
+// all-or-nothing revoke-paper-keys flow.
+type CmdPaperKeyRevoke struct {
+	libkb.Contextified
+	prefix string
+}
+
+func NewCmdPaperKeyRevoke(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "revoke",
+		Usage:        "Revoke one paper key by its name prefix",
+		ArgumentHelp: "<name prefix>",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdPaperKeyRevoke{Contextified: libkb.NewContextified(g)}, "revoke", c)
+		},
+	}
+}
+
+func (c *CmdPaperKeyRevoke) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 {
+		return fmt.Errorf("revoke needs a paper key name prefix")
+	}
+	c.prefix = strings.Join(ctx.Args(), " ")
+	return nil
+}
+
+func (c *CmdPaperKeyRevoke) Run() error {
+	keys, err := paperKeyDevices(c.G())
+	if err != nil {
+		return err
+	}
+
+	var matches []keybase1.DeviceDetail
+	for _, d := range keys {
+		if strings.HasPrefix(d.Device.Name, c.prefix) {
+			matches = append(matches, d)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no paper key matches prefix %q; see `keybase paperkey list`", c.prefix)
+	case 1:
+	default:
+		return fmt.Errorf("%d paper keys match prefix %q; use more words", len(matches), c.prefix)
+	}
+
+	target := matches[0]
+	ui := c.G().UI.GetTerminalUI()
+	ok, err := ui.PromptYesNo(PromptDescriptorDeviceRevoke,
+		fmt.Sprintf("Revoke paper key %q (created %s)?", target.Device.Name,
+			keybase1.FromTime(target.Device.CTime).Format("2006 Jan 2")), libkb.PromptDefaultNo)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	rm := &CmdDeviceRemove{Contextified: libkb.NewContextified(c.G())}
+	rm.SetIDOrName(target.Device.DeviceID.String())
+	if err := rm.Run(); err != nil {
+		return err
+	}
+	ui.Printf("Revoked paper key %q.\n", target.Device.Name)
+	return nil
+}
+
+func (c *CmdPaperKeyRevoke) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
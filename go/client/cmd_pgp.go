@@ -18,6 +18,7 @@ func NewCmdPGP(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 			NewCmdPGPGen(cl, g),
 			NewCmdPGPPull(cl, g),
 			NewCmdPGPUpdate(cl, g),
+			NewCmdPGPSubkey(cl, g),
 			NewCmdPGPSelect(cl, g),
 			NewCmdPGPSign(cl, g),
 			NewCmdPGPEncrypt(cl, g),
@@ -0,0 +1,89 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/engine"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+)
+
+// CmdPGPSubkey adds or revokes encryption subkeys on a PGP key in the
+// keybase keyring. The engines run in-process, so this command wants
+// --standalone (the key material never transits the service socket).
+type CmdPGPSubkey struct {
+	libkb.Contextified
+	op          engine.PGPSubkeyOp
+	keyIDSuffix string
+}
+
+func NewCmdPGPSubkey(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "subkey",
+		Usage: "Manage subkeys of your PGP key",
+		Subcommands: []cli.Command{
+			{
+				Name:  "add",
+				Usage: "Add a new encryption subkey and push a pgp_update link",
+				Action: func(c *cli.Context) {
+					cl.ChooseCommand(&CmdPGPSubkey{
+						Contextified: libkb.NewContextified(g),
+						op:           engine.PGPSubkeyOpAdd,
+					}, "add", c)
+					cl.SetForkCmd(libcmdline.NoFork)
+				},
+			},
+			{
+				Name:         "revoke",
+				Usage:        "Revoke a subkey by key ID suffix and push a pgp_update link",
+				ArgumentHelp: "<key-id-suffix>",
+				Action: func(c *cli.Context) {
+					cmd := &CmdPGPSubkey{
+						Contextified: libkb.NewContextified(g),
+						op:           engine.PGPSubkeyOpRevoke,
+					}
+					if len(c.Args()) > 0 {
+						cmd.keyIDSuffix = c.Args()[0]
+					}
+					cl.ChooseCommand(cmd, "revoke", c)
+					cl.SetForkCmd(libcmdline.NoFork)
+				},
+			},
+		},
+	}
+}
+
+func (c *CmdPGPSubkey) ParseArgv(ctx *cli.Context) error {
+	if c.op == engine.PGPSubkeyOpRevoke && c.keyIDSuffix == "" {
+		return fmt.Errorf("revoke needs a subkey key ID (suffix); see `keybase pgp list`")
+	}
+	return nil
+}
+
+func (c *CmdPGPSubkey) Run() error {
+	if !c.G().Standalone {
+		return fmt.Errorf("pgp subkey requires --standalone, since the secret key is handled in this process")
+	}
+	eng := engine.NewPGPSubkeyEngine(c.G(), engine.PGPSubkeyEngineArg{
+		Op:          c.op,
+		KeyIDSuffix: c.keyIDSuffix,
+	})
+	uis := libkb.UIs{
+		LogUI:    c.G().UI.GetLogUI(),
+		SecretUI: c.G().UI.GetSecretUI(),
+	}
+	m := libkb.NewMetaContextTODO(c.G()).WithUIs(uis)
+	return engine.RunEngine2(m, eng)
+}
+
+func (c *CmdPGPSubkey) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// NewCmdPolicy is the admin tooling for signed enterprise policy
+// documents: generate a signing key, sign a policy payload, and
+// verify what a client would enforce.
+func NewCmdPolicy(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "policy",
+		Usage: "Manage signed enterprise policy documents",
+		Subcommands: []cli.Command{
+			newCmdPolicyKeygen(cl, g),
+			newCmdPolicySign(cl, g),
+			newCmdPolicyVerify(cl, g),
+		},
+	}
+}
+
+type cmdPolicyKeygen struct {
+	libkb.Contextified
+}
+
+func newCmdPolicyKeygen(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "keygen",
+		Usage: "Generate an admin policy signing key",
+		Description: `Prints the secret seed (keep it offline) and the public KID to pin
+on every fleet machine as policy.admin_kid.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdPolicyKeygen{Contextified: libkb.NewContextified(g)}, "keygen", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+	}
+}
+
+func (c *cmdPolicyKeygen) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("keygen takes no arguments")
+	}
+	return nil
+}
+
+func (c *cmdPolicyKeygen) Run() error {
+	key, err := libkb.GenerateNaclSigningKeyPair()
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("secret seed: %s\n", hex.EncodeToString((*key.Private)[:32]))
+	dui.Printf("admin kid:   %s\n", key.GetKID())
+	dui.PrintfStderr("Pin the kid in each client's config as policy.admin_kid; keep the seed offline.\n")
+	return nil
+}
+
+func (c *cmdPolicyKeygen) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
+
+type cmdPolicySign struct {
+	libkb.Contextified
+	seedHex string
+	in, out string
+}
+
+func newCmdPolicySign(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "sign",
+		ArgumentHelp: "<policy.json>",
+		Usage:        "Sign a policy payload with the admin key",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdPolicySign{Contextified: libkb.NewContextified(g)}, "sign", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "seed",
+				Usage: "Hex admin secret seed from `keybase policy keygen` [required].",
+			},
+			cli.StringFlag{
+				Name:  "o, outfile",
+				Usage: "Where to write the signed envelope (default: stdout).",
+			},
+		},
+	}
+}
+
+func (c *cmdPolicySign) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("sign requires the policy payload file")
+	}
+	c.in = ctx.Args()[0]
+	c.seedHex = ctx.String("seed")
+	if c.seedHex == "" {
+		return errors.New("sign requires --seed")
+	}
+	c.out = ctx.String("outfile")
+	return nil
+}
+
+func (c *cmdPolicySign) Run() error {
+	payload, err := ioutil.ReadFile(c.in)
+	if err != nil {
+		return err
+	}
+	// Round-trip through the struct so malformed payloads fail at
+	// signing time, not on every fleet machine.
+	var policy libkb.EnterprisePolicy
+	if err := json.Unmarshal(payload, &policy); err != nil {
+		return fmt.Errorf("payload is not a valid policy: %s", err)
+	}
+
+	seed, err := hex.DecodeString(c.seedHex)
+	if err != nil {
+		return fmt.Errorf("bad --seed: %s", err)
+	}
+	key, err := libkb.MakeNaclSigningKeyPairFromSecretBytes(seed)
+	if err != nil {
+		return err
+	}
+	sig := key.Private.Sign(payload)
+	envelope, err := json.MarshalIndent(map[string]string{
+		"payload": base64.StdEncoding.EncodeToString(payload),
+		"sig":     base64.StdEncoding.EncodeToString(sig[:]),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if c.out == "" {
+		c.G().UI.GetDumbOutputUI().Printf("%s\n", envelope)
+		return nil
+	}
+	if err := ioutil.WriteFile(c.out, append(envelope, '\n'), 0644); err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Signed policy written to %s (admin kid %s)\n", c.out, key.GetKID())
+	return nil
+}
+
+func (c *cmdPolicySign) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
+
+type cmdPolicyVerify struct {
+	libkb.Contextified
+	file string
+	kid  string
+}
+
+func newCmdPolicyVerify(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "verify",
+		ArgumentHelp: "<signed-policy.json>",
+		Usage:        "Verify a signed policy as a fleet client would",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdPolicyVerify{Contextified: libkb.NewContextified(g)}, "verify", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "kid",
+				Usage: "Admin KID to verify against (default: policy.admin_kid from config).",
+			},
+		},
+	}
+}
+
+func (c *cmdPolicyVerify) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("verify requires the signed policy file")
+	}
+	c.file = ctx.Args()[0]
+	c.kid = ctx.String("kid")
+	return nil
+}
+
+func (c *cmdPolicyVerify) Run() error {
+	kid := c.kid
+	if kid == "" {
+		kid, _ = c.G().Env.GetConfig().GetStringAtPath("policy.admin_kid")
+	}
+	if kid == "" {
+		return errors.New("no admin kid: pass --kid or set policy.admin_kid")
+	}
+	raw, err := ioutil.ReadFile(c.file)
+	if err != nil {
+		return err
+	}
+	policy, err := libkb.VerifyEnterprisePolicy(raw, keybase1.KIDFromString(kid))
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(policy, "", "  ")
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("Signature verifies against %s\n", kid)
+	dui.Printf("%s\n", out)
+	return nil
+}
+
+func (c *cmdPolicyVerify) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true}
+}
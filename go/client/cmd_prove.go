@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -21,8 +22,11 @@ import (
 // CmdProve is the wrapper structure for the `keybase prove` operation.
 type CmdProve struct {
 	libkb.Contextified
-	arg    keybase1.StartProofArg
-	output string
+	arg        keybase1.StartProofArg
+	output     string
+	oauth      bool
+	oauthCfg   oauth2ServiceConfig
+	oauthToken string
 }
 
 // ParseArgv parses arguments for the prove command.
@@ -30,6 +34,7 @@ func (p *CmdProve) ParseArgv(ctx *cli.Context) error {
 	nargs := len(ctx.Args())
 	p.arg.Force = ctx.Bool("force")
 	p.output = ctx.String("output")
+	p.oauth = ctx.Bool("oauth")
 
 	if nargs > 2 || nargs == 0 {
 		return fmt.Errorf("prove takes 1 or 2 args: <service> [<username>]")
@@ -78,6 +83,18 @@ func (p *CmdProve) Run() error {
 		NewSecretUIProtocol(p.G()),
 	}
 
+	if p.oauth {
+		cfg, ok := oauth2ConfigForService(p.G(), p.arg.Service)
+		if !ok {
+			return fmt.Errorf("service %q declares no OAuth2 endpoints (prove.oauth.%s)", p.arg.Service, p.arg.Service)
+		}
+		token, err := runOAuth2Flow(p.G(), cfg, 5*time.Minute)
+		if err != nil {
+			return err
+		}
+		p.oauthCfg, p.oauthToken = cfg, token
+	}
+
 	cli, err := GetProveClient(p.G())
 	if err != nil {
 		return err
@@ -94,6 +111,17 @@ func (p *CmdProve) Run() error {
 }
 
 func (p *CmdProve) installOutputHook(ui *ProveUI) {
+	if p.oauthToken != "" {
+		ui.outputHook = func(s string) error {
+			p.G().Log.Info("Posting proof through the provider's API...")
+			if err := postProofViaProvider(p.oauthCfg, p.oauthToken, s); err != nil {
+				return err
+			}
+			p.G().Log.Info("Posted.")
+			return nil
+		}
+		return
+	}
 	if len(p.output) > 0 {
 		ui.outputHook = func(s string) error {
 			return p.fileOutputHook(s)
@@ -119,6 +147,10 @@ func NewCmdProve(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 				Name:  "force, f",
 				Usage: "Don't prompt.",
 			},
+			cli.BoolFlag{
+				Name:  "oauth",
+				Usage: "Authorize via the service's OAuth2 flow and post the proof through its API (for services declaring prove.oauth config).",
+			},
 		},
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(&CmdProve{Contextified: libkb.NewContextified(g)}, "prove", c)
@@ -0,0 +1,94 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdRemoteSign signs a payload through the configured remote signer:
+// the keyless half of the remote-signer mode. This process holds no
+// private key; the signature comes back over the
+// keybase.1.remoteSigner protocol from whatever machine runs
+// `keybase service` with the key and approves the request.
+type CmdRemoteSign struct {
+	libkb.Contextified
+	kid  string
+	file string
+}
+
+func NewCmdRemoteSign(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "remote-sign",
+		ArgumentHelp: "<file>",
+		Usage:        "Sign a payload via the configured remote signer (keyless client)",
+		Description: `Requires signing.remote_signer_addr in config (or
+KEYBASE_REMOTE_SIGNER_ADDR) pointing at a service running the signer
+side, and the KID of the signing key to front for.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdRemoteSign{Contextified: libkb.NewContextified(g)}, "remote-sign", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "kid",
+				Usage: "KID of the remote signing key (default: signing.remote_signer_kid from config).",
+			},
+		},
+	}
+}
+
+func (c *CmdRemoteSign) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("remote-sign requires one file argument (- for stdin)")
+	}
+	c.file = ctx.Args()[0]
+	c.kid = ctx.String("kid")
+	return nil
+}
+
+func (c *CmdRemoteSign) Run() error {
+	kid := c.kid
+	if kid == "" {
+		kid, _ = c.G().Env.GetConfig().GetStringAtPath("signing.remote_signer_kid")
+	}
+	if kid == "" {
+		return errors.New("no signer KID: pass --kid or set signing.remote_signer_kid")
+	}
+
+	var payload []byte
+	var err error
+	if c.file == "-" {
+		payload, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		payload, err = ioutil.ReadFile(c.file)
+	}
+	if err != nil {
+		return err
+	}
+
+	key, err := libkb.NewRemoteSignerKey(c.G(), keybase1.KIDFromString(kid))
+	if err != nil {
+		return err
+	}
+	sig, sigID, err := key.SignToString(payload)
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("%s\n", sig)
+	dui.PrintfStderr("sig id: %s\n", sigID)
+	return nil
+}
+
+func (c *CmdRemoteSign) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true}
+}
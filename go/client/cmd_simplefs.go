@@ -31,6 +31,9 @@ func NewCmdSimpleFS(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comm
 		ArgumentHelp: "[arguments...]",
 		Subcommands: []cli.Command{
 			NewCmdSimpleFSList(cl, g),
+			NewCmdSimpleFSArchiveList(cl, g),
+			NewCmdSimpleFSServeWebdav(cl, g),
+			NewCmdSimpleFSWatch(cl, g),
 			NewCmdSimpleFSCopy(cl, g),
 			NewCmdSimpleFSMove(cl, g),
 			NewCmdSimpleFSRead(cl, g),
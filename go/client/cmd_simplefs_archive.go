@@ -0,0 +1,108 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdSimpleFSArchiveList exposes a read-only view of a folder at a
+// historical revision through the archived-revision machinery, so users
+// can inspect and recover old states without mounting anything.
+type CmdSimpleFSArchiveList struct {
+	libkb.Contextified
+	path keybase1.Path
+	rev  int
+}
+
+func NewCmdSimpleFSArchiveList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "archive-ls",
+		ArgumentHelp: "<path>",
+		Usage:        "List a KBFS folder as of a historical revision",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSArchiveList{Contextified: libkb.NewContextified(g)}, "archive-ls", c)
+		},
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "rev",
+				Usage: "Historical revision number [required].",
+			},
+		},
+	}
+}
+
+func (c *CmdSimpleFSArchiveList) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("archive-ls takes one argument: the KBFS path")
+	}
+	c.rev = ctx.Int("rev")
+	if c.rev <= 0 {
+		return fmt.Errorf("archive-ls needs --rev=<revision>")
+	}
+	kbfsPath := ctx.Args()[0]
+	if !strings.HasPrefix(kbfsPath, "/keybase/") {
+		return fmt.Errorf("path must start with /keybase/")
+	}
+	c.path = keybase1.NewPathWithKbfsArchived(keybase1.KBFSArchivedPath{
+		Path: strings.TrimPrefix(kbfsPath, "/keybase"),
+		ArchivedParam: keybase1.NewKBFSArchivedParamWithRevision(
+			keybase1.KBFSRevision(c.rev)),
+	})
+	return nil
+}
+
+func (c *CmdSimpleFSArchiveList) Run() error {
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	opid, err := cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.SimpleFSClose(ctx, opid)
+
+	if err := cli.SimpleFSList(ctx, keybase1.SimpleFSListArg{
+		OpID: opid,
+		Path: c.path,
+	}); err != nil {
+		return err
+	}
+	if err := cli.SimpleFSWait(ctx, opid); err != nil {
+		return err
+	}
+
+	ui := c.G().UI.GetTerminalUI()
+	for {
+		res, err := cli.SimpleFSReadList(ctx, opid)
+		if err != nil {
+			break
+		}
+		if len(res.Entries) == 0 {
+			break
+		}
+		for _, e := range res.Entries {
+			ui.Printf("%s\t%d\t%s\n", e.Name, e.Size, e.DirentType)
+		}
+		if res.Progress == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+func (c *CmdSimpleFSArchiveList) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, KbKeyring: true, API: true}
+}
@@ -0,0 +1,219 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdSimpleFSServeWebdav bridges a KBFS path onto a local read-only
+// WebDAV endpoint, so backup tools and deployers that speak WebDAV can
+// target KBFS without FUSE. Requests authenticate with a bearer token;
+// the listener binds loopback by default.
+type CmdSimpleFSServeWebdav struct {
+	libkb.Contextified
+	kbfsPath string
+	addr     string
+	token    string
+}
+
+func NewCmdSimpleFSServeWebdav(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "serve-webdav",
+		ArgumentHelp: "<kbfs path>",
+		Usage:        "Serve a KBFS path over read-only WebDAV",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSimpleFSServeWebdav{Contextified: libkb.NewContextified(g)}, "serve-webdav", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "addr",
+				Usage: "Listen address.",
+				Value: "127.0.0.1:8334",
+			},
+			cli.StringFlag{
+				Name:  "token",
+				Usage: "Bearer token clients must present [required].",
+			},
+		},
+	}
+}
+
+func (c *CmdSimpleFSServeWebdav) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("serve-webdav takes one argument: the KBFS path to serve")
+	}
+	c.kbfsPath = ctx.Args()[0]
+	if !strings.HasPrefix(c.kbfsPath, "/keybase/") {
+		return fmt.Errorf("path must start with /keybase/")
+	}
+	c.addr = ctx.String("addr")
+	c.token = ctx.String("token")
+	if c.token == "" {
+		return fmt.Errorf("serve-webdav requires --token")
+	}
+	return nil
+}
+
+type webdavHandler struct {
+	g     *libkb.GlobalContext
+	cli   keybase1.SimpleFSClient
+	root  string // "/team/acme/docs" (without /keybase)
+	token string
+}
+
+func (h *webdavHandler) kbPath(requestPath string) keybase1.Path {
+	full := path.Join(h.root, path.Clean("/"+requestPath))
+	return keybase1.NewPathWithKbfs(full)
+}
+
+func (h *webdavHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+h.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "bad token", http.StatusUnauthorized)
+		return
+	}
+	ctx := r.Context()
+	switch r.Method {
+	case "OPTIONS":
+		w.Header().Set("DAV", "1")
+		w.Header().Set("Allow", "OPTIONS, PROPFIND, GET, HEAD")
+	case "PROPFIND":
+		h.propfind(ctx, w, r)
+	case "GET", "HEAD":
+		h.get(ctx, w, r)
+	default:
+		http.Error(w, "read-only bridge", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *webdavHandler) propfind(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	opid, err := h.cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer h.cli.SimpleFSClose(ctx, opid)
+
+	if err := h.cli.SimpleFSList(ctx, keybase1.SimpleFSListArg{
+		OpID: opid,
+		Path: h.kbPath(r.URL.Path),
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := h.cli.SimpleFSWait(ctx, opid); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	listing, err := h.cli.SimpleFSReadList(ctx, opid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(207) // Multi-Status
+	fmt.Fprintf(w, "<?xml version=\"1.0\"?>\n<D:multistatus xmlns:D=\"DAV:\">\n")
+	writeEntry := func(href string, isDir bool, size int64) {
+		fmt.Fprintf(w, "<D:response><D:href>%s</D:href><D:propstat><D:prop>", href)
+		if isDir {
+			fmt.Fprintf(w, "<D:resourcetype><D:collection/></D:resourcetype>")
+		} else {
+			fmt.Fprintf(w, "<D:resourcetype/><D:getcontentlength>%d</D:getcontentlength>", size)
+		}
+		fmt.Fprintf(w, "</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>\n")
+	}
+	base := strings.TrimSuffix(r.URL.Path, "/")
+	writeEntry(base+"/", true, 0)
+	for _, e := range listing.Entries {
+		isDir := e.DirentType == keybase1.DirentType_DIR
+		href := base + "/" + e.Name
+		if isDir {
+			href += "/"
+		}
+		writeEntry(href, isDir, int64(e.Size))
+	}
+	fmt.Fprintf(w, "</D:multistatus>\n")
+}
+
+func (h *webdavHandler) get(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	opid, err := h.cli.SimpleFSMakeOpid(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer h.cli.SimpleFSClose(ctx, opid)
+
+	if err := h.cli.SimpleFSOpen(ctx, keybase1.SimpleFSOpenArg{
+		OpID:  opid,
+		Dest:  h.kbPath(r.URL.Path),
+		Flags: keybase1.OpenFlags_READ | keybase1.OpenFlags_EXISTING,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if r.Method == "HEAD" {
+		return
+	}
+	const chunk = 1 << 20
+	var offset int64
+	for {
+		content, err := h.cli.SimpleFSRead(ctx, keybase1.SimpleFSReadArg{
+			OpID:   opid,
+			Offset: offset,
+			Size:   chunk,
+		})
+		if err != nil {
+			return
+		}
+		if len(content.Data) == 0 {
+			return
+		}
+		if _, err := w.Write(content.Data); err != nil {
+			return
+		}
+		offset += int64(len(content.Data))
+	}
+}
+
+func (c *CmdSimpleFSServeWebdav) Run() error {
+	cli, err := GetSimpleFSClient(c.G())
+	if err != nil {
+		return err
+	}
+
+	handler := &webdavHandler{
+		g:     c.G(),
+		cli:   cli,
+		root:  strings.TrimPrefix(c.kbfsPath, "/keybase"),
+		token: c.token,
+	}
+
+	ln, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetTerminalUI().Printf("Serving %s read-only over WebDAV at http://%s/ (bearer token auth)\n",
+		c.kbfsPath, ln.Addr())
+	return http.Serve(ln, handler)
+}
+
+func (c *CmdSimpleFSServeWebdav) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, KbKeyring: true, API: true}
+}
@@ -15,6 +15,12 @@ func NewCmdTeam(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 		Usage:        "Manage teams",
 		ArgumentHelp: "[arguments...]",
 		Subcommands: []cli.Command{
+			newCmdTeamAudit(cl, g),
+			NewCmdTeamInviteLink(cl, g),
+			newCmdTeamPendingChanges(cl, g),
+			newCmdTeamSyncMembers(cl, g),
+			newCmdTeamKV(cl, g),
+			newCmdTeamExport(cl, g),
 			newCmdTeamCreate(cl, g),
 			newCmdTeamAddMember(cl, g),
 			newCmdTeamRemoveMember(cl, g),
@@ -6,6 +6,9 @@ package client
 import (
 	"context"
 	"errors"
+	"time"
+
+	"github.com/keybase/client/go/teams"
 
 	"github.com/keybase/cli"
 	"github.com/keybase/client/go/libcmdline"
@@ -20,6 +23,7 @@ type CmdTeamAddMember struct {
 	Username             string
 	Role                 keybase1.TeamRole
 	SkipChatNotification bool
+	ExpiresIn            time.Duration
 }
 
 func newCmdTeamAddMember(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
@@ -48,6 +52,10 @@ func newCmdTeamAddMember(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli
 				Name:  "s, skip-chat-message",
 				Usage: "skip chat welcome message",
 			},
+			cli.DurationFlag{
+				Name:  "expires-in",
+				Usage: "time-box the membership (e.g. 720h); this device removes the member when it lapses",
+			},
 		},
 		Description: teamAddMemberDoc,
 	}
@@ -69,11 +77,16 @@ func (c *CmdTeamAddMember) ParseArgv(ctx *cli.Context) error {
 		return err
 	}
 
+	c.ExpiresIn = ctx.Duration("expires-in")
+
 	c.Email = ctx.String("email")
 	if len(c.Email) > 0 {
 		if !libkb.CheckEmail.F(c.Email) {
 			return errors.New("invalid email address")
 		}
+		if c.ExpiresIn != 0 {
+			return errors.New("--expires-in requires a username member (not an email invite)")
+		}
 		return nil
 	}
 
@@ -107,6 +120,15 @@ func (c *CmdTeamAddMember) Run() error {
 	}
 
 	dui := c.G().UI.GetDumbOutputUI()
+	if c.ExpiresIn != 0 {
+		expiresAt := time.Now().Add(c.ExpiresIn)
+		if eerr := teams.RecordMemberExpiry(c.G(), c.Team, c.Username, expiresAt); eerr != nil {
+			dui.Printf("Warning: member added but expiry not recorded: %s\n", eerr)
+		} else {
+			dui.Printf("Membership expires %s; this device will remove them then.\n",
+				expiresAt.Format("2006-01-02 15:04"))
+		}
+	}
 	if !res.Invited {
 		// TeamAddMember resulted in the user added to the team
 		if res.ChatSending {
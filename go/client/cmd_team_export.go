@@ -0,0 +1,171 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/client/go/teams"
+)
+
+// CmdTeamExport emits a team's history as machine-readable JSON for
+// compliance reporting: every membership change with chain position and
+// time, key rotations, and subteam events, straight from the replayed
+// (verified) sigchain state.
+type CmdTeamExport struct {
+	libkb.Contextified
+	team    string
+	outfile string
+}
+
+func newCmdTeamExport(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "export",
+		ArgumentHelp: "<team name>",
+		Usage:        "Export team structure and membership history as JSON",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdTeamExport{Contextified: libkb.NewContextified(g)}, "export", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "o, outfile",
+				Usage: "Write the export here rather than stdout.",
+			},
+		},
+	}
+}
+
+func (c *CmdTeamExport) ParseArgv(ctx *cli.Context) error {
+	var err error
+	if c.team, err = ParseOneTeamName(ctx); err != nil {
+		return err
+	}
+	c.outfile = ctx.String("outfile")
+	return nil
+}
+
+type teamExportEvent struct {
+	Seqno    keybase1.Seqno `json:"seqno"`
+	At       time.Time      `json:"at,omitempty"`
+	Type     string         `json:"type"`
+	Username string         `json:"username,omitempty"`
+	Role     string         `json:"role,omitempty"`
+	Subteam  string         `json:"subteam,omitempty"`
+	KeyGen   int            `json:"key_generation,omitempty"`
+}
+
+type teamExport struct {
+	Team       string            `json:"team"`
+	TeamID     keybase1.TeamID   `json:"team_id"`
+	ExportedAt time.Time         `json:"exported_at"`
+	HeadSeqno  keybase1.Seqno    `json:"head_seqno"`
+	Events     []teamExportEvent `json:"events"`
+}
+
+func (c *CmdTeamExport) Run() error {
+	if !c.G().Standalone {
+		return fmt.Errorf("team export requires --standalone for a verified in-process replay")
+	}
+	ctx := context.Background()
+
+	team, err := teams.Load(ctx, c.G(), keybase1.LoadTeamArg{
+		Name:            c.team,
+		ForceFullReload: true,
+		ForceRepoll:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	inner := team.Data.Chain
+	export := teamExport{
+		Team:       c.team,
+		TeamID:     team.ID,
+		ExportedAt: time.Now(),
+		HeadSeqno:  team.CurrentSeqno(),
+	}
+
+	// Membership history, one event per chain role change.
+	for uv, points := range inner.UserLog {
+		username, uerr := c.G().GetUPAKLoader().LookupUsername(ctx, uv.Uid)
+		name := uv.Uid.String()
+		if uerr == nil {
+			name = username.String()
+		}
+		for i, point := range points {
+			typ := "role-change"
+			switch {
+			case point.Role == keybase1.TeamRole_NONE:
+				typ = "remove"
+			case i == 0:
+				typ = "add"
+			}
+			export.Events = append(export.Events, teamExportEvent{
+				Seqno:    point.SigMeta.SigChainLocation.Seqno,
+				At:       keybase1.FromTime(point.SigMeta.Time),
+				Type:     typ,
+				Username: name,
+				Role:     point.Role.String(),
+			})
+		}
+	}
+
+	// Key rotations.
+	for gen, key := range inner.PerTeamKeys {
+		export.Events = append(export.Events, teamExportEvent{
+			Seqno:  key.Seqno,
+			Type:   "key-rotation",
+			KeyGen: int(gen),
+		})
+	}
+
+	// Subteam creations and renames.
+	for _, points := range inner.SubteamLog {
+		for i, point := range points {
+			typ := "subteam-rename"
+			if i == 0 {
+				typ = "subteam-create"
+			}
+			export.Events = append(export.Events, teamExportEvent{
+				Seqno:   point.Seqno,
+				Type:    typ,
+				Subteam: point.Name.String(),
+			})
+		}
+	}
+
+	sort.Slice(export.Events, func(i, j int) bool {
+		return export.Events[i].Seqno < export.Events[j].Seqno
+	})
+
+	blob, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	if c.outfile != "" {
+		return ioutil.WriteFile(c.outfile, blob, os.FileMode(0600))
+	}
+	_, err = c.G().UI.GetTerminalUI().OutputWriter().Write(append(blob, '\n'))
+	return err
+}
+
+func (c *CmdTeamExport) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
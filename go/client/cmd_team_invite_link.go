@@ -0,0 +1,214 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// NewCmdTeamInviteLink manages multi-use invite links backed by
+// batches of seitan tokens.
+func NewCmdTeamInviteLink(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "invite-link",
+		Usage: "Manage multi-use team invite links",
+		Subcommands: []cli.Command{
+			newCmdTeamInviteLinkCreate(cl, g),
+			newCmdTeamInviteLinkList(cl, g),
+			newCmdTeamInviteLinkRevoke(cl, g),
+		},
+	}
+}
+
+type cmdTeamInviteLinkCreate struct {
+	libkb.Contextified
+	team    string
+	handle  string
+	role    keybase1.TeamRole
+	maxUses int
+	expires time.Duration
+}
+
+func newCmdTeamInviteLinkCreate(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "create",
+		ArgumentHelp: "<team> <handle>",
+		Usage:        "Create an invite link",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdTeamInviteLinkCreate{Contextified: libkb.NewContextified(g)}, "create", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "role",
+				Value: "reader",
+				Usage: "Role joiners get (reader, writer).",
+			},
+			cli.IntFlag{
+				Name:  "max-uses",
+				Value: 10,
+				Usage: "How many joins the link allows.",
+			},
+			cli.DurationFlag{
+				Name:  "expires-in",
+				Value: 7 * 24 * time.Hour,
+				Usage: "How long until the link expires.",
+			},
+		},
+	}
+}
+
+func (c *cmdTeamInviteLinkCreate) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return errors.New("create requires a team and a handle argument")
+	}
+	c.team = ctx.Args().Get(0)
+	c.handle = ctx.Args().Get(1)
+	role, ok := keybase1.TeamRoleMap[strings.ToUpper(ctx.String("role"))]
+	if !ok {
+		return fmt.Errorf("unknown role %q", ctx.String("role"))
+	}
+	c.role = role
+	c.maxUses = ctx.Int("max-uses")
+	c.expires = ctx.Duration("expires-in")
+	return nil
+}
+
+func (c *cmdTeamInviteLinkCreate) Run() error {
+	cli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	link, err := cli.TeamCreateInviteLink(context.Background(), keybase1.TeamCreateInviteLinkArg{
+		Name:      c.team,
+		Handle:    c.handle,
+		Role:      c.role,
+		MaxUses:   c.maxUses,
+		ExpiresAt: keybase1.ToTime(time.Now().Add(c.expires)),
+	})
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("Created invite link %q for %s: %d use(s), expires %s\n",
+		link.Handle, c.team, link.RemainingUses,
+		keybase1.FromTime(link.ExpiresAt).Format("2006-01-02 15:04"))
+	return nil
+}
+
+func (c *cmdTeamInviteLinkCreate) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
+
+type cmdTeamInviteLinkList struct {
+	libkb.Contextified
+	team string
+}
+
+func newCmdTeamInviteLinkList(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "list",
+		ArgumentHelp: "<team>",
+		Usage:        "List a team's invite links",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdTeamInviteLinkList{Contextified: libkb.NewContextified(g)}, "list", c)
+		},
+	}
+}
+
+func (c *cmdTeamInviteLinkList) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("list requires a team argument")
+	}
+	c.team = ctx.Args().Get(0)
+	return nil
+}
+
+func (c *cmdTeamInviteLinkList) Run() error {
+	cli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	links, err := cli.TeamListInviteLinks(context.Background(), keybase1.TeamListInviteLinksArg{
+		Name: c.team,
+	})
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(links) == 0 {
+		dui.Printf("No invite links for %s.\n", c.team)
+		return nil
+	}
+	for _, link := range links {
+		dui.Printf("%s\t%s\t%d use(s) left\texpires %s\tjoined: %s\n",
+			link.Handle, link.Role, link.RemainingUses,
+			keybase1.FromTime(link.ExpiresAt).Format("2006-01-02"),
+			strings.Join(link.JoinedVia, ","))
+	}
+	return nil
+}
+
+func (c *cmdTeamInviteLinkList) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
+
+type cmdTeamInviteLinkRevoke struct {
+	libkb.Contextified
+	team   string
+	handle string
+}
+
+func newCmdTeamInviteLinkRevoke(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "revoke",
+		ArgumentHelp: "<team> <handle>",
+		Usage:        "Revoke an invite link and report who joined through it",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdTeamInviteLinkRevoke{Contextified: libkb.NewContextified(g)}, "revoke", c)
+		},
+	}
+}
+
+func (c *cmdTeamInviteLinkRevoke) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return errors.New("revoke requires a team and a handle argument")
+	}
+	c.team = ctx.Args().Get(0)
+	c.handle = ctx.Args().Get(1)
+	return nil
+}
+
+func (c *cmdTeamInviteLinkRevoke) Run() error {
+	cli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	joined, err := cli.TeamRevokeInviteLink(context.Background(), keybase1.TeamRevokeInviteLinkArg{
+		Name:   c.team,
+		Handle: c.handle,
+	})
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("Revoked invite link %q.\n", c.handle)
+	if len(joined) > 0 {
+		dui.Printf("Joined through it: %s\n", strings.Join(joined, ", "))
+	}
+	return nil
+}
+
+func (c *cmdTeamInviteLinkRevoke) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/teamkv"
+)
+
+// CmdTeamKV drives the team-scoped encrypted key-value store. Runs
+// standalone, since values are boxed and unboxed in this process.
+type CmdTeamKV struct {
+	libkb.Contextified
+	op       string
+	team     string
+	key      string
+	value    string
+	revision int
+}
+
+func newCmdTeamKV(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	sub := func(op, usage, argHelp string) cli.Command {
+		return cli.Command{
+			Name:         op,
+			Usage:        usage,
+			ArgumentHelp: argHelp,
+			Action: func(c *cli.Context) {
+				cmd := &CmdTeamKV{Contextified: libkb.NewContextified(g), op: op}
+				cl.ChooseCommand(cmd, op, c)
+				cl.SetForkCmd(libcmdline.NoFork)
+			},
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "revision",
+					Usage: "compare-and-swap against this revision (put/del)",
+				},
+			},
+		}
+	}
+	return cli.Command{
+		Name:  "kv",
+		Usage: "Team-scoped encrypted key-value store",
+		Subcommands: []cli.Command{
+			sub("get", "Fetch and decrypt one key", "<team> <key>"),
+			sub("put", "Encrypt and store a value", "<team> <key> <value>"),
+			sub("list", "List and decrypt all keys", "<team>"),
+			sub("del", "Delete a key", "<team> <key>"),
+		},
+	}
+}
+
+func (c *CmdTeamKV) ParseArgv(ctx *cli.Context) error {
+	args := ctx.Args()
+	c.revision = ctx.Int("revision")
+	need := map[string]int{"get": 2, "put": 3, "list": 1, "del": 2}[c.op]
+	if len(args) != need {
+		return fmt.Errorf("kv %s takes %d argument(s)", c.op, need)
+	}
+	c.team = args[0]
+	if need >= 2 {
+		c.key = args[1]
+	}
+	if need >= 3 {
+		c.value = args[2]
+	}
+	return nil
+}
+
+func (c *CmdTeamKV) Run() error {
+	if !c.G().Standalone {
+		return fmt.Errorf("team kv requires --standalone (values are boxed in this process)")
+	}
+	ctx := context.Background()
+	ui := c.G().UI.GetTerminalUI()
+	switch c.op {
+	case "get":
+		entry, err := teamkv.Get(ctx, c.G(), c.team, c.key)
+		if err != nil {
+			return err
+		}
+		ui.Printf("%s (revision %d)\n", string(entry.Value), entry.Revision)
+	case "put":
+		if err := teamkv.Put(ctx, c.G(), c.team, c.key, []byte(c.value), c.revision); err != nil {
+			return err
+		}
+		ui.Printf("Stored.\n")
+	case "list":
+		entries, err := teamkv.List(ctx, c.G(), c.team)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			ui.Printf("%s\t(revision %d)\n", e.Key, e.Revision)
+		}
+	case "del":
+		if err := teamkv.Del(ctx, c.G(), c.team, c.key, c.revision); err != nil {
+			return err
+		}
+		ui.Printf("Deleted.\n")
+	}
+	return nil
+}
+
+func (c *CmdTeamKV) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
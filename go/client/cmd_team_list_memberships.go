@@ -228,6 +228,9 @@ func (c *CmdTeamListMemberships) runUser(cli keybase1.TeamsClient) error {
 }
 
 func (c *CmdTeamListMemberships) output(details keybase1.TeamDetails) error {
+	if jsonOutputMode(c.G()) {
+		return printJSONResult(c.G(), "team-details.v1", details)
+	}
 	if c.json {
 		return c.outputJSON(details)
 	}
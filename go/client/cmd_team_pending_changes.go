@@ -0,0 +1,63 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdTeamPendingChanges lists role changes recorded under the
+// two-admin rule that still need a second admin's approval.
+type CmdTeamPendingChanges struct {
+	libkb.Contextified
+}
+
+func newCmdTeamPendingChanges(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "pending-changes",
+		Usage: "List role changes awaiting a second admin (two-admin rule)",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdTeamPendingChanges{Contextified: libkb.NewContextified(g)}, "pending-changes", c)
+		},
+	}
+}
+
+func (c *CmdTeamPendingChanges) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return errors.New("pending-changes takes no arguments")
+	}
+	return nil
+}
+
+func (c *CmdTeamPendingChanges) Run() error {
+	cli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	pending, err := cli.TeamListPendingRoleChanges(context.Background(), 0)
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if len(pending) == 0 {
+		dui.Printf("No pending role changes.\n")
+		return nil
+	}
+	for _, p := range pending {
+		dui.Printf("%s\t%s -> %s\tproposed by %s at %s\n", p.Team, p.Username, p.Role,
+			p.ProposedBy, keybase1.FromTime(p.ProposedAt).Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func (c *CmdTeamPendingChanges) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
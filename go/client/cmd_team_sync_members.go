@@ -0,0 +1,245 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// CmdTeamSyncMembers reconciles a team's membership against an external
+// roster: a CSV of username,role rows, or a SCIM-ish JSON array of
+// {"userName": ..., "role": ...}. It previews the adds, removals and
+// role changes, and applies them only with --apply.
+type CmdTeamSyncMembers struct {
+	libkb.Contextified
+	team   string
+	path   string
+	apply  bool
+	isJSON bool
+}
+
+func newCmdTeamSyncMembers(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "sync-members",
+		ArgumentHelp: "<team name>",
+		Usage:        "Reconcile team membership against an external roster",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdTeamSyncMembers{Contextified: libkb.NewContextified(g)}, "sync-members", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "from",
+				Usage: "Roster file: CSV (username,role) or SCIM-ish JSON [required].",
+			},
+			cli.BoolFlag{
+				Name:  "apply",
+				Usage: "Apply the changes; without it, just preview.",
+			},
+		},
+	}
+}
+
+func (c *CmdTeamSyncMembers) ParseArgv(ctx *cli.Context) error {
+	var err error
+	if c.team, err = ParseOneTeamName(ctx); err != nil {
+		return err
+	}
+	c.path = ctx.String("from")
+	if c.path == "" {
+		return fmt.Errorf("sync-members needs --from=<roster file>")
+	}
+	c.apply = ctx.Bool("apply")
+	c.isJSON = strings.HasSuffix(strings.ToLower(c.path), ".json")
+	return nil
+}
+
+// parseRoster reads the desired membership: username -> role.
+func parseRoster(path string, isJSON bool) (map[string]keybase1.TeamRole, error) {
+	desired := make(map[string]keybase1.TeamRole)
+	addEntry := func(username, role string) error {
+		username = strings.ToLower(strings.TrimSpace(username))
+		if username == "" {
+			return nil
+		}
+		parsed, ok := keybase1.TeamRoleMap[strings.ToUpper(strings.TrimSpace(role))]
+		if !ok {
+			return fmt.Errorf("unknown role %q for %s", role, username)
+		}
+		desired[username] = parsed
+		return nil
+	}
+
+	if isJSON {
+		blob, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var entries []struct {
+			UserName string `json:"userName"`
+			Role     string `json:"role"`
+		}
+		if err := json.Unmarshal(blob, &entries); err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if err := addEntry(e.UserName, e.Role); err != nil {
+				return nil, err
+			}
+		}
+		return desired, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if len(row) < 2 || strings.EqualFold(row[0], "username") {
+			continue
+		}
+		if err := addEntry(row[0], row[1]); err != nil {
+			return nil, err
+		}
+	}
+	return desired, nil
+}
+
+type rosterDiff struct {
+	adds    map[string]keybase1.TeamRole
+	removes []string
+	changes map[string]keybase1.TeamRole
+}
+
+// diffRoster computes the changes taking current to desired. Owners are
+// never removed by a sync, as a guard against decapitating the team
+// with a bad roster file.
+func diffRoster(current map[string]keybase1.TeamRole, desired map[string]keybase1.TeamRole) rosterDiff {
+	d := rosterDiff{
+		adds:    make(map[string]keybase1.TeamRole),
+		changes: make(map[string]keybase1.TeamRole),
+	}
+	for username, role := range desired {
+		cur, exists := current[username]
+		switch {
+		case !exists:
+			d.adds[username] = role
+		case cur != role:
+			d.changes[username] = role
+		}
+	}
+	for username, role := range current {
+		if _, wanted := desired[username]; !wanted && role != keybase1.TeamRole_OWNER {
+			d.removes = append(d.removes, username)
+		}
+	}
+	return d
+}
+
+func (c *CmdTeamSyncMembers) Run() error {
+	desired, err := parseRoster(c.path, c.isJSON)
+	if err != nil {
+		return err
+	}
+	if len(desired) == 0 {
+		return fmt.Errorf("roster %s contains no members; refusing to empty the team", c.path)
+	}
+
+	cli, err := GetTeamsClient(c.G())
+	if err != nil {
+		return err
+	}
+	details, err := cli.TeamGet(context.Background(), keybase1.TeamGetArg{Name: c.team})
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]keybase1.TeamRole)
+	collect := func(members []keybase1.TeamMemberDetails, role keybase1.TeamRole) {
+		for _, m := range members {
+			current[strings.ToLower(m.Username)] = role
+		}
+	}
+	collect(details.Members.Owners, keybase1.TeamRole_OWNER)
+	collect(details.Members.Admins, keybase1.TeamRole_ADMIN)
+	collect(details.Members.Writers, keybase1.TeamRole_WRITER)
+	collect(details.Members.Readers, keybase1.TeamRole_READER)
+
+	diff := diffRoster(current, desired)
+
+	dui := c.G().UI.GetDumbOutputUI()
+	dui.Printf("Roster sync for %s: %d adds, %d role changes, %d removals\n",
+		c.team, len(diff.adds), len(diff.changes), len(diff.removes))
+	for username, role := range diff.adds {
+		dui.Printf("  + add %s as %v\n", username, role)
+	}
+	for username, role := range diff.changes {
+		dui.Printf("  ~ change %s to %v\n", username, role)
+	}
+	for _, username := range diff.removes {
+		dui.Printf("  - remove %s\n", username)
+	}
+
+	if !c.apply {
+		dui.Printf("Preview only; re-run with --apply to make these changes.\n")
+		return nil
+	}
+
+	applied := 0
+	total := len(diff.adds) + len(diff.changes) + len(diff.removes)
+	progress := func() {
+		applied++
+		dui.Printf("[%d/%d] applied\n", applied, total)
+	}
+	for username, role := range diff.adds {
+		if _, err := cli.TeamAddMember(context.Background(), keybase1.TeamAddMemberArg{
+			Name: c.team, Username: username, Role: role,
+		}); err != nil {
+			return fmt.Errorf("adding %s: %s", username, err)
+		}
+		progress()
+	}
+	for username, role := range diff.changes {
+		if err := cli.TeamEditMember(context.Background(), keybase1.TeamEditMemberArg{
+			Name: c.team, Username: username, Role: role,
+		}); err != nil {
+			return fmt.Errorf("changing %s: %s", username, err)
+		}
+		progress()
+	}
+	for _, username := range diff.removes {
+		if err := cli.TeamRemoveMember(context.Background(), keybase1.TeamRemoveMemberArg{
+			Name: c.team, Username: username,
+		}); err != nil {
+			return fmt.Errorf("removing %s: %s", username, err)
+		}
+		progress()
+	}
+	dui.Printf("Done.\n")
+	return nil
+}
+
+func (c *CmdTeamSyncMembers) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRoster(t *testing.T) {
+	current := map[string]keybase1.TeamRole{
+		"owner1": keybase1.TeamRole_OWNER,
+		"adam":   keybase1.TeamRole_ADMIN,
+		"wanda":  keybase1.TeamRole_WRITER,
+		"gone":   keybase1.TeamRole_READER,
+	}
+	desired := map[string]keybase1.TeamRole{
+		"adam":  keybase1.TeamRole_WRITER, // demotion
+		"wanda": keybase1.TeamRole_WRITER, // unchanged
+		"newt":  keybase1.TeamRole_READER, // addition
+		// owner1 and gone absent from roster
+	}
+
+	d := diffRoster(current, desired)
+	require.Equal(t, map[string]keybase1.TeamRole{"newt": keybase1.TeamRole_READER}, d.adds)
+	require.Equal(t, map[string]keybase1.TeamRole{"adam": keybase1.TeamRole_WRITER}, d.changes)
+	// Owners survive roster omissions; others are removed.
+	require.Equal(t, []string{"gone"}, d.removes)
+}
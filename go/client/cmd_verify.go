@@ -5,7 +5,9 @@ package client
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -44,6 +46,10 @@ func NewCmdVerify(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comman
 				Name:  "o, outfile",
 				Usage: "Specify an outfile (stdout by default).",
 			},
+			cli.StringFlag{
+				Name:  "signed-at",
+				Usage: "Claimed signing time (RFC3339); lets signatures from since-revoked keys verify if the key was valid then.",
+			},
 			cli.StringFlag{
 				Name:  "S, signed-by",
 				Usage: "Assert signed by the given user (can use user assertion format).",
@@ -61,6 +67,7 @@ type CmdVerify struct {
 	UnixFilter
 	detachedData []byte
 	signedBy     string
+	signedAtTime keybase1.Time
 	spui         *SaltpackUI
 	force        bool
 }
@@ -83,6 +90,13 @@ func (c *CmdVerify) ParseArgv(ctx *cli.Context) error {
 		return err
 	}
 	c.signedBy = ctx.String("signed-by")
+	if sa := ctx.String("signed-at"); sa != "" {
+		t, err := time.Parse(time.RFC3339, sa)
+		if err != nil {
+			return fmt.Errorf("bad --signed-at time (want RFC3339, e.g. 2017-08-01T12:00:00Z): %s", err)
+		}
+		c.signedAtTime = keybase1.ToTime(t)
+	}
 	detachedFilename := ctx.String("detached")
 
 	if len(detachedFilename) > 0 {
@@ -126,8 +140,9 @@ func (c *CmdVerify) Run() (err error) {
 			Source: src,
 			Sink:   snk,
 			Opts: keybase1.SaltpackVerifyOptions{
-				Signature: c.detachedData,
-				SignedBy:  c.signedBy,
+				Signature:    c.detachedData,
+				SignedBy:     c.signedBy,
+				SignedAtTime: c.signedAtTime,
 			},
 		}
 		err = cli.SaltpackVerify(context.TODO(), arg)
@@ -8,19 +8,25 @@ import (
 
 func newCmdWallet(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 	subcommands := []cli.Command{
+		newCmdWalletAssets(cl, g),
 		newCmdWalletBalances(cl, g),
+		newCmdWalletExportHistory(cl, g),
 		newCmdWalletCancel(cl, g),
+		newCmdWalletCancelPending(cl, g),
 		newCmdWalletExport(cl, g),
 		newCmdWalletDetail(cl, g),
 		newCmdWalletHistory(cl, g),
 		newCmdWalletImport(cl, g),
 		newCmdWalletSend(cl, g),
 		newCmdWalletSetCurrency(cl, g),
+		newCmdWalletSendPath(cl, g),
 		newCmdWalletSetPrimary(cl, g),
 		newCmdWalletRequest(cl, g),
 		newCmdWalletCancelRequest(cl, g),
 		newCmdWalletLookup(cl, g),
 		newCmdWalletRename(cl, g),
+		newCmdWalletTrustline(cl, g),
+		newCmdWalletWatch(cl, g),
 	}
 	subcommands = append(subcommands, getBuildSpecificWalletCommands(cl, g)...)
 	return cli.Command{
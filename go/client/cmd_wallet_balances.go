@@ -73,17 +73,17 @@ func (c *cmdWalletBalances) runForUser(cli stellar1.LocalClient) error {
 	dui := c.G().UI.GetDumbOutputUI()
 	for i, acc := range accounts {
 		var accountName string
+		var suffix string
+		if acc.IsPrimary {
+			suffix = " (Primary)"
+		}
+		if acc.IsWatchOnly {
+			suffix += " (watch-only)"
+		}
 		if acc.Name != "" {
-			var isPrimary string
-			if acc.IsPrimary {
-				isPrimary = " (Primary)"
-			}
-			accountName = fmt.Sprintf("'%s' (%s)%s", acc.Name, acc.AccountID.String(), isPrimary)
+			accountName = fmt.Sprintf("'%s' (%s)%s", acc.Name, acc.AccountID.String(), suffix)
 		} else {
-			accountName = acc.AccountID.String()
-			if acc.IsPrimary {
-				accountName += " (Primary)"
-			}
+			accountName = acc.AccountID.String() + suffix
 		}
 		dui.Printf("Balances for account %s:\n", accountName)
 
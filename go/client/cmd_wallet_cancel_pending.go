@@ -0,0 +1,64 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/stellar1"
+	"golang.org/x/net/context"
+)
+
+type cmdWalletCancelPending struct {
+	libkb.Contextified
+	to     string
+	amount string
+}
+
+func newCmdWalletCancelPending(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	cmd := &cmdWalletCancelPending{
+		Contextified: libkb.NewContextified(g),
+	}
+	return cli.Command{
+		Name:         "cancel-pending",
+		ArgumentHelp: "<recipient> <amount>",
+		Usage:        "Cancel a payment still inside its spending-policy delay window",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(cmd, "cancel-pending", c)
+		},
+	}
+}
+
+func (c *cmdWalletCancelPending) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return errors.New("cancel-pending requires a recipient and an amount")
+	}
+	c.to = ctx.Args()[0]
+	c.amount = ctx.Args()[1]
+	return nil
+}
+
+func (c *cmdWalletCancelPending) Run() error {
+	cli, err := GetWalletClient(c.G())
+	if err != nil {
+		return err
+	}
+	err = cli.CancelPendingCLILocal(context.Background(), stellar1.CancelPendingCLILocalArg{
+		To:     c.to,
+		Amount: c.amount,
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Pending payment of %s XLM to %s cancelled.\n", c.amount, c.to)
+	return nil
+}
+
+func (c *cmdWalletCancelPending) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
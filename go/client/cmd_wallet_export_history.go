@@ -0,0 +1,191 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/stellar1"
+)
+
+// cmdWalletExportHistory exports payment history as CSV or OFX,
+// including the fiat valuation recorded at transaction time (the
+// display amount/currency stored with each payment), for tax reporting.
+type cmdWalletExportHistory struct {
+	libkb.Contextified
+	format  string
+	outfile string
+	from    time.Time
+	to      time.Time
+}
+
+func newCmdWalletExportHistory(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "export-history",
+		Usage: "Export payment history with fiat valuations as CSV or OFX",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdWalletExportHistory{Contextified: libkb.NewContextified(g)}, "export-history", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{Name: "format", Usage: "csv (default) or ofx."},
+			cli.StringFlag{Name: "o, outfile", Usage: "Write here rather than stdout."},
+			cli.StringFlag{Name: "from", Usage: "Include payments on/after this date (2006-01-02)."},
+			cli.StringFlag{Name: "to", Usage: "Include payments before this date (2006-01-02)."},
+		},
+	}
+}
+
+func (c *cmdWalletExportHistory) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("export-history takes no arguments")
+	}
+	c.format = ctx.String("format")
+	if c.format == "" {
+		c.format = "csv"
+	}
+	if c.format != "csv" && c.format != "ofx" {
+		return fmt.Errorf("unsupported format %q: use csv or ofx", c.format)
+	}
+	c.outfile = ctx.String("outfile")
+	parse := func(s string) (time.Time, error) {
+		if s == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse("2006-01-02", s)
+	}
+	var err error
+	if c.from, err = parse(ctx.String("from")); err != nil {
+		return fmt.Errorf("bad --from date: %s", err)
+	}
+	if c.to, err = parse(ctx.String("to")); err != nil {
+		return fmt.Errorf("bad --to date: %s", err)
+	}
+	return nil
+}
+
+func (c *cmdWalletExportHistory) inRange(t time.Time) bool {
+	if !c.from.IsZero() && t.Before(c.from) {
+		return false
+	}
+	if !c.to.IsZero() && !t.Before(c.to) {
+		return false
+	}
+	return true
+}
+
+func (c *cmdWalletExportHistory) Run() error {
+	cli, err := GetWalletClient(c.G())
+	if err != nil {
+		return err
+	}
+	recent, err := cli.RecentPaymentsCLILocal(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	var payments []stellar1.PaymentCLILocal
+	for _, p := range recent {
+		if p.Payment != nil {
+			payments = append(payments, *p.Payment)
+		}
+	}
+
+	var out io.Writer = c.G().UI.GetTerminalUI().OutputWriter()
+	if c.outfile != "" {
+		f, err := os.OpenFile(c.outfile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch c.format {
+	case "csv":
+		return c.writeCSV(out, payments)
+	case "ofx":
+		return c.writeOFX(out, payments)
+	}
+	return nil
+}
+
+func paymentCounterparty(p stellar1.PaymentCLILocal) string {
+	switch {
+	case p.ToUsername != nil:
+		return *p.ToUsername
+	case p.ToStellar != nil:
+		return p.ToStellar.String()
+	case p.ToAssertion != nil:
+		return *p.ToAssertion
+	default:
+		return ""
+	}
+}
+
+func (c *cmdWalletExportHistory) writeCSV(out io.Writer, payments []stellar1.PaymentCLILocal) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{
+		"time", "tx_id", "status", "amount", "asset",
+		"fiat_amount_at_tx_time", "fiat_currency", "counterparty", "note",
+	}); err != nil {
+		return err
+	}
+	for _, p := range payments {
+		t := p.Time.Time()
+		if !c.inRange(t) {
+			continue
+		}
+		fiatAmount, fiatCurrency := "", ""
+		if p.DisplayAmount != nil {
+			fiatAmount = *p.DisplayAmount
+		}
+		if p.DisplayCurrency != nil {
+			fiatCurrency = *p.DisplayCurrency
+		}
+		asset := "XLM"
+		if p.Asset.Code != "" {
+			asset = p.Asset.Code
+		}
+		if err := w.Write([]string{
+			t.UTC().Format(time.RFC3339), string(p.TxID), p.Status, p.Amount, asset,
+			fiatAmount, fiatCurrency, paymentCounterparty(p), p.Note,
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (c *cmdWalletExportHistory) writeOFX(out io.Writer, payments []stellar1.PaymentCLILocal) error {
+	fmt.Fprintf(out, "OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\n\n<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>\n")
+	for i, p := range payments {
+		t := p.Time.Time()
+		if !c.inRange(t) {
+			continue
+		}
+		memo := p.Note
+		if p.DisplayAmount != nil && p.DisplayCurrency != nil {
+			memo = fmt.Sprintf("%s [%s %s at tx time]", memo, *p.DisplayAmount, *p.DisplayCurrency)
+		}
+		fmt.Fprintf(out, "<STMTTRN><TRNTYPE>XFER<DTPOSTED>%s<TRNAMT>%s<FITID>%s<NAME>%s<MEMO>%s</STMTTRN>\n",
+			t.UTC().Format("20060102150405"), p.Amount,
+			string(p.TxID)+strconv.Itoa(i), paymentCounterparty(p), memo)
+	}
+	fmt.Fprintf(out, "</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>\n")
+	return nil
+}
+
+func (c *cmdWalletExportHistory) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, KbKeyring: true, API: true}
+}
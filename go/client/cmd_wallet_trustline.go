@@ -0,0 +1,232 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/stellar1"
+)
+
+// Trustline and multi-asset commands: `wallet trustline add/remove`,
+// `wallet assets`, and `wallet send-path` for cross-asset payments.
+
+type cmdWalletTrustline struct {
+	libkb.Contextified
+	remove    bool
+	accountID string
+	code      string
+	issuer    string
+	limit     string
+}
+
+func newCmdWalletTrustline(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "trustline",
+		ArgumentHelp: "add|remove <asset code> <issuer>",
+		Usage:        "Manage trustlines to issued assets",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdWalletTrustline{Contextified: libkb.NewContextified(g)}, "trustline", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "account",
+				Usage: "Account ID (default: primary account).",
+			},
+			cli.StringFlag{
+				Name:  "limit",
+				Usage: "Trust limit for add ('' = maximum).",
+			},
+		},
+	}
+}
+
+func (c *cmdWalletTrustline) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 3 {
+		return errors.New("usage: keybase wallet trustline add|remove <asset code> <issuer>")
+	}
+	switch ctx.Args().Get(0) {
+	case "add":
+	case "remove":
+		c.remove = true
+	default:
+		return errors.New("first argument must be add or remove")
+	}
+	c.code = ctx.Args().Get(1)
+	c.issuer = ctx.Args().Get(2)
+	c.accountID = ctx.String("account")
+	c.limit = ctx.String("limit")
+	return nil
+}
+
+func (c *cmdWalletTrustline) Run() error {
+	cli, err := GetWalletClient(c.G())
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	if c.remove {
+		txID, err := cli.RemoveTrustlineLocal(context.Background(), stellar1.RemoveTrustlineLocalArg{
+			AccountID: stellar1.AccountID(c.accountID),
+			Code:      c.code,
+			Issuer:    c.issuer,
+		})
+		if err != nil {
+			return err
+		}
+		dui.Printf("Trustline removed. tx: %s\n", txID)
+		return nil
+	}
+	txID, err := cli.AddTrustlineLocal(context.Background(), stellar1.AddTrustlineLocalArg{
+		AccountID: stellar1.AccountID(c.accountID),
+		Code:      c.code,
+		Issuer:    c.issuer,
+		Limit:     c.limit,
+	})
+	if err != nil {
+		return err
+	}
+	dui.Printf("Trustline added. tx: %s\n", txID)
+	return nil
+}
+
+func (c *cmdWalletTrustline) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
+
+type cmdWalletAssets struct {
+	libkb.Contextified
+	accountID string
+}
+
+func newCmdWalletAssets(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "assets",
+		ArgumentHelp: "<account id>",
+		Usage:        "Show all asset balances on an account",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdWalletAssets{Contextified: libkb.NewContextified(g)}, "assets", c)
+		},
+	}
+}
+
+func (c *cmdWalletAssets) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("assets requires an account ID argument")
+	}
+	c.accountID = ctx.Args().Get(0)
+	return nil
+}
+
+func (c *cmdWalletAssets) Run() error {
+	cli, err := GetWalletClient(c.G())
+	if err != nil {
+		return err
+	}
+	balances, err := cli.AssetBalancesLocal(context.Background(), stellar1.AccountID(c.accountID))
+	if err != nil {
+		return err
+	}
+	dui := c.G().UI.GetDumbOutputUI()
+	for _, b := range balances {
+		if b.Issuer == "" {
+			dui.Printf("%s\t%s\n", b.Code, b.Balance)
+		} else {
+			dui.Printf("%s\t%s\t(issued by %s, limit %s)\n", b.Code, b.Balance, b.Issuer, b.Limit)
+		}
+	}
+	return nil
+}
+
+func (c *cmdWalletAssets) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
+
+type cmdWalletSendPath struct {
+	libkb.Contextified
+	arg stellar1.SendPathPaymentCLILocalArg
+}
+
+func newCmdWalletSendPath(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "send-path",
+		ArgumentHelp: "<recipient address> <dest amount>",
+		Usage:        "Send a cross-asset path payment with a slippage bound",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&cmdWalletSendPath{Contextified: libkb.NewContextified(g)}, "send-path", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "from",
+				Usage: "Source account ID (default: primary account).",
+			},
+			cli.StringFlag{
+				Name:  "send-asset",
+				Usage: "Source asset as CODE/ISSUER ('' = native XLM).",
+			},
+			cli.StringFlag{
+				Name:  "send-max",
+				Usage: "Slippage bound in the source asset [required].",
+			},
+			cli.StringFlag{
+				Name:  "dest-asset",
+				Usage: "Destination asset as CODE/ISSUER ('' = native XLM).",
+			},
+		},
+	}
+}
+
+func splitAssetSpec(spec string) (code, issuer string, err error) {
+	if spec == "" {
+		return "", "", nil
+	}
+	parts := strings.SplitN(spec, "/", 2)
+	if len(parts) != 2 {
+		return "", "", errors.New("asset must be CODE/ISSUER")
+	}
+	return parts[0], parts[1], nil
+}
+
+func (c *cmdWalletSendPath) ParseArgv(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 2 {
+		return errors.New("usage: keybase wallet send-path <recipient address> <dest amount>")
+	}
+	c.arg.Recipient = ctx.Args().Get(0)
+	c.arg.DestAmount = ctx.Args().Get(1)
+	c.arg.FromAccountID = stellar1.AccountID(ctx.String("from"))
+	c.arg.SendMax = ctx.String("send-max")
+	if c.arg.SendMax == "" {
+		return errors.New("send-path requires --send-max")
+	}
+	if c.arg.SendCode, c.arg.SendIssuer, err = splitAssetSpec(ctx.String("send-asset")); err != nil {
+		return err
+	}
+	if c.arg.DestCode, c.arg.DestIssuer, err = splitAssetSpec(ctx.String("dest-asset")); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cmdWalletSendPath) Run() error {
+	cli, err := GetWalletClient(c.G())
+	if err != nil {
+		return err
+	}
+	txID, err := cli.SendPathPaymentCLILocal(context.Background(), c.arg)
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Path payment submitted. tx: %s\n", txID)
+	return nil
+}
+
+func (c *cmdWalletSendPath) GetUsage() libkb.Usage {
+	return libkb.Usage{Config: true, API: true, KbKeyring: true}
+}
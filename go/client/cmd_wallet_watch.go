@@ -0,0 +1,75 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/stellar1"
+	"golang.org/x/net/context"
+)
+
+type cmdWalletWatch struct {
+	libkb.Contextified
+	accountID stellar1.AccountID
+	name      string
+}
+
+func newCmdWalletWatch(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	cmd := &cmdWalletWatch{
+		Contextified: libkb.NewContextified(g),
+	}
+	return cli.Command{
+		Name:         "watch",
+		ArgumentHelp: "<account id>",
+		Usage:        "Add a stellar address as watch-only (no secret key)",
+		Description:  "Watch-only accounts show up in balances and history but cannot spend.\nUseful for keeping an eye on cold-storage accounts.",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(cmd, "watch", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "name",
+				Usage: "Name of the new watch-only account.",
+			},
+		},
+	}
+}
+
+func (c *cmdWalletWatch) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("watch requires one stellar account ID")
+	}
+	accountID, err := libkb.ParseStellarAccountID(ctx.Args()[0])
+	if err != nil {
+		return err
+	}
+	c.accountID = accountID
+	c.name = ctx.String("name")
+	return nil
+}
+
+func (c *cmdWalletWatch) Run() error {
+	cli, err := GetWalletClient(c.G())
+	if err != nil {
+		return err
+	}
+	err = cli.WatchAccountLocal(context.Background(), stellar1.WatchAccountLocalArg{
+		AccountID: c.accountID,
+		Name:      c.name,
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("Now watching %s (watch-only, cannot spend).\n", c.accountID)
+	return nil
+}
+
+func (c *cmdWalletWatch) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
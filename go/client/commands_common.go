@@ -16,13 +16,17 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 	ret := []cli.Command{
 		NewCmdAccount(cl, g),
 		NewCmdAPICall(cl, g),
+		NewCmdAPIToken(cl, g),
 		NewCmdBase62(cl, g),
+		NewCmdBot(cl, g),
 		NewCmdBTC(cl, g),
 		NewCmdCA(cl, g),
 		NewCmdChat(cl, g),
 		NewCmdCompatDir(cl),
 		NewCmdCompatPush(cl),
+		NewCmdCompletion(cl, g),
 		NewCmdConfig(cl, g),
+		NewCmdContacts(cl, g),
 		NewCmdCtl(cl, g),
 		NewCmdCurrency(cl, g),
 		NewCmdDb(cl, g),
@@ -30,6 +34,7 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 		NewCmdDeprovision(cl, g),
 		NewCmdDevice(cl, g),
 		NewCmdDismiss(cl, g),
+		NewCmdDoctor(cl, g),
 		NewCmdDismissCategory(cl, g),
 		NewCmdDumpKeyfamily(cl, g),
 		NewCmdDumpPushNotifications(cl, g),
@@ -40,6 +45,7 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 		NewCmdID(cl, g),
 		NewCmdInterestingPeople(cl, g),
 		NewCmdListTracking(cl, g),
+		NewCmdLock(cl, g),
 		NewCmdListTrackers(cl, g),
 		NewCmdLog(cl, g),
 		NewCmdLogin(cl, g),
@@ -49,9 +55,11 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 		NewCmdPassphrase(cl, g),
 		NewCmdPGP(cl, g),
 		NewCmdPing(cl, g),
+		NewCmdPolicy(cl, g),
 		NewCmdPprof(cl, g),
 		NewCmdProve(cl, g),
 		NewCmdRekey(cl, g),
+		NewCmdRemoteSign(cl, g),
 		NewCmdRIIT(cl, g),
 		NewCmdSearch(cl, g),
 		NewCmdSelfProvision(cl, g),
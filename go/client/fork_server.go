@@ -39,6 +39,12 @@ func AutoForkServer(g *libkb.GlobalContext, cl libkb.CommandLine) (bool, error)
 // assume that the server was successfully started up. Returns (true, nil) if
 // the server was actually forked, or (false, nil) if it was previously up.
 func ForkServer(g *libkb.GlobalContext, cl libkb.CommandLine, forkType keybase1.ForkType) (bool, error) {
+	if g.Env.GetRemoteCtlConnect() != "" {
+		// Talking to a remote service over TLS; a local fork would be
+		// dialed instead of the machine the user asked for.
+		g.Log.Debug("Skipping local server fork: remote-ctl connect is set")
+		return false, nil
+	}
 	srv := service.NewService(g, true /* isDaemon */)
 	forked := false
 
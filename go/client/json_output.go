@@ -0,0 +1,46 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// Shared formatter layer for the global --format=json mode. Every
+// machine-readable result goes out as one envelope:
+//
+//   {"schema": "<name>.v<N>", "data": <payload>}
+//
+// The schema name is a contract with scripts: a payload only changes
+// shape under a bumped version, so callers can pin "device-list.v1"
+// and never scrape terminal output again.
+//
+// Current schemas:
+//   identify.v1           {username, uid, keys: [kid]}
+//   user-summary-list.v1  [{username, uid, fullName?}]
+//   device-list.v1        [{id, name, type, ctime, status}]
+//   team-details.v1       keybase1.TeamDetails (wire form)
+
+type jsonEnvelope struct {
+	Schema string      `json:"schema"`
+	Data   interface{} `json:"data"`
+}
+
+// jsonOutputMode reports whether the global --format=json flag (or
+// KEYBASE_OUTPUT_FORMAT / the output_format config key) is in effect.
+func jsonOutputMode(g *libkb.GlobalContext) bool {
+	return g.Env.GetOutputFormat() == "json"
+}
+
+// printJSONResult emits one result envelope on stdout.
+func printJSONResult(g *libkb.GlobalContext, schema string, data interface{}) error {
+	out, err := json.MarshalIndent(jsonEnvelope{Schema: schema, Data: data}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = g.UI.GetDumbOutputUI().Printf("%s\n", out)
+	return err
+}
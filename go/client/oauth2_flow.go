@@ -0,0 +1,190 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// A generic OAuth2 authorization-code driver for web service proofs.
+// Services declare their endpoints in config:
+//
+//   "prove": { "oauth": { "myservice": {
+//       "auth_url": "https://svc/oauth/authorize",
+//       "token_url": "https://svc/oauth/token",
+//       "client_id": "keybase",
+//       "scope": "write:status" } } }
+//
+// The flow listens on a localhost redirect, hands the user the consent
+// URL, exchanges the code for a bearer token, and returns it so the
+// prove flow can post the signed proof through the provider's API
+// instead of asking the user to copy-paste.
+
+type oauth2ServiceConfig struct {
+	AuthURL  string
+	TokenURL string
+	ClientID string
+	Scope    string
+
+	// Where to POST the signed proof once authorized, and the form
+	// field to carry it in (default "status").
+	PostURL   string
+	PostField string
+}
+
+// oauth2ConfigForService reads the service's OAuth2 endpoints from
+// config, or returns ok=false if the service doesn't declare any.
+func oauth2ConfigForService(g *libkb.GlobalContext, service string) (cfg oauth2ServiceConfig, ok bool) {
+	reader := g.Env.GetConfig()
+	prefix := "prove.oauth." + service + "."
+	cfg.AuthURL, _ = reader.GetStringAtPath(prefix + "auth_url")
+	cfg.TokenURL, _ = reader.GetStringAtPath(prefix + "token_url")
+	cfg.ClientID, _ = reader.GetStringAtPath(prefix + "client_id")
+	cfg.Scope, _ = reader.GetStringAtPath(prefix + "scope")
+	cfg.PostURL, _ = reader.GetStringAtPath(prefix + "post_url")
+	cfg.PostField, _ = reader.GetStringAtPath(prefix + "post_field")
+	if cfg.PostField == "" {
+		cfg.PostField = "status"
+	}
+	return cfg, cfg.AuthURL != "" && cfg.TokenURL != "" && cfg.ClientID != ""
+}
+
+// postProofViaProvider publishes the signed proof text through the
+// provider's own API with the bearer token from the OAuth2 flow.
+func postProofViaProvider(cfg oauth2ServiceConfig, token, proofText string) error {
+	if cfg.PostURL == "" {
+		return fmt.Errorf("service declares no post_url; paste the proof manually")
+	}
+	req, err := http.NewRequest("POST", cfg.PostURL,
+		strings.NewReader(url.Values{cfg.PostField: {proofText}}.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("provider rejected the proof post: %s", resp.Status)
+	}
+	return nil
+}
+
+// runOAuth2Flow drives the authorization-code exchange and returns the
+// bearer token. The user opens the printed consent URL; the provider
+// redirects to our localhost listener with the code.
+func runOAuth2Flow(g *libkb.GlobalContext, cfg oauth2ServiceConfig, timeout time.Duration) (token string, err error) {
+	stateBytes, err := libkb.RandBytes(16)
+	if err != nil {
+		return "", err
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer ln.Close()
+	redirectURI := fmt.Sprintf("http://%s/oauth", ln.Addr())
+
+	consent, err := url.Parse(cfg.AuthURL)
+	if err != nil {
+		return "", err
+	}
+	q := consent.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", cfg.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("state", state)
+	if cfg.Scope != "" {
+		q.Set("scope", cfg.Scope)
+	}
+	consent.RawQuery = q.Encode()
+
+	ui := g.UI.GetTerminalUI()
+	ui.Printf("Open this URL in your browser to authorize the proof:\n\n  %s\n\n", consent)
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		send := func(ch chan string, v string) {
+			select {
+			case ch <- v:
+			default: // only the first result matters
+			}
+		}
+		if r.URL.Query().Get("state") != state {
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			select {
+			case errCh <- fmt.Errorf("OAuth2 state mismatch; possible CSRF"):
+			default:
+			}
+			return
+		}
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			http.Error(w, "missing code", http.StatusBadRequest)
+			select {
+			case errCh <- fmt.Errorf("provider redirected without a code"):
+			default:
+			}
+			return
+		}
+		fmt.Fprintf(w, "Authorized. You can close this tab and return to the terminal.")
+		send(codeCh, code)
+	})}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	var code string
+	select {
+	case code = <-codeCh:
+	case err := <-errCh:
+		return "", err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("timed out waiting for OAuth2 authorization")
+	}
+
+	resp, err := http.PostForm(cfg.TokenURL, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"client_id":    {cfg.ClientID},
+		"redirect_uri": {redirectURI},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var tr struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return "", fmt.Errorf("bad token response: %s", err)
+	}
+	if tr.Error != "" {
+		return "", fmt.Errorf("token exchange failed: %s", tr.Error)
+	}
+	if tr.AccessToken == "" {
+		return "", fmt.Errorf("token exchange returned no access token")
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return "", fmt.Errorf("unsupported token type %q", tr.TokenType)
+	}
+	return tr.AccessToken, nil
+}
@@ -409,6 +409,24 @@ func GetBadgerClient(g *libkb.GlobalContext) (cli keybase1.BadgerClient, err err
 	return cli, nil
 }
 
+func GetContactsClient(g *libkb.GlobalContext) (cli keybase1.ContactsClient, err error) {
+	rcli, _, err := GetRPCClientWithContext(g)
+	if err != nil {
+		return cli, err
+	}
+	cli = keybase1.ContactsClient{Cli: rcli}
+	return cli, nil
+}
+
+func GetApiTokenClient(g *libkb.GlobalContext) (cli keybase1.ApiTokenClient, err error) {
+	rcli, _, err := GetRPCClientWithContext(g)
+	if err != nil {
+		return cli, err
+	}
+	cli = keybase1.ApiTokenClient{Cli: rcli}
+	return cli, nil
+}
+
 func GetWalletClient(g *libkb.GlobalContext) (cli stellar1.LocalClient, err error) {
 	rcli, _, err := GetRPCClientWithContext(g)
 	if err != nil {
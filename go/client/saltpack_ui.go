@@ -40,6 +40,8 @@ func (s *SaltpackUI) doNonInteractive(arg keybase1.SaltpackPromptForDecryptArg)
 			return nil
 		}
 		err = libkb.IdentifyFailedError{Assertion: arg.Sender.Username, Reason: "sender key expired"}
+	case keybase1.SaltpackSenderType_REVOKED_BUT_VALID_AT_SIGNING:
+		s.G().Log.Warning("The signing key was valid at the claimed signing time, but has since been revoked.")
 	case keybase1.SaltpackSenderType_NOT_TRACKED:
 		s.G().Log.Warning("The sender of this message is a Keybase user you don't follow. Consider doing so for even stronger security!")
 	case keybase1.SaltpackSenderType_TRACKING_OK, keybase1.SaltpackSenderType_SELF, keybase1.SaltpackSenderType_UNKNOWN, keybase1.SaltpackSenderType_ANONYMOUS:
@@ -97,6 +99,8 @@ func (s *SaltpackUI) SaltpackPromptForDecrypt(_ context.Context, arg keybase1.Sa
 		fmt.Fprintf(w, ColorString(s.G(), "red", fmt.Sprintf("Authored by %s, however the key that authenticated this message has been revoked (key ID: %s).\n", ColorString(s.G(), "bold", arg.Sender.Username), arg.SigningKID)))
 	case keybase1.SaltpackSenderType_EXPIRED:
 		fmt.Fprintf(w, ColorString(s.G(), "red", fmt.Sprintf("Authored by %s, however the key that authenticated this message has expired (key ID: %s).\n", ColorString(s.G(), "bold", arg.Sender.Username), arg.SigningKID)))
+	case keybase1.SaltpackSenderType_REVOKED_BUT_VALID_AT_SIGNING:
+		fmt.Fprintf(w, ColorString(s.G(), "yellow", fmt.Sprintf("Authored by %s; valid at the claimed signing time, but the key has since been revoked (key ID: %s).\n", ColorString(s.G(), "bold", arg.Sender.Username), arg.SigningKID)))
 	default:
 		return fmt.Errorf("Unexpected sender type: %s", arg.Sender.SenderType)
 	}
@@ -142,6 +146,14 @@ func (s *SaltpackUI) SaltpackVerifyBadSender(_ context.Context, arg keybase1.Sal
 	case keybase1.SaltpackSenderType_REVOKED:
 		message = fmt.Sprintf("Signed by %s, but the key they used is revoked:\n    %s", ColorString(s.G(), "bold", arg.Sender.Username), arg.SigningKID.String())
 		errorReason = "sender key revoked"
+	case keybase1.SaltpackSenderType_REVOKED_BUT_VALID_AT_SIGNING:
+		// The --signed-at mode asked exactly this question: the key
+		// was valid when the signature was made. Warn, but verify.
+		w := s.terminal.ErrorWriter()
+		fmt.Fprintf(w, ColorString(s.G(), "yellow",
+			fmt.Sprintf("Signed by %s; valid at the claimed signing time, but the key has since been revoked:\n    %s\n",
+				ColorString(s.G(), "bold", arg.Sender.Username), arg.SigningKID.String())))
+		return nil
 	case keybase1.SaltpackSenderType_EXPIRED:
 		message = fmt.Sprintf("Signed by %s, but the key they used is expired:\n    %s", ColorString(s.G(), "bold", arg.Sender.Username), arg.SigningKID.String())
 		errorReason = "sender key expired"
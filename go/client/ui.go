@@ -30,6 +30,7 @@ type UI struct {
 	libkb.Contextified
 	Terminal    *Terminal
 	SecretEntry *SecretEntry
+	batch       batchPrompter
 
 	outputWriter          io.Writer
 	unescapedOutputWriter io.Writer
@@ -847,6 +848,10 @@ func (ui SecretUI) GetPassphrase(pin keybase1.GUIEntryArg, term *keybase1.Secret
 
 func (ui SecretUI) passphrasePrompt(arg libkb.PromptArg) (text string, storeSecret bool, err error) {
 
+	if text, handled, err := ui.parent.batch.answerPassphrase(ui.parent.G(), arg.TerminalPrompt); handled {
+		return text, false, err
+	}
+
 	first := true
 	var res *keybase1.SecretEntryRes
 
@@ -892,14 +897,20 @@ func (ui SecretUI) passphrasePrompt(arg libkb.PromptArg) (text string, storeSecr
 	return
 }
 
-func (ui *UI) PromptPassword(_ libkb.PromptDescriptor, s string) (string, error) {
+func (ui *UI) PromptPassword(pd libkb.PromptDescriptor, s string) (string, error) {
+	if res, handled, err := ui.batch.answerString(ui.G(), pd, s, "password"); handled {
+		return res, err
+	}
 	if ui.Terminal == nil {
 		return "", NoTerminalError{}
 	}
 	return ui.Terminal.PromptPassword(s)
 }
 
-func (ui *UI) Prompt(_ libkb.PromptDescriptor, s string) (string, error) {
+func (ui *UI) Prompt(pd libkb.PromptDescriptor, s string) (string, error) {
+	if res, handled, err := ui.batch.answerString(ui.G(), pd, s, "string"); handled {
+		return res, err
+	}
 	if ui.Terminal == nil {
 		return "", NoTerminalError{}
 	}
@@ -945,6 +956,15 @@ func PromptWithChecker(pd libkb.PromptDescriptor, ui libkb.TerminalUI, prompt st
 }
 
 func (ui *UI) PromptForConfirmation(prompt string) error {
+	if yes, handled, err := ui.batch.answerBool(ui.G(), PromptDescriptorGeneric, prompt); handled {
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return libkb.InputCanceledError{}
+		}
+		return nil
+	}
 
 	if ui.Terminal == nil {
 		return NoTerminalError{}
@@ -972,7 +992,10 @@ func (ui *UI) GetTerminalUI() libkb.TerminalUI { return ui }
 // dumb output UI too.
 func (ui *UI) GetDumbOutputUI() libkb.DumbOutputUI { return ui }
 
-func (ui *UI) PromptYesNo(_ libkb.PromptDescriptor, p string, def libkb.PromptDefault) (ret bool, err error) {
+func (ui *UI) PromptYesNo(pd libkb.PromptDescriptor, p string, def libkb.PromptDefault) (ret bool, err error) {
+	if res, handled, err := ui.batch.answerBool(ui.G(), pd, p); handled {
+		return res, err
+	}
 	return ui.Terminal.PromptYesNo(p, def)
 }
 
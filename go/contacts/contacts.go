@@ -0,0 +1,206 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// Package contacts imports local address books and resolves their
+// phone numbers and email addresses to Keybase users. Identifiers
+// leave the device only as SHA-256 hashes; the resolved mapping is
+// stored in the local db (encrypted at rest when db encryption is
+// enabled) and powers sending chat by contact name.
+package contacts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Contact is one address-book entry, parsed from vCard or JSON.
+type Contact struct {
+	Name   string   `json:"name"`
+	Emails []string `json:"emails,omitempty"`
+	Phones []string `json:"phones,omitempty"`
+}
+
+// ParseContacts accepts either a JSON array of contacts or a vCard
+// stream (BEGIN:VCARD ... END:VCARD records with FN/EMAIL/TEL lines).
+func ParseContacts(data []byte) ([]Contact, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var contacts []Contact
+		if err := json.Unmarshal(data, &contacts); err != nil {
+			return nil, fmt.Errorf("bad contacts JSON: %s", err)
+		}
+		return contacts, nil
+	}
+	if strings.HasPrefix(trimmed, "BEGIN:VCARD") {
+		return parseVCards(trimmed), nil
+	}
+	return nil, fmt.Errorf("unrecognized contacts format: want a JSON array or vCard")
+}
+
+func parseVCards(data string) (contacts []Contact) {
+	var cur *Contact
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "BEGIN:VCARD"):
+			cur = &Contact{}
+		case strings.HasPrefix(line, "END:VCARD"):
+			if cur != nil && cur.Name != "" {
+				contacts = append(contacts, *cur)
+			}
+			cur = nil
+		case cur == nil:
+			// ignore junk between records
+		case strings.HasPrefix(line, "FN:"):
+			cur.Name = strings.TrimSpace(line[len("FN:"):])
+		default:
+			// EMAIL and TEL lines routinely carry parameters, e.g.
+			// EMAIL;TYPE=work:bob@example.com
+			key := line
+			value := ""
+			if idx := strings.Index(line, ":"); idx >= 0 {
+				key, value = line[:idx], strings.TrimSpace(line[idx+1:])
+			}
+			keyName := strings.SplitN(key, ";", 2)[0]
+			switch strings.ToUpper(keyName) {
+			case "EMAIL":
+				if value != "" {
+					cur.Emails = append(cur.Emails, value)
+				}
+			case "TEL":
+				if value != "" {
+					cur.Phones = append(cur.Phones, value)
+				}
+			}
+		}
+	}
+	return contacts
+}
+
+// NormalizeEmail and NormalizePhone put identifiers in the canonical
+// form both sides hash.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for i, r := range phone {
+		switch {
+		case r == 'x' || r == 'X':
+			// extension marker; the extension isn't part of the number
+			return b.String()
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// HashIdentifier is what actually goes to the server.
+func HashIdentifier(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func contactDbKey(name string) libkb.DbKey {
+	return libkb.DbKey{Typ: libkb.DBContacts, Key: strings.ToLower(name)}
+}
+
+// Import parses, resolves and stores an address book. Returns how
+// many entries resolved to Keybase users.
+func Import(m libkb.MetaContext, data []byte) (resolved int, total int, err error) {
+	contacts, err := ParseContacts(data)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// hash -> owning contact index
+	hashOwner := make(map[string]int)
+	var hashes []string
+	for i, contact := range contacts {
+		for _, email := range contact.Emails {
+			h := HashIdentifier(NormalizeEmail(email))
+			hashOwner[h] = i
+			hashes = append(hashes, h)
+		}
+		for _, phone := range contact.Phones {
+			if p := NormalizePhone(phone); p != "" {
+				h := HashIdentifier(p)
+				hashOwner[h] = i
+				hashes = append(hashes, h)
+			}
+		}
+	}
+	if len(hashes) == 0 {
+		return 0, len(contacts), nil
+	}
+
+	res, err := m.G().API.Post(libkb.APIArg{
+		Endpoint:    "contacts/lookup",
+		SessionType: libkb.APISessionTypeREQUIRED,
+		Args: libkb.HTTPArgs{
+			"hashes": libkb.S{Val: strings.Join(hashes, ",")},
+		},
+		MetaContext: m,
+	})
+	if err != nil {
+		return 0, len(contacts), err
+	}
+
+	resolutions := res.Body.AtKey("resolutions")
+	usernames := make(map[int]string)
+	for h, idx := range hashOwner {
+		username, err := resolutions.AtKey(h).GetString()
+		if err != nil || username == "" {
+			continue
+		}
+		usernames[idx] = username
+	}
+
+	for idx, username := range usernames {
+		entry := keybase1.ResolvedContact{
+			ContactName: contacts[idx].Name,
+			Username:    username,
+		}
+		if err := m.G().LocalDb.PutObj(contactDbKey(contacts[idx].Name), nil, entry); err != nil {
+			return resolved, len(contacts), err
+		}
+		resolved++
+	}
+	return resolved, len(contacts), nil
+}
+
+// LookupByName returns the Keybase username an imported contact
+// resolved to, or "" when unknown.
+func LookupByName(m libkb.MetaContext, name string) (string, error) {
+	var entry keybase1.ResolvedContact
+	found, err := m.G().LocalDb.GetInto(&entry, contactDbKey(name))
+	if err != nil || !found {
+		return "", err
+	}
+	return entry.Username, nil
+}
+
+// List returns every stored resolution.
+func List(m libkb.MetaContext) (res []keybase1.ResolvedContact, err error) {
+	err = m.G().LocalDb.ForEachRawKv(func(id libkb.DbKey, _ []byte) error {
+		if id.Typ != libkb.DBContacts {
+			return nil
+		}
+		var entry keybase1.ResolvedContact
+		if found, err := m.G().LocalDb.GetInto(&entry, id); err == nil && found {
+			res = append(res, entry)
+		}
+		return nil
+	})
+	return res, err
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package contacts
+
+import "testing"
+
+func TestParseContactsJSON(t *testing.T) {
+	data := `[{"name": "Bob Jones", "emails": ["Bob@Example.com"], "phones": ["+1 (555) 010-2030"]}]`
+	contacts, err := ParseContacts([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contacts) != 1 {
+		t.Fatalf("expected 1 contact, got %d", len(contacts))
+	}
+	if contacts[0].Name != "Bob Jones" {
+		t.Errorf("name: %q", contacts[0].Name)
+	}
+}
+
+func TestParseVCard(t *testing.T) {
+	data := "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Alice Smith\r\nEMAIL;TYPE=work:alice@example.com\r\nTEL;TYPE=cell:+1 555 010 2030\r\nEND:VCARD\r\nBEGIN:VCARD\r\nFN:No Identifiers\r\nEND:VCARD\r\n"
+	contacts, err := ParseContacts([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts, got %d", len(contacts))
+	}
+	if contacts[0].Name != "Alice Smith" {
+		t.Errorf("name: %q", contacts[0].Name)
+	}
+	if len(contacts[0].Emails) != 1 || contacts[0].Emails[0] != "alice@example.com" {
+		t.Errorf("emails: %v", contacts[0].Emails)
+	}
+	if len(contacts[0].Phones) != 1 || contacts[0].Phones[0] != "+1 555 010 2030" {
+		t.Errorf("phones: %v", contacts[0].Phones)
+	}
+}
+
+func TestParseContactsBadFormat(t *testing.T) {
+	if _, err := ParseContacts([]byte("name,email\nbob,b@x.com")); err == nil {
+		t.Fatal("expected an error for CSV input")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := NormalizeEmail(" Bob@Example.COM "); got != "bob@example.com" {
+		t.Errorf("email: %q", got)
+	}
+	if got := NormalizePhone("+1 (555) 010-2030"); got != "+15550102030" {
+		t.Errorf("phone: %q", got)
+	}
+	if got := NormalizePhone("555 1212 x+44"); got != "5551212" {
+		t.Errorf("phone with stray plus: %q", got)
+	}
+}
+
+func TestHashIdentifierStable(t *testing.T) {
+	a := HashIdentifier("bob@example.com")
+	b := HashIdentifier("bob@example.com")
+	if a != b || len(a) != 64 {
+		t.Errorf("hash not stable or wrong length: %q vs %q", a, b)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// BotProvision mints a capability-scoped paper-style key pair for an
+// automation bot: the delegation links carry body.capabilities, so the
+// resulting credential can, say, send chat but never delegate further
+// keys or sign anything outside its scope. The phrase is the bot's
+// whole credential; hand it to the bot host and nothing else.
+type BotProvision struct {
+	libkb.Contextified
+	capabilities []string
+	passphrase   libkb.PaperKeyPhrase
+}
+
+func NewBotProvision(g *libkb.GlobalContext, capabilities []string) *BotProvision {
+	return &BotProvision{
+		Contextified: libkb.NewContextified(g),
+		capabilities: capabilities,
+	}
+}
+
+// Name is the unique engine name.
+func (e *BotProvision) Name() string {
+	return "BotProvision"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *BotProvision) Prereqs() Prereqs {
+	return Prereqs{
+		Device: true,
+	}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *BotProvision) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.SecretUIKind,
+	}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *BotProvision) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{
+		&PaperKeyGen{},
+	}
+}
+
+// Run starts the engine.
+func (e *BotProvision) Run(m libkb.MetaContext) error {
+	if len(e.capabilities) == 0 {
+		return fmt.Errorf("bot provisioning requires at least one capability")
+	}
+
+	me, err := libkb.LoadMe(libkb.NewLoadUserArgWithMetaContext(m))
+	if err != nil {
+		return err
+	}
+
+	ska1 := libkb.SecretKeyArg{
+		Me:      me,
+		KeyType: libkb.DeviceSigningKeyType,
+	}
+	signingKey, err := m.G().Keyrings.GetSecretKeyWithPrompt(m,
+		m.SecretKeyPromptArg(ska1, "You must sign the new bot key"))
+	if err != nil {
+		return err
+	}
+
+	ska2 := libkb.SecretKeyArg{
+		Me:      me,
+		KeyType: libkb.DeviceEncryptionKeyType,
+	}
+	encryptionKeyGeneric, err := m.G().Keyrings.GetSecretKeyWithPrompt(m,
+		m.SecretKeyPromptArg(ska2, "You must encrypt for the new bot key"))
+	if err != nil {
+		return err
+	}
+	encryptionKey, ok := encryptionKeyGeneric.(libkb.NaclDHKeyPair)
+	if !ok {
+		return fmt.Errorf("unexpected encryption key type")
+	}
+
+	e.passphrase, err = libkb.MakePaperKeyPhrase(libkb.PaperKeyVersion)
+	if err != nil {
+		return err
+	}
+
+	kgarg := &PaperKeyGenArg{
+		Passphrase:    e.passphrase,
+		Me:            me,
+		SigningKey:    signingKey,
+		EncryptionKey: encryptionKey,
+		Capabilities:  e.capabilities,
+	}
+	return RunEngine2(m, NewPaperKeyGen(m.G(), kgarg))
+}
+
+// Passphrase is the scoped bot credential.
+func (e *BotProvision) Passphrase() string {
+	return e.passphrase.String()
+}
@@ -131,6 +131,16 @@ func (e *DeviceHistory) loadDevices(m libkb.MetaContext, user *libkb.User) error
 			exp.CurrentDevice = true
 		}
 
+		// Machine-readable inventory fields: where in the chain the key
+		// was delegated (and revoked), and who signed the delegation.
+		exp.DelegationSeqno = cki.DelegatedAtSigChainLocation.Seqno
+		if len(cki.DelegationsList) > 0 {
+			exp.DelegationSigningKID = cki.DelegationsList[0].KID
+		}
+		if cki.RevokedAtSigChainLocation != nil {
+			exp.RevocationSeqno = cki.RevokedAtSigChainLocation.Seqno
+		}
+
 		e.devices = append(e.devices, exp)
 	}
 
@@ -1042,7 +1042,7 @@ func (e *Identify2WithUID) loadSlowCacheFromDB(m libkb.MetaContext) (ret *keybas
 	tm := ktm.Time()
 	now := e.getNow()
 	diff := now.Sub(tm)
-	if diff > libkb.Identify2CacheLongTimeout {
+	if diff > e.G().Env.GetIdentifyCacheTimeout() {
 		m.CDebugf("| Object timed out %s ago", diff)
 		return nil
 	}
@@ -1105,7 +1105,7 @@ func (e *Identify2WithUID) checkSlowCacheHit(m libkb.MetaContext) (ret bool) {
 		if u.TrackBreaks != nil {
 			return libkb.Identify2CacheBrokenTimeout
 		}
-		return libkb.Identify2CacheLongTimeout
+		return e.G().Env.GetIdentifyCacheTimeout()
 	}
 	u, err := e.getCache().Get(e.them.GetUID(), tfn, dfn, e.arg.IdentifyBehavior.WarningInsteadOfErrorOnBrokenTracks())
 
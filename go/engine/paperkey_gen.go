@@ -28,6 +28,10 @@ type PaperKeyGenArg struct {
 	SigningKey     libkb.GenericKey      // optional
 	EncryptionKey  libkb.NaclDHKeyPair   // optional
 	PerUserKeyring *libkb.PerUserKeyring // optional
+
+	// Capabilities, when set, are signed into the delegation links so
+	// the generated key pair is scoped (bot provisioning).
+	Capabilities []string
 }
 
 // PaperKeyGen is an engine.
@@ -276,6 +280,7 @@ func (e *PaperKeyGen) push(m libkb.MetaContext) (err error) {
 	// push the paper signing key
 	sigDel := libkb.Delegator{
 		NewKey:         e.sigKey,
+		Capabilities:   e.arg.Capabilities,
 		DelegationType: libkb.DelegationTypeSibkey,
 		Expire:         libkb.NaclEdDSAExpireIn,
 		ExistingKey:    e.arg.SigningKey,
@@ -287,6 +292,7 @@ func (e *PaperKeyGen) push(m libkb.MetaContext) (err error) {
 	// push the paper encryption key
 	sigEnc := libkb.Delegator{
 		NewKey:         e.encKey,
+		Capabilities:   e.arg.Capabilities,
 		DelegationType: libkb.DelegationTypeSubkey,
 		Expire:         libkb.NaclDHExpireIn,
 		ExistingKey:    e.sigKey,
@@ -0,0 +1,188 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// PGPRefreshBackground periodically re-fetches followed users' PGP keys
+// from their published locations (a configurable keyserver speaking the
+// VKS by-fingerprint interface, keys.openpgp.org by default) and
+// compares the fetched key's full hash against what the sigchain last
+// recorded. Keys that changed off-chain — new subkeys, revocations,
+// expiry bumps that were never pushed as pgp_update links — get flagged
+// in the logs so users learn about drift.
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+var PGPRefreshBackgroundSettings = BackgroundTaskSettings{
+	Start:        2 * time.Minute,
+	StartStagger: 5 * time.Minute,
+	WakeUp:       time.Minute,
+	Interval:     24 * time.Hour,
+	Limit:        10 * time.Minute,
+}
+
+// PGPRefreshBackground is an engine.
+type PGPRefreshBackground struct {
+	libkb.Contextified
+	sync.Mutex
+
+	args *PGPRefreshBackgroundArgs
+	task *BackgroundTask
+}
+
+type PGPRefreshBackgroundArgs struct {
+	// Channels used for testing. Normally nil.
+	testingMetaCh     chan<- string
+	testingRoundResCh chan<- error
+}
+
+// NewPGPRefreshBackground creates a PGPRefreshBackground engine.
+func NewPGPRefreshBackground(g *libkb.GlobalContext, args *PGPRefreshBackgroundArgs) *PGPRefreshBackground {
+	task := NewBackgroundTask(g, &BackgroundTaskArgs{
+		Name:     "PGPRefreshBackground",
+		F:        PGPRefreshBackgroundRound,
+		Settings: PGPRefreshBackgroundSettings,
+
+		testingMetaCh:     args.testingMetaCh,
+		testingRoundResCh: args.testingRoundResCh,
+	})
+	return &PGPRefreshBackground{
+		Contextified: libkb.NewContextified(g),
+		args:         args,
+		task:         task,
+	}
+}
+
+// Name is the unique engine name.
+func (e *PGPRefreshBackground) Name() string {
+	return "PGPRefreshBackground"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *PGPRefreshBackground) Prereqs() Prereqs {
+	return Prereqs{}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *PGPRefreshBackground) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *PGPRefreshBackground) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{}
+}
+
+// Run starts the engine. Returns immediately, kicks off a background goroutine.
+func (e *PGPRefreshBackground) Run(m libkb.MetaContext) (err error) {
+	return RunEngine2(m, e.task)
+}
+
+func (e *PGPRefreshBackground) Shutdown() {
+	e.task.Shutdown()
+}
+
+// pgpKeyserverURL returns the keyserver base URL, or "" if the refresh
+// job is disabled.
+func pgpKeyserverURL(m libkb.MetaContext) string {
+	if enabled, _ := m.G().Env.GetConfig().GetBoolAtPath("pgp.refresh_enabled"); !enabled {
+		return ""
+	}
+	if url, ok := m.G().Env.GetConfig().GetStringAtPath("pgp.keyserver_url"); ok && url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return "https://keys.openpgp.org"
+}
+
+func PGPRefreshBackgroundRound(m libkb.MetaContext) error {
+	serverURL := pgpKeyserverURL(m)
+	if serverURL == "" {
+		m.CDebugf("PGPRefreshBackgroundRound: disabled (set pgp.refresh_enabled)")
+		return nil
+	}
+	if m.G().ConnectivityMonitor.IsConnected(m.Ctx()) == libkb.ConnectivityMonitorNo {
+		m.CDebugf("PGPRefreshBackgroundRound giving up offline")
+		return nil
+	}
+	if !m.G().ActiveDevice.Valid() {
+		m.CDebugf("PGPRefreshBackgroundRound not logged in")
+		return nil
+	}
+
+	me := m.G().GetMyUID()
+	followed, err := m.G().GetUPAKLoader().ListFollowedUIDs(m.Ctx(), me)
+	if err != nil {
+		return err
+	}
+
+	for _, uid := range followed {
+		if err := pgpRefreshCheckUser(m, serverURL, uid); err != nil {
+			m.CDebugf("PGPRefreshBackgroundRound: check of %s failed: %s", uid, err)
+		}
+	}
+	return nil
+}
+
+// pgpRefreshCheckUser fetches each of the user's active PGP keys from
+// the keyserver and compares full hashes against the sigchain's view.
+func pgpRefreshCheckUser(m libkb.MetaContext, serverURL string, uid keybase1.UID) error {
+	user, err := libkb.LoadUser(libkb.NewLoadUserArgWithMetaContext(m).WithUID(uid).WithPublicKeyOptional())
+	if err != nil {
+		return err
+	}
+	ckf := user.GetComputedKeyFamily()
+	if ckf == nil {
+		return nil
+	}
+
+	for _, fp := range user.GetActivePGPFingerprints(false) {
+		published, err := fetchPGPKeyByFingerprint(m, serverURL, fp)
+		if err != nil {
+			m.CDebugf("PGPRefresh: fetch of %s for %s failed: %s", fp, user.GetName(), err)
+			continue
+		}
+		publishedHash, err := published.FullHash()
+		if err != nil {
+			continue
+		}
+
+		kid, err := ckf.FindKIDFromFingerprint(fp)
+		if err != nil {
+			continue
+		}
+		chainHash := ckf.ActivePGPHashForKID(kid)
+		if chainHash == "" {
+			// Chains that predate full-hash tracking can't be compared.
+			m.CDebugf("PGPRefresh: no sigchain hash recorded for %s (%s); skipping", fp, user.GetName())
+			continue
+		}
+		if chainHash != publishedHash {
+			m.CWarningf("PGP key %s of %s has changed off-chain: keyserver hash %s != sigchain hash %s. "+
+				"They may need to run `keybase pgp update`.",
+				fp, user.GetName(), publishedHash, chainHash)
+		}
+	}
+	return nil
+}
+
+// fetchPGPKeyByFingerprint pulls an armored key via the VKS
+// by-fingerprint interface.
+func fetchPGPKeyByFingerprint(m libkb.MetaContext, serverURL string, fp libkb.PGPFingerprint) (*libkb.PGPKeyBundle, error) {
+	res, err := m.G().GetExternalAPI().GetText(libkb.APIArg{
+		Endpoint:    fmt.Sprintf("%s/vks/v1/by-fingerprint/%s", serverURL, strings.ToUpper(fp.String())),
+		MetaContext: m,
+	})
+	if err != nil {
+		return nil, err
+	}
+	bundle, _, err := libkb.ReadOneKeyFromString(res.Body)
+	return bundle, err
+}
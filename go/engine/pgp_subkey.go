@@ -0,0 +1,131 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// PGPSubkeyEngine adds or revokes encryption subkeys on a PGP key held
+// in the keybase keyring: the bundle is unlocked, mutated and re-signed
+// locally, written back to the keyring, and the updated public bundle
+// is pushed with a pgp_update chain link.
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+type PGPSubkeyOp int
+
+const (
+	PGPSubkeyOpAdd PGPSubkeyOp = iota
+	PGPSubkeyOpRevoke
+)
+
+type PGPSubkeyEngineArg struct {
+	Op          PGPSubkeyOp
+	Fingerprint *libkb.PGPFingerprint // which primary key; nil means the only one
+	KeyIDSuffix string                // for revoke: which subkey
+}
+
+type PGPSubkeyEngine struct {
+	libkb.Contextified
+	arg PGPSubkeyEngineArg
+}
+
+func NewPGPSubkeyEngine(g *libkb.GlobalContext, arg PGPSubkeyEngineArg) *PGPSubkeyEngine {
+	return &PGPSubkeyEngine{
+		arg:          arg,
+		Contextified: libkb.NewContextified(g),
+	}
+}
+
+func (e *PGPSubkeyEngine) Name() string {
+	return "PGPSubkey"
+}
+
+func (e *PGPSubkeyEngine) Prereqs() Prereqs {
+	return Prereqs{Device: true}
+}
+
+func (e *PGPSubkeyEngine) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.LogUIKind,
+		libkb.SecretUIKind,
+	}
+}
+
+func (e *PGPSubkeyEngine) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{}
+}
+
+func (e *PGPSubkeyEngine) Run(m libkb.MetaContext) (err error) {
+	defer m.CTrace("PGPSubkeyEngine#Run", func() error { return err })()
+
+	me, err := libkb.LoadMe(libkb.NewLoadUserArgWithMetaContext(m))
+	if err != nil {
+		return err
+	}
+
+	// Unlock the PGP secret key from the keybase keyring.
+	ska := libkb.SecretKeyArg{
+		Me:       me,
+		KeyType:  libkb.PGPKeyType,
+		KeyQuery: e.keyQuery(),
+	}
+	key, err := m.G().Keyrings.GetSecretKeyWithPrompt(m, m.SecretKeyPromptArg(ska, "modify PGP subkeys"))
+	if err != nil {
+		return err
+	}
+	bundle, ok := key.(*libkb.PGPKeyBundle)
+	if !ok {
+		return fmt.Errorf("expected a PGP key, got %T", key)
+	}
+
+	switch e.arg.Op {
+	case PGPSubkeyOpAdd:
+		if err = bundle.AddEncryptionSubkey(); err != nil {
+			return err
+		}
+		m.UIs().LogUI.Info("Added a new encryption subkey to %s.", bundle.GetFingerprint())
+	case PGPSubkeyOpRevoke:
+		if err = bundle.RevokeSubkey(e.arg.KeyIDSuffix); err != nil {
+			return err
+		}
+		m.UIs().LogUI.Info("Revoked subkey %s on %s.", e.arg.KeyIDSuffix, bundle.GetFingerprint())
+	default:
+		return fmt.Errorf("unknown subkey operation")
+	}
+
+	// Save the mutated secret bundle back to the local keyring.
+	lks, err := libkb.NewLKSecForEncrypt(m, m.UIs().SecretUI, me.GetUID())
+	if err != nil {
+		return err
+	}
+	if _, err = libkb.WriteLksSKBToKeyring(m, bundle, lks); err != nil {
+		return err
+	}
+
+	// Push the updated public bundle with a pgp_update link.
+	del := libkb.Delegator{
+		DelegationType: libkb.DelegationTypePGPUpdate,
+		Me:             me,
+		Expire:         libkb.KeyExpireIn,
+		NewKey:         bundle,
+		Contextified:   libkb.NewContextified(e.G()),
+	}
+	if err = del.LoadSigningKey(m, m.UIs().SecretUI); err != nil {
+		return err
+	}
+	if err = del.Run(m); err != nil {
+		return err
+	}
+	m.UIs().LogUI.Info("Posted pgp_update for key %s.", bundle.GetFingerprint())
+	return nil
+}
+
+func (e *PGPSubkeyEngine) keyQuery() string {
+	if e.arg.Fingerprint == nil {
+		return ""
+	}
+	return e.arg.Fingerprint.String()
+}
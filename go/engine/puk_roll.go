@@ -67,6 +67,14 @@ func (e *PerUserKeyRoll) inner(m libkb.MetaContext) error {
 		return libkb.NoUIDError{}
 	}
 
+	// In multi-device approval mode, PUK rotation is a high link and
+	// needs a second device's sign-off.
+	if libkb.HighLinkApprovalRequired(m, libkb.HighLinkOperationPerUserKey) {
+		if err := libkb.ConsumeHighLinkApproval(m, libkb.HighLinkOperationPerUserKey); err != nil {
+			return err
+		}
+	}
+
 	me := e.args.Me
 	if me == nil {
 		m.CDebugf("PerUserKeyRoll load self")
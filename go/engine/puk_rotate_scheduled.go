@@ -0,0 +1,132 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// PerUserKeyRollScheduled rolls the user's per-user-key on a policy
+// basis rather than only when a revoke forces it: either on a cadence
+// (puk.rotation_interval) or once a configurable number of device
+// revocations have happened since the last roll
+// (puk.rotate_after_revokes). Both policies default to off.
+package engine
+
+import (
+	"github.com/keybase/client/go/libkb"
+)
+
+// PerUserKeyRollScheduled is an engine.
+type PerUserKeyRollScheduled struct {
+	libkb.Contextified
+	args       *PerUserKeyRollScheduledArgs
+	DidRollKey bool
+}
+
+type PerUserKeyRollScheduledArgs struct{}
+
+// NewPerUserKeyRollScheduled creates a PerUserKeyRollScheduled engine.
+func NewPerUserKeyRollScheduled(g *libkb.GlobalContext, args *PerUserKeyRollScheduledArgs) *PerUserKeyRollScheduled {
+	return &PerUserKeyRollScheduled{
+		args:         args,
+		Contextified: libkb.NewContextified(g),
+	}
+}
+
+// Name is the unique engine name.
+func (e *PerUserKeyRollScheduled) Name() string {
+	return "PerUserKeyRollScheduled"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *PerUserKeyRollScheduled) Prereqs() Prereqs {
+	return Prereqs{
+		Device: true,
+	}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *PerUserKeyRollScheduled) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *PerUserKeyRollScheduled) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{NewPerUserKeyRoll(e.G(), &PerUserKeyRollArgs{})}
+}
+
+// Run starts the engine.
+func (e *PerUserKeyRollScheduled) Run(m libkb.MetaContext) (err error) {
+	defer m.CTrace("PerUserKeyRollScheduled", func() error { return err })()
+	return e.inner(m)
+}
+
+func (e *PerUserKeyRollScheduled) inner(m libkb.MetaContext) error {
+	interval := m.G().Env.GetPUKRotationInterval()
+	afterRevokes := m.G().Env.GetPUKRotateAfterRevokes()
+	if interval == 0 && afterRevokes == 0 {
+		m.CDebugf("PerUserKeyRollScheduled: no rotation policy configured")
+		return nil
+	}
+
+	uid := m.G().GetMyUID()
+	if uid.IsNil() {
+		return libkb.NoUIDError{}
+	}
+
+	loadArg := libkb.NewLoadUserArgWithMetaContext(m).
+		WithUID(uid).
+		WithSelf(true).
+		WithPublicKeyOptional()
+	upak, me, err := m.G().GetUPAKLoader().LoadV2(loadArg)
+	if err != nil {
+		return err
+	}
+
+	// No PUK yet: that's PerUserKeyUpgrade's job, not ours.
+	latest := upak.Current.GetLatestPerUserKey()
+	if latest == nil {
+		m.CDebugf("PerUserKeyRollScheduled: user has no per-user-key")
+		return nil
+	}
+
+	due := false
+
+	if afterRevokes > 0 {
+		revokesSince := 0
+		for _, key := range upak.Current.DeviceKeys {
+			if key.Base.Revocation != nil && key.Base.Revocation.SigChainLocation.Seqno > latest.Seqno {
+				revokesSince++
+			}
+		}
+		if revokesSince >= afterRevokes {
+			m.CDebugf("PerUserKeyRollScheduled: %d revocations since last roll (threshold %d)", revokesSince, afterRevokes)
+			due = true
+		}
+	}
+
+	if !due && interval > 0 {
+		if me == nil {
+			me, err = libkb.LoadMe(libkb.NewLoadUserArgWithMetaContext(m).WithPublicKeyOptional())
+			if err != nil {
+				return err
+			}
+		}
+		ctime := me.GetLinkCTimeFromSeqno(latest.Seqno)
+		if !ctime.IsZero() && m.G().Clock().Now().Sub(ctime) > interval {
+			m.CDebugf("PerUserKeyRollScheduled: last roll at %s exceeds cadence %s", ctime, interval)
+			due = true
+		}
+	}
+
+	if !due {
+		m.CDebugf("PerUserKeyRollScheduled: rotation not due")
+		return nil
+	}
+
+	eng := NewPerUserKeyRoll(e.G(), &PerUserKeyRollArgs{Me: me})
+	if err := RunEngine2(m, eng); err != nil {
+		return err
+	}
+	e.DidRollKey = eng.DidNewKey
+	if e.DidRollKey {
+		m.G().NotifyRouter.HandleUserChanged(uid)
+	}
+	return nil
+}
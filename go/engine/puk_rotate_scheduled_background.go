@@ -0,0 +1,108 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// PerUserKeyRollScheduledBackground runs PerUserKeyRollScheduled in the
+// background once in a while, enforcing the user's configured PUK
+// rotation policy. See PerUserKeyRollScheduled for more info.
+
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+var PerUserKeyRollScheduledBackgroundSettings = BackgroundTaskSettings{
+	Start:        30 * time.Second, // Wait after starting the app
+	StartStagger: 30 * time.Second, // Wait an additional random amount.
+	WakeUp:       15 * time.Second, // Additional delay after waking from sleep.
+	Interval:     12 * time.Hour,   // Wait between checks
+	Limit:        5 * time.Minute,  // Time limit on each round
+}
+
+// PerUserKeyRollScheduledBackground is an engine.
+type PerUserKeyRollScheduledBackground struct {
+	libkb.Contextified
+	sync.Mutex
+
+	args *PerUserKeyRollScheduledBackgroundArgs
+	task *BackgroundTask
+}
+
+type PerUserKeyRollScheduledBackgroundArgs struct {
+	// Channels used for testing. Normally nil.
+	testingMetaCh     chan<- string
+	testingRoundResCh chan<- error
+}
+
+// NewPerUserKeyRollScheduledBackground creates a PerUserKeyRollScheduledBackground engine.
+func NewPerUserKeyRollScheduledBackground(g *libkb.GlobalContext, args *PerUserKeyRollScheduledBackgroundArgs) *PerUserKeyRollScheduledBackground {
+	task := NewBackgroundTask(g, &BackgroundTaskArgs{
+		Name:     "PerUserKeyRollScheduledBackground",
+		F:        PerUserKeyRollScheduledBackgroundRound,
+		Settings: PerUserKeyRollScheduledBackgroundSettings,
+
+		testingMetaCh:     args.testingMetaCh,
+		testingRoundResCh: args.testingRoundResCh,
+	})
+	return &PerUserKeyRollScheduledBackground{
+		Contextified: libkb.NewContextified(g),
+		args:         args,
+		// Install the task early so that Shutdown can be called before RunEngine.
+		task: task,
+	}
+}
+
+// Name is the unique engine name.
+func (e *PerUserKeyRollScheduledBackground) Name() string {
+	return "PerUserKeyRollScheduledBackground"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *PerUserKeyRollScheduledBackground) Prereqs() Prereqs {
+	return Prereqs{}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *PerUserKeyRollScheduledBackground) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *PerUserKeyRollScheduledBackground) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{&PerUserKeyRollScheduled{}}
+}
+
+// Run starts the engine.
+// Returns immediately, kicks off a background goroutine.
+func (e *PerUserKeyRollScheduledBackground) Run(m libkb.MetaContext) (err error) {
+	return RunEngine2(m, e.task)
+}
+
+func (e *PerUserKeyRollScheduledBackground) Shutdown() {
+	e.task.Shutdown()
+}
+
+func PerUserKeyRollScheduledBackgroundRound(m libkb.MetaContext) error {
+	if m.G().ConnectivityMonitor.IsConnected(m.Ctx()) == libkb.ConnectivityMonitorNo {
+		m.CDebugf("PerUserKeyRollScheduledBackgroundRound giving up offline")
+		return nil
+	}
+
+	if !m.G().ActiveDevice.Valid() {
+		m.CDebugf("PerUserKeyRollScheduledBackgroundRound not logged in")
+		return nil
+	}
+
+	if !m.G().LocalSigchainGuard().IsAvailable(m.Ctx(), "PerUserKeyRollScheduledBackgroundRound") {
+		m.CDebugf("PerUserKeyRollScheduledBackgroundRound yielding to guard")
+		return nil
+	}
+
+	arg := &PerUserKeyRollScheduledArgs{}
+	eng := NewPerUserKeyRollScheduled(m.G(), arg)
+	err := RunEngine2(m, eng)
+	return err
+}
@@ -185,6 +185,28 @@ func (e *SaltpackDecrypt) Run(m libkb.MetaContext) (err error) {
 			}
 			addToKeyring(keyring, key)
 		}
+
+		// Also add our user ephemeral keys, so exploding messages
+		// decrypt while their keys are still alive.
+		if ekBoxStorage := m.G().GetUserEKBoxStorage(); ekBoxStorage != nil {
+			maxGen, ekErr := ekBoxStorage.MaxGeneration(m.Ctx())
+			if ekErr != nil {
+				m.CDebugf("cannot enumerate user EKs for decryption: %s", ekErr)
+				maxGen = 0
+			}
+			for gen := keybase1.EkGeneration(1); gen <= maxGen; gen++ {
+				ek, ekErr := ekBoxStorage.Get(m.Ctx(), gen)
+				if ekErr != nil {
+					continue
+				}
+				ekPair, ekErr := userEKSeedToDHKey(ek.Seed)
+				if ekErr != nil {
+					continue
+				}
+				m.CDebugf("adding user EK at generation %v for decryption", gen)
+				addToKeyring(keyring, ekPair)
+			}
+		}
 	}
 
 	// For DH mode.
@@ -226,3 +248,17 @@ func (e *SaltpackDecrypt) Run(m libkb.MetaContext) (err error) {
 func (e *SaltpackDecrypt) MessageInfo() keybase1.SaltpackEncryptedMessageInfo {
 	return e.res
 }
+
+// userEKSeedToDHKey replays the ephemeral package's userEK DH key
+// derivation; engine can't import ephemeral (its tests import engine).
+func userEKSeedToDHKey(seed keybase1.Bytes32) (*libkb.NaclDHKeyPair, error) {
+	derived, err := libkb.DeriveFromSecret(seed, libkb.DeriveReasonUserEKEncryption)
+	if err != nil {
+		return nil, err
+	}
+	keypair, err := libkb.MakeNaclDHKeyPairFromSecret(derived)
+	if err != nil {
+		return nil, err
+	}
+	return &keypair, nil
+}
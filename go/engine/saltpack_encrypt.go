@@ -93,6 +93,7 @@ func (e *SaltpackEncrypt) Run(m libkb.MetaContext) (err error) {
 	kfarg := libkb.SaltpackRecipientKeyfinderArg{
 		Recipients:        e.arg.Opts.Recipients,
 		TeamRecipients:    e.arg.Opts.TeamRecipients,
+		UseExplodingKeys:  e.arg.Opts.UseExplodingKeys,
 		NoSelfEncrypt:     e.arg.Opts.NoSelfEncrypt,
 		UseEntityKeys:     e.arg.Opts.UseEntityKeys,
 		UsePaperKeys:      e.arg.Opts.UsePaperKeys,
@@ -14,7 +14,8 @@ type SaltpackSenderIdentifyArg struct {
 	interactive      bool
 	forceRemoteCheck bool
 	reason           keybase1.IdentifyReason
-	userAssertion    string // optional
+	userAssertion    string        // optional
+	signedAtTime     keybase1.Time // optional claimed signing time
 }
 
 type SaltpackSenderIdentify struct {
@@ -110,6 +111,18 @@ func (e *SaltpackSenderIdentify) Run(m libkb.MetaContext) (err error) {
 	e.res.Uid = user.GetUID()
 	e.res.Username = user.GetName()
 	if maybeSenderType != nil {
+		// A revoked key that was demonstrably active at the claimed
+		// signing time (per merkle-anchored sigchain timestamps) is a
+		// distinct outcome: the artifact is old, not forged.
+		if *maybeSenderType == keybase1.SaltpackSenderType_REVOKED && e.arg.signedAtTime != 0 {
+			active, aerr := user.GetComputedKeyFamily().KeyActiveAtTime(e.arg.publicKey, keybase1.FromTime(e.arg.signedAtTime))
+			if aerr == nil && active {
+				m.CInfof("Signing key has been revoked, but was valid at the claimed signing time (%s)",
+					keybase1.FromTime(e.arg.signedAtTime))
+				e.res.SenderType = keybase1.SaltpackSenderType_REVOKED_BUT_VALID_AT_SIGNING
+				return
+			}
+		}
 		e.res.SenderType = *maybeSenderType
 		return
 	}
@@ -87,6 +87,7 @@ func (e *SaltpackVerify) identifySender(m libkb.MetaContext, key saltpack.Signin
 			Type:   keybase1.IdentifyReasonType_VERIFY,
 		},
 		userAssertion: e.arg.Opts.SignedBy,
+		signedAtTime:  e.arg.Opts.SignedAtTime,
 	}
 
 	spsiEng := NewSaltpackSenderIdentify(m.G(), &spsiArg)
@@ -0,0 +1,143 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// TrackFreshnessBackground enforces the tracking freshness policy:
+// track statements older than track.max_age trigger a background
+// re-identify of the tracked user, so broken or changed proofs surface
+// without waiting for the next manual interaction. Configure with
+// `keybase follow set-policy`.
+
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+var TrackFreshnessBackgroundSettings = BackgroundTaskSettings{
+	Start:        time.Minute,      // Wait after starting the app
+	StartStagger: 5 * time.Minute,  // Wait an additional random amount.
+	WakeUp:       time.Minute,      // Additional delay after waking from sleep.
+	Interval:     24 * time.Hour,   // Wait between checks
+	Limit:        15 * time.Minute, // Time limit on each round
+}
+
+// TrackFreshnessBackground is an engine.
+type TrackFreshnessBackground struct {
+	libkb.Contextified
+	sync.Mutex
+
+	args *TrackFreshnessBackgroundArgs
+	task *BackgroundTask
+}
+
+type TrackFreshnessBackgroundArgs struct {
+	// Channels used for testing. Normally nil.
+	testingMetaCh     chan<- string
+	testingRoundResCh chan<- error
+}
+
+// NewTrackFreshnessBackground creates a TrackFreshnessBackground engine.
+func NewTrackFreshnessBackground(g *libkb.GlobalContext, args *TrackFreshnessBackgroundArgs) *TrackFreshnessBackground {
+	task := NewBackgroundTask(g, &BackgroundTaskArgs{
+		Name:     "TrackFreshnessBackground",
+		F:        TrackFreshnessBackgroundRound,
+		Settings: TrackFreshnessBackgroundSettings,
+
+		testingMetaCh:     args.testingMetaCh,
+		testingRoundResCh: args.testingRoundResCh,
+	})
+	return &TrackFreshnessBackground{
+		Contextified: libkb.NewContextified(g),
+		args:         args,
+		task:         task,
+	}
+}
+
+// Name is the unique engine name.
+func (e *TrackFreshnessBackground) Name() string {
+	return "TrackFreshnessBackground"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *TrackFreshnessBackground) Prereqs() Prereqs {
+	return Prereqs{}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *TrackFreshnessBackground) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *TrackFreshnessBackground) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{&ResolveThenIdentify2{}}
+}
+
+// Run starts the engine. Returns immediately, kicks off a background goroutine.
+func (e *TrackFreshnessBackground) Run(m libkb.MetaContext) (err error) {
+	return RunEngine2(m, e.task)
+}
+
+func (e *TrackFreshnessBackground) Shutdown() {
+	e.task.Shutdown()
+}
+
+func TrackFreshnessBackgroundRound(m libkb.MetaContext) error {
+	maxAge := m.G().Env.GetTrackMaxAge()
+	if maxAge == 0 {
+		m.CDebugf("TrackFreshnessBackgroundRound: no freshness policy configured")
+		return nil
+	}
+	if m.G().ConnectivityMonitor.IsConnected(m.Ctx()) == libkb.ConnectivityMonitorNo {
+		m.CDebugf("TrackFreshnessBackgroundRound giving up offline")
+		return nil
+	}
+	if !m.G().ActiveDevice.Valid() {
+		m.CDebugf("TrackFreshnessBackgroundRound not logged in")
+		return nil
+	}
+
+	var stale []*libkb.TrackChainLink
+	err := m.G().GetFullSelfer().WithSelf(m.Ctx(), func(me *libkb.User) error {
+		if me.IDTable() == nil {
+			return nil
+		}
+		now := m.G().Clock().Now()
+		for _, track := range me.IDTable().GetTrackList() {
+			if now.Sub(track.GetCTime()) > maxAge {
+				stale = append(stale, track)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	m.CDebugf("TrackFreshnessBackgroundRound: %d stale track(s) to re-verify", len(stale))
+	for _, track := range stale {
+		trackedUID, err := track.GetTrackedUID()
+		if err != nil {
+			continue
+		}
+		eng := NewIdentify2WithUID(m.G(), &keybase1.Identify2Arg{
+			Uid:              trackedUID,
+			CanSuppressUI:    true,
+			IdentifyBehavior: keybase1.TLFIdentifyBehavior_CHAT_CLI,
+			Reason:           keybase1.IdentifyReason{Reason: "background track freshness re-verification"},
+		})
+		if err := RunEngine2(m, eng); err != nil {
+			// A failed identify is exactly what the policy exists to
+			// surface; let tracker UIs know.
+			m.CWarningf("TrackFreshnessBackgroundRound: re-identify of %s failed: %s", trackedUID, err)
+			if name, nerr := track.GetTrackedUsername(); nerr == nil {
+				m.G().NotifyRouter.HandleTrackingChanged(trackedUID, name, true)
+			}
+		}
+	}
+	return nil
+}
@@ -387,3 +387,18 @@ func activeUserEKMetadata(ctx context.Context, g *libkb.GlobalContext, statement
 	}
 	return activeMetadata, nil
 }
+
+// FetchLatestUserEKMetadata returns the given user's current (signed,
+// verified) user ephemeral key metadata, for encrypting to them with
+// exploding semantics. Returns nil if the user has no active userEK.
+func FetchLatestUserEKMetadata(ctx context.Context, g *libkb.GlobalContext, uid keybase1.UID) (*keybase1.UserEkMetadata, error) {
+	statement, _, wrongKID, err := fetchUserEKStatement(ctx, g, uid)
+	if err != nil {
+		return nil, err
+	}
+	if wrongKID || statement == nil {
+		return nil, nil
+	}
+	metadata := statement.CurrentUserEkMetadata
+	return &metadata, nil
+}
@@ -191,6 +191,15 @@ func mainInner(g *libkb.GlobalContext, startupErrors []error) error {
 		return err
 	}
 
+	if !cl.IsService() {
+		// Honor a global --as <user> for just this command.
+		restore, err := client.MaybeSwitchAsUser(g)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
 	err = cmd.Run()
 	if !cl.IsService() && !cl.SkipOutOfDateCheck() {
 		// Errors that come up in printing this warning are logged but ignored.
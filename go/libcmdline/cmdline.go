@@ -495,6 +495,22 @@ func (p *CommandLine) PopulateApp(addHelp bool, extraFlags []cli.Flag) {
 			Name:  "log-format",
 			Usage: "Log format (default, plain, file, fancy).",
 		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "Output format for commands that support it (text, json).",
+		},
+		cli.BoolFlag{
+			Name:  "batch",
+			Usage: "Never prompt interactively; answer from --prompt-file or fail with exit code 30.",
+		},
+		cli.StringFlag{
+			Name:  "prompt-file",
+			Usage: "JSON file of prompt answers for --batch mode.",
+		},
+		cli.StringFlag{
+			Name:  "as",
+			Usage: "Run this command as another account provisioned on this device.",
+		},
 		cli.StringFlag{
 			Name:  "log-prefix",
 			Usage: "Specify a prefix for a unique log file name.",
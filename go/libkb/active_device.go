@@ -229,6 +229,20 @@ func (a *ActiveDevice) clear() error {
 	return nil
 }
 
+// ClearSecretKeys drops the in-memory private key material and
+// passphrase cache while leaving the login session (user, device ID)
+// intact: the locked state. The next operation that needs a key goes
+// back through SecretUI for the passphrase.
+func (a *ActiveDevice) ClearSecretKeys() {
+	a.Lock()
+	defer a.Unlock()
+	a.signingKey = nil
+	a.encryptionKey = nil
+	a.passphrase = nil
+	a.provisioningKey = nil
+	a.secretPromptCancelTimer.Reset()
+}
+
 func (a *ActiveDevice) SecretPromptCancelTimer() *CancelTimer {
 	a.RLock()
 	defer a.RUnlock()
@@ -286,8 +286,30 @@ func doRequestShared(m MetaContext, api Requester, arg APIArg, req *http.Request
 		m.CDebugf("| full request: json:%s querystring:%s", jpStr, argStr)
 	}
 
+	// Fail fast if the breaker has seen enough consecutive connection
+	// failures that the API server looks unreachable.
+	if !api.isExternal() && !m.G().APICircuitBreaker.AllowRequest(m) {
+		err = APINetError{err: APICircuitOpenError{}}
+		return
+	}
+
 	timer := m.G().Timers.Start(timerType)
+	requestStart := m.G().Clock().Now()
 	internalResp, canc, err := doRetry(m, arg, cli, req)
+	if !api.isExternal() {
+		if err != nil {
+			// Only transport failures count toward the breaker: a
+			// caller canceling its own context, or retries exhausted
+			// on HTTP statuses from a reachable server, say nothing
+			// about connectivity.
+			exhausted, ok := err.(APIRetryExhaustedError)
+			if m.Ctx().Err() == nil && !(ok && exhausted.StatusOnly) {
+				m.G().APICircuitBreaker.Failure(m)
+			}
+		} else {
+			m.G().APICircuitBreaker.Success(m)
+		}
+	}
 
 	finisher = func() {
 		if internalResp != nil {
@@ -348,6 +370,11 @@ func doRequestShared(m MetaContext, api Requester, arg APIArg, req *http.Request
 		}
 	}
 
+	// External endpoints are arbitrary URLs; only account for our own API.
+	if !api.isExternal() {
+		m.G().NetworkStats.Record(arg.Endpoint, int64(jsonBytes), m.G().Clock().Now().Sub(requestStart))
+	}
+
 	return internalResp, finisher, jw, nil
 }
 
@@ -365,6 +392,14 @@ func doRetry(m MetaContext, arg APIArg, cli *Client, req *http.Request) (*http.R
 	if ConnectivityMonitorNo == m.G().ConnectivityMonitor.IsConnected(m.Ctx()) {
 		arg.InitialTimeout = HTTPFastTimeout
 		arg.RetryCount = 0
+	} else {
+		policy := arg.RetryPolicy
+		if policy == nil {
+			policy = defaultRetryPolicy(arg.Endpoint, req.Method)
+		}
+		if policy != nil {
+			return doRetryWithPolicy(m, arg, cli, req, policy)
+		}
 	}
 
 	if arg.InitialTimeout == 0 && arg.RetryCount == 0 {
@@ -675,7 +710,36 @@ func (a *InternalAPIEngine) Get(arg APIArg) (*APIRes, error) {
 	if err != nil {
 		return nil, err
 	}
-	return a.DoRequest(arg, req)
+	if !arg.UseDiskCache {
+		return a.DoRequest(arg, req)
+	}
+
+	m := arg.GetMetaContext(a.G()).EnsureCtx().WithLogTag("API")
+	if body, found := apiDiskCacheGet(m, req.URL); found {
+		if res, err := a.apiResFromBody(arg, body); err == nil {
+			return res, nil
+		}
+		// A cached body that no longer decodes is treated as a miss.
+	}
+	res, err := a.DoRequest(arg, req)
+	if err == nil && res.HTTPStatus == 200 && res.AppStatus.Code == SCOk {
+		apiDiskCachePut(m, req.URL, res.Body)
+	}
+	return res, err
+}
+
+// apiResFromBody rebuilds an APIRes from a cached response body,
+// running the same status checks as doRequest.
+func (a *InternalAPIEngine) apiResFromBody(arg APIArg, jw *jsonw.Wrapper) (*APIRes, error) {
+	status, err := jw.AtKey("status").ToDictionary()
+	if err != nil {
+		return nil, err
+	}
+	appStatus, err := a.checkAppStatusFromJSONWrapper(arg, status)
+	if err != nil {
+		return nil, err
+	}
+	return &APIRes{status, jw, 200, appStatus}, nil
 }
 
 // GetResp performs a GET request and returns the http response. The finisher
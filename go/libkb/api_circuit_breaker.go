@@ -0,0 +1,122 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"sync"
+	"time"
+
+	context "golang.org/x/net/context"
+)
+
+const (
+	// apiBreakerThreshold is how many consecutive connection failures
+	// trip the breaker.
+	apiBreakerThreshold = 4
+
+	// apiBreakerCooldown is how long we fail fast after tripping before
+	// letting a probe request through.
+	apiBreakerCooldown = 15 * time.Second
+)
+
+// APICircuitBreaker sits in front of the internal API engine and trips
+// after consecutive connection failures to the API server. While open,
+// requests fail fast with APICircuitOpenError instead of each caller
+// timing out independently; after a cooldown, a single probe request is
+// let through, and its outcome closes the breaker or re-arms the
+// cooldown. HTTP-level errors don't count, only transport failures.
+type APICircuitBreaker struct {
+	Contextified
+	sync.Mutex
+	consecutiveFailures int
+	trippedAt           time.Time
+	probing             bool
+}
+
+func NewAPICircuitBreaker(g *GlobalContext) *APICircuitBreaker {
+	return &APICircuitBreaker{Contextified: NewContextified(g)}
+}
+
+// AllowRequest is consulted before each internal API request. It
+// returns false while the breaker is open and inside its cooldown.
+func (b *APICircuitBreaker) AllowRequest(m MetaContext) bool {
+	b.Lock()
+	defer b.Unlock()
+	if b.trippedAt.IsZero() {
+		return true
+	}
+	if b.G().Clock().Now().Sub(b.trippedAt) < apiBreakerCooldown {
+		return false
+	}
+	// Half-open: let one probe through, fail fast for everybody else
+	// until it reports back.
+	if b.probing {
+		return false
+	}
+	b.probing = true
+	m.CDebugf("APICircuitBreaker: cooldown over, probing")
+	return true
+}
+
+// Failure records a connection-level failure against the breaker.
+func (b *APICircuitBreaker) Failure(m MetaContext) {
+	b.Lock()
+	defer b.Unlock()
+	b.probing = false
+	if !b.trippedAt.IsZero() {
+		// Probe failed; restart the cooldown.
+		b.trippedAt = b.G().Clock().Now()
+		return
+	}
+	b.consecutiveFailures++
+	if b.consecutiveFailures >= apiBreakerThreshold {
+		b.trippedAt = b.G().Clock().Now()
+		m.CWarningf("APICircuitBreaker: tripped after %d consecutive connection failures", b.consecutiveFailures)
+	}
+}
+
+// Success resets the breaker after any successful request.
+func (b *APICircuitBreaker) Success(m MetaContext) {
+	b.Lock()
+	defer b.Unlock()
+	if !b.trippedAt.IsZero() {
+		m.CDebugf("APICircuitBreaker: reset after successful request")
+	}
+	b.consecutiveFailures = 0
+	b.trippedAt = time.Time{}
+	b.probing = false
+}
+
+// IsOpen is true while requests are failing fast.
+func (b *APICircuitBreaker) IsOpen() bool {
+	b.Lock()
+	defer b.Unlock()
+	return !b.trippedAt.IsZero()
+}
+
+// ConnectivityMonitorWithBreaker layers the breaker's view of the API
+// server over another ConnectivityMonitor, so that subsystems polling
+// G.ConnectivityMonitor (SigChainLoader offline mode, chat) observe the
+// breaker tripping without each timing out on its own.
+type ConnectivityMonitorWithBreaker struct {
+	breaker  *APICircuitBreaker
+	delegate ConnectivityMonitor
+}
+
+func NewConnectivityMonitorWithBreaker(breaker *APICircuitBreaker, delegate ConnectivityMonitor) ConnectivityMonitorWithBreaker {
+	return ConnectivityMonitorWithBreaker{breaker: breaker, delegate: delegate}
+}
+
+func (c ConnectivityMonitorWithBreaker) IsConnected(ctx context.Context) ConnectivityMonitorResult {
+	if c.breaker.IsOpen() {
+		return ConnectivityMonitorNo
+	}
+	return c.delegate.IsConnected(ctx)
+}
+
+func (c ConnectivityMonitorWithBreaker) CheckReachability(ctx context.Context) error {
+	return c.delegate.CheckReachability(ctx)
+}
+
+var _ ConnectivityMonitor = ConnectivityMonitorWithBreaker{}
@@ -0,0 +1,66 @@
+package libkb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keybase/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPICircuitBreaker(t *testing.T) {
+	tc := SetupTest(t, "api_circuit_breaker", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+	fakeClock := clockwork.NewFakeClockAt(time.Now())
+	tc.G.SetClock(fakeClock)
+
+	b := NewAPICircuitBreaker(tc.G)
+
+	// Below the threshold, requests keep flowing.
+	for i := 0; i < apiBreakerThreshold-1; i++ {
+		require.True(t, b.AllowRequest(m))
+		b.Failure(m)
+	}
+	require.False(t, b.IsOpen())
+	require.True(t, b.AllowRequest(m))
+
+	// One more failure trips it.
+	b.Failure(m)
+	require.True(t, b.IsOpen())
+	require.False(t, b.AllowRequest(m))
+
+	// After the cooldown, exactly one probe goes through.
+	fakeClock.Advance(apiBreakerCooldown + time.Second)
+	require.True(t, b.AllowRequest(m))
+	require.False(t, b.AllowRequest(m))
+
+	// Failed probe re-arms the cooldown.
+	b.Failure(m)
+	require.False(t, b.AllowRequest(m))
+
+	// Successful probe closes the breaker.
+	fakeClock.Advance(apiBreakerCooldown + time.Second)
+	require.True(t, b.AllowRequest(m))
+	b.Success(m)
+	require.False(t, b.IsOpen())
+	require.True(t, b.AllowRequest(m))
+}
+
+func TestConnectivityMonitorWithBreaker(t *testing.T) {
+	tc := SetupTest(t, "api_circuit_breaker", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+
+	b := NewAPICircuitBreaker(tc.G)
+	cm := NewConnectivityMonitorWithBreaker(b, NullConnectivityMonitor{})
+	require.Equal(t, ConnectivityMonitorYes, cm.IsConnected(m.Ctx()))
+
+	for i := 0; i < apiBreakerThreshold; i++ {
+		b.Failure(m)
+	}
+	require.Equal(t, ConnectivityMonitorNo, cm.IsConnected(m.Ctx()))
+
+	b.Success(m)
+	require.Equal(t, ConnectivityMonitorYes, cm.IsConnected(m.Ctx()))
+}
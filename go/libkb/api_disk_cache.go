@@ -0,0 +1,64 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+
+	jsonw "github.com/keybase/go-jsonw"
+)
+
+// A small disk-backed cache for idempotent API GETs, layered into
+// InternalAPIEngine.Get for requests that opt in via APIArg.UseDiskCache.
+// Entries are keyed by the normalized request URL (query args are
+// sorted by PrepareGet), so only queries whose replies are fully
+// determined by the URL belong here: sig/get with explicit seqno
+// ranges, merkle/path pinned to a hash_meta, key fetches by KID.
+// Multiple subsystems asking for the same immutable data then share one
+// download, across processes and restarts.
+
+// apiDiskCacheMaxAgeSeconds bounds entry lifetime. The data is
+// immutable, but a horizon keeps the cache from accumulating entries
+// for queries that never recur.
+const apiDiskCacheMaxAgeSeconds = 7 * 24 * 60 * 60
+
+func apiDiskCacheDbKey(u *url.URL) DbKey {
+	sum := sha256.Sum256([]byte(u.String()))
+	return DbKey{Typ: DBApiCache, Key: hex.EncodeToString(sum[:16])}
+}
+
+// apiDiskCacheGet returns the cached response body for a normalized
+// URL, or found=false on a miss, decode failure, or expired entry.
+func apiDiskCacheGet(m MetaContext, u *url.URL) (body *jsonw.Wrapper, found bool) {
+	jw, err := m.G().LocalDb.Get(apiDiskCacheDbKey(u))
+	if err != nil || jw == nil {
+		return nil, false
+	}
+	fetched, err := jw.AtKey("fetched").GetInt64()
+	if err != nil {
+		return nil, false
+	}
+	if m.G().Clock().Now().Unix()-fetched > apiDiskCacheMaxAgeSeconds {
+		return nil, false
+	}
+	body = jw.AtKey("body")
+	if body.IsNil() {
+		return nil, false
+	}
+	m.CDebugf("| apiDiskCache hit for %s", u.Path)
+	return body, true
+}
+
+// apiDiskCachePut stores a response body under its normalized URL.
+// Failures are logged and swallowed; the cache is best-effort.
+func apiDiskCachePut(m MetaContext, u *url.URL, body *jsonw.Wrapper) {
+	envelope := jsonw.NewDictionary()
+	_ = envelope.SetKey("fetched", jsonw.NewInt64(m.G().Clock().Now().Unix()))
+	_ = envelope.SetKey("body", body)
+	if err := m.G().LocalDb.Put(apiDiskCacheDbKey(u), nil, envelope); err != nil {
+		m.CDebugf("| apiDiskCache put error for %s: %s", u.Path, err)
+	}
+}
@@ -0,0 +1,63 @@
+package libkb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIDiskCache(t *testing.T) {
+	tc := SetupTest(t, "api_disk_cache", 1)
+	defer tc.Cleanup()
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		fmt.Fprintf(w, `{"status": {"code": 0, "name": "OK"}, "n": %d}`, n)
+	}))
+	defer srv.Close()
+
+	os.Setenv("KEYBASE_SERVER_URI", srv.URL)
+	defer os.Unsetenv("KEYBASE_SERVER_URI")
+	require.NoError(t, tc.G.ConfigureAPI())
+
+	arg := APIArg{
+		Endpoint:     "sig/get",
+		Args:         HTTPArgs{"uid": S{Val: "deadbeef19"}, "low": I{5}, "high": I{10}},
+		UseDiskCache: true,
+		MetaContext:  NewMetaContextForTest(tc),
+	}
+
+	res, err := tc.G.API.Get(arg)
+	require.NoError(t, err)
+	n, err := res.Body.AtKey("n").GetInt()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// Second identical request is served from disk; the server isn't hit.
+	res, err = tc.G.API.Get(arg)
+	require.NoError(t, err)
+	n, err = res.Body.AtKey("n").GetInt()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	// A different query misses.
+	arg.Args = HTTPArgs{"uid": S{Val: "deadbeef19"}, "low": I{11}, "high": I{20}}
+	res, err = tc.G.API.Get(arg)
+	require.NoError(t, err)
+	n, err = res.Body.AtKey("n").GetInt()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	// Without the opt-in, the same URL goes back to the server.
+	arg.UseDiskCache = false
+	_, err = tc.G.API.Get(arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
@@ -0,0 +1,224 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// APIRetryPolicy describes how the API layer should retry a request:
+// how many attempts, how to back off between them, and which HTTP
+// status codes warrant another try. It's honored centrally in the
+// doRetry path shared by GetResp and Post; callers set it on APIArg or
+// pick up a per-endpoint default.
+type APIRetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first.
+	MaxAttempts int
+
+	// InitialWait is the sleep before the second attempt; each further
+	// wait is scaled by Multiplier (exponential backoff).
+	InitialWait time.Duration
+
+	// Multiplier scales the wait after every attempt; values <= 1.0 are
+	// treated as "no growth".
+	Multiplier float64
+
+	// Jitter is the fraction of each wait randomized away, in [0,1]: a
+	// wait w becomes uniform in [w-w*Jitter/2, w+w*Jitter/2] so that
+	// clients recovering together don't retry in lockstep.
+	Jitter float64
+
+	// AttemptTimeout, if set, bounds each individual attempt; otherwise
+	// the client's configured timeout applies.
+	AttemptTimeout time.Duration
+
+	// RetryableStatuses is the set of HTTP statuses to retry. Nil means
+	// the transient server-side classes: 429 and all of 5xx. Transport
+	// errors are always retried.
+	RetryableStatuses []int
+}
+
+// Waits tuned for small idempotent GETs; see defaultRetryPolicies.
+const (
+	apiRetryDefaultAttempts    = 3
+	apiRetryDefaultInitialWait = 250 * time.Millisecond
+	apiRetryDefaultMultiplier  = 2.0
+	apiRetryDefaultJitter      = 0.5
+)
+
+// defaultRetryPolicies maps endpoints to the policy used when the
+// caller doesn't set one explicitly. Only idempotent GET endpoints
+// belong here; the default is consulted for GET requests alone.
+var defaultRetryPolicies = map[string]*APIRetryPolicy{
+	"sig/get":     defaultGETRetryPolicy(),
+	"key/fetch":   defaultGETRetryPolicy(),
+	"merkle/path": defaultGETRetryPolicy(),
+}
+
+func defaultGETRetryPolicy() *APIRetryPolicy {
+	return &APIRetryPolicy{
+		MaxAttempts: apiRetryDefaultAttempts,
+		InitialWait: apiRetryDefaultInitialWait,
+		Multiplier:  apiRetryDefaultMultiplier,
+		Jitter:      apiRetryDefaultJitter,
+	}
+}
+
+// defaultRetryPolicy returns the built-in policy for an endpoint, or
+// nil if there isn't one or the method isn't idempotent.
+func defaultRetryPolicy(endpoint string, method string) *APIRetryPolicy {
+	if method != "GET" {
+		return nil
+	}
+	return defaultRetryPolicies[endpoint]
+}
+
+// shouldRetryStatus reports whether an HTTP status warrants another
+// attempt under this policy.
+func (p *APIRetryPolicy) shouldRetryStatus(status int) bool {
+	if p.RetryableStatuses == nil {
+		return status == 429 || (status >= 500 && status <= 599)
+	}
+	for _, s := range p.RetryableStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// jitteredWait spreads a wait uniformly over [w-w*Jitter/2, w+w*Jitter/2].
+func (p *APIRetryPolicy) jitteredWait(wait time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return wait
+	}
+	spread := float64(wait) * p.Jitter
+	return time.Duration(float64(wait) - spread/2.0 + rand.Float64()*spread)
+}
+
+// nextWait grows the backoff for the attempt after this one.
+func (p *APIRetryPolicy) nextWait(wait time.Duration) time.Duration {
+	if p.Multiplier <= 1.0 {
+		return wait
+	}
+	return time.Duration(float64(wait) * p.Multiplier)
+}
+
+// apiRetryStats counts retries per endpoint, so `keybase log send` and
+// friends can report which endpoints are flapping.
+var apiRetryStats = struct {
+	sync.Mutex
+	counts map[string]int
+}{counts: make(map[string]int)}
+
+func recordAPIRetry(endpoint string) {
+	apiRetryStats.Lock()
+	apiRetryStats.counts[endpoint]++
+	apiRetryStats.Unlock()
+}
+
+// APIRetryCounts returns a copy of the cumulative per-endpoint retry
+// counters for this process.
+func APIRetryCounts() map[string]int {
+	apiRetryStats.Lock()
+	defer apiRetryStats.Unlock()
+	ret := make(map[string]int, len(apiRetryStats.counts))
+	for k, v := range apiRetryStats.counts {
+		ret[k] = v
+	}
+	return ret
+}
+
+// doRetryWithPolicy runs the request under an APIRetryPolicy: transport
+// errors and retryable statuses trigger further attempts, separated by
+// jittered exponential backoff. Same contract as doRetry: it returns
+// the response, a canceler the caller must run when done with the
+// response, and an error.
+func doRetryWithPolicy(m MetaContext, arg APIArg, cli *Client, req *http.Request, policy *APIRetryPolicy) (*http.Response, func(), error) {
+	timeout := cli.cli.Timeout
+	if policy.AttemptTimeout != 0 {
+		timeout = policy.AttemptTimeout
+	}
+
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	wait := policy.InitialWait
+	var lastErr error
+	var lastWasStatus bool
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			recordAPIRetry(arg.Endpoint)
+			sleep := policy.jitteredWait(wait)
+			m.CDebugf("retry attempt %d of %d for %s after %s", i, attempts, arg.Endpoint, sleep)
+			if sleep > 0 {
+				// Wake on cancellation too: a canceled identify must
+				// not sit out its remaining backoff. The wall-clock
+				// case bounds the wait under the fake clocks tests
+				// install, which never advance on their own and would
+				// otherwise park this loop (and the test) forever.
+				select {
+				case <-m.G().Clock().After(sleep):
+				case <-time.After(sleep):
+				case <-m.Ctx().Done():
+					return nil, nil, m.Ctx().Err()
+				}
+			}
+			wait = policy.nextWait(wait)
+		}
+
+		resp, canc, err := doTimeout(m, cli, req, timeout)
+		if err == nil && !policy.shouldRetryStatus(resp.StatusCode) {
+			return resp, canc, nil
+		}
+		if err == nil {
+			lastErr = fmt.Errorf("retryable HTTP status %s", resp.Status)
+			lastWasStatus = true
+			DiscardAndCloseBody(resp)
+		} else {
+			lastErr = err
+			lastWasStatus = false
+		}
+		if canc != nil {
+			canc()
+		}
+
+		// If we went offline mid-loop, bail out rather than sleeping
+		// through attempts that cannot succeed.
+		if ConnectivityMonitorNo == m.G().ConnectivityMonitor.IsConnected(m.Ctx()) {
+			m.CDebugf("retry loop aborting since chat went offline")
+			break
+		}
+
+		if req.GetBody != nil {
+			// request body consumed, need to get it back
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return nil, nil, APIRetryExhaustedError{Attempts: attempts, LastErr: lastErr, StatusOnly: lastWasStatus}
+}
+
+// APIRetryExhaustedError reports that every attempt under a retry
+// policy failed. StatusOnly is true when the final failure was a
+// well-formed HTTP response with a retryable status: the server was
+// reachable, so connectivity trackers must not count it as a
+// transport failure.
+type APIRetryExhaustedError struct {
+	Attempts   int
+	LastErr    error
+	StatusOnly bool
+}
+
+func (e APIRetryExhaustedError) Error() string {
+	return fmt.Sprintf("doRetryWithPolicy failed, attempts: %d, last err: %s", e.Attempts, e.LastErr)
+}
@@ -0,0 +1,80 @@
+package libkb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyShouldRetryStatus(t *testing.T) {
+	p := &APIRetryPolicy{}
+	require.True(t, p.shouldRetryStatus(500))
+	require.True(t, p.shouldRetryStatus(503))
+	require.True(t, p.shouldRetryStatus(429))
+	require.False(t, p.shouldRetryStatus(200))
+	require.False(t, p.shouldRetryStatus(404))
+
+	p = &APIRetryPolicy{RetryableStatuses: []int{502}}
+	require.True(t, p.shouldRetryStatus(502))
+	require.False(t, p.shouldRetryStatus(503))
+	require.False(t, p.shouldRetryStatus(429))
+}
+
+func TestRetryPolicyWaits(t *testing.T) {
+	p := &APIRetryPolicy{InitialWait: 100 * time.Millisecond, Multiplier: 2.0, Jitter: 0.5}
+	require.Equal(t, 200*time.Millisecond, p.nextWait(100*time.Millisecond))
+
+	// Jitter keeps the wait within [w-w/4, w+w/4] for Jitter=0.5.
+	for i := 0; i < 100; i++ {
+		w := p.jitteredWait(100 * time.Millisecond)
+		require.True(t, w >= 75*time.Millisecond && w <= 125*time.Millisecond, "wait %s out of range", w)
+	}
+
+	// No growth and no jitter when unset.
+	p = &APIRetryPolicy{InitialWait: 100 * time.Millisecond}
+	require.Equal(t, 100*time.Millisecond, p.nextWait(100*time.Millisecond))
+	require.Equal(t, 100*time.Millisecond, p.jitteredWait(100*time.Millisecond))
+}
+
+func TestDoRetryWithPolicy(t *testing.T) {
+	tc := SetupTest(t, "api_retry", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli := NewClient(tc.G.Env, nil, false)
+	req, err := http.NewRequest("GET", srv.URL, nil)
+	require.NoError(t, err)
+
+	arg := APIArg{Endpoint: "test/retry"}
+	policy := &APIRetryPolicy{MaxAttempts: 4, InitialWait: time.Millisecond, Multiplier: 2.0}
+	before := APIRetryCounts()["test/retry"]
+	resp, canc, err := doRetryWithPolicy(m, arg, cli, req, policy)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(3), atomic.LoadInt32(&hits))
+	require.Equal(t, before+2, APIRetryCounts()["test/retry"])
+	DiscardAndCloseBody(resp)
+	if canc != nil {
+		canc()
+	}
+
+	// All attempts exhausted -> error.
+	atomic.StoreInt32(&hits, -1000)
+	policy = &APIRetryPolicy{MaxAttempts: 2, InitialWait: time.Millisecond}
+	_, _, err = doRetryWithPolicy(m, arg, cli, req, policy)
+	require.Error(t, err)
+}
@@ -0,0 +1,150 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// Capability tokens restrict what a local RPC connection may call.
+// The token itself is random and shown only at creation; the store
+// keeps its sha256 alongside the granted RPC method-name prefixes.
+
+const apiTokenPrefix = "kbtok_"
+
+// Friendly capability names accepted by `keybase api-token create
+// --allow`, expanded to RPC method-name prefixes at creation time.
+// Anything not in this table is treated as a literal prefix.
+var apiTokenAliases = map[string][]string{
+	"chat.send":   {"chat.1.local.postLocal", "chat.1.local.postLocalNonblock", "chat.1.local.postTextNonblock"},
+	"chat.read":   {"chat.1.local.getThreadLocal", "chat.1.local.getInboxAndUnboxLocal", "chat.1.local.getInboxNonblockLocal"},
+	"fs.read":     {"keybase.1.SimpleFS.simpleFSList", "keybase.1.SimpleFS.simpleFSReadList", "keybase.1.SimpleFS.simpleFSOpen", "keybase.1.SimpleFS.simpleFSRead", "keybase.1.SimpleFS.simpleFSStat", "keybase.1.SimpleFS.simpleFSMakeOpid", "keybase.1.SimpleFS.simpleFSWait", "keybase.1.SimpleFS.simpleFSClose"},
+	"wallet.read": {"stellar.1.local.balancesLocal", "stellar.1.local.recentPaymentsCLILocal", "stellar.1.local.walletGetAccountsCLILocal"},
+	"user.lookup": {"keybase.1.user", "keybase.1.identify"},
+}
+
+func apiTokenDbKey(hash string) DbKey {
+	return DbKey{Typ: DBApiTokens, Key: hash}
+}
+
+func hashApiToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ExpandApiTokenAllow maps friendly capability names to RPC prefixes,
+// passing unknown entries through as literal prefixes.
+func ExpandApiTokenAllow(allow []string) (res []string) {
+	for _, a := range allow {
+		if expanded, ok := apiTokenAliases[a]; ok {
+			res = append(res, expanded...)
+		} else {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+// CreateApiToken mints a new capability token. The returned token is
+// the only copy; the store keeps its hash.
+func CreateApiToken(m MetaContext, label string, allow []string) (token string, err error) {
+	if label == "" {
+		return "", fmt.Errorf("api token needs a label")
+	}
+	if len(allow) == 0 {
+		return "", fmt.Errorf("api token needs at least one allowed namespace")
+	}
+	existing, err := ListApiTokens(m)
+	if err != nil {
+		return "", err
+	}
+	for _, info := range existing {
+		if info.Label == label {
+			return "", fmt.Errorf("api token label %q already in use", label)
+		}
+	}
+	var raw [32]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", err
+	}
+	token = apiTokenPrefix + hex.EncodeToString(raw[:])
+	info := keybase1.ApiTokenInfo{
+		Label:     label,
+		Allow:     ExpandApiTokenAllow(allow),
+		CreatedAt: keybase1.ToTime(m.G().Clock().Now()),
+	}
+	if err := m.G().LocalDb.PutObj(apiTokenDbKey(hashApiToken(token)), nil, info); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ListApiTokens returns metadata for every live token.
+func ListApiTokens(m MetaContext) (res []keybase1.ApiTokenInfo, err error) {
+	err = m.G().LocalDb.ForEachRawKv(func(id DbKey, _ []byte) error {
+		if id.Typ != DBApiTokens {
+			return nil
+		}
+		var info keybase1.ApiTokenInfo
+		if found, err := m.G().LocalDb.GetInto(&info, id); err != nil || !found {
+			return nil
+		}
+		res = append(res, info)
+		return nil
+	})
+	return res, err
+}
+
+// RevokeApiToken deletes the token with the given label.
+func RevokeApiToken(m MetaContext, label string) error {
+	var stale []DbKey
+	err := m.G().LocalDb.ForEachRawKv(func(id DbKey, _ []byte) error {
+		if id.Typ != DBApiTokens {
+			return nil
+		}
+		var info keybase1.ApiTokenInfo
+		if found, err := m.G().LocalDb.GetInto(&info, id); err != nil || !found {
+			return nil
+		}
+		if info.Label == label {
+			stale = append(stale, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return fmt.Errorf("no api token with label %q", label)
+	}
+	for _, id := range stale {
+		if err := m.G().LocalDb.Delete(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckApiToken verifies a presented token and returns its grants.
+func CheckApiToken(m MetaContext, token string) (allow []string, err error) {
+	if !strings.HasPrefix(token, apiTokenPrefix) {
+		return nil, fmt.Errorf("malformed api token")
+	}
+	hash := hashApiToken(token)
+	var info keybase1.ApiTokenInfo
+	found, err := m.G().LocalDb.GetInto(&info, apiTokenDbKey(hash))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown or revoked api token")
+	}
+	return info.Allow, nil
+}
@@ -23,9 +23,11 @@ type APIArg struct {
 	SessionType     APISessionType
 	HTTPStatus      []int
 	AppStatusCodes  []int
-	InitialTimeout  time.Duration // optional
-	RetryMultiplier float64       // optional
-	RetryCount      int           // optional
+	InitialTimeout  time.Duration   // optional
+	RetryMultiplier float64         // optional
+	RetryCount      int             // optional
+	RetryPolicy     *APIRetryPolicy // optional; takes precedence over the fields above
+	UseDiskCache    bool            // optional; only for GETs whose reply is fully determined by the URL
 	NetContext      context.Context
 	MetaContext     MetaContext
 }
@@ -0,0 +1,34 @@
+package libkb
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCapabilities(t *testing.T) {
+	tc := SetupTest(t, "capabilities", 1)
+	defer tc.Cleanup()
+
+	botKID := keybase1.KID("0120aa0a")
+	fullKID := keybase1.KID("0120bb0a")
+	cki := NewComputedKeyInfos(tc.G)
+	cki.Infos[botKID] = &ComputedKeyInfo{KID: botKID, Capabilities: []string{"chat-send"}}
+	cki.Infos[fullKID] = &ComputedKeyInfo{KID: fullKID}
+	ckf := ComputedKeyFamily{cki: cki, Contextified: NewContextified(tc.G)}
+
+	// Scoped keys may only do what their delegation says.
+	require.True(t, ckf.KeyHasCapability(botKID, "chat-send"))
+	require.False(t, ckf.KeyHasCapability(botKID, "sibkey-delegation"))
+	require.Equal(t, []string{"chat-send"}, ckf.KeyCapabilities(botKID))
+
+	// Unscoped keys are unrestricted.
+	require.True(t, ckf.KeyHasCapability(fullKID, "sibkey-delegation"))
+	require.Nil(t, ckf.KeyCapabilities(fullKID))
+
+	// Unknown keys hold no capabilities... but KeyCapabilities returns
+	// nil, which reads as unrestricted; callers must check key validity
+	// separately, as all verification paths do.
+	require.Nil(t, ckf.KeyCapabilities(keybase1.KID("0120cc0a")))
+}
@@ -1187,38 +1187,70 @@ func (c *ChainLink) checkServerSignatureMetadata(ckf ComputedKeyFamily) (ret key
 	return verifyKID, nil
 }
 
-func (c *ChainLink) Store(g *GlobalContext) (didStore bool, err error) {
+// prepareStore runs the pre-storage checks and packs the link for
+// writing. It returns (nil, nil) if the link is already stored and
+// clean, and so needs no write.
+func (c *ChainLink) prepareStore(g *GlobalContext) (packed *jsonw.Wrapper, err error) {
 
 	g.VDL.Log(VLog1, "| Storing Link %s...", c.id)
 	if c.storedLocally && !c.dirty {
 		g.VDL.Log(VLog1, "| Bailed on link %s since wasn't dirty...", c.id)
-		return didStore, nil
+		return nil, nil
 	}
 
 	if err = c.VerifyLink(); err != nil {
-		return false, err
+		return nil, err
 	}
 
 	if !c.IsStubbed() && (!c.hashVerified || !c.payloadVerified) {
 		err = fmt.Errorf("Internal error; should have been verified in Store()")
-		return false, err
+		return nil, err
 	}
 
-	packed, err := c.Pack()
-	if err != nil {
+	return c.Pack()
+}
+
+func (c *ChainLink) storeKey() DbKey {
+	return DbKey{Typ: DBLink, Key: c.id.String()}
+}
+
+// markStored flags the link as clean after its write (direct or via a
+// committed batch) has landed.
+func (c *ChainLink) markStored() {
+	c.storedLocally = true
+	c.dirty = false
+}
+
+func (c *ChainLink) Store(g *GlobalContext) (didStore bool, err error) {
+	packed, err := c.prepareStore(g)
+	if err != nil || packed == nil {
 		return false, err
 	}
 
-	key := DbKey{Typ: DBLink, Key: c.id.String()}
-
 	// Don't write with any aliases
-	if err = g.LocalDb.Put(key, nil, packed); err != nil {
+	if err = g.LocalDb.Put(c.storeKey(), nil, packed); err != nil {
 		return false, err
 	}
 	g.VDL.Log(VLog1, "| Store Link %s", c.id)
 
-	c.storedLocally = true
-	c.dirty = false
+	c.markStored()
+	return true, nil
+}
+
+// StoreToBatch is like Store, but accumulates the write into batch
+// instead of issuing its own put. The caller must mark the link stored
+// (markStored) only once the batch commits.
+func (c *ChainLink) StoreToBatch(g *GlobalContext, batch JSONLocalDbBatch) (didStore bool, err error) {
+	packed, err := c.prepareStore(g)
+	if err != nil || packed == nil {
+		return false, err
+	}
+
+	// Don't write with any aliases
+	if err = batch.Put(c.storeKey(), nil, packed); err != nil {
+		return false, err
+	}
+	g.VDL.Log(VLog1, "| Batched Link %s", c.id)
 	return true, nil
 }
 
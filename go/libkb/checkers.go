@@ -49,9 +49,10 @@ var CheckPassphraseNew = Checker{
 		if len(r) > 0 && unicode.IsSpace(r[0]) {
 			return false
 		}
-		return len(s) >= MinPassphraseLength
+		// The enterprise policy can raise (never lower) the minimum.
+		return len(s) >= PolicyMinPassphraseLength()
 	},
-	Hint:          fmt.Sprintf("passphrase must be %d or more characters", MinPassphraseLength),
+	Hint:          fmt.Sprintf("passphrase must be %d or more characters (your administrator's policy may require more)", MinPassphraseLength),
 	PreserveSpace: true,
 }
 
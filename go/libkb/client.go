@@ -31,6 +31,16 @@ type ClientConfig struct {
 	Prefix     string
 	UseCookies bool
 	Timeout    time.Duration
+
+	// Transport, if non-nil, is used in place of the http.Transport we
+	// otherwise build (SOCKS/Tor dialers, corporate proxies with their own
+	// CAs, request logging).
+	Transport http.RoundTripper
+
+	// AttemptHTTP2 enables HTTP/2 negotiation with the server. Sigchain and
+	// merkle loading generate bursts of small requests that benefit from
+	// multiplexing over one connection.
+	AttemptHTTP2 bool
 }
 
 type Client struct {
@@ -125,7 +135,18 @@ func genClientConfigForInternalAPI(g *GlobalContext) (*ClientConfig, error) {
 		return nil, err
 	}
 
-	ret := &ClientConfig{host, port, useTLS, url, rootCAs, url.Path, true, e.GetAPITimeout()}
+	ret := &ClientConfig{
+		Host:         host,
+		Port:         port,
+		UseTLS:       useTLS,
+		URL:          url,
+		RootCAs:      rootCAs,
+		Prefix:       url.Path,
+		UseCookies:   true,
+		Timeout:      e.GetAPITimeout(),
+		Transport:    g.apiRoundTripper,
+		AttemptHTTP2: e.GetAttemptHTTP2(),
+	}
 	return ret, nil
 }
 
@@ -142,6 +163,10 @@ func NewClient(e *Env, config *ClientConfig, needCookie bool) *Client {
 		jar, _ = cookiejar.New(nil)
 	}
 
+	if config != nil && config.Transport != nil {
+		return newClientWithTransport(config, config.Transport, jar)
+	}
+
 	var xprt http.Transport
 	var timeout time.Duration
 
@@ -194,6 +219,13 @@ func NewClient(e *Env, config *ClientConfig, needCookie bool) *Client {
 		}
 	}
 
+	// A custom Dial or TLSClientConfig normally turns off the standard
+	// library's transparent HTTP/2 support, so we have to opt back in.
+	// It's not compatible with a SOCKS dialer.
+	if config != nil && config.AttemptHTTP2 && !e.GetTorMode().Enabled() {
+		xprt.ForceAttemptHTTP2 = true
+	}
+
 	if config == nil || config.Timeout == 0 {
 		timeout = HTTPDefaultTimeout
 	} else {
@@ -211,3 +243,24 @@ func NewClient(e *Env, config *ClientConfig, needCookie bool) *Client {
 	ret.cli.Transport = &xprt
 	return ret
 }
+
+// newClientWithTransport builds a Client around a user-supplied
+// RoundTripper, skipping all of our own transport configuration; the
+// caller's transport is assumed to handle proxies, CAs and dialing itself.
+func newClientWithTransport(config *ClientConfig, xprt http.RoundTripper, jar *cookiejar.Jar) *Client {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = HTTPDefaultTimeout
+	}
+	ret := &Client{
+		cli: &http.Client{
+			Timeout:   timeout,
+			Transport: xprt,
+		},
+		config: config,
+	}
+	if jar != nil {
+		ret.cli.Jar = jar
+	}
+	return ret
+}
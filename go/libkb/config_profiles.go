@@ -0,0 +1,129 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"os"
+	"strings"
+)
+
+// Config profiles: named override sets stored under "profiles" in the
+// config file, selected by KEYBASE_PROFILE or the "active_profile"
+// key. Path-style lookups (the Get*AtPath family, which all tunable
+// settings go through) resolve profile values before global ones,
+// giving the overall layering flags > env > profile > global that
+// Env's getter chains already provide for the first two levels.
+// Structural state (current_user, per-user session blobs) is
+// deliberately outside profile resolution.
+//
+//   { "active_profile": "work",
+//     "profiles": { "work": { "proxy": "http://corp:3128" } },
+//     "proxy": "" }
+
+const configProfilesKey = "profiles"
+const configActiveProfileKey = "active_profile"
+
+// ActiveConfigProfile names the profile in effect, or "".
+func (f *JSONConfigFile) ActiveConfigProfile() string {
+	if p := os.Getenv("KEYBASE_PROFILE"); p != "" {
+		return p
+	}
+	p, _ := f.JSONFile.GetStringAtPath(configActiveProfileKey)
+	return p
+}
+
+// profileKeyFor maps a lookup path into the active profile's subtree,
+// or returns false when profile resolution doesn't apply (no active
+// profile, or the path addresses the profile machinery itself).
+func (f *JSONConfigFile) profileKeyFor(p string) (string, bool) {
+	profile := f.ActiveConfigProfile()
+	if profile == "" || p == configActiveProfileKey || strings.HasPrefix(p, configProfilesKey+".") {
+		return "", false
+	}
+	return configProfilesKey + "." + profile + "." + p, true
+}
+
+func (f *JSONConfigFile) GetStringAtPath(p string) (string, bool) {
+	if pk, ok := f.profileKeyFor(p); ok {
+		if v, found := f.JSONFile.GetStringAtPath(pk); found {
+			return v, true
+		}
+	}
+	return f.JSONFile.GetStringAtPath(p)
+}
+
+func (f *JSONConfigFile) GetBoolAtPath(p string) (bool, bool) {
+	if pk, ok := f.profileKeyFor(p); ok {
+		if v, found := f.JSONFile.GetBoolAtPath(pk); found {
+			return v, true
+		}
+	}
+	return f.JSONFile.GetBoolAtPath(p)
+}
+
+func (f *JSONConfigFile) GetIntAtPath(p string) (int, bool) {
+	if pk, ok := f.profileKeyFor(p); ok {
+		if v, found := f.JSONFile.GetIntAtPath(pk); found {
+			return v, true
+		}
+	}
+	return f.JSONFile.GetIntAtPath(p)
+}
+
+func (f *JSONConfigFile) GetNullAtPath(p string) bool {
+	if pk, ok := f.profileKeyFor(p); ok {
+		if f.JSONFile.GetNullAtPath(pk) {
+			return true
+		}
+	}
+	return f.JSONFile.GetNullAtPath(p)
+}
+
+// GetInterfaceAtPath resolves through the active profile like the
+// typed getters, so value inspection matches effective behavior.
+func (f *JSONConfigFile) GetInterfaceAtPath(p string) (interface{}, error) {
+	if pk, ok := f.profileKeyFor(p); ok {
+		if i, err := f.JSONFile.GetInterfaceAtPath(pk); err == nil {
+			return i, nil
+		}
+	}
+	return f.JSONFile.GetInterfaceAtPath(p)
+}
+
+// ConfigProfileNames lists the defined profiles.
+func (f *JSONConfigFile) ConfigProfileNames() (res []string) {
+	w := f.jw.AtKey(configProfilesKey)
+	keys, err := w.Keys()
+	if err != nil {
+		return nil
+	}
+	return keys
+}
+
+// ValueProvenance reports which layer a config-file lookup resolves
+// from: "profile:<name>", "config", or "unset". (Flag and env layers
+// are reported by the caller, which knows their names.)
+func (f *JSONConfigFile) ValueProvenance(p string) string {
+	if pk, ok := f.profileKeyFor(p); ok {
+		if _, found := f.JSONFile.GetStringAtPath(pk); found {
+			return "profile:" + f.ActiveConfigProfile()
+		}
+		if _, found := f.JSONFile.GetBoolAtPath(pk); found {
+			return "profile:" + f.ActiveConfigProfile()
+		}
+		if _, found := f.JSONFile.GetIntAtPath(pk); found {
+			return "profile:" + f.ActiveConfigProfile()
+		}
+	}
+	if _, found := f.JSONFile.GetStringAtPath(p); found {
+		return "config"
+	}
+	if _, found := f.JSONFile.GetBoolAtPath(p); found {
+		return "config"
+	}
+	if _, found := f.JSONFile.GetIntAtPath(p); found {
+		return "config"
+	}
+	return "unset"
+}
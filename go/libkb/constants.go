@@ -113,6 +113,7 @@ const (
 	LinkCacheCleanDur = 1 * time.Minute
 
 	UPAKCacheSize                     = 2000
+	UPAKDiskCacheSize                 = 10000
 	UIDMapFullNameCacheSize           = 100000
 	ImplicitTeamConflictInfoCacheSize = 10000
 
@@ -123,6 +124,9 @@ const (
 
 	CriticalClockSkewLimit = time.Hour
 
+	// Cap on any one remote proof check during identify.
+	ProofCheckDefaultTimeout = 45 * time.Second
+
 	ChatBoxerMerkleFreshness    = 10 * time.Minute
 	TeamMerkleFreshnessForAdmin = 30 * time.Second
 	EphemeralKeyMerkleFreshness = 30 * time.Second
@@ -615,6 +619,8 @@ const (
 	DeriveReasonPUKStellarBundle   DeriveReason = "Derived-User-NaCl-SecretBox-StellarBundle-1"
 	DeriveReasonPUKStellarNoteSelf DeriveReason = "Derived-User-NaCl-SecretBox-StellarSelfNote-1"
 
+	DeriveReasonLocalDbAtRest DeriveReason = "Derived-LocalDb-AtRest-SecretBox-1"
+
 	DeriveReasonDeviceEKEncryption  DeriveReason = "Derived-Ephemeral-Device-NaCl-DH-1"
 	DeriveReasonUserEKEncryption    DeriveReason = "Derived-Ephemeral-User-NaCl-DH-1"
 	DeriveReasonTeamEKEncryption    DeriveReason = "Derived-Ephemeral-Team-NaCl-DH-1"
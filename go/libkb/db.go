@@ -85,7 +85,20 @@ type JSONLocalDb struct {
 	engine LocalDb
 }
 
-func NewJSONLocalDb(e LocalDb) *JSONLocalDb  { return &JSONLocalDb{e} }
+func NewJSONLocalDb(e LocalDb) *JSONLocalDb { return &JSONLocalDb{e} }
+
+// newLocalDbEngine builds the LocalDb implementation, wrapping it
+// with at-rest encryption when configured. LevelDB is the only
+// engine; the short-lived sqlite option was removed because no
+// driver is linked into this tree.
+func newLocalDbEngine(g *GlobalContext, filename func() string) LocalDb {
+	var engine LocalDb = NewLevelDb(g, filename)
+	if g.Env.GetDbEncryptAtRest() {
+		return NewEncryptedLocalDb(g, engine)
+	}
+	return engine
+}
+
 func (j *JSONLocalDb) Open() error           { return j.engine.Open() }
 func (j *JSONLocalDb) ForceOpen() error      { return j.engine.ForceOpen() }
 func (j *JSONLocalDb) Close() error          { return j.engine.Close() }
@@ -126,6 +139,51 @@ func (j *JSONLocalDb) OpenTransaction() (JSONLocalDbTransaction, error) {
 	return jtr, nil
 }
 
+// ForEachRawKv walks every kv-table row of the underlying engine, when
+// the engine supports iteration (maintenance passes like DbVerify).
+func (j *JSONLocalDb) ForEachRawKv(fn func(id DbKey, value []byte) error) error {
+	it, ok := j.engine.(rawKvIterable)
+	if !ok {
+		return NewDBError("DB engine does not support iteration")
+	}
+	return it.ForEachRawKv(fn)
+}
+
+func (j *JSONLocalDb) BeginBatch() (JSONLocalDbBatch, error) {
+	b, err := j.engine.BeginBatch()
+	if err != nil {
+		return JSONLocalDbBatch{}, err
+	}
+	return JSONLocalDbBatch{b: b}, nil
+}
+
+// JSONLocalDbBatch is the JSON-marshaling layer over a LocalDbBatch;
+// see LocalDbBatch for the semantics.
+type JSONLocalDbBatch struct {
+	b LocalDbBatch
+}
+
+func (j JSONLocalDbBatch) PutRaw(id DbKey, b []byte) error { return j.b.Put(id, nil, b) }
+func (j JSONLocalDbBatch) Delete(id DbKey) error           { return j.b.Delete(id) }
+func (j JSONLocalDbBatch) Commit() error                   { return j.b.Commit() }
+func (j JSONLocalDbBatch) Discard()                        { j.b.Discard() }
+
+func (j JSONLocalDbBatch) Put(id DbKey, aliases []DbKey, val *jsonw.Wrapper) error {
+	bytes, err := val.Marshal()
+	if err == nil {
+		err = j.b.Put(id, aliases, bytes)
+	}
+	return err
+}
+
+func (j JSONLocalDbBatch) PutObj(id DbKey, aliases []DbKey, obj interface{}) (err error) {
+	bytes, err := json.Marshal(obj)
+	if err == nil {
+		err = j.b.Put(id, aliases, bytes)
+	}
+	return err
+}
+
 type JSONLocalDbTransaction struct {
 	tr LocalDbTransaction
 }
@@ -168,6 +226,22 @@ const (
 	DBTeamChain         = 0x10
 	DBUserPlusAllKeysV1 = 0x19
 
+	DBContacts                 = 0xbc
+	DBApiTokens                = 0xbd
+	DBGregorJournal            = 0xbe
+	DBStellarPendingPayment    = 0xbf
+	DBStellarSchedules         = 0xc0
+	DBTeamInviteLinks          = 0xc2
+	DBTeamPendingChange        = 0xc3
+	DBTeamMemberExpiry         = 0xc4
+	DBChatReadReceipts         = 0xc6
+	DBChatPins                 = 0xc7
+	DBChatTranslation          = 0xc8
+	DBChatIndex                = 0xc9
+	DBHighLinkApproval         = 0xca
+	DBUserTombstone            = 0xcb
+	DBLocalDbEncryption        = 0xcc
+	DBApiCache                 = 0xcd
 	DBTeamAuditor              = 0xce
 	DBAttachmentUploader       = 0xcf
 	DBDiskLRUEntries           = 0xda
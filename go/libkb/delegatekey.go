@@ -5,6 +5,8 @@ package libkb
 
 import (
 	"encoding/hex"
+	"fmt"
+
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
 	jsonw "github.com/keybase/go-jsonw"
 )
@@ -29,7 +31,8 @@ type Delegator struct {
 	EncodedPrivateKey    string
 	Ctime                int64
 	DelegationType       DelegationType
-	Aggregated           bool // During aggregation we skip some steps (posting, updating some state)
+	Capabilities         []string // optional capability scoping for the new key (bot keys)
+	Aggregated           bool     // During aggregation we skip some steps (posting, updating some state)
 	PerUserKeyGeneration keybase1.PerUserKeyGeneration
 	MerkleRoot           *MerkleRoot
 
@@ -163,6 +166,24 @@ func (d *Delegator) Run(m MetaContext) (err error) {
 		return
 	}
 
+	// A capability-scoped signing key (a bot key, say) may not
+	// delegate further keys unless its own delegation allows it.
+	if d.Me != nil && d.ExistingKey != nil {
+		if ckf := d.Me.GetComputedKeyFamily(); ckf != nil {
+			if !ckf.KeyHasCapability(d.ExistingKey.GetKID(), CapabilitySibkeyDelegation) {
+				return fmt.Errorf("signing key %s is capability-scoped and may not delegate keys", d.ExistingKey.GetKID())
+			}
+		}
+	}
+
+	// In multi-device approval mode, high links need a second device's
+	// sign-off before we'll post them.
+	if HighLinkApprovalRequired(m, string(d.DelegationType)) {
+		if err = ConsumeHighLinkApproval(m, string(d.DelegationType)); err != nil {
+			return err
+		}
+	}
+
 	d.MerkleRoot = m.G().MerkleClient.LastRoot()
 
 	// We'll need to generate two proofs, so set the Ctime
@@ -197,6 +218,11 @@ func (d *Delegator) Run(m MetaContext) (err error) {
 
 func (d *Delegator) SignAndPost(m MetaContext, jw *jsonw.Wrapper) (err error) {
 
+	// Catch wrong-prev/stale-seqno mistakes locally before signing.
+	if err = PrePlayCheckProspectiveLink(m, d.Me, jw); err != nil {
+		return err
+	}
+
 	var linkid LinkID
 
 	if d.sig, d.sigID, linkid, err = SignJSON(jw, d.GetSigningKey()); err != nil {
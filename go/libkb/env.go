@@ -772,6 +772,8 @@ func (e *Env) GetSkipLogoutIfRevokedCheck() bool {
 
 func (e *Env) GetProxy() string {
 	return e.GetString(
+		// An enterprise policy's forced proxy outranks everything.
+		func() string { return PolicyForcedProxy() },
 		func() string { return e.cmd.GetProxy() },
 		func() string { return os.Getenv("https_proxy") },
 		func() string { return os.Getenv("http_proxy") },
@@ -843,6 +845,223 @@ func (e *Env) GetAPITimeout() time.Duration {
 	)
 }
 
+// GetProofCheckTimeout bounds one remote proof check during identify,
+// so a single slow host can't stall the whole result. Override the
+// default with identify.proof_timeout, or per proof type with
+// identify.proof_timeouts.<type> (e.g. identify.proof_timeouts.dns).
+func (e *Env) GetProofCheckTimeout(proofType string) time.Duration {
+	parse := func(path string) (time.Duration, bool) {
+		ds, ok := e.GetConfig().GetStringAtPath(path)
+		if !ok {
+			return 0, false
+		}
+		d, err := time.ParseDuration(ds)
+		if err != nil {
+			return 0, false
+		}
+		return d, true
+	}
+	return e.GetDuration(ProofCheckDefaultTimeout,
+		func() (time.Duration, bool) { return parse("identify.proof_timeouts." + proofType) },
+		func() (time.Duration, bool) { return parse("identify.proof_timeout") },
+	)
+}
+
+// GetTeamStalenessBudget returns how stale a verified cached team
+// chain may be and still be served when the client is offline. 0 (the
+// default) keeps the original hard-fail behavior.
+func (e *Env) GetTeamStalenessBudget() time.Duration {
+	return e.GetDuration(0,
+		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_TEAM_STALENESS_BUDGET") },
+		func() (time.Duration, bool) {
+			ds, ok := e.GetConfig().GetStringAtPath("team.staleness_budget")
+			if !ok {
+				return 0, false
+			}
+			d, err := time.ParseDuration(ds)
+			if err != nil {
+				return 0, false
+			}
+			return d, true
+		},
+	)
+}
+
+// GetIdentifyCacheTimeout returns how long a clean identify outcome is
+// served from cache before external proofs get re-checked. Overridable
+// via identify.cache_timeout for users who want fresher (or cheaper)
+// proof checking.
+func (e *Env) GetIdentifyCacheTimeout() time.Duration {
+	return e.GetDuration(Identify2CacheLongTimeout,
+		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_IDENTIFY_CACHE_TIMEOUT") },
+		func() (time.Duration, bool) {
+			ds, ok := e.GetConfig().GetStringAtPath("identify.cache_timeout")
+			if !ok {
+				return 0, false
+			}
+			d, err := time.ParseDuration(ds)
+			if err != nil {
+				return 0, false
+			}
+			return d, true
+		},
+	)
+}
+
+// GetTrackMaxAge returns the tracking freshness policy: track
+// statements older than this trigger a background re-identify of the
+// tracked user. 0 (the default) disables the policy.
+func (e *Env) GetTrackMaxAge() time.Duration {
+	return e.GetDuration(0,
+		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_TRACK_MAX_AGE") },
+		func() (time.Duration, bool) {
+			ds, ok := e.GetConfig().GetStringAtPath("track.max_age")
+			if !ok {
+				return 0, false
+			}
+			d, err := time.ParseDuration(ds)
+			if err != nil {
+				return 0, false
+			}
+			return d, true
+		},
+	)
+}
+
+// GetTrackAutoRetrack returns whether a clean background re-identify
+// should also refresh the track statement automatically.
+func (e *Env) GetTrackAutoRetrack() bool {
+	return e.GetBool(false,
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_TRACK_AUTO_RETRACK") },
+		func() (bool, bool) { return e.GetConfig().GetBoolAtPath("track.auto_retrack") },
+	)
+}
+
+// GetPUKRotationInterval returns the cadence on which the per-user-key
+// should be rotated proactively, or 0 (the default) for no scheduled
+// rotation.
+func (e *Env) GetPUKRotationInterval() time.Duration {
+	return e.GetDuration(0,
+		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_PUK_ROTATION_INTERVAL") },
+		func() (time.Duration, bool) {
+			ds, ok := e.GetConfig().GetStringAtPath("puk.rotation_interval")
+			if !ok {
+				return 0, false
+			}
+			d, err := time.ParseDuration(ds)
+			if err != nil {
+				return 0, false
+			}
+			return d, true
+		},
+	)
+}
+
+// GetPUKRotateAfterRevokes returns how many device revocations since
+// the last per-user-key roll should force a fresh roll, or 0 (the
+// default) to disable the policy.
+func (e *Env) GetPUKRotateAfterRevokes() int {
+	return e.GetInt(0,
+		func() (int, bool) { return e.getEnvInt("KEYBASE_PUK_ROTATE_AFTER_REVOKES") },
+		func() (int, bool) { return e.GetConfig().GetIntAtPath("puk.rotate_after_revokes") },
+	)
+}
+
+// GetDbEncryptAtRest returns true unless at-rest encryption of LocalDb
+// values has been turned off via KEYBASE_DB_DISABLE_ENCRYPTION=1 or the
+// db.disable_at_rest_encryption config setting (a debugging escape
+// hatch for poking at the raw DB with external tooling).
+func (e *Env) GetDbEncryptAtRest() bool {
+	return !e.GetBool(false,
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_DB_DISABLE_ENCRYPTION") },
+		func() (bool, bool) { return e.GetConfig().GetBoolAtPath("db.disable_at_rest_encryption") },
+	)
+}
+
+// GetAutoLockTimeout returns how long the service may sit idle
+// before dropping in-memory secrets, or 0 for never.
+func (e *Env) GetAutoLockTimeout() time.Duration {
+	return e.GetDuration(0,
+		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_AUTO_LOCK_TIMEOUT") },
+		func() (time.Duration, bool) {
+			ds, ok := e.GetConfig().GetStringAtPath("service.auto_lock_timeout")
+			if !ok {
+				return 0, false
+			}
+			d, err := time.ParseDuration(ds)
+			if err != nil {
+				return 0, false
+			}
+			return d, true
+		},
+	)
+}
+
+// GetAsUser returns the account a single command should run as
+// (the global --as flag), or "" for the active account.
+func (e *Env) GetAsUser() string {
+	return e.GetString(
+		func() string { return e.cmd.GetGString("as") },
+	)
+}
+
+// GetBatchMode reports whether interactive prompts are forbidden:
+// they are answered from the prompt file or fail with exit code 30.
+func (e *Env) GetBatchMode() bool {
+	return e.GetBool(false,
+		func() (bool, bool) { return e.cmd.GetBool("batch", true) },
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_BATCH") },
+	)
+}
+
+// GetPromptFile returns the JSON answers file for batch mode.
+func (e *Env) GetPromptFile() string {
+	return e.GetString(
+		func() string { return e.cmd.GetGString("prompt-file") },
+		func() string { return os.Getenv("KEYBASE_PROMPT_FILE") },
+	)
+}
+
+// GetOutputFormat returns the requested CLI output format: "json"
+// for the machine-readable mode, "" or "text" for human output.
+func (e *Env) GetOutputFormat() string {
+	return e.GetString(
+		func() string { return e.cmd.GetGString("format") },
+		func() string { return os.Getenv("KEYBASE_OUTPUT_FORMAT") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("output_format")
+			return s
+		},
+	)
+}
+
+func (e *Env) GetNativeNotifications() bool {
+	return e.GetBool(false,
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_NATIVE_NOTIFICATIONS") },
+		func() (bool, bool) { return e.GetConfig().GetBoolAtPath("service.native_notifications") },
+	)
+}
+
+func (e *Env) GetMetricsBind() string {
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_METRICS_BIND") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("service.metrics_bind")
+			return s
+		},
+	)
+}
+
+// GetAttemptHTTP2 returns true unless HTTP/2 negotiation with the API
+// server has been turned off via KEYBASE_ATTEMPT_HTTP2=0 or the
+// api.attempt_http2 config setting.
+func (e *Env) GetAttemptHTTP2() bool {
+	return e.GetBool(true,
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_ATTEMPT_HTTP2") },
+		func() (bool, bool) { return e.GetConfig().GetBoolAtPath("api.attempt_http2") },
+	)
+}
+
 func (e *Env) GetScraperTimeout() time.Duration {
 	return e.GetDuration(HTTPDefaultScraperTimeout,
 		func() (time.Duration, bool) { return e.cmd.GetScraperTimeout() },
@@ -903,6 +1122,15 @@ func (e *Env) GetUPAKCacheSize() int {
 	)
 }
 
+// GetUPAKDiskCacheSize bounds how many UPAKs we keep in the on-disk
+// cache before the loader prunes the least recently cached.
+func (e *Env) GetUPAKDiskCacheSize() int {
+	return e.GetInt(UPAKDiskCacheSize,
+		func() (int, bool) { return e.getEnvInt("KEYBASE_UPAK_DISK_CACHE_SIZE") },
+		func() (int, bool) { return e.GetConfig().GetIntAtPath("upak.disk_cache_size") },
+	)
+}
+
 func (e *Env) GetUIDMapFullNameCacheSize() int {
 	return e.GetInt(UIDMapFullNameCacheSize,
 		e.cmd.GetUIDMapFullNameCacheSize,
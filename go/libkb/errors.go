@@ -1375,6 +1375,17 @@ func (e APINetError) Error() string {
 
 //=============================================================================
 
+// APICircuitOpenError is returned, without any network activity, while
+// the API circuit breaker is failing fast after repeated connection
+// failures.
+type APICircuitOpenError struct{}
+
+func (e APICircuitOpenError) Error() string {
+	return "API request failed fast: circuit breaker is open"
+}
+
+//=============================================================================
+
 type NoDecryptionKeyError struct {
 	Msg string
 }
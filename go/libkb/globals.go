@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"runtime"
 	"sync"
@@ -46,24 +47,28 @@ type StandaloneChatConnector interface {
 }
 
 type GlobalContext struct {
-	Log              logger.Logger // Handles all logging
-	VDL              *VDebugLog    // verbose debug log
-	Env              *Env          // Env variables, cmdline args & config
-	SKBKeyringMu     *sync.Mutex   // Protects all attempts to mutate the SKBKeyringFile
-	Keyrings         *Keyrings     // Gpg Keychains holding keys
-	perUserKeyringMu *sync.Mutex
-	perUserKeyring   *PerUserKeyring      // Keyring holding per user keys
-	API              API                  // How to make a REST call to the server
-	Resolver         Resolver             // cache of resolve results
-	LocalDb          *JSONLocalDb         // Local DB for cache
-	LocalChatDb      *JSONLocalDb         // Local DB for cache
-	MerkleClient     *MerkleClient        // client for querying server's merkle sig tree
-	XAPI             ExternalAPI          // for contacting Twitter, Github, etc.
-	Output           io.Writer            // where 'Stdout'-style output goes
-	DNSNSFetcher     DNSNameServerFetcher // The mobile apps potentially pass an implementor of this interface which is used to grab currently configured DNS name servers
-	AppState         *AppState            // The state of focus for the currently running instance of the app
-	ChatHelper       ChatHelper           // conveniently send chat messages
-	RPCCanceller     *RPCCanceller        // register live RPCs so they can be cancelleed en masse
+	Log               logger.Logger // Handles all logging
+	VDL               *VDebugLog    // verbose debug log
+	Env               *Env          // Env variables, cmdline args & config
+	SKBKeyringMu      *sync.Mutex   // Protects all attempts to mutate the SKBKeyringFile
+	Keyrings          *Keyrings     // Gpg Keychains holding keys
+	perUserKeyringMu  *sync.Mutex
+	perUserKeyring    *PerUserKeyring       // Keyring holding per user keys
+	API               API                   // How to make a REST call to the server
+	apiRoundTripper   http.RoundTripper     // optional override for the API client's transport
+	APICircuitBreaker *APICircuitBreaker    // fail fast once the API server looks unreachable
+	NetworkStats      *NetworkStatsRecorder // per-endpoint byte/latency accounting for API requests
+	Metrics           *MetricsRegistry      // counters/gauges for the optional Prometheus endpoint
+	Resolver          Resolver              // cache of resolve results
+	LocalDb           *JSONLocalDb          // Local DB for cache
+	LocalChatDb       *JSONLocalDb          // Local DB for cache
+	MerkleClient      *MerkleClient         // client for querying server's merkle sig tree
+	XAPI              ExternalAPI           // for contacting Twitter, Github, etc.
+	Output            io.Writer             // where 'Stdout'-style output goes
+	DNSNSFetcher      DNSNameServerFetcher  // The mobile apps potentially pass an implementor of this interface which is used to grab currently configured DNS name servers
+	AppState          *AppState             // The state of focus for the currently running instance of the app
+	ChatHelper        ChatHelper            // conveniently send chat messages
+	RPCCanceller      *RPCCanceller         // register live RPCs so they can be cancelleed en masse
 
 	cacheMu          *sync.RWMutex   // protects all caches
 	ProofCache       *ProofCache     // where to cache proof results
@@ -225,7 +230,10 @@ func (g *GlobalContext) Init() *GlobalContext {
 	g.teamAuditor = newNullTeamAuditor()
 	g.stellar = newNullStellar(g)
 	g.fullSelfer = NewUncachedFullSelf(g)
-	g.ConnectivityMonitor = NullConnectivityMonitor{}
+	g.APICircuitBreaker = NewAPICircuitBreaker(g)
+	g.NetworkStats = NewNetworkStatsRecorder()
+	g.Metrics = NewMetricsRegistry()
+	g.ConnectivityMonitor = NewConnectivityMonitorWithBreaker(g.APICircuitBreaker, NullConnectivityMonitor{})
 	g.localSigchainGuard = NewLocalSigchainGuard(g)
 	g.AppState = NewAppState(g)
 	g.RPCCanceller = NewRPCCanceller()
@@ -415,6 +423,15 @@ func (g *GlobalContext) ConfigureAPI() error {
 	return nil
 }
 
+// SetAPIRoundTripper installs rt as the transport for all subsequent API
+// server requests, in place of the http.Transport we otherwise build. It's
+// for embedders who need SOCKS dialing, custom proxy CAs, or request
+// logging. Call it before ConfigureAPI (or call ConfigureAPI again after)
+// so that already-built clients are remade with the new transport.
+func (g *GlobalContext) SetAPIRoundTripper(rt http.RoundTripper) {
+	g.apiRoundTripper = rt
+}
+
 // shutdownCachesLocked shutdown any non-nil caches that have running goroutines
 // in them. It can be called from either configureMemCachesLocked (via logout or flush),
 // or via Shutdown. In either case, callers must hold g.cacheMu.
@@ -512,8 +529,8 @@ func (g *GlobalContext) configureDiskCachesLocked() error {
 	// We consider the local DBs as caches; they're caching our
 	// fetches from the server after all (and also our cryptographic
 	// checking).
-	g.LocalDb = NewJSONLocalDb(NewLevelDb(g, g.Env.GetDbFilename))
-	g.LocalChatDb = NewJSONLocalDb(NewLevelDb(g, g.Env.GetChatDbFilename))
+	g.LocalDb = NewJSONLocalDb(newLocalDbEngine(g, g.Env.GetDbFilename))
+	g.LocalChatDb = NewJSONLocalDb(newLocalDbEngine(g, g.Env.GetChatDbFilename))
 
 	epick := FirstErrorPicker{}
 	epick.Push(g.LocalDb.Open())
@@ -826,6 +843,16 @@ func (g *GlobalContext) GetStoredSecretAccessGroup() string {
 	return g.Env.GetStoredSecretAccessGroup()
 }
 
+// LockSecrets drops all in-memory secret key material without
+// logging out: the auto-lock / `keybase lock` state.
+func (g *GlobalContext) LockSecrets() {
+	g.ActiveDevice.ClearSecretKeys()
+	g.secretStoreMu.Lock()
+	g.secretStore.LockMem()
+	g.secretStoreMu.Unlock()
+	g.ClearPerUserKeyring()
+}
+
 func (g *GlobalContext) GetUsersWithStoredSecrets(ctx context.Context) ([]string, error) {
 	g.secretStoreMu.Lock()
 	defer g.secretStoreMu.Unlock()
@@ -1084,6 +1111,12 @@ func (g *GlobalContext) LoadUserByUID(uid keybase1.UID) (*User, error) {
 func (g *GlobalContext) BustLocalUserCache(u keybase1.UID) {
 	g.GetUPAKLoader().Invalidate(g.NetContext, u)
 	g.GetFullSelfer().HandleUserChanged(u)
+	// Cached identify outcomes are stale the moment the sigchain moves;
+	// repeated identify callers (chat, KBFS) otherwise keep serving the
+	// old proof set for the full TTL.
+	if c := g.Identify2Cache(); c != nil {
+		_ = c.Delete(u)
+	}
 }
 
 func (g *GlobalContext) OverrideUPAKLoader(upak UPAKLoader) {
@@ -5,6 +5,7 @@ package libkb
 
 import (
 	"fmt"
+	context "golang.org/x/net/context"
 	"strings"
 	"time"
 
@@ -123,9 +124,8 @@ func CanonicalProofName(t TypedChainLink) string {
 //
 //=========================================================================
 
-//=========================================================================
+// =========================================================================
 // Remote, Web and Social
-//
 type RemoteProofChainLink interface {
 	TypedChainLink
 	TableKey() string
@@ -228,6 +228,9 @@ func (w *WebProofChainLink) ComputeTrackDiff(tl *TrackLookup) (res TrackDiff) {
 		res = TrackDiffNone{}
 	} else if w.protocol == "https" && find(tl.ids["http"]) {
 		res = TrackDiffUpgraded{"http", "https"}
+	} else if tl.IsProofTypeExcluded(w.TableKey()) {
+		// Deliberately untracked; not news.
+		res = TrackDiffNone{}
 	} else {
 		res = TrackDiffNew{}
 	}
@@ -271,6 +274,10 @@ func NewSocialProofChainLink(b GenericChainLink, s, u, proofText string) *Social
 func (s *SocialProofChainLink) ComputeTrackDiff(tl *TrackLookup) TrackDiff {
 	k, v := s.ToKeyValuePair()
 	if list, found := tl.ids[k]; !found || len(list) == 0 {
+		if tl.IsProofTypeExcluded(k) {
+			// Deliberately untracked; not news.
+			return TrackDiffNone{}
+		}
 		return TrackDiffNew{}
 	} else if expected := list[len(list)-1]; !Cicmp(expected, v) {
 		return TrackDiffClash{observed: v, expected: expected}
@@ -396,9 +403,8 @@ func remoteProofInsertIntoTable(l RemoteProofChainLink, tab *IdentityTable) {
 //
 //=========================================================================
 
-//=========================================================================
+// =========================================================================
 // TrackChainLink
-//
 type TrackChainLink struct {
 	GenericChainLink
 	whomUsername  NormalizedUsername
@@ -640,9 +646,10 @@ func (s *EldestChainLink) insertIntoTable(tab *IdentityTable) {
 
 type SibkeyChainLink struct {
 	GenericChainLink
-	kid        keybase1.KID
-	device     *Device
-	reverseSig string
+	kid          keybase1.KID
+	device       *Device
+	reverseSig   string
+	capabilities []string
 }
 
 func ParseSibkeyChainLink(b GenericChainLink) (ret *SibkeyChainLink, err error) {
@@ -668,7 +675,20 @@ func ParseSibkeyChainLink(b GenericChainLink) (ret *SibkeyChainLink, err error)
 		}
 	}
 
-	ret = &SibkeyChainLink{b, kid, device, rs}
+	// Optional capability scoping for bot/service keys; see
+	// ComputedKeyInfo.Capabilities.
+	var capabilities []string
+	if jw := payload.AtPath("body.capabilities"); !jw.IsNil() {
+		if n, lerr := jw.Len(); lerr == nil {
+			for i := 0; i < n; i++ {
+				if c, cerr := jw.AtIndex(i).GetString(); cerr == nil {
+					capabilities = append(capabilities, c)
+				}
+			}
+		}
+	}
+
+	ret = &SibkeyChainLink{b, kid, device, rs, capabilities}
 	return
 }
 
@@ -677,6 +697,7 @@ func (s *SibkeyChainLink) GetRole() KeyRole              { return DLGSibkey }
 func (s *SibkeyChainLink) Type() string                  { return string(DelegationTypeSibkey) }
 func (s *SibkeyChainLink) ToDisplayString() string       { return s.kid.String() }
 func (s *SibkeyChainLink) GetDevice() *Device            { return s.device }
+func (s *SibkeyChainLink) GetCapabilities() []string     { return s.capabilities }
 func (s *SibkeyChainLink) GetPGPFullHash() string        { return s.extractPGPFullHash("sibkey") }
 func (s *SibkeyChainLink) insertIntoTable(tab *IdentityTable) {
 	tab.insertLink(s)
@@ -1462,7 +1483,12 @@ func (idt *IdentityTable) Identify(m MetaContext, is IdentifyState, forceRemoteC
 	errs := make(chan error, len(is.res.ProofChecks))
 	for _, lcr := range is.res.ProofChecks {
 		go func(l *LinkCheckResult) {
-			errs <- idt.identifyActiveProof(m, l, is, forceRemoteCheck, ui, ccl, itm)
+			// Bound each proof check separately so one slow host
+			// doesn't stall the rest; results stream to the UI as the
+			// other checks complete.
+			ctx, cancel := context.WithTimeout(m.Ctx(), idt.G().Env.GetProofCheckTimeout(l.link.TableKey()))
+			defer cancel()
+			errs <- idt.identifyActiveProof(m.WithCtx(ctx), l, is, forceRemoteCheck, ui, ccl, itm)
 		}(lcr)
 	}
 
@@ -1592,7 +1618,7 @@ func (idt *IdentityTable) proofRemoteCheck(m MetaContext, hasPreviousTrack, forc
 
 	// Call the Global context's version of what a proof checker is. We might want to stub it out
 	// for the purposes of testing.
-	pc, res.err = MakeProofChecker(m.G().Services, p)
+	pc, res.err = MakeProofChecker(m, m.G().Services, p)
 
 	if res.err != nil {
 		return
@@ -203,7 +203,7 @@ func (i IdentifyOutcome) IsOK() bool {
 }
 
 func (i IdentifyOutcome) TrackingStatement() *jsonw.Wrapper {
-	return i.remoteProofLinks().TrackingStatement()
+	return i.remoteProofLinks().TrackingStatementExcluding(i.TrackOptions.ExcludeProofTypes)
 }
 
 func (i IdentifyOutcome) GetErrorAndWarnings(strict bool) (warnings Warnings, err error) {
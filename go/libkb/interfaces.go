@@ -129,6 +129,20 @@ type LocalDbTransaction interface {
 	Discard()
 }
 
+// LocalDbBatch accumulates writes in memory and lands them all in a
+// single DB write on Commit. Unlike a transaction it offers no reads
+// and no isolation; it exists so that bulk writers (storing a long
+// sigchain, say) pay for one fsync instead of one per record.
+type LocalDbBatch interface {
+	Put(id DbKey, aliases []DbKey, value []byte) error
+	Delete(id DbKey) error
+	Commit() error
+	// Discard abandons the batch; engines that back the batch with a
+	// real transaction must roll it back here or leak resources.
+	// A no-op for in-memory batches, and safe to call after Commit.
+	Discard()
+}
+
 type LocalDb interface {
 	LocalDbOps
 	Open() error
@@ -136,6 +150,7 @@ type LocalDb interface {
 	Close() error
 	Nuke() (string, error)
 	OpenTransaction() (LocalDbTransaction, error)
+	BeginBatch() (LocalDbBatch, error)
 }
 
 type KVStorer interface {
@@ -844,6 +859,7 @@ type SaltpackRecipientKeyfinderArg struct {
 	UsePaperKeys      bool
 	UseDeviceKeys     bool // Does not include Paper Keys
 	UseRepudiableAuth bool // This is needed as team keys (implicit or not) are not compatible with repudiable authentication, so we can error out.
+	UseExplodingKeys  bool // Encrypt to recipients' current user ephemeral keys, so the ciphertext dies with them.
 }
 
 type SaltpackReceiverSymmetricKey struct {
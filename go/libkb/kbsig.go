@@ -170,6 +170,13 @@ func (u *User) ToTrackingStatement(w *jsonw.Wrapper, outcome *IdentifyOutcome) (
 	track.SetKey("basics", u.ToTrackingStatementBasics(&err))
 	track.SetKey("id", UIDWrapper(u.id))
 	track.SetKey("remote_proofs", outcome.TrackingStatement())
+	if excl := outcome.TrackOptions.ExcludeProofTypes; len(excl) > 0 {
+		ew := jsonw.NewArray(len(excl))
+		for i, e := range excl {
+			ew.SetIndex(i, jsonw.NewString(e))
+		}
+		track.SetKey("excluded_proof_types", ew)
+	}
 
 	if err != nil {
 		return err
@@ -450,6 +457,14 @@ func KeyProof(m MetaContext, arg Delegator) (ret *jsonw.Wrapper, err error) {
 
 	body := ret.AtKey("body")
 
+	if len(arg.Capabilities) > 0 {
+		capsw := jsonw.NewArray(len(arg.Capabilities))
+		for i, c := range arg.Capabilities {
+			capsw.SetIndex(i, jsonw.NewString(c))
+		}
+		body.SetKey("capabilities", capsw)
+	}
+
 	if arg.Device != nil {
 		device := *arg.Device
 		device.Kid = arg.NewKey.GetKID()
@@ -90,6 +90,10 @@ type ComputedKeyInfo struct {
 	// legacy behavior of combining every instance of this key that we got from
 	// the server minus revocations.
 	ActivePGPHash string
+
+	// Capabilities restricts what this key may do, as signed into its
+	// delegation link (bot/service keys). Nil means unrestricted.
+	Capabilities []string
 }
 
 func (cki ComputedKeyInfo) GetCTime() time.Time {
@@ -572,6 +576,15 @@ func (ckf ComputedKeyFamily) FindActiveEncryptionSubkey(kid keybase1.KID) (ret G
 	return key, *ckip, nil
 }
 
+// ActivePGPHashForKID returns the full hash of the PGP key version the
+// sigchain last pinned for kid, or "" if no hash was ever recorded.
+func (ckf ComputedKeyFamily) ActivePGPHashForKID(kid keybase1.KID) string {
+	if info, found := ckf.cki.Infos[kid]; found {
+		return info.ActivePGPHash
+	}
+	return ""
+}
+
 func (ckf ComputedKeyFamily) FindKIDFromFingerprint(fp PGPFingerprint) (kid keybase1.KID, err error) {
 	kid, ok := ckf.kf.pgp2kid[fp]
 	if !ok {
@@ -623,7 +636,57 @@ func (ckf *ComputedKeyFamily) Delegate(tcl TypedChainLink) (err error) {
 	err = ckf.cki.Delegate(kid, tm, sigid, tcl.GetKID(), tcl.GetParentKid(),
 		tcl.GetPGPFullHash(), (tcl.GetRole() == DLGSibkey), tcl.GetCTime(), tcl.GetETime(),
 		mhm, tcl.GetFirstAppearedMerkleSeqnoUnverified(), tcl.ToSigChainLocation())
-	return
+	if err != nil {
+		return err
+	}
+
+	// Carry any capability scoping from the delegation link onto the
+	// computed key info, where verifiers consult it.
+	if cc, ok := tcl.(capabilityCarrier); ok {
+		if caps := cc.GetCapabilities(); len(caps) > 0 {
+			if info, found := ckf.cki.Infos[kid]; found {
+				info.Capabilities = caps
+			}
+		}
+	}
+	return nil
+}
+
+// capabilityCarrier is implemented by chain links that scope the
+// delegated key to specific capabilities.
+type capabilityCarrier interface {
+	GetCapabilities() []string
+}
+
+// Capability names signed into scoped delegations.
+const (
+	CapabilityChatSend         = "chat-send"
+	CapabilitySibkeyDelegation = "sibkey-delegation"
+	CapabilityPUKAccess        = "puk-access"
+)
+
+// KeyCapabilities returns the capability scoping signed into a key's
+// delegation, or nil for an unrestricted key.
+func (ckf ComputedKeyFamily) KeyCapabilities(kid keybase1.KID) []string {
+	if info, found := ckf.cki.Infos[kid]; found {
+		return info.Capabilities
+	}
+	return nil
+}
+
+// KeyHasCapability reports whether the key may perform the named
+// operation. Keys delegated without scoping may do anything.
+func (ckf ComputedKeyFamily) KeyHasCapability(kid keybase1.KID, capability string) bool {
+	caps := ckf.KeyCapabilities(kid)
+	if caps == nil {
+		return true
+	}
+	for _, c := range caps {
+		if c == capability {
+			return true
+		}
+	}
+	return false
 }
 
 func (ckf *ComputedKeyFamily) DelegatePerUserKey(perUserKey keybase1.PerUserKey) (err error) {
@@ -1253,6 +1316,28 @@ func (ckf ComputedKeyFamily) GetSaltpackSenderTypeIfInactive(kid keybase1.KID) (
 	return nil, fmt.Errorf("Key %s neither active nor revoked (%d)", kid.String(), info.Status)
 }
 
+// KeyActiveAtTime reports whether the key was active at the given
+// time, judged by the merkle-anchored delegation and revocation
+// timestamps replayed from the sigchain. Verifiers of old artifacts use
+// this to distinguish "valid at signing time, key since revoked" from a
+// signature that postdates the revocation.
+func (ckf ComputedKeyFamily) KeyActiveAtTime(kid keybase1.KID, t time.Time) (bool, error) {
+	info := ckf.cki.Infos[kid]
+	if info == nil {
+		return false, KeyFamilyError{fmt.Sprintf("key %s not found in key infos", kid)}
+	}
+	if info.DelegatedAt == nil || t.Unix() < info.DelegatedAt.Unix {
+		return false, nil
+	}
+	if info.RevokedAt != nil && t.Unix() >= info.RevokedAt.Unix {
+		return false, nil
+	}
+	if etime := info.GetETime(); !etime.IsZero() && t.After(etime) {
+		return false, nil
+	}
+	return true, nil
+}
+
 // If there aren't any per-user-keys for the user, return nil.
 func (ckf *ComputedKeyFamily) GetLatestPerUserKey() *keybase1.PerUserKey {
 	var currentGeneration keybase1.PerUserKeyGeneration
@@ -0,0 +1,38 @@
+package libkb
+
+import (
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyActiveAtTime(t *testing.T) {
+	tc := SetupTest(t, "key_active_at_time", 1)
+	defer tc.Cleanup()
+
+	kid := keybase1.KID("0120dd0a")
+	cki := NewComputedKeyInfos(tc.G)
+	cki.Infos[kid] = &ComputedKeyInfo{
+		KID:         kid,
+		DelegatedAt: &KeybaseTime{Unix: 1000},
+		RevokedAt:   &KeybaseTime{Unix: 2000},
+	}
+	ckf := ComputedKeyFamily{cki: cki, Contextified: NewContextified(tc.G)}
+
+	check := func(unix int64, want bool) {
+		active, err := ckf.KeyActiveAtTime(kid, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, want, active, "at unix %d", unix)
+	}
+	check(999, false)  // before delegation
+	check(1000, true)  // at delegation
+	check(1500, true)  // mid-window
+	check(2000, false) // at revocation
+	check(2500, false) // after revocation
+
+	// Unknown keys error rather than guessing.
+	_, err := ckf.KeyActiveAtTime(keybase1.KID("0120ee0a"), time.Unix(1500, 0))
+	require.Error(t, err)
+}
@@ -6,6 +6,7 @@ package libkb
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -13,6 +14,7 @@ import (
 	errors "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 // table names
@@ -314,6 +316,70 @@ func (l *LevelDb) OpenTransaction() (LocalDbTransaction, error) {
 	return ltr, nil
 }
 
+// ForEachRawKv walks every row of the kv table, passing the parsed key
+// and raw value to fn. It's used by maintenance passes (the at-rest
+// encryption sweep, DB verification) that need to see all entries.
+func (l *LevelDb) ForEachRawKv(fn func(id DbKey, value []byte) error) error {
+	return l.doWhileOpenAndNukeIfCorrupted(func() error {
+		iter := l.db.NewIterator(util.BytesPrefix([]byte(levelDbTableKv+":")), nil)
+		defer iter.Release()
+		for iter.Next() {
+			// Not DbKeyParse: raw keys may contain further colons
+			// (e.g. "kv:c3:team:user"), which it rejects.
+			parts := strings.SplitN(string(iter.Key()), ":", 3)
+			if len(parts) != 3 || parts[0] != levelDbTableKv {
+				continue
+			}
+			typ, err := strconv.ParseUint(parts[1], 16, 8)
+			if err != nil {
+				continue
+			}
+			id := DbKey{Typ: ObjType(typ), Key: parts[2]}
+			value := make([]byte, len(iter.Value()))
+			copy(value, iter.Value())
+			if err := fn(id, value); err != nil {
+				return err
+			}
+		}
+		return iter.Error()
+	})
+}
+
+func (l *LevelDb) BeginBatch() (LocalDbBatch, error) {
+	return &LevelDbBatch{b: new(leveldb.Batch), l: l}, nil
+}
+
+// LevelDbBatch collects writes in memory; Commit lands them in one
+// leveldb write.
+type LevelDbBatch struct {
+	b *leveldb.Batch
+	l *LevelDb
+}
+
+func (b *LevelDbBatch) Put(id DbKey, aliases []DbKey, value []byte) error {
+	idb := id.ToBytes(levelDbTableKv)
+	b.b.Put(idb, value)
+	for _, alias := range aliases {
+		b.b.Put(alias.ToBytes(levelDbTableLo), idb)
+	}
+	return nil
+}
+
+func (b *LevelDbBatch) Delete(id DbKey) error {
+	b.b.Delete(id.ToBytes(levelDbTableKv))
+	return nil
+}
+
+func (b *LevelDbBatch) Commit() (err error) {
+	defer convertNoSpaceError(err)
+	return b.l.doWhileOpenAndNukeIfCorrupted(func() error {
+		return b.l.db.Write(b.b, nil)
+	})
+}
+
+// Discard is a no-op: the pending writes are only in memory.
+func (b *LevelDbBatch) Discard() {}
+
 type LevelDbTransaction struct {
 	tr *leveldb.Transaction
 }
@@ -358,3 +358,65 @@ func TestLevelDb(t *testing.T) {
 
 	td.teardown()
 }
+
+func TestLevelDbBatch(t *testing.T) {
+	tc := SetupTest(t, "LevelDbBatch", 1)
+	defer tc.Cleanup()
+
+	var td teardowner
+	defer td.teardown()
+
+	db, err := createTempLevelDbForTest(&tc, &td)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := db.BeginBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aliasKey := DbKey{Typ: DBLookupUsername, Key: "alias"}
+	for i := 0; i < 100; i++ {
+		key := DbKey{Typ: DBLink, Key: fmt.Sprintf("batch-%d", i)}
+		var aliases []DbKey
+		if i == 0 {
+			aliases = []DbKey{aliasKey}
+		}
+		if err := batch.Put(key, aliases, []byte(fmt.Sprintf("value-%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing lands before Commit.
+	if _, found, err := db.Get(DbKey{Typ: DBLink, Key: "batch-0"}); err != nil {
+		t.Fatal(err)
+	} else if found {
+		t.Fatal("expected no value before batch commit")
+	}
+
+	if err := batch.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		key := DbKey{Typ: DBLink, Key: fmt.Sprintf("batch-%d", i)}
+		val, found, err := db.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Fatalf("expected to find %v after commit", key)
+		}
+		if !bytes.Equal(val, []byte(fmt.Sprintf("value-%d", i))) {
+			t.Fatalf("wrong value for %v: %s", key, val)
+		}
+	}
+
+	// Aliases written through the batch resolve like direct puts.
+	if _, found, err := db.Lookup(aliasKey); err != nil {
+		t.Fatal(err)
+	} else if !found {
+		t.Fatal("expected alias lookup to hit after commit")
+	}
+}
@@ -304,6 +304,15 @@ func LoadUser(arg LoadUserArg) (ret *User, err error) {
 
 	m.CDebugf("| resolved to %s", arg.uid)
 
+	// A fresh tombstone means we recently learned this user is deleted;
+	// don't re-hit the network just to be told again. An explicit force
+	// reload bypasses the tombstone (accounts can be resurrected).
+	if !arg.forceReload {
+		if err = checkUserTombstone(m, arg.uid); err != nil {
+			return nil, err
+		}
+	}
+
 	// We can get the user object's body from either the resolution result or
 	// if it was plumbed through as a parameter.
 	resolveBody := rres.body
@@ -324,6 +333,9 @@ func LoadUser(arg LoadUserArg) (ret *User, err error) {
 	// load user from local, remote
 	ret, refresh, err = loadUser(m, arg.uid, resolveBody, sigHints, arg.forceReload, arg.merkleLeaf)
 	if err != nil {
+		if _, deleted := err.(UserDeletedError); deleted {
+			putUserTombstone(m, arg.uid)
+		}
 		return nil, err
 	}
 
@@ -337,6 +349,9 @@ func LoadUser(arg LoadUserArg) (ret *User, err error) {
 	}
 
 	if err = ret.LoadSigChains(m, &ret.leaf, arg.self); err != nil {
+		if _, deleted := err.(UserDeletedError); deleted {
+			putUserTombstone(m, arg.uid)
+		}
 		return ret, err
 	}
 
@@ -0,0 +1,334 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// EncryptedLocalDb wraps a LocalDb engine and transparently secretboxes
+// values at rest with a key derived from the device's stored secret
+// (via SecretStore). Cached sigchains, UPAKs and chat bodies otherwise
+// sit unencrypted in the LevelDB files. Keys and alias pointers are not
+// encrypted, just values.
+//
+// Reads accept both boxed and legacy plaintext values, so turning the
+// feature on (or reading a DB written before it existed) always works;
+// a one-time sweep re-encrypts existing plaintext rows the first time
+// the key is available. If no device secret is available (not logged
+// in, say) writes fall through in plaintext and are picked up by the
+// sweep later. The db.disable_at_rest_encryption config setting (or
+// KEYBASE_DB_DISABLE_ENCRYPTION=1) is the escape hatch for debugging
+// with external LevelDB tooling.
+type EncryptedLocalDb struct {
+	inner LocalDb
+	Contextified
+
+	keyMu       sync.Mutex
+	key         [32]byte
+	keySet      bool
+	lastKeyMiss time.Time
+}
+
+// localDbKeyRetryInterval throttles SecretStore probes while no device
+// secret is available, so logged-out DB traffic doesn't hammer it.
+const localDbKeyRetryInterval = 10 * time.Second
+
+var _ LocalDb = (*EncryptedLocalDb)(nil)
+
+// localDbBoxedPrefix marks an encrypted value; the format is
+// prefix || version || nonce[24] || secretbox.
+var localDbBoxedPrefix = []byte("KBBX")
+
+const localDbCryptoVersion = 1
+
+func NewEncryptedLocalDb(g *GlobalContext, inner LocalDb) *EncryptedLocalDb {
+	return &EncryptedLocalDb{
+		inner:        inner,
+		Contextified: NewContextified(g),
+	}
+}
+
+// getKey derives (and caches) the at-rest key from the device's stored
+// secret. ok=false when no secret is available yet.
+func (e *EncryptedLocalDb) getKey() (key [32]byte, ok bool) {
+	e.keyMu.Lock()
+	defer e.keyMu.Unlock()
+	if e.keySet {
+		return e.key, true
+	}
+	now := e.G().Clock().Now()
+	if !e.lastKeyMiss.IsZero() && now.Sub(e.lastKeyMiss) < localDbKeyRetryInterval {
+		return key, false
+	}
+	e.lastKeyMiss = now
+	m := NewMetaContextBackground(e.G())
+	un := e.G().Env.GetUsername()
+	if un.IsNil() {
+		return key, false
+	}
+	secret, err := e.G().SecretStore().RetrieveSecret(m, un)
+	if err != nil || secret.IsNil() {
+		return key, false
+	}
+	var inKey [32]byte
+	copy(inKey[:], secret.Bytes())
+	derived, err := DeriveFromSecret(inKey, DeriveReasonLocalDbAtRest)
+	if err != nil {
+		return key, false
+	}
+	e.key = derived
+	e.keySet = true
+	return e.key, true
+}
+
+// box encrypts a value for storage. With no key available it falls
+// back to plaintext by design (the sweep picks those rows up later),
+// but nonce generation failures are propagated, never silently written
+// around.
+func (e *EncryptedLocalDb) box(value []byte) ([]byte, error) {
+	key, ok := e.getKey()
+	if !ok {
+		return value, nil
+	}
+	nonce, err := RandBytes(24)
+	if err != nil {
+		return nil, err
+	}
+	var fnonce [24]byte
+	copy(fnonce[:], nonce)
+	out := append([]byte{}, localDbBoxedPrefix...)
+	out = append(out, localDbCryptoVersion)
+	out = append(out, fnonce[:]...)
+	return secretbox.Seal(out, value, &fnonce, &key), nil
+}
+
+func (e *EncryptedLocalDb) unbox(value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, localDbBoxedPrefix) {
+		// Legacy plaintext value; see the migration sweep.
+		return value, nil
+	}
+	rest := value[len(localDbBoxedPrefix):]
+	if len(rest) < 1+24 {
+		return nil, DBError{Msg: "truncated encrypted value"}
+	}
+	if rest[0] != localDbCryptoVersion {
+		return nil, DBError{Msg: "bad crypto version on encrypted value"}
+	}
+	var nonce [24]byte
+	copy(nonce[:], rest[1:25])
+	key, ok := e.getKey()
+	if !ok {
+		return nil, DBError{Msg: "encrypted value but no device secret available"}
+	}
+	pt, opened := secretbox.Open(nil, rest[25:], &nonce, &key)
+	if !opened {
+		return nil, DBError{Msg: "failed to decrypt value"}
+	}
+	return pt, nil
+}
+
+func (e *EncryptedLocalDb) Open() error {
+	if err := e.inner.Open(); err != nil {
+		return err
+	}
+	e.maybeSweep()
+	return nil
+}
+
+func (e *EncryptedLocalDb) ForceOpen() error {
+	if err := e.inner.ForceOpen(); err != nil {
+		return err
+	}
+	e.maybeSweep()
+	return nil
+}
+
+func (e *EncryptedLocalDb) Close() error          { return e.inner.Close() }
+func (e *EncryptedLocalDb) Nuke() (string, error) { return e.inner.Nuke() }
+func (e *EncryptedLocalDb) Delete(id DbKey) error { return e.inner.Delete(id) }
+
+func (e *EncryptedLocalDb) Put(id DbKey, aliases []DbKey, value []byte) error {
+	boxed, err := e.box(value)
+	if err != nil {
+		return err
+	}
+	return e.inner.Put(id, aliases, boxed)
+}
+
+func (e *EncryptedLocalDb) Get(id DbKey) ([]byte, bool, error) {
+	value, found, err := e.inner.Get(id)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	value, err = e.unbox(value)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (e *EncryptedLocalDb) Lookup(id DbKey) ([]byte, bool, error) {
+	value, found, err := e.inner.Lookup(id)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	value, err = e.unbox(value)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (e *EncryptedLocalDb) OpenTransaction() (LocalDbTransaction, error) {
+	tr, err := e.inner.OpenTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return encryptedLocalDbTransaction{tr: tr, e: e}, nil
+}
+
+type encryptedLocalDbTransaction struct {
+	tr LocalDbTransaction
+	e  *EncryptedLocalDb
+}
+
+func (t encryptedLocalDbTransaction) Put(id DbKey, aliases []DbKey, value []byte) error {
+	boxed, err := t.e.box(value)
+	if err != nil {
+		return err
+	}
+	return t.tr.Put(id, aliases, boxed)
+}
+
+func (t encryptedLocalDbTransaction) Get(id DbKey) ([]byte, bool, error) {
+	value, found, err := t.tr.Get(id)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	value, err = t.e.unbox(value)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (t encryptedLocalDbTransaction) Lookup(id DbKey) ([]byte, bool, error) {
+	value, found, err := t.tr.Lookup(id)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	value, err = t.e.unbox(value)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (t encryptedLocalDbTransaction) Delete(id DbKey) error { return t.tr.Delete(id) }
+func (t encryptedLocalDbTransaction) Commit() error         { return t.tr.Commit() }
+func (t encryptedLocalDbTransaction) Discard()              { t.tr.Discard() }
+
+func (e *EncryptedLocalDb) BeginBatch() (LocalDbBatch, error) {
+	b, err := e.inner.BeginBatch()
+	if err != nil {
+		return nil, err
+	}
+	return encryptedLocalDbBatch{b: b, e: e}, nil
+}
+
+type encryptedLocalDbBatch struct {
+	b LocalDbBatch
+	e *EncryptedLocalDb
+}
+
+func (b encryptedLocalDbBatch) Put(id DbKey, aliases []DbKey, value []byte) error {
+	boxed, err := b.e.box(value)
+	if err != nil {
+		return err
+	}
+	return b.b.Put(id, aliases, boxed)
+}
+
+func (b encryptedLocalDbBatch) Delete(id DbKey) error { return b.b.Delete(id) }
+func (b encryptedLocalDbBatch) Commit() error         { return b.b.Commit() }
+
+// rawKvIterable is implemented by engines that can walk their kv table;
+// the migration sweep and DbVerify need it.
+type rawKvIterable interface {
+	ForEachRawKv(fn func(id DbKey, value []byte) error) error
+}
+
+func (b encryptedLocalDbBatch) Discard() { b.b.Discard() }
+
+// ForEachRawKv walks the inner engine, handing fn decrypted values.
+// Rows that fail to unbox are passed through raw, so integrity scans
+// see (and can condemn) them.
+func (e *EncryptedLocalDb) ForEachRawKv(fn func(id DbKey, value []byte) error) error {
+	it, ok := e.inner.(rawKvIterable)
+	if !ok {
+		return NewDBError("DB engine does not support iteration")
+	}
+	_, haveKey := e.getKey()
+	return it.ForEachRawKv(func(id DbKey, value []byte) error {
+		if bytes.HasPrefix(value, localDbBoxedPrefix) {
+			// Without the key we can't judge boxed rows; skip them
+			// rather than letting a scan condemn healthy data.
+			if !haveKey {
+				return nil
+			}
+			pt, err := e.unbox(value)
+			if err != nil {
+				// Undecryptable with the right key: surface the
+				// ciphertext so integrity scans can condemn it.
+				return fn(id, value)
+			}
+			value = pt
+		}
+		return fn(id, value)
+	})
+}
+
+var localDbEncryptionMarker = DbKey{Typ: DBLocalDbEncryption, Key: "swept-v1"}
+
+// maybeSweep re-encrypts existing plaintext values, once, as soon as
+// the device secret is available. Progress is recorded under a marker
+// key so subsequent opens skip the walk.
+func (e *EncryptedLocalDb) maybeSweep() {
+	if _, ok := e.getKey(); !ok {
+		return
+	}
+	if _, found, _ := e.inner.Get(localDbEncryptionMarker); found {
+		return
+	}
+	iter, ok := e.inner.(rawKvIterable)
+	if !ok {
+		return
+	}
+	var swept int
+	err := iter.ForEachRawKv(func(id DbKey, value []byte) error {
+		if bytes.HasPrefix(value, localDbBoxedPrefix) {
+			return nil
+		}
+		boxed, err := e.box(value)
+		if err != nil {
+			return err
+		}
+		if err := e.inner.Put(id, nil, boxed); err != nil {
+			return err
+		}
+		swept++
+		return nil
+	})
+	if err != nil {
+		e.G().Log.Debug("EncryptedLocalDb: sweep aborted: %s", err)
+		return
+	}
+	e.G().Log.Debug("EncryptedLocalDb: swept %d plaintext values", swept)
+	_ = e.inner.Put(localDbEncryptionMarker, nil, []byte("done"))
+}
@@ -0,0 +1,96 @@
+package libkb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEncryptedLocalDb(tc *TestContext, inner LocalDb) *EncryptedLocalDb {
+	e := NewEncryptedLocalDb(tc.G, inner)
+	// Short-circuit the SecretStore derivation; these tests have no
+	// provisioned device.
+	e.key = [32]byte{1, 2, 3, 4}
+	e.keySet = true
+	return e
+}
+
+func TestEncryptedLocalDbRoundTrip(t *testing.T) {
+	tc := SetupTest(t, "encrypted_local_db", 1)
+	defer tc.Cleanup()
+
+	inner := NewMemDb()
+	e := testEncryptedLocalDb(&tc, inner)
+
+	key := DbKey{Typ: DBLink, Key: "roundtrip"}
+	plaintext := []byte(`{"secret": "sigchain goes here"}`)
+	require.NoError(t, e.Put(key, nil, plaintext))
+
+	// The value on disk is boxed, not the plaintext.
+	raw, found, err := inner.Get(key)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, bytes.HasPrefix(raw, localDbBoxedPrefix))
+	require.False(t, bytes.Contains(raw, []byte("sigchain")))
+
+	// And reads round-trip.
+	got, found, err := e.Get(key)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, plaintext, got)
+
+	// Legacy plaintext values read through untouched.
+	legacyKey := DbKey{Typ: DBLink, Key: "legacy"}
+	require.NoError(t, inner.Put(legacyKey, nil, []byte("old plaintext")))
+	got, found, err = e.Get(legacyKey)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("old plaintext"), got)
+
+	// A wrong key fails closed rather than returning garbage.
+	e2 := testEncryptedLocalDb(&tc, inner)
+	e2.key = [32]byte{9, 9, 9}
+	_, _, err = e2.Get(key)
+	require.Error(t, err)
+}
+
+func TestEncryptedLocalDbSweep(t *testing.T) {
+	tc := SetupTest(t, "encrypted_local_db", 1)
+	defer tc.Cleanup()
+
+	var td teardowner
+	defer td.teardown()
+
+	inner, err := createTempLevelDbForTest(&tc, &td)
+	require.NoError(t, err)
+
+	// Seed some plaintext rows, as if written before encryption was on.
+	for _, k := range []string{"a", "b", "c"} {
+		require.NoError(t, inner.Put(DbKey{Typ: DBLink, Key: k}, nil, []byte("plain-"+k)))
+	}
+
+	e := testEncryptedLocalDb(&tc, inner)
+	require.NoError(t, e.Open())
+
+	// The sweep re-encrypted every row in place.
+	for _, k := range []string{"a", "b", "c"} {
+		raw, found, err := inner.Get(DbKey{Typ: DBLink, Key: k})
+		require.NoError(t, err)
+		require.True(t, found)
+		require.True(t, bytes.HasPrefix(raw, localDbBoxedPrefix), "row %s not swept", k)
+
+		got, found, err := e.Get(DbKey{Typ: DBLink, Key: k})
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, []byte("plain-"+k), got)
+	}
+
+	// The marker prevents a second walk from re-boxing boxed rows.
+	rawA1, _, err := inner.Get(DbKey{Typ: DBLink, Key: "a"})
+	require.NoError(t, err)
+	require.NoError(t, e.Open())
+	rawA2, _, err := inner.Get(DbKey{Typ: DBLink, Key: "a"})
+	require.NoError(t, err)
+	require.Equal(t, rawA1, rawA2)
+}
@@ -29,6 +29,39 @@ func (m *MemDb) OpenTransaction() (res LocalDbTransaction, err error) {
 	return res, errors.New("not implemented")
 }
 
+// BeginBatch on a MemDb has no fsync to amortize; the batch just queues
+// the writes and applies them on Commit.
+func (m *MemDb) BeginBatch() (LocalDbBatch, error) {
+	return &memDbBatch{db: m}, nil
+}
+
+type memDbBatch struct {
+	db  *MemDb
+	ops []func()
+}
+
+func (b *memDbBatch) Put(id DbKey, aliases []DbKey, value []byte) error {
+	b.ops = append(b.ops, func() { b.db.Put(id, aliases, value) })
+	return nil
+}
+
+func (b *memDbBatch) Delete(id DbKey) error {
+	b.ops = append(b.ops, func() { b.db.Delete(id) })
+	return nil
+}
+
+func (b *memDbBatch) Commit() error {
+	for _, op := range b.ops {
+		op()
+	}
+	b.ops = nil
+	return nil
+}
+
+func (b *memDbBatch) Discard() {
+	b.ops = nil
+}
+
 func (m *MemDb) Put(id DbKey, aliases []DbKey, value []byte) error {
 	m.Lock()
 	defer m.Unlock()
@@ -209,6 +209,9 @@ type MerkleClient struct {
 	// The first node we saw that has skip pointers; not used in production
 	firstSkip *keybase1.Seqno
 
+	// Coalesces concurrent merkle/path fetches for the same query
+	pathCoalescer *merkleCoalescer
+
 	// protects whole object
 	sync.RWMutex
 }
@@ -396,10 +399,11 @@ func GetNodeHashVoid(w *jsonw.Wrapper, nhp *NodeHash, errp *error) {
 
 func NewMerkleClient(g *GlobalContext) *MerkleClient {
 	return &MerkleClient{
-		keyring:      NewSpecialKeyRing(g.Env.GetMerkleKIDs(), g),
-		verified:     make(map[keybase1.Seqno]bool),
-		lastRoot:     nil,
-		Contextified: NewContextified(g),
+		keyring:       NewSpecialKeyRing(g.Env.GetMerkleKIDs(), g),
+		verified:      make(map[keybase1.Seqno]bool),
+		lastRoot:      nil,
+		pathCoalescer: newMerkleCoalescer(g),
+		Contextified:  NewContextified(g),
 	}
 }
 
@@ -696,12 +700,21 @@ func (mc *MerkleClient) lookupPathAndSkipSequenceHelper(m MetaContext, q HTTPArg
 		q.Add("last", I{int(*lastSeqno)})
 	}
 
-	apiRes, err = m.G().API.Get(APIArg{
-		Endpoint:       "merkle/path",
-		SessionType:    APISessionTypeNONE,
-		Args:           q,
-		AppStatusCodes: []int{SCOk, SCNotFound, SCDeleted},
-		MetaContext:    m,
+	// Lookups pinned to a hash_meta are immutable, so they can be
+	// served from the disk cache across processes and restarts.
+	_, pinnedToHash := q["start_hash_meta"]
+
+	// Simultaneous lookups for the same query (concurrent identifies of
+	// one user, typically) share a single network request.
+	apiRes, err = mc.pathCoalescer.Get(m, q.EncodeToString(), func() (*APIRes, error) {
+		return m.G().API.Get(APIArg{
+			Endpoint:       "merkle/path",
+			SessionType:    APISessionTypeNONE,
+			Args:           q,
+			AppStatusCodes: []int{SCOk, SCNotFound, SCDeleted},
+			UseDiskCache:   pinnedToHash,
+			MetaContext:    m,
+		})
 	})
 
 	if err != nil {
@@ -0,0 +1,108 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"sync"
+	"time"
+)
+
+// merklePathCacheTTL is how long a completed merkle/path response is
+// served to followers after the original request finishes. It should be
+// short; concurrent identifies of the same user land within this window,
+// but we don't want to hide fresh sigchain updates for much longer.
+const merklePathCacheTTL = 3 * time.Second
+
+// merkleFlight is one in-flight (or recently completed) merkle/path
+// fetch. Followers block on done, then read res/err.
+type merkleFlight struct {
+	done chan struct{}
+	res  *APIRes
+	err  error
+
+	// completedAt is zero while the fetch is still in flight.
+	completedAt time.Time
+}
+
+// merkleCoalescer deduplicates concurrent merkle/path requests for the
+// same query, and serves the response from a small TTL cache for a few
+// seconds afterwards. Concurrent identifies of the same user otherwise
+// issue duplicate network requests for identical paths.
+type merkleCoalescer struct {
+	Contextified
+	sync.Mutex
+	flights map[string]*merkleFlight
+}
+
+func newMerkleCoalescer(g *GlobalContext) *merkleCoalescer {
+	return &merkleCoalescer{
+		Contextified: NewContextified(g),
+		flights:      make(map[string]*merkleFlight),
+	}
+}
+
+// Get returns the APIRes for the given query, either by joining an
+// in-flight request, serving a recently completed one, or calling fetch.
+// Errors are shared with concurrent followers but never cached beyond
+// the lifetime of the flight that produced them.
+func (mc *merkleCoalescer) Get(m MetaContext, key string, fetch func() (*APIRes, error)) (*APIRes, error) {
+	mc.Lock()
+	mc.pruneLocked()
+	f, found := mc.flights[key]
+	if found {
+		mc.Unlock()
+		<-f.done
+		mc.Lock()
+		// A failed flight is evicted as soon as it lands; share the
+		// error with followers but don't cache it for later callers.
+		if f.err != nil && mc.flights[key] == f {
+			delete(mc.flights, key)
+		}
+		mc.Unlock()
+		if f.err != nil {
+			return nil, f.err
+		}
+		m.VLogf(VLog1, "| merkleCoalescer: joined flight for %s", key)
+		return f.res, nil
+	}
+	f = &merkleFlight{done: make(chan struct{})}
+	mc.flights[key] = f
+	mc.Unlock()
+
+	f.res, f.err = fetch()
+	f.completedAt = mc.G().Clock().Now()
+	close(f.done)
+
+	if f.err != nil {
+		mc.Lock()
+		if mc.flights[key] == f {
+			delete(mc.flights, key)
+		}
+		mc.Unlock()
+	}
+	return f.res, f.err
+}
+
+// pruneLocked evicts all completed flights whose TTL has lapsed, so the
+// map doesn't accumulate entries for queries that never repeat (the key
+// embeds the caller's last root seqno, which advances constantly).
+// Callers must hold the lock.
+func (mc *merkleCoalescer) pruneLocked() {
+	for key, f := range mc.flights {
+		if mc.expired(f) {
+			delete(mc.flights, key)
+		}
+	}
+}
+
+// expired is true for a completed flight whose TTL has lapsed. Callers
+// must hold the lock. In-flight requests never expire.
+func (mc *merkleCoalescer) expired(f *merkleFlight) bool {
+	select {
+	case <-f.done:
+	default:
+		return false
+	}
+	return mc.G().Clock().Now().Sub(f.completedAt) > merklePathCacheTTL
+}
@@ -0,0 +1,95 @@
+package libkb
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/keybase/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleCoalescerSharesFlights(t *testing.T) {
+	tc := SetupTest(t, "merkle_coalescer", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+
+	mc := newMerkleCoalescer(tc.G)
+	var fetches int32
+	release := make(chan struct{})
+	res := &APIRes{}
+
+	fetch := func() (*APIRes, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return res, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := mc.Get(m, "uid=deadbeef19", fetch)
+			require.NoError(t, err)
+			require.Equal(t, res, got)
+		}()
+	}
+
+	// Wait for at least one fetch to start, then let it finish.
+	for atomic.LoadInt32(&fetches) == 0 {
+	}
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// Within the TTL, a later call is served from cache.
+	got, err := mc.Get(m, "uid=deadbeef19", func() (*APIRes, error) {
+		t.Fatal("fetch called for cached flight")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, res, got)
+
+	// A different key misses.
+	release2 := make(chan struct{})
+	close(release2)
+	_, err = mc.Get(m, "uid=deadbeef20", func() (*APIRes, error) {
+		return res, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestMerkleCoalescerExpiry(t *testing.T) {
+	tc := SetupTest(t, "merkle_coalescer", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+	fakeClock := clockwork.NewFakeClockAt(time.Now())
+	tc.G.SetClock(fakeClock)
+
+	mc := newMerkleCoalescer(tc.G)
+	var fetches int
+	fetch := func() (*APIRes, error) {
+		fetches++
+		return &APIRes{}, nil
+	}
+
+	_, err := mc.Get(m, "k", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 1, fetches)
+
+	// Still cached just inside the TTL.
+	fakeClock.Advance(merklePathCacheTTL)
+	_, err = mc.Get(m, "k", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 1, fetches)
+
+	// Expired beyond the TTL.
+	fakeClock.Advance(time.Millisecond)
+	_, err = mc.Get(m, "k", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 2, fetches)
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MetricsRegistry is a small process-wide registry of counters and
+// gauges for the optional Prometheus endpoint. Subsystems record into
+// it unconditionally (the cost is a mutex and a map write); it only
+// turns into network traffic if service.metrics_bind is configured.
+type MetricsRegistry struct {
+	sync.Mutex
+	counters   map[string]float64
+	gauges     map[string]float64
+	gaugeFuncs map[string]func() float64
+}
+
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		counters:   make(map[string]float64),
+		gauges:     make(map[string]float64),
+		gaugeFuncs: make(map[string]func() float64),
+	}
+}
+
+// IncCounter adds delta to a monotonically increasing counter.
+func (r *MetricsRegistry) IncCounter(name string, delta float64) {
+	r.Lock()
+	defer r.Unlock()
+	r.counters[name] += delta
+}
+
+// ObserveDuration folds one timed operation into name_count and
+// name_seconds_sum, the cheap two-series summary Prometheus rates
+// well.
+func (r *MetricsRegistry) ObserveDuration(name string, d time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	r.counters[name+"_count"]++
+	r.counters[name+"_seconds_sum"] += d.Seconds()
+}
+
+// SetGauge records the current value of a gauge.
+func (r *MetricsRegistry) SetGauge(name string, value float64) {
+	r.Lock()
+	defer r.Unlock()
+	r.gauges[name] = value
+}
+
+// RegisterGaugeFunc registers a callback evaluated at scrape time.
+// Re-registering a name replaces the callback.
+func (r *MetricsRegistry) RegisterGaugeFunc(name string, fn func() float64) {
+	r.Lock()
+	defer r.Unlock()
+	r.gaugeFuncs[name] = fn
+}
+
+type MetricSample struct {
+	Name  string
+	Value float64
+	Gauge bool
+}
+
+// Snapshot returns all series, sorted by name. Gauge callbacks run
+// outside the registry lock so they may themselves record metrics.
+func (r *MetricsRegistry) Snapshot() []MetricSample {
+	r.Lock()
+	res := make([]MetricSample, 0, len(r.counters)+len(r.gauges)+len(r.gaugeFuncs))
+	for name, v := range r.counters {
+		res = append(res, MetricSample{Name: name, Value: v})
+	}
+	for name, v := range r.gauges {
+		res = append(res, MetricSample{Name: name, Value: v, Gauge: true})
+	}
+	gauges := make(map[string]func() float64, len(r.gaugeFuncs))
+	for name, fn := range r.gaugeFuncs {
+		gauges[name] = fn
+	}
+	r.Unlock()
+
+	for name, fn := range gauges {
+		res = append(res, MetricSample{Name: name, Value: fn(), Gauge: true})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+)
+
+// Opt-in m-of-n device approval for high-value sigchain links. With
+// account.require_multi_device_approval set, sibkey delegations and
+// per-user-key rotations refuse to post until a second device has
+// approved the operation class, so a single compromised device can't
+// silently add keys. Approvals are short-lived tokens recorded through
+// the account.approveHighLink RPC; the KEX channel (or gregor, for
+// remote devices) is the intended transport for ferrying the approval
+// from the second device, and plugs in at that RPC.
+
+// highLinkApprovalTTL bounds how long an approval stays usable. It
+// covers one interactive operation, not a standing grant.
+const highLinkApprovalTTL = 10 * 60 // seconds
+
+type highLinkApproval struct {
+	Operation string `json:"operation"`
+	GrantedAt int64  `json:"granted_at"`
+}
+
+func highLinkApprovalDbKey(operation string) DbKey {
+	return DbKey{Typ: DBHighLinkApproval, Key: operation}
+}
+
+// Operation classes covered by multi-device approval.
+const (
+	HighLinkOperationSibkey     = string(DelegationTypeSibkey)
+	HighLinkOperationPerUserKey = "per_user_key"
+)
+
+// HighLinkApprovalRequired reports whether the given operation needs a
+// second device's approval before posting: the mode is on, the link is
+// a high one, and the user actually has another active device that
+// could approve.
+func HighLinkApprovalRequired(m MetaContext, operation string) bool {
+	if operation != HighLinkOperationSibkey && operation != HighLinkOperationPerUserKey {
+		return false
+	}
+	required := m.G().Env.GetBool(false,
+		func() (bool, bool) { return m.G().Env.getEnvBool("KEYBASE_REQUIRE_MULTI_DEVICE_APPROVAL") },
+		func() (bool, bool) {
+			return m.G().Env.GetConfig().GetBoolAtPath("account.require_multi_device_approval")
+		},
+	)
+	if !required {
+		return false
+	}
+
+	// With fewer than two devices there is nobody to ask; don't brick
+	// the account.
+	uid := m.G().GetMyUID()
+	if uid.IsNil() {
+		return false
+	}
+	upak, _, err := m.G().GetUPAKLoader().LoadV2(NewLoadUserArgWithMetaContext(m).WithUID(uid).WithPublicKeyOptional())
+	if err != nil {
+		m.CDebugf("HighLinkApprovalRequired: self load failed: %s; requiring approval anyway", err)
+		return true
+	}
+	active := 0
+	for _, key := range upak.Current.DeviceKeys {
+		if key.Base.Revocation == nil && key.Base.IsSibkey {
+			active++
+		}
+	}
+	return active >= 2
+}
+
+// RecordHighLinkApproval stores a fresh approval token for one
+// operation class ("sibkey" or "per_user_key").
+func RecordHighLinkApproval(m MetaContext, operation string) error {
+	approval := highLinkApproval{
+		Operation: operation,
+		GrantedAt: m.G().Clock().Now().Unix(),
+	}
+	return m.G().LocalDb.PutObj(highLinkApprovalDbKey(operation), nil, approval)
+}
+
+// ConsumeHighLinkApproval checks for a fresh approval and burns it.
+// It returns an error describing how to approve if none exists.
+func ConsumeHighLinkApproval(m MetaContext, operation string) error {
+	var approval highLinkApproval
+	found, err := m.G().LocalDb.GetInto(&approval, highLinkApprovalDbKey(operation))
+	if err != nil {
+		return err
+	}
+	fresh := found && m.G().Clock().Now().Unix()-approval.GrantedAt <= highLinkApprovalTTL
+	if !fresh {
+		return DelegationApprovalError{Operation: operation}
+	}
+	// One approval covers one operation.
+	return m.G().LocalDb.Delete(highLinkApprovalDbKey(operation))
+}
+
+// DelegationApprovalError is returned when a high link needs a second
+// device's sign-off that hasn't arrived.
+type DelegationApprovalError struct {
+	Operation string
+}
+
+func (e DelegationApprovalError) Error() string {
+	return fmt.Sprintf("the %q operation requires approval from a second device (multi-device approval mode is on)", e.Operation)
+}
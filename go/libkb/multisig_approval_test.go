@@ -0,0 +1,40 @@
+package libkb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keybase/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighLinkApprovals(t *testing.T) {
+	tc := SetupTest(t, "multisig_approval", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+	fakeClock := clockwork.NewFakeClockAt(time.Now())
+	tc.G.SetClock(fakeClock)
+
+	// No approval recorded: consumption fails with the guidance error.
+	err := ConsumeHighLinkApproval(m, HighLinkOperationSibkey)
+	require.Error(t, err)
+	require.IsType(t, DelegationApprovalError{}, err)
+
+	// A recorded approval is consumable exactly once.
+	require.NoError(t, RecordHighLinkApproval(m, HighLinkOperationSibkey))
+	require.NoError(t, ConsumeHighLinkApproval(m, HighLinkOperationSibkey))
+	require.Error(t, ConsumeHighLinkApproval(m, HighLinkOperationSibkey))
+
+	// Approvals don't cross operation classes.
+	require.NoError(t, RecordHighLinkApproval(m, HighLinkOperationPerUserKey))
+	require.Error(t, ConsumeHighLinkApproval(m, HighLinkOperationSibkey))
+
+	// Stale approvals are refused.
+	require.NoError(t, RecordHighLinkApproval(m, HighLinkOperationSibkey))
+	fakeClock.Advance(highLinkApprovalTTL*time.Second + time.Minute)
+	require.Error(t, ConsumeHighLinkApproval(m, HighLinkOperationSibkey))
+
+	// The mode is off by default, so nothing requires approval.
+	require.False(t, HighLinkApprovalRequired(m, HighLinkOperationSibkey))
+	require.False(t, HighLinkApprovalRequired(m, "subkey"))
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// NetworkStatsRecorder accumulates per-endpoint request, byte and
+// latency counts for the API client, building on the WithTimeBuckets
+// instrumentation already present in doRequestShared. It answers the
+// question "which subsystem is eating my data plan": sigchain loads,
+// chat, and KBFS each talk to distinct endpoints.
+type NetworkStatsRecorder struct {
+	sync.Mutex
+	stats map[string]*endpointStats
+}
+
+type endpointStats struct {
+	count   int
+	bytesIn int64
+	total   time.Duration
+}
+
+func NewNetworkStatsRecorder() *NetworkStatsRecorder {
+	return &NetworkStatsRecorder{stats: make(map[string]*endpointStats)}
+}
+
+// Record notes one completed request. bytesIn is the size of the
+// response body as read (only JSON replies are counted; streaming
+// responses report 0).
+func (r *NetworkStatsRecorder) Record(endpoint string, bytesIn int64, dur time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	s, found := r.stats[endpoint]
+	if !found {
+		s = &endpointStats{}
+		r.stats[endpoint] = s
+	}
+	s.count++
+	s.bytesIn += bytesIn
+	s.total += dur
+}
+
+// Report returns the cumulative per-endpoint counters for this process,
+// sorted by bytes received, largest consumers first.
+func (r *NetworkStatsRecorder) Report() []keybase1.NetworkStatsEntry {
+	r.Lock()
+	defer r.Unlock()
+	ret := make([]keybase1.NetworkStatsEntry, 0, len(r.stats))
+	for endpoint, s := range r.stats {
+		ret = append(ret, keybase1.NetworkStatsEntry{
+			Endpoint: endpoint,
+			Count:    s.count,
+			BytesIn:  s.bytesIn,
+			TotalMs:  int64(s.total / time.Millisecond),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].BytesIn != ret[j].BytesIn {
+			return ret[i].BytesIn > ret[j].BytesIn
+		}
+		return ret[i].Endpoint < ret[j].Endpoint
+	})
+	return ret
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keybase/go-crypto/openpgp"
+	"github.com/keybase/go-crypto/openpgp/packet"
+)
+
+// Subkey management for PGP keys held in the keybase keyring. The
+// engines could long generate and import whole keys but not touch the
+// subkeys of an existing one; these helpers add a fresh encryption
+// subkey or revoke an old one, re-signing the bundle with the primary
+// key. Callers push the updated bundle with a pgp_update chain link
+// (see PGPUpdateEngine).
+
+const pgpSubkeyDefaultRSABits = 2048
+
+// AddEncryptionSubkey generates a new RSA encryption subkey, binds it
+// to the primary key, and re-signs the bundle. The bundle must hold an
+// unlocked private key.
+func (p *PGPKeyBundle) AddEncryptionSubkey() error {
+	if p.PrivateKey == nil || p.PrivateKey.Encrypted {
+		return fmt.Errorf("adding a subkey requires the unlocked private key")
+	}
+
+	currentTime := time.Now()
+	encryptingPriv, err := rsa.GenerateKey(rand.Reader, pgpSubkeyDefaultRSABits)
+	if err != nil {
+		return err
+	}
+
+	subkey := openpgp.Subkey{
+		PublicKey:  packet.NewRSAPublicKey(currentTime, &encryptingPriv.PublicKey),
+		PrivateKey: packet.NewRSAPrivateKey(currentTime, encryptingPriv),
+		Sig: &packet.Signature{
+			CreationTime:              currentTime,
+			SigType:                   packet.SigTypeSubkeyBinding,
+			PubKeyAlgo:                p.PrimaryKey.PubKeyAlgo,
+			Hash:                      crypto.SHA256,
+			FlagsValid:                true,
+			FlagEncryptStorage:        true,
+			FlagEncryptCommunications: true,
+			IssuerKeyId:               &p.PrimaryKey.KeyId,
+		},
+	}
+	subkey.PublicKey.IsSubkey = true
+	subkey.PrivateKey.IsSubkey = true
+
+	// Sign just the new binding; re-serializing the whole entity would
+	// regenerate existing signatures, which isn't safe on parsed keys.
+	if err := subkey.Sig.SignKey(subkey.PublicKey, p.PrivateKey, nil); err != nil {
+		return err
+	}
+	p.Subkeys = append(p.Subkeys, subkey)
+	return nil
+}
+
+// RevokeSubkey attaches a revocation signature to the subkey whose
+// key ID ends with the given hex suffix.
+func (p *PGPKeyBundle) RevokeSubkey(keyIDSuffix string) error {
+	if p.PrivateKey == nil || p.PrivateKey.Encrypted {
+		return fmt.Errorf("revoking a subkey requires the unlocked private key")
+	}
+
+	idx := -1
+	suffix := strings.ToLower(keyIDSuffix)
+	for i, subkey := range p.Subkeys {
+		if strings.HasSuffix(strings.ToLower(subkey.PublicKey.KeyIdString()), suffix) {
+			if idx >= 0 {
+				return fmt.Errorf("key ID suffix %q is ambiguous", keyIDSuffix)
+			}
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("no subkey matches key ID suffix %q", keyIDSuffix)
+	}
+
+	sig := &packet.Signature{
+		CreationTime: time.Now(),
+		SigType:      packet.SigTypeSubkeyRevocation,
+		PubKeyAlgo:   p.PrimaryKey.PubKeyAlgo,
+		Hash:         crypto.SHA256,
+		IssuerKeyId:  &p.PrimaryKey.KeyId,
+	}
+	if err := sig.SignKey(p.Subkeys[idx].PublicKey, p.PrivateKey, nil); err != nil {
+		return err
+	}
+	p.Subkeys[idx].Revocation = sig
+	return nil
+}
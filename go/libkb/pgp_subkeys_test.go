@@ -0,0 +1,49 @@
+package libkb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func genTestPGPBundle(t *testing.T, tc TestContext) *PGPKeyBundle {
+	arg := PGPGenArg{PrimaryBits: 1024, SubkeyBits: 1024}
+	arg.AddDefaultUID(tc.G)
+	bundle, err := GeneratePGPKeyBundle(tc.G, arg, tc.G.UI.GetLogUI())
+	require.NoError(t, err)
+	return bundle
+}
+
+func TestPGPAddEncryptionSubkey(t *testing.T) {
+	tc := SetupTest(t, "pgp_subkeys", 1)
+	defer tc.Cleanup()
+
+	bundle := genTestPGPBundle(t, tc)
+	before := len(bundle.Subkeys)
+
+	require.NoError(t, bundle.AddEncryptionSubkey())
+	require.Len(t, bundle.Subkeys, before+1)
+
+	added := bundle.Subkeys[len(bundle.Subkeys)-1]
+	require.True(t, added.PublicKey.IsSubkey)
+	require.True(t, added.Sig.FlagEncryptCommunications)
+
+	// The binding signature must verify against the primary key.
+	require.NoError(t, bundle.PrimaryKey.VerifyKeySignature(added.PublicKey, added.Sig))
+}
+
+func TestPGPRevokeSubkey(t *testing.T) {
+	tc := SetupTest(t, "pgp_subkeys", 1)
+	defer tc.Cleanup()
+
+	bundle := genTestPGPBundle(t, tc)
+	require.NotEmpty(t, bundle.Subkeys)
+	target := bundle.Subkeys[0].PublicKey.KeyIdString()
+
+	require.NoError(t, bundle.RevokeSubkey(target[len(target)-8:]))
+	require.NotNil(t, bundle.Subkeys[0].Revocation)
+	require.NoError(t, bundle.PrimaryKey.VerifyKeySignature(bundle.Subkeys[0].PublicKey, bundle.Subkeys[0].Revocation))
+
+	// Unknown suffixes are refused.
+	require.Error(t, bundle.RevokeSubkey("zzzzzzzz"))
+}
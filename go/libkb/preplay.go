@@ -0,0 +1,106 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	jsonw "github.com/keybase/go-jsonw"
+)
+
+// Pre-play validation for prospective sigchain links. Engines used to
+// sign and post, and only learn about semantic mistakes — wrong prev,
+// stale seqno, revoking a key that isn't there — from the server's
+// rejection. These checks run the proposed payload against the local
+// replayed chain state first, so callers get a precise local error
+// before anything is signed or posted.
+
+// PrePlayCheckProspectiveLink validates the proposed (not yet signed)
+// payload against me's current chain and computed key family.
+func PrePlayCheckProspectiveLink(m MetaContext, me *User, payload *jsonw.Wrapper) (err error) {
+	defer m.CTrace("PrePlayCheckProspectiveLink", func() error { return err })()
+
+	if me == nil || payload == nil {
+		return nil
+	}
+
+	if err := prePlayCheckPrev(me, payload); err != nil {
+		return err
+	}
+	return prePlayCheckRevokes(me, payload)
+}
+
+func prePlayCheckPrev(me *User, payload *jsonw.Wrapper) error {
+	lastSeqno := me.GetSigChainLastKnownSeqno()
+	lastLink := me.GetSigChainLastKnownID()
+
+	seqno, err := payload.AtKey("seqno").GetInt64()
+	if err != nil {
+		return fmt.Errorf("pre-play: proposed link has no seqno: %s", err)
+	}
+	if keybase1.Seqno(seqno) != lastSeqno+1 {
+		return fmt.Errorf("pre-play: proposed seqno %d doesn't extend local chain tail %d", seqno, lastSeqno)
+	}
+
+	prevRaw := payload.AtKey("prev")
+	if prevRaw.IsNil() {
+		if lastSeqno != 0 {
+			return fmt.Errorf("pre-play: proposed link has null prev but chain is at seqno %d", lastSeqno)
+		}
+		return nil
+	}
+	prev, err := prevRaw.GetString()
+	if err != nil {
+		return fmt.Errorf("pre-play: bad prev in proposed link: %s", err)
+	}
+	if lastLink == nil {
+		return fmt.Errorf("pre-play: proposed link has prev %s but local chain is empty", prev)
+	}
+	if prev != lastLink.String() {
+		return fmt.Errorf("pre-play: prev %s doesn't match local chain tail %s", prev, lastLink)
+	}
+	return nil
+}
+
+// prePlayCheckRevokes verifies that every key the proposed link revokes
+// is currently active in the computed key family.
+func prePlayCheckRevokes(me *User, payload *jsonw.Wrapper) error {
+	revoke := payload.AtPath("body.revoke")
+	if revoke.IsNil() {
+		return nil
+	}
+
+	var kids []keybase1.KID
+	if kid, err := revoke.AtKey("kid").GetString(); err == nil && kid != "" {
+		kids = append(kids, keybase1.KIDFromString(kid))
+	}
+	if arr := revoke.AtKey("kids"); !arr.IsNil() {
+		n, err := arr.Len()
+		if err == nil {
+			for i := 0; i < n; i++ {
+				if kid, err := arr.AtIndex(i).GetString(); err == nil && kid != "" {
+					kids = append(kids, keybase1.KIDFromString(kid))
+				}
+			}
+		}
+	}
+	if len(kids) == 0 {
+		return nil
+	}
+
+	ckf := me.GetComputedKeyFamily()
+	if ckf == nil {
+		return fmt.Errorf("pre-play: no computed key family to check revocations against")
+	}
+	for _, kid := range kids {
+		if _, err := ckf.FindKeyWithKIDUnsafe(kid); err != nil {
+			return fmt.Errorf("pre-play: proposed link revokes unknown key %s", kid)
+		}
+		if _, err := ckf.getCkiIfActiveNow(kid); err != nil {
+			return fmt.Errorf("pre-play: proposed link revokes inactive key %s: %s", kid, err)
+		}
+	}
+	return nil
+}
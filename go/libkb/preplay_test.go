@@ -0,0 +1,49 @@
+package libkb
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	jsonw "github.com/keybase/go-jsonw"
+	"github.com/stretchr/testify/require"
+)
+
+func prePlayPayload(t *testing.T, raw string) *jsonw.Wrapper {
+	jw, err := jsonw.Unmarshal([]byte(raw))
+	require.NoError(t, err)
+	return jw
+}
+
+func TestPrePlayCheckPrev(t *testing.T) {
+	// A user with no local chain: only a first link (seqno 1, null
+	// prev) extends it.
+	me := NewUserThin("t_alice", keybase1.UID("295a7eea607af32040647123732bc819"))
+
+	err := prePlayCheckPrev(me, prePlayPayload(t, `{"seqno": 1, "prev": null}`))
+	require.NoError(t, err)
+
+	err = prePlayCheckPrev(me, prePlayPayload(t, `{"seqno": 5, "prev": null}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "doesn't extend local chain tail")
+
+	err = prePlayCheckPrev(me, prePlayPayload(t, `{"seqno": 1, "prev": "deadbeef"}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "local chain is empty")
+
+	err = prePlayCheckPrev(me, prePlayPayload(t, `{"prev": null}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no seqno")
+}
+
+func TestPrePlayCheckRevokes(t *testing.T) {
+	me := NewUserThin("t_alice", keybase1.UID("295a7eea607af32040647123732bc819"))
+
+	// Links that revoke nothing pass without a key family.
+	require.NoError(t, prePlayCheckRevokes(me, prePlayPayload(t, `{"body": {"type": "sibkey"}}`)))
+
+	// Revoking with no computed key family is caught.
+	err := prePlayCheckRevokes(me, prePlayPayload(t,
+		`{"body": {"revoke": {"kid": "0120deadbeef0a"}}}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no computed key family")
+}
@@ -0,0 +1,107 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	context "golang.org/x/net/context"
+)
+
+// External proof checkers. A proof type can be validated by a helper
+// executable declared in config:
+//
+//   "proof_checkers": { "ldap": "/usr/local/bin/kb-check-ldap" }
+//
+// The helper receives the proof hint as JSON on stdin and prints a
+// structured verdict on stdout, letting organizations wire up internal
+// proof types (LDAP, internal wikis) without forking the built-in
+// checker table. A configured helper takes precedence over any built-in
+// checker for that type.
+
+// externalProofRequest is what the helper reads on stdin.
+type externalProofRequest struct {
+	Service    string `json:"service"`
+	Username   string `json:"username"`
+	APIURL     string `json:"api_url"`
+	HumanURL   string `json:"human_url"`
+	CheckText  string `json:"check_text"`
+	TorEnabled bool   `json:"tor_enabled"`
+}
+
+// externalProofVerdict is what the helper must print on stdout.
+type externalProofVerdict struct {
+	Status  string `json:"status"` // "ok" or "failed"
+	Message string `json:"message"`
+}
+
+const externalProofCheckerTimeout = 30 * time.Second
+
+// externalProofCheckerPath returns the helper configured for a proof
+// type, or "".
+func externalProofCheckerPath(g *GlobalContext, proofType string) string {
+	path, _ := g.Env.GetConfig().GetStringAtPath("proof_checkers." + proofType)
+	return path
+}
+
+// ExternalProofChecker shells out to the configured helper.
+type ExternalProofChecker struct {
+	Contextified
+	path string
+	link RemoteProofChainLink
+}
+
+var _ ProofChecker = (*ExternalProofChecker)(nil)
+
+func NewExternalProofChecker(g *GlobalContext, path string, l RemoteProofChainLink) *ExternalProofChecker {
+	return &ExternalProofChecker{
+		Contextified: NewContextified(g),
+		path:         path,
+		link:         l,
+	}
+}
+
+func (c *ExternalProofChecker) CheckStatus(m MetaContext, h SigHint, pcm ProofCheckerMode, pvlU PvlUnparsed) ProofError {
+	_, username := c.link.ToKeyValuePair()
+	req := externalProofRequest{
+		Service:    c.link.TableKey(),
+		Username:   username,
+		APIURL:     h.apiURL,
+		HumanURL:   h.humanURL,
+		CheckText:  h.checkText,
+		TorEnabled: m.G().Env.GetTorMode().Enabled(),
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return NewProofError(keybase1.ProofStatus_INTERNAL_ERROR, "bad external checker request: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(m.Ctx(), externalProofCheckerTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.path)
+	cmd.Stdin = bytes.NewReader(input)
+	out, err := cmd.Output()
+	if err != nil {
+		return NewProofError(keybase1.ProofStatus_INTERNAL_ERROR,
+			"external checker %s failed: %s", c.path, err)
+	}
+
+	var verdict externalProofVerdict
+	if err := json.Unmarshal(out, &verdict); err != nil {
+		return NewProofError(keybase1.ProofStatus_INTERNAL_ERROR,
+			"external checker %s printed a bad verdict: %s", c.path, err)
+	}
+	if verdict.Status != "ok" {
+		return NewProofError(keybase1.ProofStatus_CONTENT_FAILURE,
+			"external checker rejected proof: %s", verdict.Message)
+	}
+	return nil
+}
+
+func (c *ExternalProofChecker) GetTorError() ProofError { return nil }
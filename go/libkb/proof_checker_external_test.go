@@ -0,0 +1,52 @@
+package libkb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCheckerScript(t *testing.T, dir, body string) string {
+	path := filepath.Join(dir, "checker.sh")
+	require.NoError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0700))
+	return path
+}
+
+func TestExternalProofChecker(t *testing.T) {
+	tc := SetupTest(t, "external_proof_checker", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+
+	dir, err := ioutil.TempDir("", "ext-checker")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	link := &SocialProofChainLink{service: "ldap", username: "alice"}
+	hint := SigHint{apiURL: "https://wiki.internal/proof"}
+
+	// Helper approves: proof passes.
+	ok := writeCheckerScript(t, dir, `cat > /dev/null; echo '{"status": "ok"}'`)
+	perr := NewExternalProofChecker(tc.G, ok, link).CheckStatus(m, hint, ProofCheckerModeActive, PvlUnparsed{})
+	require.Nil(t, perr)
+
+	// Helper rejects with a message: surfaced as a content failure.
+	rej := writeCheckerScript(t, dir, `cat > /dev/null; echo '{"status": "failed", "message": "no such ldap user"}'`)
+	perr = NewExternalProofChecker(tc.G, rej, link).CheckStatus(m, hint, ProofCheckerModeActive, PvlUnparsed{})
+	require.NotNil(t, perr)
+	require.Equal(t, keybase1.ProofStatus_CONTENT_FAILURE, perr.GetProofStatus())
+	require.Contains(t, perr.GetDesc(), "no such ldap user")
+
+	// Garbage output is an internal error, not a silent pass.
+	bad := writeCheckerScript(t, dir, `cat > /dev/null; echo 'not json'`)
+	perr = NewExternalProofChecker(tc.G, bad, link).CheckStatus(m, hint, ProofCheckerModeActive, PvlUnparsed{})
+	require.NotNil(t, perr)
+	require.Equal(t, keybase1.ProofStatus_INTERNAL_ERROR, perr.GetProofStatus())
+
+	// Missing helper is an internal error too.
+	perr = NewExternalProofChecker(tc.G, filepath.Join(dir, "nope"), link).CheckStatus(m, hint, ProofCheckerModeActive, PvlUnparsed{})
+	require.NotNil(t, perr)
+}
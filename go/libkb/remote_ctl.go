@@ -0,0 +1,149 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/keybase/client/go/logger"
+)
+
+// Remote headless control: the service can listen on TCP with TLS and
+// client-certificate mutual authentication, as a supported alternative
+// to hand-forwarding the unix socket. Key material lives under
+// <configdir>/remote-ctl/, generated by `keybase ctl remote-keygen`.
+
+// RemoteCtlServerName is the SNI/SAN name both sides pin, so the
+// certs work regardless of which host or IP carries the connection.
+const RemoteCtlServerName = "keybase-remote-ctl"
+
+const (
+	RemoteCtlCAFile         = "ca.pem"
+	RemoteCtlCAKeyFile      = "ca-key.pem"
+	RemoteCtlServerCertFile = "server.pem"
+	RemoteCtlServerKeyFile  = "server-key.pem"
+	RemoteCtlClientCertFile = "client.pem"
+	RemoteCtlClientKeyFile  = "client-key.pem"
+)
+
+// GetRemoteCtlBind returns the TCP address the service should listen
+// on for mutually-authenticated remote control, or "" for disabled.
+func (e *Env) GetRemoteCtlBind() string {
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_REMOTE_CTL_BIND") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("service.remote_ctl_bind")
+			return s
+		},
+	)
+}
+
+// GetRemoteCtlConnect returns the remote service address a client
+// should dial instead of the local unix socket, or "" for local.
+func (e *Env) GetRemoteCtlConnect() string {
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_REMOTE_CTL_CONNECT") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("service.remote_ctl_connect")
+			return s
+		},
+	)
+}
+
+// RemoteCtlDir returns the directory holding remote-control key
+// material.
+func RemoteCtlDir(g *GlobalContext) string {
+	return filepath.Join(g.Env.GetConfigDir(), "remote-ctl")
+}
+
+func remoteCtlCAPool(dir string) (*x509.CertPool, error) {
+	caPem, err := ioutil.ReadFile(filepath.Join(dir, RemoteCtlCAFile))
+	if err != nil {
+		return nil, fmt.Errorf("remote-ctl CA unavailable (run `keybase ctl remote-keygen`): %s", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		return nil, fmt.Errorf("remote-ctl CA file %s contains no usable certificates", RemoteCtlCAFile)
+	}
+	return pool, nil
+}
+
+// RemoteCtlServerTLSConfig builds the service-side listener config:
+// our server cert, and clients must present a cert signed by our CA.
+func RemoteCtlServerTLSConfig(g *GlobalContext) (*tls.Config, error) {
+	dir := RemoteCtlDir(g)
+	cert, err := tls.LoadX509KeyPair(
+		filepath.Join(dir, RemoteCtlServerCertFile),
+		filepath.Join(dir, RemoteCtlServerKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("remote-ctl server keypair unavailable (run `keybase ctl remote-keygen`): %s", err)
+	}
+	pool, err := remoteCtlCAPool(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// RemoteCtlClientTLSConfig builds the dialing-side config: our client
+// cert, verifying the server against the shared CA and pinned name.
+func RemoteCtlClientTLSConfig(g *GlobalContext) (*tls.Config, error) {
+	dir := RemoteCtlDir(g)
+	cert, err := tls.LoadX509KeyPair(
+		filepath.Join(dir, RemoteCtlClientCertFile),
+		filepath.Join(dir, RemoteCtlClientKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("remote-ctl client keypair unavailable (copy it from the service machine): %s", err)
+	}
+	pool, err := remoteCtlCAPool(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   RemoteCtlServerName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// RemoteCtlSocket is a Socket that dials a remote service over
+// mutually-authenticated TLS instead of the local unix socket.
+type RemoteCtlSocket struct {
+	log  logger.Logger
+	g    *GlobalContext
+	addr string
+}
+
+func NewRemoteCtlSocket(g *GlobalContext, addr string) RemoteCtlSocket {
+	log := g.Log
+	if log == nil {
+		log = logger.NewNull()
+	}
+	return RemoteCtlSocket{log: log, g: g, addr: addr}
+}
+
+func (s RemoteCtlSocket) BindToSocket() (net.Listener, error) {
+	return nil, fmt.Errorf("cannot bind a remote-ctl socket; the service listens via service.remote_ctl_bind")
+}
+
+func (s RemoteCtlSocket) DialSocket() (net.Conn, error) {
+	cfg, err := RemoteCtlClientTLSConfig(s.g)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Debug("| tls.Dial(tcp:%s)", s.addr)
+	return tls.Dial("tcp", s.addr, cfg)
+}
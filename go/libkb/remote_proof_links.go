@@ -79,8 +79,22 @@ func (r *RemoteProofLinks) Active() []RemoteProofChainLink {
 // TrackingStatement generates the remote proofs portions of the
 // tracking statement from the active proofs.
 func (r *RemoteProofLinks) TrackingStatement() *jsonw.Wrapper {
+	return r.TrackingStatementExcluding(nil)
+}
+
+// TrackingStatementExcluding is TrackingStatement with selected proof
+// types (by table key: service name or protocol) left out, for
+// selective tracking.
+func (r *RemoteProofLinks) TrackingStatementExcluding(excluded []string) *jsonw.Wrapper {
+	skip := make(map[string]bool, len(excluded))
+	for _, e := range excluded {
+		skip[e] = true
+	}
 	var proofs []*jsonw.Wrapper
 	for _, x := range r.active() {
+		if skip[x.link.TableKey()] {
+			continue
+		}
 		d, err := x.link.ToTrackingStatement(x.state)
 		if err != nil {
 			r.G().Log.Warning("Problem with a proof: %s", err)
@@ -0,0 +1,156 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	rpc "github.com/keybase/go-framed-msgpack-rpc/rpc"
+	context "golang.org/x/net/context"
+)
+
+// Remote signer mode: a service that holds no private device key
+// forwards sigchain signing requests to a remote signer — another
+// device, or an HSM gateway — over the keybase.1.remoteSigner
+// msgpack-RPC protocol. Enterprises use it to centralize custody of
+// provisioning keys. Enable by pointing signing.remote_signer_addr at
+// the signer's socket; the signer side confirms each request (see
+// service.RemoteSignerHandler).
+
+// GetRemoteSignerAddr returns the remote signer endpoint
+// ("unix:///path" or "tcp://host:port"), or "" when the mode is off.
+func (e *Env) GetRemoteSignerAddr() string {
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_REMOTE_SIGNER_ADDR") },
+		func() string {
+			s, _ := e.GetConfig().GetStringAtPath("signing.remote_signer_addr")
+			return s
+		},
+	)
+}
+
+// RemoteSignerKey is a GenericKey whose signatures are produced by the
+// remote signer. Only signing is supported; the public half behaves
+// like any NaCl signing key.
+type RemoteSignerKey struct {
+	Contextified
+	pub  NaclSigningKeyPublic
+	addr string
+}
+
+var _ GenericKey = (*RemoteSignerKey)(nil)
+
+// NewRemoteSignerKey builds a key that fronts for kid, forwarding
+// signatures to the configured signer.
+func NewRemoteSignerKey(g *GlobalContext, kid keybase1.KID) (*RemoteSignerKey, error) {
+	addr := g.Env.GetRemoteSignerAddr()
+	if addr == "" {
+		return nil, fmt.Errorf("remote signer mode is not configured (signing.remote_signer_addr)")
+	}
+	pub := KIDToNaclSigningKeyPublic(kid.ToBytes())
+	if pub == nil {
+		return nil, fmt.Errorf("KID %s is not an EdDSA signing key", kid)
+	}
+	return &RemoteSignerKey{
+		Contextified: NewContextified(g),
+		pub:          *pub,
+		addr:         addr,
+	}, nil
+}
+
+func (k *RemoteSignerKey) pubPair() NaclSigningKeyPair {
+	return NaclSigningKeyPair{Public: k.pub}
+}
+
+func (k *RemoteSignerKey) dial() (net.Conn, error) {
+	network, address := "unix", k.addr
+	const (
+		unixPrefix = "unix://"
+		tcpPrefix  = "tcp://"
+	)
+	switch {
+	case len(k.addr) > len(unixPrefix) && k.addr[:len(unixPrefix)] == unixPrefix:
+		address = k.addr[len(unixPrefix):]
+	case len(k.addr) > len(tcpPrefix) && k.addr[:len(tcpPrefix)] == tcpPrefix:
+		network, address = "tcp", k.addr[len(tcpPrefix):]
+	}
+	return net.DialTimeout(network, address, 10*time.Second)
+}
+
+func (k *RemoteSignerKey) SignToString(msg []byte) (sig string, id keybase1.SigID, err error) {
+	conn, err := k.dial()
+	if err != nil {
+		return sig, id, fmt.Errorf("remote signer unreachable: %s", err)
+	}
+	defer conn.Close()
+
+	cli := keybase1.RemoteSignerClient{Cli: rpc.NewClient(rpc.NewTransport(conn, nil, nil), nil, nil)}
+	res, err := cli.RemoteSign(context.Background(), keybase1.RemoteSignArg{
+		Req: keybase1.RemoteSignRequest{
+			Payload:    msg,
+			SigningKID: k.GetKID(),
+			Reason:     "sigchain link",
+		},
+	})
+	if err != nil {
+		return sig, id, err
+	}
+
+	// Never trust the signer blindly: the signature must verify against
+	// the KID we asked for, over exactly the payload we sent.
+	signed, _, err := k.pubPair().VerifyStringAndExtract(nil, res.Sig)
+	if err != nil {
+		return sig, id, fmt.Errorf("remote signer returned a bad signature: %s", err)
+	}
+	if !FastByteArrayEq(signed, msg) {
+		return sig, id, fmt.Errorf("remote signer signed a different payload than requested")
+	}
+	return res.Sig, res.SigID, nil
+}
+
+func (k *RemoteSignerKey) GetKID() keybase1.KID             { return k.pubPair().GetKID() }
+func (k *RemoteSignerKey) GetBinaryKID() keybase1.BinaryKID { return k.pubPair().GetBinaryKID() }
+func (k *RemoteSignerKey) GetAlgoType() AlgoType            { return KIDNaclEddsa }
+
+func (k *RemoteSignerKey) VerifyStringAndExtract(ctx VerifyContext, sig string) ([]byte, keybase1.SigID, error) {
+	return k.pubPair().VerifyStringAndExtract(ctx, sig)
+}
+
+func (k *RemoteSignerKey) VerifyString(ctx VerifyContext, sig string, msg []byte) (keybase1.SigID, error) {
+	return k.pubPair().VerifyString(ctx, sig, msg)
+}
+
+func (k *RemoteSignerKey) EncryptToString(plaintext []byte, sender GenericKey) (string, error) {
+	return "", KeyCannotEncryptError{}
+}
+
+func (k *RemoteSignerKey) DecryptFromString(ciphertext string) ([]byte, keybase1.KID, error) {
+	return nil, "", KeyCannotDecryptError{}
+}
+
+func (k *RemoteSignerKey) SecretSymmetricKey(reason EncryptionReason) (NaclSecretBoxKey, error) {
+	return NaclSecretBoxKey{}, KeyCannotDecryptError{}
+}
+
+func (k *RemoteSignerKey) VerboseDescription() string {
+	return fmt.Sprintf("255-bit EdDSA signing key (remote signer at %s)", k.addr)
+}
+
+func (k *RemoteSignerKey) CheckSecretKey() error { return nil }
+func (k *RemoteSignerKey) CanSign() bool         { return true }
+func (k *RemoteSignerKey) CanEncrypt() bool      { return false }
+func (k *RemoteSignerKey) CanDecrypt() bool      { return false }
+func (k *RemoteSignerKey) HasSecretKey() bool    { return true }
+
+func (k *RemoteSignerKey) Encode() (string, error) {
+	return k.GetKID().String(), nil
+}
+
+func (k *RemoteSignerKey) ExportPublicAndPrivate() (RawPublicKey, RawPrivateKey, error) {
+	return nil, nil, fmt.Errorf("remote signer keys have no local private half")
+}
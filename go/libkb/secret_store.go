@@ -148,9 +148,15 @@ func NewSecretStoreLocked(m MetaContext) *SecretStoreLocked {
 	mem := NewSecretStoreMem()
 
 	if m.G().Env.RememberPassphrase() {
-		// use os-specific secret store
-		m.CDebugf("NewSecretStoreLocked: using os-specific SecretStore")
-		disk = NewSecretStoreAll(m)
+		if execStore := NewSecretStoreExec(m.G()); execStore != nil {
+			// config selected an external backend (pass/vault/exec)
+			m.CDebugf("NewSecretStoreLocked: using exec-backed SecretStore")
+			disk = execStore
+		} else {
+			// use os-specific secret store
+			m.CDebugf("NewSecretStoreLocked: using os-specific SecretStore")
+			disk = NewSecretStoreAll(m)
+		}
 	} else {
 		// config or command line flag said to use in-memory secret store
 		m.CDebugf("NewSecretStoreLocked: using memory-only SecretStore")
@@ -227,6 +233,18 @@ func (s *SecretStoreLocked) ClearSecret(m MetaContext, username NormalizedUserna
 	return s.disk.ClearSecret(m, username)
 }
 
+// LockMem drops every secret held in the in-memory layer. With
+// remember_passphrase=false there is no disk layer, so this locks the
+// device until the passphrase is entered again.
+func (s *SecretStoreLocked) LockMem() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.mem = NewSecretStoreMem()
+}
+
 func (s *SecretStoreLocked) GetUsersWithStoredSecrets(m MetaContext) ([]string, error) {
 	if s == nil || s.isNil() {
 		return nil, nil
@@ -0,0 +1,148 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// SecretStoreExec stores device secrets through external commands, so
+// headless servers can keep them in `pass` (gpg-agent backed), a
+// HashiCorp Vault KV path, or any custom tool instead of the
+// plaintext secret store file. Select with config:
+//
+//   "secret_store": { "backend": "pass" }                    // pass/gpg-agent
+//   "secret_store": { "backend": "vault",
+//                     "vault_path": "secret/keybase" }       // vault CLI
+//   "secret_store": { "backend": "exec",
+//                     "get": "...", "set": "...",
+//                     "del": "...", "list": "..." }          // custom
+//
+// Commands run via the shell with KEYBASE_SECRET_USER set; `set`
+// receives the base64 secret on stdin, `get` prints it on stdout,
+// `list` prints one username per line.
+type SecretStoreExec struct {
+	Contextified
+	getCmd  string
+	setCmd  string
+	delCmd  string
+	listCmd string
+}
+
+var _ SecretStoreAll = (*SecretStoreExec)(nil)
+
+// NewSecretStoreExec builds the store for the configured backend, or
+// returns nil when the default OS store should be used.
+func NewSecretStoreExec(g *GlobalContext) *SecretStoreExec {
+	backend, _ := g.Env.GetConfig().GetStringAtPath("secret_store.backend")
+	switch backend {
+	case "", "system":
+		return nil
+	case "pass":
+		return &SecretStoreExec{
+			Contextified: NewContextified(g),
+			getCmd:       `pass show "keybase/$KEYBASE_SECRET_USER"`,
+			setCmd:       `pass insert -m -f "keybase/$KEYBASE_SECRET_USER"`,
+			delCmd:       `pass rm -f "keybase/$KEYBASE_SECRET_USER"`,
+			listCmd:      `pass ls keybase | tail -n +2 | sed 's/^[^a-zA-Z0-9_-]*//'`,
+		}
+	case "vault":
+		path, _ := g.Env.GetConfig().GetStringAtPath("secret_store.vault_path")
+		if path == "" {
+			path = "secret/keybase"
+		}
+		return &SecretStoreExec{
+			Contextified: NewContextified(g),
+			getCmd:       fmt.Sprintf(`vault kv get -field=secret "%s/$KEYBASE_SECRET_USER"`, path),
+			setCmd:       fmt.Sprintf(`vault kv put "%s/$KEYBASE_SECRET_USER" secret=-`, path),
+			delCmd:       fmt.Sprintf(`vault kv delete "%s/$KEYBASE_SECRET_USER"`, path),
+			listCmd:      fmt.Sprintf(`vault kv list -format=yaml "%s" | sed -n 's/^- //p'`, path),
+		}
+	case "exec":
+		get, _ := g.Env.GetConfig().GetStringAtPath("secret_store.get")
+		set, _ := g.Env.GetConfig().GetStringAtPath("secret_store.set")
+		del, _ := g.Env.GetConfig().GetStringAtPath("secret_store.del")
+		list, _ := g.Env.GetConfig().GetStringAtPath("secret_store.list")
+		if get == "" || set == "" {
+			g.Log.Warning("secret_store.backend=exec needs at least secret_store.get and secret_store.set; falling back to the system store")
+			return nil
+		}
+		return &SecretStoreExec{
+			Contextified: NewContextified(g),
+			getCmd:       get,
+			setCmd:       set,
+			delCmd:       del,
+			listCmd:      list,
+		}
+	default:
+		g.Log.Warning("unknown secret_store.backend %q; falling back to the system store", backend)
+		return nil
+	}
+}
+
+func (s *SecretStoreExec) run(cmdStr string, username NormalizedUsername, stdin []byte) ([]byte, error) {
+	cmd := exec.Command("/bin/sh", "-c", cmdStr)
+	cmd.Env = append(os.Environ(), "KEYBASE_SECRET_USER="+string(username))
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("secret store command failed: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func (s *SecretStoreExec) RetrieveSecret(m MetaContext, username NormalizedUsername) (LKSecFullSecret, error) {
+	out, err := s.run(s.getCmd, username, nil)
+	if err != nil {
+		return LKSecFullSecret{}, SecretStoreError{Msg: err.Error()}
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return LKSecFullSecret{}, SecretStoreError{Msg: fmt.Sprintf("backend returned invalid base64: %s", err)}
+	}
+	return newLKSecFullSecretFromBytes(raw)
+}
+
+func (s *SecretStoreExec) StoreSecret(m MetaContext, username NormalizedUsername, secret LKSecFullSecret) error {
+	encoded := base64.StdEncoding.EncodeToString(secret.Bytes())
+	if _, err := s.run(s.setCmd, username, []byte(encoded+"\n")); err != nil {
+		return SecretStoreError{Msg: err.Error()}
+	}
+	return nil
+}
+
+func (s *SecretStoreExec) ClearSecret(m MetaContext, username NormalizedUsername) error {
+	if s.delCmd == "" {
+		return nil
+	}
+	if _, err := s.run(s.delCmd, username, nil); err != nil {
+		return SecretStoreError{Msg: err.Error()}
+	}
+	return nil
+}
+
+func (s *SecretStoreExec) GetUsersWithStoredSecrets(m MetaContext) (res []string, err error) {
+	if s.listCmd == "" {
+		return nil, nil
+	}
+	out, err := s.run(s.listCmd, "", nil)
+	if err != nil {
+		return nil, SecretStoreError{Msg: err.Error()}
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSecretStoreExec round-trips a secret through shell commands
+// backed by a temp directory, the same contract pass/vault presets
+// rely on.
+func TestSecretStoreExec(t *testing.T) {
+	tc := SetupTest(t, "secret_store_exec", 1)
+	defer tc.Cleanup()
+
+	dir, err := ioutil.TempDir("", "kb-exec-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := &SecretStoreExec{
+		Contextified: NewContextified(tc.G),
+		getCmd:       `cat "` + dir + `/$KEYBASE_SECRET_USER"`,
+		setCmd:       `cat > "` + dir + `/$KEYBASE_SECRET_USER"`,
+		delCmd:       `rm "` + dir + `/$KEYBASE_SECRET_USER"`,
+		listCmd:      `ls "` + dir + `"`,
+	}
+
+	m := NewMetaContextForTest(tc)
+	nu := NewNormalizedUsername("t_exec")
+
+	secret, err := newLKSecFullSecretFromBytes(bytes.Repeat([]byte{0x42}, LKSecLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.StoreSecret(m, nu, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "t_exec")); err != nil {
+		t.Fatalf("backend file missing: %s", err)
+	}
+
+	got, err := store.RetrieveSecret(m, nu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(secret) {
+		t.Fatal("retrieved secret differs")
+	}
+
+	users, err := store.GetUsersWithStoredSecrets(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0] != "t_exec" {
+		t.Fatalf("users: %v", users)
+	}
+
+	if err := store.ClearSecret(m, nu); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.RetrieveSecret(m, nu); err == nil {
+		t.Fatal("expected retrieve to fail after clear")
+	}
+}
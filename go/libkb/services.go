@@ -14,12 +14,19 @@ import (
 
 //=============================================================================
 
-func MakeProofChecker(c ExternalServicesCollector, l RemoteProofChainLink) (ProofChecker, ProofError) {
+func MakeProofChecker(m MetaContext, c ExternalServicesCollector, l RemoteProofChainLink) (ProofChecker, ProofError) {
+	k := l.TableKey()
+
+	// A helper executable declared in config overrides the built-in
+	// table for this proof type; see proof_checker_external.go.
+	if path := externalProofCheckerPath(m.G(), k); path != "" {
+		return NewExternalProofChecker(m.G(), path, l), nil
+	}
+
 	if c == nil {
 		return nil, NewProofError(keybase1.ProofStatus_UNKNOWN_TYPE,
 			"No proof services configured")
 	}
-	k := l.TableKey()
 	st := c.GetServiceType(k)
 	if st == nil {
 		return nil, NewProofError(keybase1.ProofStatus_UNKNOWN_TYPE,
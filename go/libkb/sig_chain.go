@@ -220,6 +220,9 @@ func (sc *SigChain) Bump(mt MerkleTriple) {
 
 func (sc *SigChain) LoadFromServer(m MetaContext, t *MerkleTriple, selfUID keybase1.UID) (dirtyTail *MerkleTriple, err error) {
 	m, tbs := m.WithTimeBuckets()
+	defer func(start time.Time) {
+		m.G().Metrics.ObserveDuration("keybase_sigchain_load", m.G().Clock().Now().Sub(start))
+	}(m.G().Clock().Now())
 	low := sc.GetLastLoadedSeqno()
 	sc.loadedFromLinkOne = (low == keybase1.Seqno(0) || low == keybase1.Seqno(-1))
 
@@ -430,13 +433,37 @@ func (sc SigChain) GetLastLoadedSeqno() (ret keybase1.Seqno) {
 	return
 }
 
+// Store writes all dirty links to LocalDb in a single batch, so that an
+// initial load of a long chain costs one fsync rather than one per
+// link. Links are only marked clean once the batch has committed.
 func (sc *SigChain) Store(m MetaContext) (err error) {
+	batch, err := sc.G().LocalDb.BeginBatch()
+	if err != nil {
+		return err
+	}
+	// Release the batch on every path that doesn't commit it;
+	// engine batches may hold real resources.
+	defer batch.Discard()
+	var batched []*ChainLink
 	for i := len(sc.chainLinks) - 1; i >= 0; i-- {
 		link := sc.chainLinks[i]
 		var didStore bool
-		if didStore, err = link.Store(sc.G()); err != nil || !didStore {
-			return
+		if didStore, err = link.StoreToBatch(sc.G(), batch); err != nil {
+			return err
+		}
+		if !didStore {
+			break
 		}
+		batched = append(batched, link)
+	}
+	if len(batched) == 0 {
+		return nil
+	}
+	if err = batch.Commit(); err != nil {
+		return err
+	}
+	for _, link := range batched {
+		link.markStored()
 	}
 	return nil
 }
@@ -503,16 +530,16 @@ func cropToRightmostSubchain(links []*ChainLink, eldest keybase1.KID) (ChainLink
 
 // When we're *in the middle of a subchain* (see the note below), there are
 // four ways we can tell that a link is the start of a new subchain:
-// 1) The link is seqno 1, the very first link the user ever makes.
-// 2) The link has the type "eldest". Modern seqno 1 links and sigchain resets
-//    take this form, but old ones don't.
-// 3) The link has a new eldest kid relative to the one that came before. In
-//    the olden days, all sigchain resets were of this form. Note that oldest
-//    links didn't have the eldest_kid field at all, so the signing kid was
-//    assumed to be the eldest.
-// 4) One of a set of six hardcoded links that made it in back when case 3 was
-//    the norm, but we forgot to prohibit reusing the same eldest key. We figured
-//    out this set from server data, once we noticed the mistake.
+//  1. The link is seqno 1, the very first link the user ever makes.
+//  2. The link has the type "eldest". Modern seqno 1 links and sigchain resets
+//     take this form, but old ones don't.
+//  3. The link has a new eldest kid relative to the one that came before. In
+//     the olden days, all sigchain resets were of this form. Note that oldest
+//     links didn't have the eldest_kid field at all, so the signing kid was
+//     assumed to be the eldest.
+//  4. One of a set of six hardcoded links that made it in back when case 3 was
+//     the norm, but we forgot to prohibit reusing the same eldest key. We figured
+//     out this set from server data, once we noticed the mistake.
 //
 // Note: This excludes cases where a subchain has length zero, either because
 // the account is totally new, or because it just did a reset but has no new
@@ -126,6 +126,9 @@ func (s SocketInfo) dialSocket(dialFile string) (ret net.Conn, err error) {
 }
 
 func NewSocket(g *GlobalContext) (ret Socket, err error) {
+	if addr := g.Env.GetRemoteCtlConnect(); addr != "" {
+		return NewRemoteCtlSocket(g, addr), nil
+	}
 	var dialFiles []string
 	dialFiles, err = g.Env.GetSocketDialFiles()
 	if err != nil {
@@ -17,6 +17,9 @@ import (
 )
 
 func NewSocket(g *GlobalContext) (ret Socket, err error) {
+	if addr := g.Env.GetRemoteCtlConnect(); addr != "" {
+		return NewRemoteCtlSocket(g, addr), nil
+	}
 	var s string
 	s, err = g.Env.GetSocketBindFile()
 	if err != nil {
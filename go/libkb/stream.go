@@ -175,6 +175,13 @@ func (ewc RemoteStream) Reset() (err error) {
 	return ewc.Cli.Reset(context.TODO(), keybase1.ResetArg{S: ewc.Stream, SessionID: ewc.SessionID})
 }
 
+// streamFrameSize is how much data each stream RPC round trip carries.
+// bufio's 4KB default meant tens of millions of round trips for a
+// multi-GB saltpack pipe; 1MB frames keep memory bounded while making
+// large files practical. The msgpack framing handles payloads of this
+// size fine.
+const streamFrameSize = 1024 * 1024
+
 type RemoteStreamBuffered struct {
 	rs *RemoteStream
 	r  *bufio.Reader
@@ -212,6 +219,6 @@ func (x *RemoteStreamBuffered) Reset() (err error) {
 }
 
 func (x *RemoteStreamBuffered) createBufs() {
-	x.r = bufio.NewReader(x.rs)
-	x.w = bufio.NewWriter(x.rs)
+	x.r = bufio.NewReaderSize(x.rs, streamFrameSize)
+	x.w = bufio.NewWriterSize(x.rs, streamFrameSize)
 }
@@ -56,3 +56,27 @@ func TorParseProxy(s string) (*url.URL, error) {
 	}
 	return url.Parse(s)
 }
+
+// CheckTorLeaks audits the running configuration for traffic that would
+// bypass the SOCKS proxy while Tor mode is on, returning a description
+// of each potential leak. It's advisory: the service logs these at
+// startup and when Tor mode is switched on at runtime.
+func CheckTorLeaks(g *GlobalContext) (leaks []string) {
+	if !g.Env.GetTorMode().Enabled() {
+		return nil
+	}
+	if g.apiRoundTripper != nil {
+		leaks = append(leaks, "a custom API transport is installed; it must do its own SOCKS dialing")
+	}
+	if doh, _ := g.Env.GetConfig().GetStringAtPath("dns.doh_url"); doh != "" {
+		leaks = append(leaks, "dns.doh_url is set; DoH lookups dial the resolver directly")
+	}
+	if !g.Env.GetGregorDisabled() {
+		// The service refuses to start gregor in Tor mode, but flag a
+		// config that would re-enable it.
+		if g.Env.GetTorMode() == TorLeaky {
+			leaks = append(leaks, "leaky Tor mode keeps gregor push connections direct")
+		}
+	}
+	return leaks
+}
@@ -109,6 +109,29 @@ type TrackLookup struct {
 	trackerSeqno keybase1.Seqno      // The seqno in the tracker's sighcain
 }
 
+// IsProofTypeExcluded reports whether the original track statement
+// deliberately left out this proof type (selective tracking); changes
+// to excluded proofs are not worth warning about.
+func (l *TrackLookup) IsProofTypeExcluded(tableKey string) bool {
+	if l == nil || l.link == nil {
+		return false
+	}
+	jw := l.link.UnmarshalPayloadJSON().AtPath("body.track.excluded_proof_types")
+	if jw.IsNil() {
+		return false
+	}
+	n, err := jw.Len()
+	if err != nil {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if e, err := jw.AtIndex(i).GetString(); err == nil && e == tableKey {
+			return true
+		}
+	}
+	return false
+}
+
 func (l TrackLookup) ToSummary() TrackSummary {
 	ret := TrackSummary{
 		time:     l.GetCTime(),
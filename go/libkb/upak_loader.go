@@ -1,11 +1,12 @@
 package libkb
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
-
+	"sort"
 	"sync"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/keybase/client/go/protocol/keybase1"
@@ -34,6 +35,8 @@ type UPAKLoader interface {
 	LoadV2WithKID(ctx context.Context, uid keybase1.UID, kid keybase1.KID) (*keybase1.UserPlusKeysV2AllIncarnations, error)
 	CheckDeviceForUIDAndUsername(ctx context.Context, uid keybase1.UID, did keybase1.DeviceID, n NormalizedUsername) error
 	Batcher(ctx context.Context, getArg func(int) *LoadUserArg, processResult func(int, *keybase1.UserPlusKeysV2AllIncarnations), window int) (err error)
+	CacheStats() keybase1.UPAKStats
+	LoadAtMerkleSeqno(ctx context.Context, uid keybase1.UID, s keybase1.Seqno) (*keybase1.UserPlusKeysV2AllIncarnations, error)
 }
 
 // CachedUPAKLoader is a UPAKLoader implementation that can cache results both
@@ -45,20 +48,34 @@ type CachedUPAKLoader struct {
 	Freshness      time.Duration
 	noCache        bool
 	TestDeadlocker func()
+
+	statsMu      sync.Mutex
+	stats        keybase1.UPAKStats
+	diskPutCount int
+	purging      bool
 }
 
 // NewCachedUPAKLoader constructs a new CachedUPAKLoader
 func NewCachedUPAKLoader(g *GlobalContext, f time.Duration) *CachedUPAKLoader {
-	c, err := lru.New(g.Env.GetUPAKCacheSize())
-	if err != nil {
-		panic(fmt.Sprintf("could not create lru cache (size = %d)", g.Env.GetUPAKCacheSize()))
-	}
-	return &CachedUPAKLoader{
+	ret := &CachedUPAKLoader{
 		Contextified: NewContextified(g),
 		Freshness:    f,
-		cache:        c,
 		noCache:      false,
 	}
+	c, err := lru.NewWithEvict(g.Env.GetUPAKCacheSize(), func(key interface{}, value interface{}) {
+		// Purge fires this callback per entry; a full flush isn't
+		// LRU pressure, so don't count it.
+		ret.statsMu.Lock()
+		if !ret.purging {
+			ret.stats.MemEvictions++
+		}
+		ret.statsMu.Unlock()
+	})
+	if err != nil {
+		panic(fmt.Sprintf("could not create lru cache (size = %d)", g.Env.GetUPAKCacheSize()))
+	}
+	ret.cache = c
+	return ret
 }
 
 // NewUncachedUPAKLoader creates a UPAK loader that doesn't do any caching.
@@ -112,6 +129,7 @@ func (u *CachedUPAKLoader) getCachedUPAK(ctx context.Context, uid keybase1.UID,
 		// Note that below we check the minor version and then discard the cached object if it's
 		// stale. But no need in memory, since we'll never have the old version in memory.
 		u.G().VDL.CLogf(ctx, VLog0, "| hit memory cache")
+		u.bumpStat(func(s *keybase1.UPAKStats) { s.MemHits++ })
 		if info != nil {
 			info.InCache = true
 		}
@@ -140,6 +158,7 @@ func (u *CachedUPAKLoader) getCachedUPAK(ctx context.Context, uid keybase1.UID,
 
 		if hit {
 			upak = &tmp
+			u.bumpStat(func(s *keybase1.UPAKStats) { s.DiskHits++ })
 			if info != nil {
 				info.InDiskCache = true
 			}
@@ -150,6 +169,7 @@ func (u *CachedUPAKLoader) getCachedUPAK(ctx context.Context, uid keybase1.UID,
 
 	if upak == nil {
 		u.G().VDL.CLogf(ctx, VLog0, "| missed cache")
+		u.bumpStat(func(s *keybase1.UPAKStats) { s.Misses++ })
 		return nil, true
 	}
 	diff := u.G().Clock().Now().Sub(keybase1.FromTime(upak.Uvv.CachedAt))
@@ -158,6 +178,7 @@ func (u *CachedUPAKLoader) getCachedUPAK(ctx context.Context, uid keybase1.UID,
 		u.G().VDL.CLogf(ctx, VLog0, "| cache hit was fresh (cached %s ago)", diff)
 	} else {
 		u.G().VDL.CLogf(ctx, VLog0, "| cache hit was stale (by %s)", u.Freshness-diff)
+		u.bumpStat(func(s *keybase1.UPAKStats) { s.StaleLoads++ })
 	}
 	return upak, fresh
 }
@@ -236,9 +257,81 @@ func (u *CachedUPAKLoader) putUPAKToCache(ctx context.Context, obj *keybase1.Use
 		u.G().Log.CWarningf(ctx, "Error in writing UPAK for %s: %s", uid, err)
 	}
 	u.deleteV1UPAK(uid)
+	u.maybePruneDiskCache(ctx)
 	return err
 }
 
+// bumpStat updates one cache telemetry counter.
+func (u *CachedUPAKLoader) bumpStat(f func(s *keybase1.UPAKStats)) {
+	u.statsMu.Lock()
+	f(&u.stats)
+	u.statsMu.Unlock()
+}
+
+// CacheStats returns a snapshot of the cache telemetry counters for
+// this process: how identification traffic is hitting memory and disk,
+// and how much is being evicted or reloaded stale.
+func (u *CachedUPAKLoader) CacheStats() keybase1.UPAKStats {
+	u.statsMu.Lock()
+	defer u.statsMu.Unlock()
+	return u.stats
+}
+
+// upakDiskPruneEvery bounds how often we pay for a full scan of the
+// disk cache; the budget itself comes from Env.GetUPAKDiskCacheSize.
+const upakDiskPruneEvery = 256
+
+// maybePruneDiskCache keeps the on-disk UPAK cache within its
+// configured budget by deleting the least recently cached entries. It
+// runs a full scan only every upakDiskPruneEvery disk writes.
+func (u *CachedUPAKLoader) maybePruneDiskCache(ctx context.Context) {
+	u.statsMu.Lock()
+	u.diskPutCount++
+	due := u.diskPutCount%upakDiskPruneEvery == 0
+	u.statsMu.Unlock()
+	if !due {
+		return
+	}
+
+	budget := u.G().Env.GetUPAKDiskCacheSize()
+	if budget <= 0 {
+		return
+	}
+
+	type entry struct {
+		id       DbKey
+		cachedAt keybase1.Time
+	}
+	var entries []entry
+	err := u.G().LocalDb.ForEachRawKv(func(id DbKey, value []byte) error {
+		if id.Typ != DBUserPlusKeysVersioned {
+			return nil
+		}
+		var tmp keybase1.UserPlusKeysV2AllIncarnations
+		if jsonErr := json.Unmarshal(value, &tmp); jsonErr != nil {
+			return nil
+		}
+		entries = append(entries, entry{id: id, cachedAt: tmp.Uvv.CachedAt})
+		return nil
+	})
+	if err != nil {
+		u.G().VDL.CLogf(ctx, VLog0, "| UPAK disk prune scan failed: %s", err)
+		return
+	}
+	if len(entries) <= budget {
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].cachedAt < entries[j].cachedAt })
+	for _, e := range entries[:len(entries)-budget] {
+		if err := u.G().LocalDb.Delete(e.id); err != nil {
+			continue
+		}
+		u.bumpStat(func(s *keybase1.UPAKStats) { s.DiskDeletes++ })
+	}
+	u.G().VDL.CLogf(ctx, VLog0, "| UPAK disk prune deleted %d entries (budget %d)", len(entries)-budget, budget)
+}
+
 func (u *CachedUPAKLoader) PutUserToCache(ctx context.Context, user *User) error {
 
 	lock := u.locktab.AcquireOnName(ctx, u.G(), user.GetUID().String())
@@ -829,7 +922,13 @@ func (u *CachedUPAKLoader) removeMemCache(ctx context.Context, uid keybase1.UID)
 }
 
 func (u *CachedUPAKLoader) purgeMemCache() {
+	u.statsMu.Lock()
+	u.purging = true
+	u.statsMu.Unlock()
 	u.cache.Purge()
+	u.statsMu.Lock()
+	u.purging = false
+	u.statsMu.Unlock()
 }
 
 func checkDeviceValidForUID(ctx context.Context, u UPAKLoader, uid keybase1.UID, did keybase1.DeviceID) error {
@@ -911,3 +1010,89 @@ func (u *CachedUPAKLoader) Batcher(ctx context.Context, getArg func(int) *LoadUs
 
 	return eg.Wait()
 }
+
+// LoadAtMerkleSeqno reconstructs a user's key state as of the merkle
+// root at the given seqno. The historical root and the user's leaf in
+// it are fetched with full skip-pointer verification; the leaf's chain
+// tail is then matched against the replayed sigchain, and the exported
+// UPAK is pruned back to that point — keys delegated later are dropped,
+// and revocations that happened later are undone. Verifiers of old
+// artifacts (git commits, saltpack messages) can thereby check
+// signatures against the key set valid at signing time rather than the
+// current one. Roots that predate the user's current incarnation are
+// not supported and return an error.
+func (u *CachedUPAKLoader) LoadAtMerkleSeqno(ctx context.Context, uid keybase1.UID, s keybase1.Seqno) (ret *keybase1.UserPlusKeysV2AllIncarnations, err error) {
+	m := NewMetaContext(ctx, u.G())
+	defer m.CTrace(fmt.Sprintf("CachedUPAKLoader#LoadAtMerkleSeqno(%s,%d)", uid, s), func() error { return err })()
+
+	leaf, _, err := u.G().MerkleClient.LookupLeafAtSeqno(m, uid.AsUserOrTeam(), s)
+	if err != nil {
+		return nil, err
+	}
+	if leaf == nil || leaf.Public == nil {
+		return nil, MerkleClientError{fmt.Sprintf("no public leaf for %s at merkle seqno %d", uid, s), merkleErrorNotFound}
+	}
+	tail := leaf.Public
+
+	upak, _, err := u.LoadV2(NewLoadUserArgWithMetaContext(m).WithUID(uid).WithPublicKeyOptional())
+	if err != nil {
+		return nil, err
+	}
+
+	// The replayed chain must agree with the historical leaf; otherwise
+	// the root's tail belongs to a chain we didn't verify (e.g. a past
+	// incarnation), and we can't vouch for the key state. A cached UPAK
+	// can also simply predate the root's tail, so force one reload
+	// before concluding anything.
+	linkID, found := upak.SeqnoLinkIDs[tail.Seqno]
+	if !found {
+		upak, _, err = u.LoadV2(NewLoadUserArgWithMetaContext(m).WithUID(uid).WithPublicKeyOptional().WithForcePoll(true))
+		if err != nil {
+			return nil, err
+		}
+		linkID, found = upak.SeqnoLinkIDs[tail.Seqno]
+	}
+	if !found {
+		return nil, MerkleClientError{fmt.Sprintf("no replayed chain link at seqno %d for %s", tail.Seqno, uid), merkleErrorNotFound}
+	}
+	if tail.LinkID.String() != string(linkID) {
+		return nil, MerkleClientError{fmt.Sprintf("chain tail mismatch at seqno %d for %s", tail.Seqno, uid), merkleErrorBadLeaf}
+	}
+
+	cpy := upak.DeepCopy()
+	pruneUPAKToChainSeqno(&cpy.Current, tail.Seqno)
+	return &cpy, nil
+}
+
+// pruneUPAKToChainSeqno rewinds one incarnation's key state to the
+// given chain seqno: keys delegated after it vanish, revocations after
+// it are undone.
+func pruneUPAKToChainSeqno(upk *keybase1.UserPlusKeysV2, s keybase1.Seqno) {
+	for kid, key := range upk.DeviceKeys {
+		if key.Base.Provisioning.SigChainLocation.Seqno > s {
+			delete(upk.DeviceKeys, kid)
+			continue
+		}
+		if key.Base.Revocation != nil && key.Base.Revocation.SigChainLocation.Seqno > s {
+			key.Base.Revocation = nil
+			upk.DeviceKeys[kid] = key
+		}
+	}
+	for kid, key := range upk.PGPKeys {
+		if key.Base.Provisioning.SigChainLocation.Seqno > s {
+			delete(upk.PGPKeys, kid)
+			continue
+		}
+		if key.Base.Revocation != nil && key.Base.Revocation.SigChainLocation.Seqno > s {
+			key.Base.Revocation = nil
+			upk.PGPKeys[kid] = key
+		}
+	}
+	var puks []keybase1.PerUserKey
+	for _, puk := range upk.PerUserKeys {
+		if puk.Seqno <= s {
+			puks = append(puks, puk)
+		}
+	}
+	upk.PerUserKeys = puks
+}
@@ -0,0 +1,57 @@
+package libkb
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func sigMetaAt(s keybase1.Seqno) keybase1.SignatureMetadata {
+	return keybase1.SignatureMetadata{
+		SigChainLocation: keybase1.SigChainLocation{Seqno: s},
+	}
+}
+
+func TestPruneUPAKToChainSeqno(t *testing.T) {
+	revokedAt8 := sigMetaAt(8)
+	upk := keybase1.UserPlusKeysV2{
+		DeviceKeys: map[keybase1.KID]keybase1.PublicKeyV2NaCl{
+			"old": {Base: keybase1.PublicKeyV2Base{
+				Provisioning: sigMetaAt(2),
+			}},
+			"revoked-later": {Base: keybase1.PublicKeyV2Base{
+				Provisioning: sigMetaAt(3),
+				Revocation:   &revokedAt8,
+			}},
+			"new": {Base: keybase1.PublicKeyV2Base{
+				Provisioning: sigMetaAt(7),
+			}},
+		},
+		PGPKeys: map[keybase1.KID]keybase1.PublicKeyV2PGPSummary{
+			"pgp-new": {Base: keybase1.PublicKeyV2Base{
+				Provisioning: sigMetaAt(6),
+			}},
+		},
+		PerUserKeys: []keybase1.PerUserKey{
+			{Gen: 1, Seqno: 4},
+			{Gen: 2, Seqno: 9},
+		},
+	}
+
+	pruneUPAKToChainSeqno(&upk, 5)
+
+	// Keys delegated after seqno 5 are gone.
+	require.NotContains(t, upk.DeviceKeys, keybase1.KID("new"))
+	require.NotContains(t, upk.PGPKeys, keybase1.KID("pgp-new"))
+	require.Contains(t, upk.DeviceKeys, keybase1.KID("old"))
+
+	// A revocation that happened after seqno 5 is undone, so the key
+	// reads as live at that point in time.
+	require.Contains(t, upk.DeviceKeys, keybase1.KID("revoked-later"))
+	require.Nil(t, upk.DeviceKeys["revoked-later"].Base.Revocation)
+
+	// Per-user keys rewound too.
+	require.Len(t, upk.PerUserKeys, 1)
+	require.Equal(t, 1, upk.PerUserKeys[0].Gen)
+}
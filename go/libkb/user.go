@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"time"
 
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
 	stellar1 "github.com/keybase/client/go/protocol/stellar1"
@@ -722,6 +723,19 @@ func (u *User) SigningKeyPub() (GenericKey, error) {
 	return pubKey, nil
 }
 
+// GetLinkCTimeFromSeqno returns the client time of the chain link at
+// the given seqno, or the zero time if we don't have that link.
+func (u *User) GetLinkCTimeFromSeqno(seqno keybase1.Seqno) time.Time {
+	if u.sigChain() == nil {
+		return time.Time{}
+	}
+	link := u.sigChain().GetLinkFromSeqno(seqno)
+	if link == nil {
+		return time.Time{}
+	}
+	return link.GetCTime()
+}
+
 func (u *User) GetSigIDFromSeqno(seqno keybase1.Seqno) keybase1.SigID {
 	if u.sigChain() == nil {
 		return ""
@@ -0,0 +1,80 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// A tombstone cache for deleted users. Without it, every load of a
+// deleted user re-hits the network just to learn the same thing again
+// (LoadServerBody returns UserDeletedError each time). Tombstones live
+// in LocalDb with a TTL, since in principle the server could resurrect
+// an account.
+
+const userTombstoneTTL = 6 * 60 * 60 // seconds
+
+type userTombstone struct {
+	Status   keybase1.StatusCode `json:"status"`
+	CachedAt int64               `json:"cached_at"`
+}
+
+func userTombstoneDbKey(uid keybase1.UID) DbKey {
+	return DbKey{Typ: DBUserTombstone, Key: uid.String()}
+}
+
+// checkUserTombstone returns a non-nil error (UserDeletedError) if we
+// have a fresh tombstone recorded for the uid, short-circuiting the
+// network load.
+func checkUserTombstone(m MetaContext, uid keybase1.UID) error {
+	var ts userTombstone
+	found, err := m.G().LocalDb.GetInto(&ts, userTombstoneDbKey(uid))
+	if err != nil || !found {
+		return nil
+	}
+	if m.G().Clock().Now().Unix()-ts.CachedAt > userTombstoneTTL {
+		return nil
+	}
+	m.CDebugf("| user %s is tombstoned (status=%d); skipping network load", uid, ts.Status)
+	return UserDeletedError{Msg: "user was deleted (cached)"}
+}
+
+// putUserTombstone records that a load found the user deleted, and
+// fires a UserChanged notification if the victim is someone the current
+// user follows, so tracker UIs notice without another load.
+func putUserTombstone(m MetaContext, uid keybase1.UID) {
+	// Only write a fresh tombstone once; re-recording just extends the
+	// TTL and re-fires notifications.
+	var existing userTombstone
+	if found, _ := m.G().LocalDb.GetInto(&existing, userTombstoneDbKey(uid)); found {
+		if m.G().Clock().Now().Unix()-existing.CachedAt <= userTombstoneTTL {
+			return
+		}
+	}
+
+	ts := userTombstone{
+		Status:   keybase1.StatusCode_SCDeleted,
+		CachedAt: m.G().Clock().Now().Unix(),
+	}
+	if err := m.G().LocalDb.PutObj(userTombstoneDbKey(uid), nil, ts); err != nil {
+		m.CDebugf("| failed to store tombstone for %s: %s", uid, err)
+		return
+	}
+
+	me := m.G().Env.GetUID()
+	if me.IsNil() {
+		return
+	}
+	followed, err := m.G().GetUPAKLoader().ListFollowedUIDs(m.Ctx(), me)
+	if err != nil {
+		return
+	}
+	for _, f := range followed {
+		if f.Equal(uid) {
+			m.CDebugf("| followed user %s became deleted; notifying", uid)
+			m.G().NotifyRouter.HandleUserChanged(uid)
+			break
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package libkb
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/clockwork"
+	"github.com/stretchr/testify/require"
+	"time"
+)
+
+func TestUserTombstones(t *testing.T) {
+	tc := SetupTest(t, "user_tombstones", 1)
+	defer tc.Cleanup()
+	m := NewMetaContextForTest(tc)
+	fakeClock := clockwork.NewFakeClockAt(time.Now())
+	tc.G.SetClock(fakeClock)
+
+	uid := keybase1.UID("295a7eea607af32040647123732bc819")
+
+	// No tombstone: loads proceed.
+	require.NoError(t, checkUserTombstone(m, uid))
+
+	putUserTombstone(m, uid)
+	err := checkUserTombstone(m, uid)
+	require.Error(t, err)
+	require.IsType(t, UserDeletedError{}, err)
+
+	// Expired tombstones stop blocking loads.
+	fakeClock.Advance(userTombstoneTTL*time.Second + time.Minute)
+	require.NoError(t, checkUserTombstone(m, uid))
+}
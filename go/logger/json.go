@@ -0,0 +1,90 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	logging "github.com/keybase/go-logging"
+)
+
+// jsonFormatter renders each record as one JSON object per line, for
+// service deployments that feed logs into structured pipelines.
+// Context log tags (UIDs, conversation IDs, request IDs) that
+// prepareString folded into the message as a trailing "[tags:...]"
+// suffix are lifted back out into a proper tags object.
+type jsonFormatter struct{}
+
+type jsonLogEntry struct {
+	Time   string            `json:"time"`
+	Level  string            `json:"level"`
+	Module string            `json:"module"`
+	Caller string            `json:"caller,omitempty"`
+	Msg    string            `json:"msg"`
+	Tags   map[string]string `json:"tags,omitempty"`
+}
+
+// splitLogTags pulls a trailing " [tags:k=v,k2=v2]" suffix off a
+// message, returning the bare message and the parsed tags.
+func splitLogTags(msg string) (string, map[string]string) {
+	const marker = " [tags:"
+	idx := strings.LastIndex(msg, marker)
+	if idx < 0 || !strings.HasSuffix(msg, "]") {
+		return msg, nil
+	}
+	raw := msg[idx+len(marker) : len(msg)-1]
+	tags := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			// Not actually a tag suffix; leave the message alone.
+			return msg, nil
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return msg[:idx], tags
+}
+
+func (f jsonFormatter) Format(calldepth int, r *logging.Record, w io.Writer) error {
+	msg, tags := splitLogTags(r.Message())
+	entry := jsonLogEntry{
+		Time:   r.Time.UTC().Format(time.RFC3339Nano),
+		Level:  r.Level.String(),
+		Module: r.Module,
+		Msg:    msg,
+		Tags:   tags,
+	}
+	// Matches the offset go-logging's own %{shortfile} verb uses.
+	if _, file, line, ok := runtime.Caller(calldepth + 1); ok {
+		entry.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(line); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// SetModuleLevel adjusts the log level of one go-logging module at
+// runtime, e.g. from `keybase log set-level`. Module names match what
+// was passed to New(); levels are the go-logging names
+// (debug, info, notice, warning, error, critical).
+func SetModuleLevel(module, level string) error {
+	lvl, err := logging.LogLevel(strings.ToUpper(level))
+	if err != nil {
+		return err
+	}
+	logging.SetLevel(lvl, module)
+	return nil
+}
@@ -306,6 +306,13 @@ func (log *Standard) Configure(style string, debug bool, filename string) {
 		logfmt = fileFormat // Good for logging to files
 	case "fancy":
 		logfmt = fancyFormat // Fancy, good for terminals with color
+	case "json":
+		// Structured one-object-per-line output; see json.go.
+		if debug {
+			logging.SetLevel(logging.DEBUG, log.module)
+		}
+		logging.SetFormatter(jsonFormatter{})
+		return
 	}
 
 	if debug {
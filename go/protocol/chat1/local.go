@@ -20,11 +20,21 @@ func (o VersionKind) DeepCopy() VersionKind {
 
 type MessageText struct {
 	Body string `codec:"body" json:"body"`
+	// ReplyTo names the thread root this message replies to, grouping
+	// replies into first-class threads instead of flat quote-replies.
+	ReplyTo *MessageID `codec:"replyTo,omitempty" json:"replyTo,omitempty"`
 }
 
 func (o MessageText) DeepCopy() MessageText {
 	return MessageText{
 		Body: o.Body,
+		ReplyTo: (func(x *MessageID) *MessageID {
+			if x == nil {
+				return nil
+			}
+			tmp := (*x).DeepCopy()
+			return &tmp
+		})(o.ReplyTo),
 	}
 }
 
@@ -4631,6 +4641,83 @@ type UpgradeKBFSConversationToImpteamArg struct {
 	ConvID ConversationID `codec:"convID" json:"convID"`
 }
 
+type LocalIndexHit struct {
+	ConvID string      `codec:"convID" json:"convID"`
+	MsgIDs []MessageID `codec:"msgIDs" json:"msgIDs"`
+}
+
+func (o LocalIndexHit) DeepCopy() LocalIndexHit {
+	return LocalIndexHit{
+		ConvID: o.ConvID,
+		MsgIDs: (func(x []MessageID) []MessageID {
+			if x == nil {
+				return nil
+			}
+			ret := make([]MessageID, len(x))
+			for i, v := range x {
+				vCopy := v.DeepCopy()
+				ret[i] = vCopy
+			}
+			return ret
+		})(o.MsgIDs),
+	}
+}
+
+type SearchLocalIndexArg struct {
+	Query string `codec:"query" json:"query"`
+}
+
+type TranslateMessageLocalArg struct {
+	ConvID     ConversationID `codec:"convID" json:"convID"`
+	MsgID      MessageID      `codec:"msgID" json:"msgID"`
+	TargetLang string         `codec:"targetLang" json:"targetLang"`
+}
+
+type PinMessageLocalArg struct {
+	ConvID ConversationID `codec:"convID" json:"convID"`
+	MsgID  MessageID      `codec:"msgID" json:"msgID"`
+}
+
+type UnpinMessageLocalArg struct {
+	ConvID ConversationID `codec:"convID" json:"convID"`
+	MsgID  MessageID      `codec:"msgID" json:"msgID"`
+}
+
+type ListPinsLocalArg struct {
+	ConvID ConversationID `codec:"convID" json:"convID"`
+}
+
+type PinEntryRes struct {
+	MsgID    MessageID    `codec:"msgID" json:"msgID"`
+	PinnedBy string       `codec:"pinnedBy" json:"pinnedBy"`
+	PinnedAt gregor1.Time `codec:"pinnedAt" json:"pinnedAt"`
+}
+
+func (o PinEntryRes) DeepCopy() PinEntryRes {
+	return PinEntryRes{
+		MsgID:    o.MsgID.DeepCopy(),
+		PinnedBy: o.PinnedBy,
+		PinnedAt: o.PinnedAt.DeepCopy(),
+	}
+}
+
+type SeenByLocalArg struct {
+	ConvID ConversationID `codec:"convID" json:"convID"`
+	MsgID  MessageID      `codec:"msgID" json:"msgID"`
+}
+
+type ReadReceiptRes struct {
+	Username string       `codec:"username" json:"username"`
+	SeenAt   gregor1.Time `codec:"seenAt" json:"seenAt"`
+}
+
+func (o ReadReceiptRes) DeepCopy() ReadReceiptRes {
+	return ReadReceiptRes{
+		Username: o.Username,
+		SeenAt:   o.SeenAt.DeepCopy(),
+	}
+}
+
 type GetSearchRegexpArg struct {
 	SessionID        int                          `codec:"sessionID" json:"sessionID"`
 	ConversationID   ConversationID               `codec:"conversationID" json:"conversationID"`
@@ -4647,6 +4734,28 @@ type GetSearchRegexpArg struct {
 type GetStaticConfigArg struct {
 }
 
+type LocalConversationStats struct {
+	ConvID     string `codec:"convID" json:"convID"`
+	Blocks     int    `codec:"blocks" json:"blocks"`
+	BytesTotal int64  `codec:"bytesTotal" json:"bytesTotal"`
+}
+
+func (o LocalConversationStats) DeepCopy() LocalConversationStats {
+	return LocalConversationStats{
+		ConvID:     o.ConvID,
+		Blocks:     o.Blocks,
+		BytesTotal: o.BytesTotal,
+	}
+}
+
+type GetLocalRetentionStatsArg struct {
+}
+
+type GetThreadRepliesArg struct {
+	ConvID    ConversationID `codec:"convID" json:"convID"`
+	RootMsgID MessageID      `codec:"rootMsgID" json:"rootMsgID"`
+}
+
 type LocalInterface interface {
 	GetThreadLocal(context.Context, GetThreadLocalArg) (GetThreadLocalRes, error)
 	GetCachedThread(context.Context, GetCachedThreadArg) (GetThreadLocalRes, error)
@@ -4701,6 +4810,17 @@ type LocalInterface interface {
 	SetConvMinWriterRoleLocal(context.Context, SetConvMinWriterRoleLocalArg) error
 	UpgradeKBFSConversationToImpteam(context.Context, ConversationID) error
 	GetSearchRegexp(context.Context, GetSearchRegexpArg) (GetSearchRegexpRes, error)
+	SearchLocalIndex(context.Context, string) ([]LocalIndexHit, error)
+	TranslateMessageLocal(context.Context, TranslateMessageLocalArg) (string, error)
+	PinMessageLocal(context.Context, PinMessageLocalArg) error
+	UnpinMessageLocal(context.Context, UnpinMessageLocalArg) error
+	ListPinsLocal(context.Context, ConversationID) ([]PinEntryRes, error)
+	SeenByLocal(context.Context, SeenByLocalArg) ([]ReadReceiptRes, error)
+	// Report how much message data each conversation retains on this
+	// device.
+	GetLocalRetentionStats(context.Context) ([]LocalConversationStats, error)
+	// Fetch the replies grouped under a thread root.
+	GetThreadReplies(context.Context, GetThreadRepliesArg) ([]MessageUnboxed, error)
 	GetStaticConfig(context.Context) (StaticConfig, error)
 }
 
@@ -5546,6 +5666,129 @@ func LocalProtocol(i LocalInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"searchLocalIndex": {
+				MakeArg: func() interface{} {
+					ret := make([]SearchLocalIndexArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SearchLocalIndexArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SearchLocalIndexArg)(nil), args)
+						return
+					}
+					ret, err = i.SearchLocalIndex(ctx, (*typedArgs)[0].Query)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"translateMessageLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]TranslateMessageLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]TranslateMessageLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]TranslateMessageLocalArg)(nil), args)
+						return
+					}
+					ret, err = i.TranslateMessageLocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"pinMessageLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]PinMessageLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]PinMessageLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]PinMessageLocalArg)(nil), args)
+						return
+					}
+					err = i.PinMessageLocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"unpinMessageLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]UnpinMessageLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]UnpinMessageLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]UnpinMessageLocalArg)(nil), args)
+						return
+					}
+					err = i.UnpinMessageLocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"listPinsLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]ListPinsLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ListPinsLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ListPinsLocalArg)(nil), args)
+						return
+					}
+					ret, err = i.ListPinsLocal(ctx, (*typedArgs)[0].ConvID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"seenByLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]SeenByLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SeenByLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SeenByLocalArg)(nil), args)
+						return
+					}
+					ret, err = i.SeenByLocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"getLocalRetentionStats": {
+				MakeArg: func() interface{} {
+					ret := make([]GetLocalRetentionStatsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					ret, err = i.GetLocalRetentionStats(ctx)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"getThreadReplies": {
+				MakeArg: func() interface{} {
+					ret := make([]GetThreadRepliesArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]GetThreadRepliesArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]GetThreadRepliesArg)(nil), args)
+						return
+					}
+					ret, err = i.GetThreadReplies(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 			"getStaticConfig": {
 				MakeArg: func() interface{} {
 					ret := make([]GetStaticConfigArg, 1)
@@ -5834,11 +6077,56 @@ func (c LocalClient) UpgradeKBFSConversationToImpteam(ctx context.Context, convI
 	return
 }
 
+func (c LocalClient) SearchLocalIndex(ctx context.Context, query string) (res []LocalIndexHit, err error) {
+	__arg := SearchLocalIndexArg{Query: query}
+	err = c.Cli.Call(ctx, "chat.1.local.searchLocalIndex", []interface{}{__arg}, &res)
+	return
+}
+
+func (c LocalClient) TranslateMessageLocal(ctx context.Context, __arg TranslateMessageLocalArg) (res string, err error) {
+	err = c.Cli.Call(ctx, "chat.1.local.translateMessageLocal", []interface{}{__arg}, &res)
+	return
+}
+
+func (c LocalClient) PinMessageLocal(ctx context.Context, __arg PinMessageLocalArg) (err error) {
+	err = c.Cli.Call(ctx, "chat.1.local.pinMessageLocal", []interface{}{__arg}, nil)
+	return
+}
+
+func (c LocalClient) UnpinMessageLocal(ctx context.Context, __arg UnpinMessageLocalArg) (err error) {
+	err = c.Cli.Call(ctx, "chat.1.local.unpinMessageLocal", []interface{}{__arg}, nil)
+	return
+}
+
+func (c LocalClient) ListPinsLocal(ctx context.Context, convID ConversationID) (res []PinEntryRes, err error) {
+	__arg := ListPinsLocalArg{ConvID: convID}
+	err = c.Cli.Call(ctx, "chat.1.local.listPinsLocal", []interface{}{__arg}, &res)
+	return
+}
+
+func (c LocalClient) SeenByLocal(ctx context.Context, __arg SeenByLocalArg) (res []ReadReceiptRes, err error) {
+	err = c.Cli.Call(ctx, "chat.1.local.seenByLocal", []interface{}{__arg}, &res)
+	return
+}
+
 func (c LocalClient) GetSearchRegexp(ctx context.Context, __arg GetSearchRegexpArg) (res GetSearchRegexpRes, err error) {
 	err = c.Cli.Call(ctx, "chat.1.local.getSearchRegexp", []interface{}{__arg}, &res)
 	return
 }
 
+// Report how much message data each conversation retains on this
+// device.
+func (c LocalClient) GetLocalRetentionStats(ctx context.Context) (res []LocalConversationStats, err error) {
+	err = c.Cli.Call(ctx, "chat.1.local.getLocalRetentionStats", []interface{}{GetLocalRetentionStatsArg{}}, &res)
+	return
+}
+
+// Fetch the replies grouped under a thread root.
+func (c LocalClient) GetThreadReplies(ctx context.Context, __arg GetThreadRepliesArg) (res []MessageUnboxed, err error) {
+	err = c.Cli.Call(ctx, "chat.1.local.getThreadReplies", []interface{}{__arg}, &res)
+	return
+}
+
 func (c LocalClient) GetStaticConfig(ctx context.Context) (res StaticConfig, err error) {
 	err = c.Cli.Call(ctx, "chat.1.local.getStaticConfig", []interface{}{GetStaticConfigArg{}}, &res)
 	return
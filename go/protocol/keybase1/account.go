@@ -91,6 +91,53 @@ type SetLockdownModeArg struct {
 	Enabled   bool `codec:"enabled" json:"enabled"`
 }
 
+type SchedulePerUserKeyRotationArg struct {
+	SessionID               int   `codec:"sessionID" json:"sessionID"`
+	RotationIntervalSeconds int64 `codec:"rotationIntervalSeconds" json:"rotationIntervalSeconds"`
+	RotateAfterRevokes      int   `codec:"rotateAfterRevokes" json:"rotateAfterRevokes"`
+}
+
+type ApproveHighLinkArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Operation string `codec:"operation" json:"operation"`
+}
+
+type ListProvisionedUsersArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type SwitchAccountArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Username  string `codec:"username" json:"username"`
+}
+
+type ResetPreview struct {
+	TeamNames        []string `codec:"teamNames" json:"teamNames"`
+	FollowerCount    int      `codec:"followerCount" json:"followerCount"`
+	GitRepoCount     int      `codec:"gitRepoCount" json:"gitRepoCount"`
+	HasStellarWallet bool     `codec:"hasStellarWallet" json:"hasStellarWallet"`
+}
+
+func (o ResetPreview) DeepCopy() ResetPreview {
+	return ResetPreview{
+		TeamNames: (func(x []string) []string {
+			if x == nil {
+				return nil
+			}
+			ret := make([]string, len(x))
+			copy(ret, x)
+			return ret
+		})(o.TeamNames),
+		FollowerCount:    o.FollowerCount,
+		GitRepoCount:     o.GitRepoCount,
+		HasStellarWallet: o.HasStellarWallet,
+	}
+}
+
+type PreviewResetArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
 type AccountInterface interface {
 	// Change the passphrase from old to new. If old isn't set, and force is false,
 	// then prompt at the UI for it. If old isn't set and force is true, then we'll
@@ -107,6 +154,23 @@ type AccountInterface interface {
 	ResetAccount(context.Context, ResetAccountArg) error
 	GetLockdownMode(context.Context, int) (GetLockdownResponse, error)
 	SetLockdownMode(context.Context, SetLockdownModeArg) error
+	// Configure proactive per-user-key rotation: on a cadence
+	// (rotationIntervalSeconds), after a number of device revocations
+	// (rotateAfterRevokes), or both. Zero disables a policy.
+	SchedulePerUserKeyRotation(context.Context, SchedulePerUserKeyRotationArg) error
+	// Record a second-device approval for a high sigchain link operation
+	// ("sibkey" or "per_user_key"); see multi-device approval mode.
+	ApproveHighLink(context.Context, ApproveHighLinkArg) error
+	// Users provisioned on this device with a stored secret, i.e.
+	// switchable without a passphrase.
+	ListProvisionedUsers(context.Context, int) ([]string, error)
+	// Switch the active account to another provisioned user without
+	// re-entering a passphrase.
+	SwitchAccount(context.Context, SwitchAccountArg) error
+	// Compute what an account reset would cost: team memberships that
+	// lapse, followers whose tracking breaks, encrypted git repos that
+	// become unreadable, and whether a stellar wallet is attached.
+	PreviewReset(context.Context, int) (ResetPreview, error)
 }
 
 func AccountProtocol(i AccountInterface) rpc.Protocol {
@@ -225,6 +289,86 @@ func AccountProtocol(i AccountInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"schedulePerUserKeyRotation": {
+				MakeArg: func() interface{} {
+					ret := make([]SchedulePerUserKeyRotationArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SchedulePerUserKeyRotationArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SchedulePerUserKeyRotationArg)(nil), args)
+						return
+					}
+					err = i.SchedulePerUserKeyRotation(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"approveHighLink": {
+				MakeArg: func() interface{} {
+					ret := make([]ApproveHighLinkArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ApproveHighLinkArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ApproveHighLinkArg)(nil), args)
+						return
+					}
+					err = i.ApproveHighLink(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"listProvisionedUsers": {
+				MakeArg: func() interface{} {
+					ret := make([]ListProvisionedUsersArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ListProvisionedUsersArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ListProvisionedUsersArg)(nil), args)
+						return
+					}
+					ret, err = i.ListProvisionedUsers(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"switchAccount": {
+				MakeArg: func() interface{} {
+					ret := make([]SwitchAccountArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SwitchAccountArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SwitchAccountArg)(nil), args)
+						return
+					}
+					err = i.SwitchAccount(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"previewReset": {
+				MakeArg: func() interface{} {
+					ret := make([]PreviewResetArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]PreviewResetArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]PreviewResetArg)(nil), args)
+						return
+					}
+					ret, err = i.PreviewReset(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 		},
 	}
 }
@@ -277,3 +421,42 @@ func (c AccountClient) SetLockdownMode(ctx context.Context, __arg SetLockdownMod
 	err = c.Cli.Call(ctx, "keybase.1.account.setLockdownMode", []interface{}{__arg}, nil)
 	return
 }
+
+// Configure proactive per-user-key rotation: on a cadence
+// (rotationIntervalSeconds), after a number of device revocations
+// (rotateAfterRevokes), or both. Zero disables a policy.
+func (c AccountClient) SchedulePerUserKeyRotation(ctx context.Context, __arg SchedulePerUserKeyRotationArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.account.schedulePerUserKeyRotation", []interface{}{__arg}, nil)
+	return
+}
+
+// Record a second-device approval for a high sigchain link operation
+// ("sibkey" or "per_user_key"); see multi-device approval mode.
+func (c AccountClient) ApproveHighLink(ctx context.Context, __arg ApproveHighLinkArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.account.approveHighLink", []interface{}{__arg}, nil)
+	return
+}
+
+// Users provisioned on this device with a stored secret, i.e.
+// switchable without a passphrase.
+func (c AccountClient) ListProvisionedUsers(ctx context.Context, sessionID int) (res []string, err error) {
+	__arg := ListProvisionedUsersArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.account.listProvisionedUsers", []interface{}{__arg}, &res)
+	return
+}
+
+// Switch the active account to another provisioned user without
+// re-entering a passphrase.
+func (c AccountClient) SwitchAccount(ctx context.Context, __arg SwitchAccountArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.account.switchAccount", []interface{}{__arg}, nil)
+	return
+}
+
+// Compute what an account reset would cost: team memberships that
+// lapse, followers whose tracking breaks, encrypted git repos that
+// become unreadable, and whether a stellar wallet is attached.
+func (c AccountClient) PreviewReset(ctx context.Context, sessionID int) (res ResetPreview, err error) {
+	__arg := PreviewResetArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.account.previewReset", []interface{}{__arg}, &res)
+	return
+}
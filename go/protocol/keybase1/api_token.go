@@ -0,0 +1,149 @@
+// Auto-generated by avdl-compiler v1.3.24 (https://github.com/keybase/node-avdl-compiler)
+//   Input file: avdl/keybase1/api_token.avdl
+
+package keybase1
+
+import (
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+	context "golang.org/x/net/context"
+)
+
+type ApiTokenInfo struct {
+	Label     string   `codec:"label" json:"label"`
+	Allow     []string `codec:"allow" json:"allow"`
+	CreatedAt Time     `codec:"createdAt" json:"createdAt"`
+}
+
+func (o ApiTokenInfo) DeepCopy() ApiTokenInfo {
+	return ApiTokenInfo{
+		Label: o.Label,
+		Allow: (func(x []string) []string {
+			if x == nil {
+				return nil
+			}
+			ret := make([]string, len(x))
+			for i, v := range x {
+				vCopy := v
+				ret[i] = vCopy
+			}
+			return ret
+		})(o.Allow),
+		CreatedAt: o.CreatedAt.DeepCopy(),
+	}
+}
+
+type ApiTokenCreateArg struct {
+	Label string   `codec:"label" json:"label"`
+	Allow []string `codec:"allow" json:"allow"`
+}
+
+type ApiTokenListArg struct {
+}
+
+type ApiTokenRevokeArg struct {
+	Label string `codec:"label" json:"label"`
+}
+
+type ApiTokenAuthorizeArg struct {
+	Token string `codec:"token" json:"token"`
+}
+
+type ApiTokenInterface interface {
+	ApiTokenCreate(context.Context, ApiTokenCreateArg) (string, error)
+	ApiTokenList(context.Context) ([]ApiTokenInfo, error)
+	ApiTokenRevoke(context.Context, string) error
+	ApiTokenAuthorize(context.Context, string) error
+}
+
+func ApiTokenProtocol(i ApiTokenInterface) rpc.Protocol {
+	return rpc.Protocol{
+		Name: "keybase.1.apiToken",
+		Methods: map[string]rpc.ServeHandlerDescription{
+			"apiTokenCreate": {
+				MakeArg: func() interface{} {
+					ret := make([]ApiTokenCreateArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ApiTokenCreateArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ApiTokenCreateArg)(nil), args)
+						return
+					}
+					ret, err = i.ApiTokenCreate(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"apiTokenList": {
+				MakeArg: func() interface{} {
+					ret := make([]ApiTokenListArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					ret, err = i.ApiTokenList(ctx)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"apiTokenRevoke": {
+				MakeArg: func() interface{} {
+					ret := make([]ApiTokenRevokeArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ApiTokenRevokeArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ApiTokenRevokeArg)(nil), args)
+						return
+					}
+					err = i.ApiTokenRevoke(ctx, (*typedArgs)[0].Label)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"apiTokenAuthorize": {
+				MakeArg: func() interface{} {
+					ret := make([]ApiTokenAuthorizeArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ApiTokenAuthorizeArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ApiTokenAuthorizeArg)(nil), args)
+						return
+					}
+					err = i.ApiTokenAuthorize(ctx, (*typedArgs)[0].Token)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+		},
+	}
+}
+
+type ApiTokenClient struct {
+	Cli rpc.GenericClient
+}
+
+func (c ApiTokenClient) ApiTokenCreate(ctx context.Context, __arg ApiTokenCreateArg) (res string, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.apiToken.apiTokenCreate", []interface{}{__arg}, &res)
+	return
+}
+
+func (c ApiTokenClient) ApiTokenList(ctx context.Context) (res []ApiTokenInfo, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.apiToken.apiTokenList", []interface{}{ApiTokenListArg{}}, &res)
+	return
+}
+
+func (c ApiTokenClient) ApiTokenRevoke(ctx context.Context, label string) (err error) {
+	__arg := ApiTokenRevokeArg{Label: label}
+	err = c.Cli.Call(ctx, "keybase.1.apiToken.apiTokenRevoke", []interface{}{__arg}, nil)
+	return
+}
+
+func (c ApiTokenClient) ApiTokenAuthorize(ctx context.Context, token string) (err error) {
+	__arg := ApiTokenAuthorizeArg{Token: token}
+	err = c.Cli.Call(ctx, "keybase.1.apiToken.apiTokenAuthorize", []interface{}{__arg}, nil)
+	return
+}
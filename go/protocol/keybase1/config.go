@@ -444,6 +444,10 @@ type GetValueArg struct {
 	Path string `codec:"path" json:"path"`
 }
 
+type GetValueProvenanceArg struct {
+	Path string `codec:"path" json:"path"`
+}
+
 type CheckAPIServerOutOfDateWarningArg struct {
 }
 
@@ -465,6 +469,12 @@ type SetRememberPassphraseArg struct {
 	Remember  bool `codec:"remember" json:"remember"`
 }
 
+type SetTorModeArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Mode      string `codec:"mode" json:"mode"`
+	Proxy     string `codec:"proxy" json:"proxy"`
+}
+
 type ConfigInterface interface {
 	GetCurrentStatus(context.Context, int) (GetCurrentStatusRes, error)
 	GetExtendedStatus(context.Context, int) (ExtendedStatus, error)
@@ -479,6 +489,9 @@ type ConfigInterface interface {
 	SetValue(context.Context, SetValueArg) error
 	ClearValue(context.Context, string) error
 	GetValue(context.Context, string) (ConfigValue, error)
+	// Which layer a config lookup resolves from: "profile:<name>",
+	// "config", or "unset". Flag/env layers are visible to the caller.
+	GetValueProvenance(context.Context, string) (string, error)
 	// Check whether the API server has told us we're out of date.
 	CheckAPIServerOutOfDateWarning(context.Context) (OutOfDateInfo, error)
 	// Wait for client type to connect to service.
@@ -486,6 +499,9 @@ type ConfigInterface interface {
 	GetBootstrapStatus(context.Context, int) (BootstrapStatus, error)
 	GetRememberPassphrase(context.Context, int) (bool, error)
 	SetRememberPassphrase(context.Context, SetRememberPassphraseArg) error
+	// Switch Tor mode at runtime: mode is "none", "strict" or "leaky",
+	// proxy the SOCKS5 address. API clients are rebuilt immediately.
+	SetTorMode(context.Context, SetTorModeArg) error
 }
 
 func ConfigProtocol(i ConfigInterface) rpc.Protocol {
@@ -652,6 +668,22 @@ func ConfigProtocol(i ConfigInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"getValueProvenance": {
+				MakeArg: func() interface{} {
+					ret := make([]GetValueProvenanceArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]GetValueProvenanceArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]GetValueProvenanceArg)(nil), args)
+						return
+					}
+					ret, err = i.GetValueProvenance(ctx, (*typedArgs)[0].Path)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 			"checkAPIServerOutOfDateWarning": {
 				MakeArg: func() interface{} {
 					ret := make([]CheckAPIServerOutOfDateWarningArg, 1)
@@ -727,6 +759,22 @@ func ConfigProtocol(i ConfigInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"setTorMode": {
+				MakeArg: func() interface{} {
+					ret := make([]SetTorModeArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SetTorModeArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SetTorModeArg)(nil), args)
+						return
+					}
+					err = i.SetTorMode(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 		},
 	}
 }
@@ -795,6 +843,14 @@ func (c ConfigClient) GetValue(ctx context.Context, path string) (res ConfigValu
 	return
 }
 
+// Which layer a config lookup resolves from: "profile:<name>",
+// "config", or "unset". Flag/env layers are visible to the caller.
+func (c ConfigClient) GetValueProvenance(ctx context.Context, path string) (res string, err error) {
+	__arg := GetValueProvenanceArg{Path: path}
+	err = c.Cli.Call(ctx, "keybase.1.config.getValueProvenance", []interface{}{__arg}, &res)
+	return
+}
+
 // Check whether the API server has told us we're out of date.
 func (c ConfigClient) CheckAPIServerOutOfDateWarning(ctx context.Context) (res OutOfDateInfo, err error) {
 	err = c.Cli.Call(ctx, "keybase.1.config.checkAPIServerOutOfDateWarning", []interface{}{CheckAPIServerOutOfDateWarningArg{}}, &res)
@@ -823,3 +879,10 @@ func (c ConfigClient) SetRememberPassphrase(ctx context.Context, __arg SetRememb
 	err = c.Cli.Call(ctx, "keybase.1.config.setRememberPassphrase", []interface{}{__arg}, nil)
 	return
 }
+
+// Switch Tor mode at runtime: mode is "none", "strict" or "leaky",
+// proxy the SOCKS5 address. API clients are rebuilt immediately.
+func (c ConfigClient) SetTorMode(ctx context.Context, __arg SetTorModeArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.config.setTorMode", []interface{}{__arg}, nil)
+	return
+}
@@ -0,0 +1,110 @@
+// Auto-generated by avdl-compiler v1.3.24 (https://github.com/keybase/node-avdl-compiler)
+//   Input file: avdl/keybase1/contacts.avdl
+
+package keybase1
+
+import (
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+	context "golang.org/x/net/context"
+)
+
+type ResolvedContact struct {
+	ContactName string `codec:"contactName" json:"contactName"`
+	Username    string `codec:"username" json:"username"`
+}
+
+func (o ResolvedContact) DeepCopy() ResolvedContact {
+	return ResolvedContact{
+		ContactName: o.ContactName,
+		Username:    o.Username,
+	}
+}
+
+type ImportContactsArg struct {
+	Contents string `codec:"contents" json:"contents"`
+}
+
+type ListContactsArg struct {
+}
+
+type ResolveContactArg struct {
+	Name string `codec:"name" json:"name"`
+}
+
+type ContactsInterface interface {
+	ImportContacts(context.Context, string) (int, error)
+	ListContacts(context.Context) ([]ResolvedContact, error)
+	ResolveContact(context.Context, string) (string, error)
+}
+
+func ContactsProtocol(i ContactsInterface) rpc.Protocol {
+	return rpc.Protocol{
+		Name: "keybase.1.contacts",
+		Methods: map[string]rpc.ServeHandlerDescription{
+			"importContacts": {
+				MakeArg: func() interface{} {
+					ret := make([]ImportContactsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ImportContactsArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ImportContactsArg)(nil), args)
+						return
+					}
+					ret, err = i.ImportContacts(ctx, (*typedArgs)[0].Contents)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"listContacts": {
+				MakeArg: func() interface{} {
+					ret := make([]ListContactsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					ret, err = i.ListContacts(ctx)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"resolveContact": {
+				MakeArg: func() interface{} {
+					ret := make([]ResolveContactArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ResolveContactArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ResolveContactArg)(nil), args)
+						return
+					}
+					ret, err = i.ResolveContact(ctx, (*typedArgs)[0].Name)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+		},
+	}
+}
+
+type ContactsClient struct {
+	Cli rpc.GenericClient
+}
+
+func (c ContactsClient) ImportContacts(ctx context.Context, contents string) (res int, err error) {
+	__arg := ImportContactsArg{Contents: contents}
+	err = c.Cli.Call(ctx, "keybase.1.contacts.importContacts", []interface{}{__arg}, &res)
+	return
+}
+
+func (c ContactsClient) ListContacts(ctx context.Context) (res []ResolvedContact, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.contacts.listContacts", []interface{}{ListContactsArg{}}, &res)
+	return
+}
+
+func (c ContactsClient) ResolveContact(ctx context.Context, name string) (res string, err error) {
+	__arg := ResolveContactArg{Name: name}
+	err = c.Cli.Call(ctx, "keybase.1.contacts.resolveContact", []interface{}{__arg}, &res)
+	return
+}
@@ -125,6 +125,24 @@ type DbGetArg struct {
 	Key       DbKey `codec:"key" json:"key"`
 }
 
+type DbVerifyResult struct {
+	Scanned int `codec:"scanned" json:"scanned"`
+	Corrupt int `codec:"corrupt" json:"corrupt"`
+	Deleted int `codec:"deleted" json:"deleted"`
+}
+
+func (o DbVerifyResult) DeepCopy() DbVerifyResult {
+	return DbVerifyResult{
+		Scanned: o.Scanned,
+		Corrupt: o.Corrupt,
+		Deleted: o.Deleted,
+	}
+}
+
+type DbVerifyArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
 type CtlInterface interface {
 	Stop(context.Context, StopArg) error
 	LogRotate(context.Context, int) error
@@ -134,6 +152,7 @@ type CtlInterface interface {
 	DbDelete(context.Context, DbDeleteArg) error
 	DbPut(context.Context, DbPutArg) error
 	DbGet(context.Context, DbGetArg) (*DbValue, error)
+	DbVerify(context.Context, int) (DbVerifyResult, error)
 }
 
 func CtlProtocol(i CtlInterface) rpc.Protocol {
@@ -268,6 +287,22 @@ func CtlProtocol(i CtlInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"dbVerify": {
+				MakeArg: func() interface{} {
+					ret := make([]DbVerifyArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DbVerifyArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DbVerifyArg)(nil), args)
+						return
+					}
+					ret, err = i.DbVerify(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 		},
 	}
 }
@@ -319,3 +354,9 @@ func (c CtlClient) DbGet(ctx context.Context, __arg DbGetArg) (res *DbValue, err
 	err = c.Cli.Call(ctx, "keybase.1.ctl.dbGet", []interface{}{__arg}, &res)
 	return
 }
+
+func (c CtlClient) DbVerify(ctx context.Context, sessionID int) (res DbVerifyResult, err error) {
+	__arg := DbVerifyArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.ctl.dbVerify", []interface{}{__arg}, &res)
+	return
+}
@@ -9,14 +9,17 @@ import (
 )
 
 type DeviceDetail struct {
-	Device          Device  `codec:"device" json:"device"`
-	Eldest          bool    `codec:"eldest" json:"eldest"`
-	Provisioner     *Device `codec:"provisioner,omitempty" json:"provisioner,omitempty"`
-	ProvisionedAt   *Time   `codec:"provisionedAt,omitempty" json:"provisionedAt,omitempty"`
-	RevokedAt       *Time   `codec:"revokedAt,omitempty" json:"revokedAt,omitempty"`
-	RevokedBy       KID     `codec:"revokedBy" json:"revokedBy"`
-	RevokedByDevice *Device `codec:"revokedByDevice,omitempty" json:"revokedByDevice,omitempty"`
-	CurrentDevice   bool    `codec:"currentDevice" json:"currentDevice"`
+	Device               Device  `codec:"device" json:"device"`
+	Eldest               bool    `codec:"eldest" json:"eldest"`
+	Provisioner          *Device `codec:"provisioner,omitempty" json:"provisioner,omitempty"`
+	ProvisionedAt        *Time   `codec:"provisionedAt,omitempty" json:"provisionedAt,omitempty"`
+	RevokedAt            *Time   `codec:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	RevokedBy            KID     `codec:"revokedBy" json:"revokedBy"`
+	RevokedByDevice      *Device `codec:"revokedByDevice,omitempty" json:"revokedByDevice,omitempty"`
+	CurrentDevice        bool    `codec:"currentDevice" json:"currentDevice"`
+	DelegationSeqno      Seqno   `codec:"delegationSeqno" json:"delegationSeqno"`
+	DelegationSigningKID KID     `codec:"delegationSigningKID" json:"delegationSigningKID"`
+	RevocationSeqno      Seqno   `codec:"revocationSeqno" json:"revocationSeqno"`
 }
 
 func (o DeviceDetail) DeepCopy() DeviceDetail {
@@ -52,7 +55,10 @@ func (o DeviceDetail) DeepCopy() DeviceDetail {
 			tmp := (*x).DeepCopy()
 			return &tmp
 		})(o.RevokedByDevice),
-		CurrentDevice: o.CurrentDevice,
+		CurrentDevice:        o.CurrentDevice,
+		DelegationSeqno:      o.DelegationSeqno.DeepCopy(),
+		DelegationSigningKID: o.DelegationSigningKID.DeepCopy(),
+		RevocationSeqno:      o.RevocationSeqno.DeepCopy(),
 	}
 }
 
@@ -9,6 +9,26 @@ import (
 	context "golang.org/x/net/context"
 )
 
+type GregorJournalEvent struct {
+	Kind     string `codec:"kind" json:"kind"`
+	System   string `codec:"system" json:"system"`
+	Category string `codec:"category" json:"category"`
+	MsgID    string `codec:"msgID" json:"msgID"`
+	Ctime    Time   `codec:"ctime" json:"ctime"`
+	Body     string `codec:"body" json:"body"`
+}
+
+func (o GregorJournalEvent) DeepCopy() GregorJournalEvent {
+	return GregorJournalEvent{
+		Kind:     o.Kind,
+		System:   o.System,
+		Category: o.Category,
+		MsgID:    o.MsgID,
+		Ctime:    o.Ctime.DeepCopy(),
+		Body:     o.Body,
+	}
+}
+
 type GetStateArg struct {
 }
 
@@ -39,8 +59,13 @@ type UpdateCategoryArg struct {
 	Dtime    gregor1.TimeOrOffset `codec:"dtime" json:"dtime"`
 }
 
+type ReplayJournalArg struct {
+	Since Time `codec:"since" json:"since"`
+}
+
 type GregorInterface interface {
 	GetState(context.Context) (gregor1.State, error)
+	ReplayJournal(context.Context, Time) ([]GregorJournalEvent, error)
 	InjectItem(context.Context, InjectItemArg) (gregor1.MsgID, error)
 	DismissCategory(context.Context, gregor1.Category) error
 	DismissItem(context.Context, gregor1.MsgID) error
@@ -63,6 +88,22 @@ func GregorProtocol(i GregorInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"replayJournal": {
+				MakeArg: func() interface{} {
+					ret := make([]ReplayJournalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ReplayJournalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ReplayJournalArg)(nil), args)
+						return
+					}
+					ret, err = i.ReplayJournal(ctx, (*typedArgs)[0].Since)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 			"injectItem": {
 				MakeArg: func() interface{} {
 					ret := make([]InjectItemArg, 1)
@@ -156,6 +197,12 @@ func (c GregorClient) GetState(ctx context.Context) (res gregor1.State, err erro
 	return
 }
 
+func (c GregorClient) ReplayJournal(ctx context.Context, since Time) (res []GregorJournalEvent, err error) {
+	__arg := ReplayJournalArg{Since: since}
+	err = c.Cli.Call(ctx, "keybase.1.gregor.replayJournal", []interface{}{__arg}, &res)
+	return
+}
+
 func (c GregorClient) InjectItem(ctx context.Context, __arg InjectItemArg) (res gregor1.MsgID, err error) {
 	err = c.Cli.Call(ctx, "keybase.1.gregor.injectItem", []interface{}{__arg}, &res)
 	return
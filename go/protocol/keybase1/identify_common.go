@@ -156,6 +156,9 @@ type TrackOptions struct {
 	ExpiringLocal bool        `codec:"expiringLocal" json:"expiringLocal"`
 	ForPGPPull    bool        `codec:"forPGPPull" json:"forPGPPull"`
 	SigVersion    *SigVersion `codec:"sigVersion,omitempty" json:"sigVersion,omitempty"`
+	// Proof table keys (service names / protocols) deliberately left
+	// out of the track statement; their later changes won't warn.
+	ExcludeProofTypes []string `codec:"excludeProofTypes" json:"excludeProofTypes"`
 }
 
 func (o TrackOptions) DeepCopy() TrackOptions {
@@ -165,6 +168,14 @@ func (o TrackOptions) DeepCopy() TrackOptions {
 		ForceRetrack:  o.ForceRetrack,
 		ExpiringLocal: o.ExpiringLocal,
 		ForPGPPull:    o.ForPGPPull,
+		ExcludeProofTypes: (func(x []string) []string {
+			if x == nil {
+				return nil
+			}
+			ret := make([]string, len(x))
+			copy(ret, x)
+			return ret
+		})(o.ExcludeProofTypes),
 		SigVersion: (func(x *SigVersion) *SigVersion {
 			if x == nil {
 				return nil
@@ -8,14 +8,57 @@ import (
 	context "golang.org/x/net/context"
 )
 
+type NetworkStatsEntry struct {
+	Endpoint string `codec:"endpoint" json:"endpoint"`
+	Count    int    `codec:"count" json:"count"`
+	BytesIn  int64  `codec:"bytesIn" json:"bytesIn"`
+	TotalMs  int64  `codec:"totalMs" json:"totalMs"`
+}
+
+func (o NetworkStatsEntry) DeepCopy() NetworkStatsEntry {
+	return NetworkStatsEntry{
+		Endpoint: o.Endpoint,
+		Count:    o.Count,
+		BytesIn:  o.BytesIn,
+		TotalMs:  o.TotalMs,
+	}
+}
+
 type RegisterLoggerArg struct {
 	SessionID int      `codec:"sessionID" json:"sessionID"`
 	Name      string   `codec:"name" json:"name"`
 	Level     LogLevel `codec:"level" json:"level"`
 }
 
+type NetworkStatsArg struct {
+}
+
+type SetLevelArg struct {
+	Module string `codec:"module" json:"module"`
+	Level  string `codec:"level" json:"level"`
+}
+
+type DiagnosticsBundleRes struct {
+	Path string `codec:"path" json:"path"`
+	Key  string `codec:"key" json:"key"`
+}
+
+func (o DiagnosticsBundleRes) DeepCopy() DiagnosticsBundleRes {
+	return DiagnosticsBundleRes{
+		Path: o.Path,
+		Key:  o.Key,
+	}
+}
+
+type CreateDiagnosticsBundleArg struct {
+	Outfile string `codec:"outfile" json:"outfile"`
+}
+
 type LogInterface interface {
 	RegisterLogger(context.Context, RegisterLoggerArg) error
+	NetworkStats(context.Context) ([]NetworkStatsEntry, error)
+	SetLevel(context.Context, SetLevelArg) error
+	CreateDiagnosticsBundle(context.Context, string) (DiagnosticsBundleRes, error)
 }
 
 func LogProtocol(i LogInterface) rpc.Protocol {
@@ -38,6 +81,49 @@ func LogProtocol(i LogInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"networkStats": {
+				MakeArg: func() interface{} {
+					ret := make([]NetworkStatsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					ret, err = i.NetworkStats(ctx)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"setLevel": {
+				MakeArg: func() interface{} {
+					ret := make([]SetLevelArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SetLevelArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SetLevelArg)(nil), args)
+						return
+					}
+					err = i.SetLevel(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"createDiagnosticsBundle": {
+				MakeArg: func() interface{} {
+					ret := make([]CreateDiagnosticsBundleArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]CreateDiagnosticsBundleArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]CreateDiagnosticsBundleArg)(nil), args)
+						return
+					}
+					ret, err = i.CreateDiagnosticsBundle(ctx, (*typedArgs)[0].Outfile)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 		},
 	}
 }
@@ -50,3 +136,19 @@ func (c LogClient) RegisterLogger(ctx context.Context, __arg RegisterLoggerArg)
 	err = c.Cli.Call(ctx, "keybase.1.log.registerLogger", []interface{}{__arg}, nil)
 	return
 }
+
+func (c LogClient) NetworkStats(ctx context.Context) (res []NetworkStatsEntry, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.log.networkStats", []interface{}{NetworkStatsArg{}}, &res)
+	return
+}
+
+func (c LogClient) SetLevel(ctx context.Context, __arg SetLevelArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.log.setLevel", []interface{}{__arg}, nil)
+	return
+}
+
+func (c LogClient) CreateDiagnosticsBundle(ctx context.Context, outfile string) (res DiagnosticsBundleRes, err error) {
+	__arg := CreateDiagnosticsBundleArg{Outfile: outfile}
+	err = c.Cli.Call(ctx, "keybase.1.log.createDiagnosticsBundle", []interface{}{__arg}, &res)
+	return
+}
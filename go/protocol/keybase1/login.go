@@ -73,6 +73,15 @@ type UnlockArg struct {
 	SessionID int `codec:"sessionID" json:"sessionID"`
 }
 
+type LockSessionArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type BotProvisionArg struct {
+	SessionID    int      `codec:"sessionID" json:"sessionID"`
+	Capabilities []string `codec:"capabilities" json:"capabilities"`
+}
+
 type UnlockWithPassphraseArg struct {
 	SessionID  int    `codec:"sessionID" json:"sessionID"`
 	Passphrase string `codec:"passphrase" json:"passphrase"`
@@ -124,6 +133,13 @@ type LoginInterface interface {
 	PaperKeySubmit(context.Context, PaperKeySubmitArg) error
 	// Unlock restores access to local key store by priming passphrase stream cache.
 	Unlock(context.Context, int) error
+	// Drop in-memory secret key material without logging out; the next
+	// operation that needs a key prompts for the passphrase (see also
+	// service.auto_lock_timeout).
+	LockSession(context.Context, int) error
+	// Mint a capability-scoped paper-style key for an automation bot;
+	// returns the paper phrase, the bot's whole credential.
+	BotProvision(context.Context, BotProvisionArg) (string, error)
 	UnlockWithPassphrase(context.Context, UnlockWithPassphraseArg) error
 	// accountDelete is for devel/testing to delete the current user's account.
 	AccountDelete(context.Context, int) error
@@ -313,6 +329,38 @@ func LoginProtocol(i LoginInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"lockSession": {
+				MakeArg: func() interface{} {
+					ret := make([]LockSessionArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]LockSessionArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]LockSessionArg)(nil), args)
+						return
+					}
+					err = i.LockSession(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"botProvision": {
+				MakeArg: func() interface{} {
+					ret := make([]BotProvisionArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]BotProvisionArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]BotProvisionArg)(nil), args)
+						return
+					}
+					ret, err = i.BotProvision(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 			"unlockWithPassphrase": {
 				MakeArg: func() interface{} {
 					ret := make([]UnlockWithPassphraseArg, 1)
@@ -454,6 +502,22 @@ func (c LoginClient) Unlock(ctx context.Context, sessionID int) (err error) {
 	return
 }
 
+// Drop in-memory secret key material without logging out; the next
+// operation that needs a key prompts for the passphrase (see also
+// service.auto_lock_timeout).
+func (c LoginClient) LockSession(ctx context.Context, sessionID int) (err error) {
+	__arg := LockSessionArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.login.lockSession", []interface{}{__arg}, nil)
+	return
+}
+
+// Mint a capability-scoped paper-style key for an automation bot;
+// returns the paper phrase, the bot's whole credential.
+func (c LoginClient) BotProvision(ctx context.Context, __arg BotProvisionArg) (res string, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.login.botProvision", []interface{}{__arg}, &res)
+	return
+}
+
 func (c LoginClient) UnlockWithPassphrase(ctx context.Context, __arg UnlockWithPassphraseArg) (err error) {
 	err = c.Cli.Call(ctx, "keybase.1.login.unlockWithPassphrase", []interface{}{__arg}, nil)
 	return
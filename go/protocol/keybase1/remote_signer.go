@@ -0,0 +1,86 @@
+// Auto-generated by avdl-compiler v1.3.24 (https://github.com/keybase/node-avdl-compiler)
+//   Input file: avdl/keybase1/remote_signer.avdl
+
+package keybase1
+
+import (
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+	context "golang.org/x/net/context"
+)
+
+type RemoteSignRequest struct {
+	Payload    []byte `codec:"payload" json:"payload"`
+	SigningKID KID    `codec:"signingKID" json:"signingKID"`
+	Reason     string `codec:"reason" json:"reason"`
+}
+
+func (o RemoteSignRequest) DeepCopy() RemoteSignRequest {
+	return RemoteSignRequest{
+		Payload: (func(x []byte) []byte {
+			if x == nil {
+				return nil
+			}
+			return append([]byte{}, x...)
+		})(o.Payload),
+		SigningKID: o.SigningKID.DeepCopy(),
+		Reason:     o.Reason,
+	}
+}
+
+type RemoteSignResult struct {
+	Sig   string `codec:"sig" json:"sig"`
+	SigID SigID  `codec:"sigID" json:"sigID"`
+}
+
+func (o RemoteSignResult) DeepCopy() RemoteSignResult {
+	return RemoteSignResult{
+		Sig:   o.Sig,
+		SigID: o.SigID.DeepCopy(),
+	}
+}
+
+type RemoteSignArg struct {
+	SessionID int               `codec:"sessionID" json:"sessionID"`
+	Req       RemoteSignRequest `codec:"req" json:"req"`
+}
+
+type RemoteSignerInterface interface {
+	// Sign a sigchain payload on behalf of a keyless client. The signer
+	// side confirms (or is configured to allow) each request.
+	RemoteSign(context.Context, RemoteSignArg) (RemoteSignResult, error)
+}
+
+func RemoteSignerProtocol(i RemoteSignerInterface) rpc.Protocol {
+	return rpc.Protocol{
+		Name: "keybase.1.remoteSigner",
+		Methods: map[string]rpc.ServeHandlerDescription{
+			"remoteSign": {
+				MakeArg: func() interface{} {
+					ret := make([]RemoteSignArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]RemoteSignArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]RemoteSignArg)(nil), args)
+						return
+					}
+					ret, err = i.RemoteSign(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+		},
+	}
+}
+
+type RemoteSignerClient struct {
+	Cli rpc.GenericClient
+}
+
+// Sign a sigchain payload on behalf of a keyless client. The signer
+// side confirms (or is configured to allow) each request.
+func (c RemoteSignerClient) RemoteSign(ctx context.Context, __arg RemoteSignArg) (res RemoteSignResult, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.remoteSigner.remoteSign", []interface{}{__arg}, &res)
+	return
+}
@@ -48,6 +48,9 @@ type SaltpackEncryptOptions struct {
 	Binary                    bool             `codec:"binary" json:"binary"`
 	SaltpackVersion           int              `codec:"saltpackVersion" json:"saltpackVersion"`
 	UseKBFSKeysOnlyForTesting bool             `codec:"useKBFSKeysOnlyForTesting" json:"useKBFSKeysOnlyForTesting"`
+	// Encrypt to recipients' current ephemeral keys; the ciphertext
+	// becomes undecryptable when those keys expire.
+	UseExplodingKeys bool `codec:"useExplodingKeys" json:"useExplodingKeys"`
 }
 
 func (o SaltpackEncryptOptions) DeepCopy() SaltpackEncryptOptions {
@@ -82,6 +85,7 @@ func (o SaltpackEncryptOptions) DeepCopy() SaltpackEncryptOptions {
 		Binary:                    o.Binary,
 		SaltpackVersion:           o.SaltpackVersion,
 		UseKBFSKeysOnlyForTesting: o.UseKBFSKeysOnlyForTesting,
+		UseExplodingKeys:          o.UseExplodingKeys,
 	}
 }
 
@@ -116,11 +120,15 @@ func (o SaltpackSignOptions) DeepCopy() SaltpackSignOptions {
 type SaltpackVerifyOptions struct {
 	SignedBy  string `codec:"signedBy" json:"signedBy"`
 	Signature []byte `codec:"signature" json:"signature"`
+	// Claimed signing time: when set, a since-revoked signing key that
+	// was valid at this time verifies with a warning instead of failing.
+	SignedAtTime Time `codec:"signedAtTime" json:"signedAtTime"`
 }
 
 func (o SaltpackVerifyOptions) DeepCopy() SaltpackVerifyOptions {
 	return SaltpackVerifyOptions{
-		SignedBy: o.SignedBy,
+		SignedBy:     o.SignedBy,
+		SignedAtTime: o.SignedAtTime.DeepCopy(),
 		Signature: (func(x []byte) []byte {
 			if x == nil {
 				return nil
@@ -19,19 +19,23 @@ const (
 	SaltpackSenderType_SELF           SaltpackSenderType = 5
 	SaltpackSenderType_REVOKED        SaltpackSenderType = 6
 	SaltpackSenderType_EXPIRED        SaltpackSenderType = 7
+	// The signing key has been revoked, but was valid at the claimed
+	// signing time.
+	SaltpackSenderType_REVOKED_BUT_VALID_AT_SIGNING SaltpackSenderType = 8
 )
 
 func (o SaltpackSenderType) DeepCopy() SaltpackSenderType { return o }
 
 var SaltpackSenderTypeMap = map[string]SaltpackSenderType{
-	"NOT_TRACKED":    0,
-	"UNKNOWN":        1,
-	"ANONYMOUS":      2,
-	"TRACKING_BROKE": 3,
-	"TRACKING_OK":    4,
-	"SELF":           5,
-	"REVOKED":        6,
-	"EXPIRED":        7,
+	"NOT_TRACKED":                  0,
+	"UNKNOWN":                      1,
+	"ANONYMOUS":                    2,
+	"TRACKING_BROKE":               3,
+	"TRACKING_OK":                  4,
+	"SELF":                         5,
+	"REVOKED":                      6,
+	"EXPIRED":                      7,
+	"REVOKED_BUT_VALID_AT_SIGNING": 8,
 }
 
 var SaltpackSenderTypeRevMap = map[SaltpackSenderType]string{
@@ -43,6 +47,7 @@ var SaltpackSenderTypeRevMap = map[SaltpackSenderType]string{
 	5: "SELF",
 	6: "REVOKED",
 	7: "EXPIRED",
+	8: "REVOKED_BUT_VALID_AT_SIGNING",
 }
 
 func (e SaltpackSenderType) String() string {
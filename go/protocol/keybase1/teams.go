@@ -2723,6 +2723,78 @@ type TeamRotateKeyArg struct {
 	TeamID TeamID `codec:"teamID" json:"teamID"`
 }
 
+type TeamInviteLinkInfo struct {
+	Handle        string   `codec:"handle" json:"handle"`
+	Role          TeamRole `codec:"role" json:"role"`
+	MaxUses       int      `codec:"maxUses" json:"maxUses"`
+	RemainingUses int      `codec:"remainingUses" json:"remainingUses"`
+	ExpiresAt     Time     `codec:"expiresAt" json:"expiresAt"`
+	JoinedVia     []string `codec:"joinedVia" json:"joinedVia"`
+}
+
+func (o TeamInviteLinkInfo) DeepCopy() TeamInviteLinkInfo {
+	return TeamInviteLinkInfo{
+		Handle:        o.Handle,
+		Role:          o.Role.DeepCopy(),
+		MaxUses:       o.MaxUses,
+		RemainingUses: o.RemainingUses,
+		ExpiresAt:     o.ExpiresAt.DeepCopy(),
+		JoinedVia: (func(x []string) []string {
+			if x == nil {
+				return nil
+			}
+			ret := make([]string, len(x))
+			for i, v := range x {
+				vCopy := v
+				ret[i] = vCopy
+			}
+			return ret
+		})(o.JoinedVia),
+	}
+}
+
+type TeamCreateInviteLinkArg struct {
+	SessionID int      `codec:"sessionID" json:"sessionID"`
+	Name      string   `codec:"name" json:"name"`
+	Handle    string   `codec:"handle" json:"handle"`
+	Role      TeamRole `codec:"role" json:"role"`
+	MaxUses   int      `codec:"maxUses" json:"maxUses"`
+	ExpiresAt Time     `codec:"expiresAt" json:"expiresAt"`
+}
+
+type TeamListInviteLinksArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Name      string `codec:"name" json:"name"`
+}
+
+type TeamRevokeInviteLinkArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Name      string `codec:"name" json:"name"`
+	Handle    string `codec:"handle" json:"handle"`
+}
+
+type PendingRoleChangeInfo struct {
+	Team       string   `codec:"team" json:"team"`
+	Username   string   `codec:"username" json:"username"`
+	Role       TeamRole `codec:"role" json:"role"`
+	ProposedBy string   `codec:"proposedBy" json:"proposedBy"`
+	ProposedAt Time     `codec:"proposedAt" json:"proposedAt"`
+}
+
+func (o PendingRoleChangeInfo) DeepCopy() PendingRoleChangeInfo {
+	return PendingRoleChangeInfo{
+		Team:       o.Team,
+		Username:   o.Username,
+		Role:       o.Role.DeepCopy(),
+		ProposedBy: o.ProposedBy,
+		ProposedAt: o.ProposedAt.DeepCopy(),
+	}
+}
+
+type TeamListPendingRoleChangesArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
 type TeamDebugArg struct {
 	TeamID TeamID `codec:"teamID" json:"teamID"`
 }
@@ -2817,6 +2889,13 @@ type TeamsInterface interface {
 	SetTeamMemberShowcase(context.Context, SetTeamMemberShowcaseArg) error
 	CanUserPerform(context.Context, string) (TeamOperation, error)
 	TeamRotateKey(context.Context, TeamID) error
+	// Multi-use invite links backed by batches of seitan tokens.
+	TeamCreateInviteLink(context.Context, TeamCreateInviteLinkArg) (TeamInviteLinkInfo, error)
+	TeamListInviteLinks(context.Context, TeamListInviteLinksArg) ([]TeamInviteLinkInfo, error)
+	TeamRevokeInviteLink(context.Context, TeamRevokeInviteLinkArg) ([]string, error)
+	// Role changes recorded under the two-admin rule, awaiting a second
+	// admin's approval on this service instance.
+	TeamListPendingRoleChanges(context.Context, int) ([]PendingRoleChangeInfo, error)
 	TeamDebug(context.Context, TeamID) (TeamDebugRes, error)
 	GetTarsDisabled(context.Context, string) (bool, error)
 	SetTarsDisabled(context.Context, SetTarsDisabledArg) error
@@ -3451,6 +3530,70 @@ func TeamsProtocol(i TeamsInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"teamCreateInviteLink": {
+				MakeArg: func() interface{} {
+					ret := make([]TeamCreateInviteLinkArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]TeamCreateInviteLinkArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]TeamCreateInviteLinkArg)(nil), args)
+						return
+					}
+					ret, err = i.TeamCreateInviteLink(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"teamListInviteLinks": {
+				MakeArg: func() interface{} {
+					ret := make([]TeamListInviteLinksArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]TeamListInviteLinksArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]TeamListInviteLinksArg)(nil), args)
+						return
+					}
+					ret, err = i.TeamListInviteLinks(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"teamRevokeInviteLink": {
+				MakeArg: func() interface{} {
+					ret := make([]TeamRevokeInviteLinkArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]TeamRevokeInviteLinkArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]TeamRevokeInviteLinkArg)(nil), args)
+						return
+					}
+					ret, err = i.TeamRevokeInviteLink(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"teamListPendingRoleChanges": {
+				MakeArg: func() interface{} {
+					ret := make([]TeamListPendingRoleChangesArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]TeamListPendingRoleChangesArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]TeamListPendingRoleChangesArg)(nil), args)
+						return
+					}
+					ret, err = i.TeamListPendingRoleChanges(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 			"teamDebug": {
 				MakeArg: func() interface{} {
 					ret := make([]TeamDebugArg, 1)
@@ -3801,6 +3944,30 @@ func (c TeamsClient) TeamRotateKey(ctx context.Context, teamID TeamID) (err erro
 	return
 }
 
+// Multi-use invite links backed by batches of seitan tokens.
+func (c TeamsClient) TeamCreateInviteLink(ctx context.Context, __arg TeamCreateInviteLinkArg) (res TeamInviteLinkInfo, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.teams.teamCreateInviteLink", []interface{}{__arg}, &res)
+	return
+}
+
+func (c TeamsClient) TeamListInviteLinks(ctx context.Context, __arg TeamListInviteLinksArg) (res []TeamInviteLinkInfo, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.teams.teamListInviteLinks", []interface{}{__arg}, &res)
+	return
+}
+
+func (c TeamsClient) TeamRevokeInviteLink(ctx context.Context, __arg TeamRevokeInviteLinkArg) (res []string, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.teams.teamRevokeInviteLink", []interface{}{__arg}, &res)
+	return
+}
+
+// Role changes recorded under the two-admin rule, awaiting a second
+// admin's approval on this service instance.
+func (c TeamsClient) TeamListPendingRoleChanges(ctx context.Context, sessionID int) (res []PendingRoleChangeInfo, err error) {
+	__arg := TeamListPendingRoleChangesArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.teams.teamListPendingRoleChanges", []interface{}{__arg}, &res)
+	return
+}
+
 func (c TeamsClient) TeamDebug(ctx context.Context, teamID TeamID) (res TeamDebugRes, err error) {
 	__arg := TeamDebugArg{TeamID: teamID}
 	err = c.Cli.Call(ctx, "keybase.1.teams.teamDebug", []interface{}{__arg}, &res)
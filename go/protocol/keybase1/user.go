@@ -393,6 +393,30 @@ type FindNextMerkleRootAfterResetArg struct {
 	Prev       ResetMerkleRoot `codec:"prev" json:"prev"`
 }
 
+type UPAKStats struct {
+	MemHits      int `codec:"memHits" json:"memHits"`
+	DiskHits     int `codec:"diskHits" json:"diskHits"`
+	Misses       int `codec:"misses" json:"misses"`
+	StaleLoads   int `codec:"staleLoads" json:"staleLoads"`
+	MemEvictions int `codec:"memEvictions" json:"memEvictions"`
+	DiskDeletes  int `codec:"diskDeletes" json:"diskDeletes"`
+}
+
+func (o UPAKStats) DeepCopy() UPAKStats {
+	return UPAKStats{
+		MemHits:      o.MemHits,
+		DiskHits:     o.DiskHits,
+		Misses:       o.Misses,
+		StaleLoads:   o.StaleLoads,
+		MemEvictions: o.MemEvictions,
+		DiskDeletes:  o.DiskDeletes,
+	}
+}
+
+type GetUPAKStatsArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
 type UserInterface interface {
 	ListTrackers(context.Context, ListTrackersArg) ([]Tracker, error)
 	ListTrackersByName(context.Context, ListTrackersByNameArg) ([]Tracker, error)
@@ -439,6 +463,7 @@ type UserInterface interface {
 	// at resetSeqno. You should pass it prev, which was the last known Merkle root at the time of
 	// the reset. Usually, we'll just turn up the next Merkle root, but not always.
 	FindNextMerkleRootAfterReset(context.Context, FindNextMerkleRootAfterResetArg) (NextMerkleRootRes, error)
+	GetUPAKStats(context.Context, int) (UPAKStats, error)
 }
 
 func UserProtocol(i UserInterface) rpc.Protocol {
@@ -813,6 +838,22 @@ func UserProtocol(i UserInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"getUPAKStats": {
+				MakeArg: func() interface{} {
+					ret := make([]GetUPAKStatsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]GetUPAKStatsArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]GetUPAKStatsArg)(nil), args)
+						return
+					}
+					ret, err = i.GetUPAKStats(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 		},
 	}
 }
@@ -962,3 +1003,9 @@ func (c UserClient) FindNextMerkleRootAfterReset(ctx context.Context, __arg Find
 	err = c.Cli.Call(ctx, "keybase.1.user.findNextMerkleRootAfterReset", []interface{}{__arg}, &res)
 	return
 }
+
+func (c UserClient) GetUPAKStats(ctx context.Context, sessionID int) (res UPAKStats, err error) {
+	__arg := GetUPAKStatsArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.user.getUPAKStats", []interface{}{__arg}, &res)
+	return
+}
@@ -154,20 +154,23 @@ func (o BundleSecretV1) DeepCopy() BundleSecretV1 {
 type AccountMode int
 
 const (
-	AccountMode_NONE AccountMode = 0
-	AccountMode_USER AccountMode = 1
+	AccountMode_NONE  AccountMode = 0
+	AccountMode_USER  AccountMode = 1
+	AccountMode_WATCH AccountMode = 2
 )
 
 func (o AccountMode) DeepCopy() AccountMode { return o }
 
 var AccountModeMap = map[string]AccountMode{
-	"NONE": 0,
-	"USER": 1,
+	"NONE":  0,
+	"USER":  1,
+	"WATCH": 2,
 }
 
 var AccountModeRevMap = map[AccountMode]string{
 	0: "NONE",
 	1: "USER",
+	2: "WATCH",
 }
 
 func (e AccountMode) String() string {
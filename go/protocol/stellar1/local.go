@@ -560,6 +560,7 @@ func (o PaymentCLILocal) DeepCopy() PaymentCLILocal {
 type OwnAccountCLILocal struct {
 	AccountID    AccountID            `codec:"accountID" json:"accountID"`
 	IsPrimary    bool                 `codec:"isPrimary" json:"isPrimary"`
+	IsWatchOnly  bool                 `codec:"isWatchOnly" json:"isWatchOnly"`
 	Name         string               `codec:"name" json:"name"`
 	Balance      []Balance            `codec:"balance" json:"balance"`
 	ExchangeRate *OutsideExchangeRate `codec:"exchangeRate,omitempty" json:"exchangeRate,omitempty"`
@@ -567,9 +568,10 @@ type OwnAccountCLILocal struct {
 
 func (o OwnAccountCLILocal) DeepCopy() OwnAccountCLILocal {
 	return OwnAccountCLILocal{
-		AccountID: o.AccountID.DeepCopy(),
-		IsPrimary: o.IsPrimary,
-		Name:      o.Name,
+		AccountID:   o.AccountID.DeepCopy(),
+		IsPrimary:   o.IsPrimary,
+		IsWatchOnly: o.IsWatchOnly,
+		Name:        o.Name,
 		Balance: (func(x []Balance) []Balance {
 			if x == nil {
 				return nil
@@ -796,6 +798,60 @@ type PaymentDetailCLILocalArg struct {
 type WalletInitLocalArg struct {
 }
 
+type CancelPendingCLILocalArg struct {
+	To     string `codec:"to" json:"to"`
+	Amount string `codec:"amount" json:"amount"`
+}
+
+type WatchAccountLocalArg struct {
+	AccountID AccountID `codec:"accountID" json:"accountID"`
+	Name      string    `codec:"name" json:"name"`
+}
+
+type AddTrustlineLocalArg struct {
+	AccountID AccountID `codec:"accountID" json:"accountID"`
+	Code      string    `codec:"code" json:"code"`
+	Issuer    string    `codec:"issuer" json:"issuer"`
+	Limit     string    `codec:"limit" json:"limit"`
+}
+
+type RemoveTrustlineLocalArg struct {
+	AccountID AccountID `codec:"accountID" json:"accountID"`
+	Code      string    `codec:"code" json:"code"`
+	Issuer    string    `codec:"issuer" json:"issuer"`
+}
+
+type SendPathPaymentCLILocalArg struct {
+	FromAccountID AccountID `codec:"fromAccountID" json:"fromAccountID"`
+	Recipient     string    `codec:"recipient" json:"recipient"`
+	SendCode      string    `codec:"sendCode" json:"sendCode"`
+	SendIssuer    string    `codec:"sendIssuer" json:"sendIssuer"`
+	SendMax       string    `codec:"sendMax" json:"sendMax"`
+	DestCode      string    `codec:"destCode" json:"destCode"`
+	DestIssuer    string    `codec:"destIssuer" json:"destIssuer"`
+	DestAmount    string    `codec:"destAmount" json:"destAmount"`
+}
+
+type AssetBalanceLocal struct {
+	Code    string `codec:"code" json:"code"`
+	Issuer  string `codec:"issuer" json:"issuer"`
+	Balance string `codec:"balance" json:"balance"`
+	Limit   string `codec:"limit" json:"limit"`
+}
+
+func (o AssetBalanceLocal) DeepCopy() AssetBalanceLocal {
+	return AssetBalanceLocal{
+		Code:    o.Code,
+		Issuer:  o.Issuer,
+		Balance: o.Balance,
+		Limit:   o.Limit,
+	}
+}
+
+type AssetBalancesLocalArg struct {
+	AccountID AccountID `codec:"accountID" json:"accountID"`
+}
+
 type WalletDumpLocalArg struct {
 }
 
@@ -877,6 +933,12 @@ type LocalInterface interface {
 	RecentPaymentsCLILocal(context.Context, *AccountID) ([]PaymentOrErrorCLILocal, error)
 	PaymentDetailCLILocal(context.Context, string) (PaymentCLILocal, error)
 	WalletInitLocal(context.Context) error
+	CancelPendingCLILocal(context.Context, CancelPendingCLILocalArg) error
+	WatchAccountLocal(context.Context, WatchAccountLocalArg) error
+	AddTrustlineLocal(context.Context, AddTrustlineLocalArg) (string, error)
+	RemoveTrustlineLocal(context.Context, RemoveTrustlineLocalArg) (string, error)
+	SendPathPaymentCLILocal(context.Context, SendPathPaymentCLILocalArg) (string, error)
+	AssetBalancesLocal(context.Context, AccountID) ([]AssetBalanceLocal, error)
 	WalletDumpLocal(context.Context) (Bundle, error)
 	WalletGetAccountsCLILocal(context.Context) ([]OwnAccountCLILocal, error)
 	OwnAccountLocal(context.Context, AccountID) (bool, error)
@@ -1401,6 +1463,102 @@ func LocalProtocol(i LocalInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"cancelPendingCLILocal": {
+				MakeArg: func() interface{} {
+					ret := make([]CancelPendingCLILocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]CancelPendingCLILocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]CancelPendingCLILocalArg)(nil), args)
+						return
+					}
+					err = i.CancelPendingCLILocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"watchAccountLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]WatchAccountLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]WatchAccountLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]WatchAccountLocalArg)(nil), args)
+						return
+					}
+					err = i.WatchAccountLocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"addTrustlineLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]AddTrustlineLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]AddTrustlineLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]AddTrustlineLocalArg)(nil), args)
+						return
+					}
+					ret, err = i.AddTrustlineLocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"removeTrustlineLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]RemoveTrustlineLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]RemoveTrustlineLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]RemoveTrustlineLocalArg)(nil), args)
+						return
+					}
+					ret, err = i.RemoveTrustlineLocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"sendPathPaymentCLILocal": {
+				MakeArg: func() interface{} {
+					ret := make([]SendPathPaymentCLILocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SendPathPaymentCLILocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SendPathPaymentCLILocalArg)(nil), args)
+						return
+					}
+					ret, err = i.SendPathPaymentCLILocal(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"assetBalancesLocal": {
+				MakeArg: func() interface{} {
+					ret := make([]AssetBalancesLocalArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]AssetBalancesLocalArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]AssetBalancesLocalArg)(nil), args)
+						return
+					}
+					ret, err = i.AssetBalancesLocal(ctx, (*typedArgs)[0].AccountID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 			"walletDumpLocal": {
 				MakeArg: func() interface{} {
 					ret := make([]WalletDumpLocalArg, 1)
@@ -1737,6 +1895,37 @@ func (c LocalClient) WalletInitLocal(ctx context.Context) (err error) {
 	return
 }
 
+func (c LocalClient) CancelPendingCLILocal(ctx context.Context, __arg CancelPendingCLILocalArg) (err error) {
+	err = c.Cli.Call(ctx, "stellar.1.local.cancelPendingCLILocal", []interface{}{__arg}, nil)
+	return
+}
+
+func (c LocalClient) WatchAccountLocal(ctx context.Context, __arg WatchAccountLocalArg) (err error) {
+	err = c.Cli.Call(ctx, "stellar.1.local.watchAccountLocal", []interface{}{__arg}, nil)
+	return
+}
+
+func (c LocalClient) AddTrustlineLocal(ctx context.Context, __arg AddTrustlineLocalArg) (res string, err error) {
+	err = c.Cli.Call(ctx, "stellar.1.local.addTrustlineLocal", []interface{}{__arg}, &res)
+	return
+}
+
+func (c LocalClient) RemoveTrustlineLocal(ctx context.Context, __arg RemoveTrustlineLocalArg) (res string, err error) {
+	err = c.Cli.Call(ctx, "stellar.1.local.removeTrustlineLocal", []interface{}{__arg}, &res)
+	return
+}
+
+func (c LocalClient) SendPathPaymentCLILocal(ctx context.Context, __arg SendPathPaymentCLILocalArg) (res string, err error) {
+	err = c.Cli.Call(ctx, "stellar.1.local.sendPathPaymentCLILocal", []interface{}{__arg}, &res)
+	return
+}
+
+func (c LocalClient) AssetBalancesLocal(ctx context.Context, accountID AccountID) (res []AssetBalanceLocal, err error) {
+	__arg := AssetBalancesLocalArg{AccountID: accountID}
+	err = c.Cli.Call(ctx, "stellar.1.local.assetBalancesLocal", []interface{}{__arg}, &res)
+	return
+}
+
 func (c LocalClient) WalletDumpLocal(ctx context.Context) (res Bundle, err error) {
 	err = c.Cli.Call(ctx, "stellar.1.local.walletDumpLocal", []interface{}{WalletDumpLocalArg{}}, &res)
 	return
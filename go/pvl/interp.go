@@ -517,10 +517,19 @@ func runDNSOne(m metaContext, domain string, scripts []scriptT, mknewstate state
 	// Fetch TXT records
 	var txts []string
 	var err error
-	if m.getStubDNS() == nil {
-		txts, err = runDNSTXTQuery(m, domain)
-	} else {
+	switch {
+	case m.getStubDNS() != nil:
 		txts, err = m.getStubDNS().LookupTXT(domain)
+	case dohURL(m) != "":
+		var authenticated bool
+		txts, authenticated, err = runDNSTXTQueryDoH(m, dohURL(m), domain)
+		debug(m, "DoH lookup for %s: dnssec_authenticated=%v", domain, authenticated)
+		if err == nil && !authenticated && dnssecRequired(m) {
+			return libkb.NewProofError(keybase1.ProofStatus_DNS_ERROR,
+				"DNS answer for %s was not DNSSEC-authenticated", domain)
+		}
+	default:
+		txts, err = runDNSTXTQuery(m, domain)
 	}
 
 	if err != nil {
@@ -5,6 +5,7 @@ package saltpackkeys
 
 import (
 	"fmt"
+	"github.com/keybase/client/go/ephemeral"
 
 	"github.com/keybase/client/go/engine"
 	"github.com/keybase/client/go/externals"
@@ -57,9 +58,27 @@ func (e *SaltpackRecipientKeyfinderEngine) Name() string {
 func (e *SaltpackRecipientKeyfinderEngine) Run(m libkb.MetaContext) (err error) {
 	defer m.CTrace("SaltpackRecipientKeyfinder#Run", func() error { return err })()
 
-	err = e.AddOwnKeysIfNeeded(m)
-	if err != nil {
-		return err
+	if e.Arg.UseExplodingKeys {
+		// Exploding: the sender's own copy must die with everyone
+		// else's, so add our current userEK rather than long-term keys.
+		if !e.Arg.NoSelfEncrypt {
+			if !m.ActiveDevice().Valid() {
+				return libkb.NewLoginRequiredError("need to be logged in to encrypt with exploding keys")
+			}
+			upak, _, err := m.G().GetUPAKLoader().LoadV2(
+				libkb.NewLoadUserArgWithMetaContext(m).WithUID(m.ActiveDevice().UID()).WithPublicKeyOptional())
+			if err != nil {
+				return err
+			}
+			if err := e.addUserEphemeralKey(m, &upak.Current); err != nil {
+				return err
+			}
+		}
+	} else {
+		err = e.AddOwnKeysIfNeeded(m)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = e.identifyAndAddRecipients(m)
@@ -152,7 +171,12 @@ func (e *SaltpackRecipientKeyfinderEngine) identifyAndAddUserRecipient(m libkb.M
 	upk, err := e.IdentifyUser(m, u) // For existing users
 	switch {
 	case err == nil:
-		// nothing to do here
+		if e.Arg.UseExplodingKeys {
+			// Exploding encryption goes to the recipient's current
+			// ephemeral key only; no long-term keys are added, so the
+			// ciphertext dies when the EK expires.
+			return e.addUserEphemeralKey(m, upk)
+		}
 	case libkb.IsIdentifyProofError(err):
 		return fmt.Errorf("Cannot encrypt for %v as their account has changed since you last followed them (it might have been compromised!): please review their identity (with `keybase follow %v`) and then try again (err = %v)", u, u, err)
 	case libkb.IsNotFoundError(err) || libkb.IsResolutionError(err):
@@ -197,9 +221,30 @@ func (e *SaltpackRecipientKeyfinderEngine) identifyAndAddUserRecipient(m libkb.M
 	return nil
 }
 
+// addUserEphemeralKey adds the recipient's current userEK as the sole
+// receiver key for this user.
+func (e *SaltpackRecipientKeyfinderEngine) addUserEphemeralKey(m libkb.MetaContext, upk *keybase1.UserPlusKeysV2) error {
+	metadata, err := ephemeral.FetchLatestUserEKMetadata(m.Ctx(), m.G(), upk.Uid)
+	if err != nil {
+		return fmt.Errorf("error fetching ephemeral key for %v: %s", upk.Username, err)
+	}
+	if metadata == nil {
+		return libkb.NewRecipientNotFoundError(fmt.Sprintf(
+			"cannot encrypt with exploding keys for %v: they have no active ephemeral key", upk.Username))
+	}
+	m.CDebugf("adding user %v's ephemeral key (generation %d)", upk.Username, metadata.Generation)
+	e.RecipientEntityKeyMap[upk.Uid.AsUserOrTeam()] = append(
+		e.RecipientEntityKeyMap[upk.Uid.AsUserOrTeam()], metadata.Kid)
+	return nil
+}
+
 func (e *SaltpackRecipientKeyfinderEngine) lookupAndAddTeam(m libkb.MetaContext, teamName string) error {
+	// Force a repoll so a key rotation that just happened (say, after
+	// kicking a member out) can't leave us encrypting to a cached,
+	// superseded per-team key that the removed member still holds.
 	team, err := teams.Load(m.Ctx(), m.G(), keybase1.LoadTeamArg{
-		Name: teamName,
+		Name:        teamName,
+		ForceRepoll: true,
 	})
 	if err != nil {
 		return teams.FixupTeamGetError(m.Ctx(), m.G(), err, teamName, false /* public bool: this might not be true, but the message is less specific for private teams */)
@@ -6,10 +6,13 @@ package service
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/keybase/client/go/engine"
+	"github.com/keybase/client/go/git"
 	"github.com/keybase/client/go/libkb"
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/client/go/teams"
 	"github.com/keybase/go-framed-msgpack-rpc/rpc"
 	"golang.org/x/net/context"
 )
@@ -164,3 +167,111 @@ func (h *AccountHandler) SetLockdownMode(ctx context.Context, arg keybase1.SetLo
 	_, err = h.G().API.Post(apiArg)
 	return err
 }
+
+// SchedulePerUserKeyRotation persists the user's per-user-key rotation
+// policy; the PerUserKeyRollScheduledBackground task enforces it.
+func (h *AccountHandler) SchedulePerUserKeyRotation(ctx context.Context, arg keybase1.SchedulePerUserKeyRotationArg) error {
+	writer := h.G().Env.GetConfigWriter()
+	if writer == nil {
+		return errors.New("no config writer available")
+	}
+	interval := time.Duration(arg.RotationIntervalSeconds) * time.Second
+	if err := writer.SetStringAtPath("puk.rotation_interval", interval.String()); err != nil {
+		return err
+	}
+	return writer.SetIntAtPath("puk.rotate_after_revokes", arg.RotateAfterRevokes)
+}
+
+// ApproveHighLink records a second-device approval for a high sigchain
+// link; the KEX/gregor plumbing (or a trusted local client) calls this
+// on the device about to post.
+func (h *AccountHandler) ApproveHighLink(ctx context.Context, arg keybase1.ApproveHighLinkArg) error {
+	if arg.Operation != libkb.HighLinkOperationSibkey && arg.Operation != libkb.HighLinkOperationPerUserKey {
+		return fmt.Errorf("unknown high link operation %q", arg.Operation)
+	}
+	m := libkb.NewMetaContext(ctx, h.G())
+	return libkb.RecordHighLinkApproval(m, arg.Operation)
+}
+
+// ListProvisionedUsers names the accounts switchable on this device
+// without a passphrase: provisioned, with a secret in the store.
+func (h *AccountHandler) ListProvisionedUsers(ctx context.Context, sessionID int) ([]string, error) {
+	return h.G().GetUsersWithStoredSecrets(ctx)
+}
+
+// SwitchAccount changes the active account to another provisioned
+// user using the stored device secret; no passphrase prompt, and no
+// teardown beyond what logout already does. Per-account caches are
+// uid-keyed in the local db, so the target's caches are warm.
+func (h *AccountHandler) SwitchAccount(ctx context.Context, arg keybase1.SwitchAccountArg) (err error) {
+	m := libkb.NewMetaContext(ctx, h.G())
+	defer m.CTrace(fmt.Sprintf("SwitchAccount(%s)", arg.Username), func() error { return err })()
+
+	target := libkb.NewNormalizedUsername(arg.Username)
+	if h.G().Env.GetUsername().Eq(target) && h.G().ActiveDevice.Valid() {
+		return nil
+	}
+
+	stored, err := h.G().GetUsersWithStoredSecrets(ctx)
+	if err != nil {
+		return err
+	}
+	var found bool
+	for _, u := range stored {
+		if libkb.NewNormalizedUsername(u).Eq(target) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s has no stored secret on this device; log in once with `keybase login %s`", arg.Username, arg.Username)
+	}
+
+	if err := h.G().Logout(); err != nil {
+		return err
+	}
+	eng := engine.NewLoginProvisionedDevice(h.G(), arg.Username)
+	eng.SecretStoreOnly = true
+	uis := libkb.UIs{SessionID: arg.SessionID}
+	return engine.RunEngine2(m.WithUIs(uis), eng)
+}
+
+// PreviewReset reports what an account reset would cost before the user
+// goes through with it: teams that lapse, followers whose tracking
+// breaks, encrypted git repos that become unreadable, and whether a
+// stellar wallet is attached. Partial failures degrade to partial
+// results rather than failing the whole preview.
+func (h *AccountHandler) PreviewReset(ctx context.Context, sessionID int) (res keybase1.ResetPreview, err error) {
+	m := libkb.NewMetaContext(ctx, h.G())
+	defer m.CTraceTimed("AccountHandler#PreviewReset", func() error { return err })()
+
+	if teamList, terr := teams.ListTeamsUnverified(ctx, h.G(), keybase1.TeamListUnverifiedArg{}); terr != nil {
+		m.CDebugf("PreviewReset: team listing failed: %s", terr)
+	} else {
+		for _, t := range teamList.Teams {
+			res.TeamNames = append(res.TeamNames, t.FqName)
+		}
+	}
+
+	eng := engine.NewListTrackers2(h.G(), keybase1.ListTrackers2Arg{Reverse: true})
+	if terr := engine.RunEngine2(m, eng); terr != nil {
+		m.CDebugf("PreviewReset: follower listing failed: %s", terr)
+	} else {
+		res.FollowerCount = len(eng.GetResults().Users)
+	}
+
+	if repos, gerr := git.GetAllMetadata(ctx, h.G()); gerr != nil {
+		m.CDebugf("PreviewReset: git metadata failed: %s", gerr)
+	} else {
+		res.GitRepoCount = len(repos)
+	}
+
+	uid := h.G().GetMyUID()
+	if !uid.IsNil() {
+		if upak, _, uerr := h.G().GetUPAKLoader().LoadV2(libkb.NewLoadUserArgWithMetaContext(m).WithUID(uid).WithPublicKeyOptional()); uerr == nil {
+			res.HasStellarWallet = upak.Current.StellarAccountID != nil
+		}
+	}
+
+	return res, nil
+}
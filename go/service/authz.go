@@ -0,0 +1,180 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+	"golang.org/x/net/context"
+)
+
+// connAuthz holds the capability state of one local RPC connection.
+// Connections start unrestricted (full account power, the historical
+// behavior); once a capability token is presented the connection is
+// limited to the token's namespaces for its remaining lifetime.
+type connAuthz struct {
+	sync.Mutex
+	restricted bool
+	allow      []string
+}
+
+func newConnAuthz() *connAuthz {
+	return &connAuthz{}
+}
+
+func (a *connAuthz) restrict(allow []string) {
+	a.Lock()
+	defer a.Unlock()
+	if a.restricted {
+		// Re-authorizing narrows to the intersection-by-replacement:
+		// the new token's grants win, never widening back out.
+		a.allow = intersectAllow(a.allow, allow)
+		return
+	}
+	a.restricted = true
+	a.allow = allow
+}
+
+func intersectAllow(old, new []string) (res []string) {
+	for _, n := range new {
+		for _, o := range old {
+			if n == o || methodUnderPrefix(n, o) {
+				res = append(res, n)
+				break
+			} else if methodUnderPrefix(o, n) {
+				res = append(res, o)
+				break
+			}
+		}
+	}
+	return res
+}
+
+func (a *connAuthz) isRestricted() bool {
+	a.Lock()
+	defer a.Unlock()
+	return a.restricted
+}
+
+// methodUnderPrefix reports whether a full RPC method name falls under
+// a dotted namespace prefix ("chat.1.local" covers
+// "chat.1.local.postLocal" but not "chat.1.localforever.x").
+func methodUnderPrefix(method, prefix string) bool {
+	return method == prefix || strings.HasPrefix(method, prefix+".")
+}
+
+// alwaysAllowed are methods any connection may call, restricted or
+// not: presenting a token, and the handshake-ish calls every client
+// library issues before real work.
+var alwaysAllowed = []string{
+	"keybase.1.apiToken.apiTokenAuthorize",
+	"keybase.1.config.getConfig",
+	"keybase.1.config.getCurrentStatus",
+}
+
+func (a *connAuthz) permits(method string) error {
+	a.Lock()
+	defer a.Unlock()
+	if !a.restricted {
+		return nil
+	}
+	for _, m := range alwaysAllowed {
+		if method == m {
+			return nil
+		}
+	}
+	for _, prefix := range a.allow {
+		if methodUnderPrefix(method, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("method %s not covered by this connection's api token", method)
+}
+
+// AuthorizingProtocol wraps every handler of a protocol with the
+// connection's capability check, and marks RPC activity for the
+// auto-lock idle timer. Follows CancellingProtocol's shape.
+func AuthorizingProtocol(authz *connAuthz, touch func(), prot rpc.Protocol) (res rpc.Protocol) {
+	res.Name = prot.Name
+	res.WrapError = prot.WrapError
+	res.Methods = make(map[string]rpc.ServeHandlerDescription)
+	for name, ldesc := range prot.Methods {
+		var newDesc rpc.ServeHandlerDescription
+		desc := ldesc
+		method := prot.Name + "." + name
+		newDesc.MakeArg = desc.MakeArg
+		newDesc.MethodType = desc.MethodType
+		newDesc.Handler = func(ctx context.Context, arg interface{}) (interface{}, error) {
+			touch()
+			if err := authz.permits(method); err != nil {
+				return nil, err
+			}
+			return desc.Handler(ctx, arg)
+		}
+		res.Methods[name] = newDesc
+	}
+	return res
+}
+
+// ApiTokenHandler serves the keybase.1.apiToken protocol for one
+// connection.
+type ApiTokenHandler struct {
+	libkb.Contextified
+	*BaseHandler
+	authz *connAuthz
+}
+
+func NewApiTokenHandler(xp rpc.Transporter, g *libkb.GlobalContext, authz *connAuthz) *ApiTokenHandler {
+	return &ApiTokenHandler{
+		Contextified: libkb.NewContextified(g),
+		BaseHandler:  NewBaseHandler(g, xp),
+		authz:        authz,
+	}
+}
+
+func (h *ApiTokenHandler) checkUnrestricted(op string) error {
+	if h.authz.isRestricted() {
+		return fmt.Errorf("%s requires an unrestricted connection", op)
+	}
+	return nil
+}
+
+func (h *ApiTokenHandler) ApiTokenCreate(ctx context.Context, arg keybase1.ApiTokenCreateArg) (res string, err error) {
+	defer h.G().CTraceTimed(ctx, "ApiTokenCreate", func() error { return err })()
+	if err := h.checkUnrestricted("apiTokenCreate"); err != nil {
+		return "", err
+	}
+	return libkb.CreateApiToken(libkb.NewMetaContext(ctx, h.G()), arg.Label, arg.Allow)
+}
+
+func (h *ApiTokenHandler) ApiTokenList(ctx context.Context) (res []keybase1.ApiTokenInfo, err error) {
+	defer h.G().CTraceTimed(ctx, "ApiTokenList", func() error { return err })()
+	if err := h.checkUnrestricted("apiTokenList"); err != nil {
+		return nil, err
+	}
+	return libkb.ListApiTokens(libkb.NewMetaContext(ctx, h.G()))
+}
+
+func (h *ApiTokenHandler) ApiTokenRevoke(ctx context.Context, label string) (err error) {
+	defer h.G().CTraceTimed(ctx, "ApiTokenRevoke", func() error { return err })()
+	if err := h.checkUnrestricted("apiTokenRevoke"); err != nil {
+		return err
+	}
+	return libkb.RevokeApiToken(libkb.NewMetaContext(ctx, h.G()), label)
+}
+
+func (h *ApiTokenHandler) ApiTokenAuthorize(ctx context.Context, token string) (err error) {
+	defer h.G().CTraceTimed(ctx, "ApiTokenAuthorize", func() error { return err })()
+	allow, err := libkb.CheckApiToken(libkb.NewMetaContext(ctx, h.G()), token)
+	if err != nil {
+		return err
+	}
+	h.authz.restrict(allow)
+	return nil
+}
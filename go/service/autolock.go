@@ -0,0 +1,47 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Auto-lock: when service.auto_lock_timeout is set, the service drops
+// in-memory secrets after that much RPC inactivity, the moral
+// equivalent of a screen lock. Operations arriving afterwards go back
+// through SecretUI for the passphrase (or fail with the batch-mode
+// prompt contract), so nothing is ever silently dropped.
+
+type autoLocker struct {
+	lastActivity int64 // unix nanos, atomically updated
+}
+
+func (a *autoLocker) touch() {
+	atomic.StoreInt64(&a.lastActivity, time.Now().UnixNano())
+}
+
+func (a *autoLocker) idle() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&a.lastActivity)))
+}
+
+// startAutoLock begins the inactivity watcher when configured.
+func (d *Service) startAutoLock() {
+	timeout := d.G().Env.GetAutoLockTimeout()
+	if timeout <= 0 {
+		return
+	}
+	d.autoLock.touch()
+	d.G().Log.Info("auto-lock: locking secrets after %s of inactivity", timeout)
+	go func() {
+		for {
+			time.Sleep(timeout / 4)
+			if d.autoLock.idle() >= timeout && d.G().ActiveDevice.Valid() {
+				d.G().Log.Info("auto-lock: idle for %s; dropping in-memory secrets", timeout)
+				d.G().LockSecrets()
+				d.autoLock.touch()
+			}
+		}
+	}()
+}
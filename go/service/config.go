@@ -5,6 +5,7 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -46,6 +47,14 @@ func (h ConfigHandler) GetCurrentStatus(ctx context.Context, sessionID int) (res
 	return
 }
 
+// GetValueProvenance reports which config layer a path resolves from.
+func (h ConfigHandler) GetValueProvenance(_ context.Context, path string) (string, error) {
+	if cfg, ok := h.G().Env.GetConfig().(*libkb.JSONConfigFile); ok {
+		return cfg.ValueProvenance(path), nil
+	}
+	return "unset", nil
+}
+
 func (h ConfigHandler) GetValue(_ context.Context, path string) (ret keybase1.ConfigValue, err error) {
 	var i interface{}
 	i, err = h.G().Env.GetConfig().GetInterfaceAtPath(path)
@@ -313,3 +322,35 @@ func (h ConfigHandler) SetRememberPassphrase(ctx context.Context, arg keybase1.S
 
 	return nil
 }
+
+// SetTorMode switches Tor mode at runtime: it persists tor.mode and
+// tor.proxy, rebuilds the API clients so new requests go through the
+// SOCKS proxy immediately, and logs any traffic classes that would
+// still leak.
+func (h ConfigHandler) SetTorMode(ctx context.Context, arg keybase1.SetTorModeArg) error {
+	if _, err := libkb.StringToTorMode(arg.Mode); err != nil {
+		return err
+	}
+	writer := h.G().Env.GetConfigWriter()
+	if writer == nil {
+		return errors.New("no config writer available")
+	}
+	if err := writer.SetStringAtPath("tor.mode", arg.Mode); err != nil {
+		return err
+	}
+	if arg.Proxy != "" {
+		if err := writer.SetStringAtPath("tor.proxy", arg.Proxy); err != nil {
+			return err
+		}
+	}
+	if err := h.G().ConfigReload(); err != nil {
+		return err
+	}
+	if err := h.G().ConfigureAPI(); err != nil {
+		return err
+	}
+	for _, leak := range libkb.CheckTorLeaks(h.G()) {
+		h.G().Log.CWarningf(ctx, "Tor leak check: %s", leak)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/keybase/client/go/contacts"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+)
+
+// ContactsHandler serves the keybase.1.contacts protocol.
+type ContactsHandler struct {
+	libkb.Contextified
+	*BaseHandler
+}
+
+func NewContactsHandler(xp rpc.Transporter, g *libkb.GlobalContext) *ContactsHandler {
+	return &ContactsHandler{
+		Contextified: libkb.NewContextified(g),
+		BaseHandler:  NewBaseHandler(g, xp),
+	}
+}
+
+func (h *ContactsHandler) ImportContacts(ctx context.Context, contents string) (resolved int, err error) {
+	defer h.G().CTraceTimed(ctx, "ContactsHandler#ImportContacts", func() error { return err })()
+	resolved, _, err = contacts.Import(libkb.NewMetaContext(ctx, h.G()), []byte(contents))
+	return resolved, err
+}
+
+func (h *ContactsHandler) ListContacts(ctx context.Context) (res []keybase1.ResolvedContact, err error) {
+	defer h.G().CTraceTimed(ctx, "ContactsHandler#ListContacts", func() error { return err })()
+	return contacts.List(libkb.NewMetaContext(ctx, h.G()))
+}
+
+func (h *ContactsHandler) ResolveContact(ctx context.Context, name string) (res string, err error) {
+	defer h.G().CTraceTimed(ctx, "ContactsHandler#ResolveContact", func() error { return err })()
+	return contacts.LookupByName(libkb.NewMetaContext(ctx, h.G()), name)
+}
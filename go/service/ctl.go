@@ -139,3 +139,18 @@ func (c *CtlHandler) DbPut(_ context.Context, arg keybase1.DbPutArg) (err error)
 
 	return nil
 }
+
+func (c *CtlHandler) DbVerify(_ context.Context, sessionID int) (res keybase1.DbVerifyResult, err error) {
+	m := libkb.NewMetaContextBackground(c.G())
+	res, err = libkb.DbVerify(m)
+	if err != nil {
+		return res, err
+	}
+	if res.Deleted > 0 {
+		// We had the deleted records' state in memory too; dropping the
+		// caches makes the next load re-fetch the affected chains.
+		c.G().Log.Debug("Flushing caches after DbVerify deleted %d records", res.Deleted)
+		c.G().FlushCaches()
+	}
+	return res, nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// Diagnostics bundle: one encrypted archive with everything support
+// usually asks for across five commands — recent logs, LocalDb stats,
+// metrics, a goroutine dump, the config with secrets redacted, and
+// the active RPC connection list. The archive is sealed with a fresh
+// secretbox key returned to the caller, so the bundle itself can
+// travel over untrusted channels.
+
+const diagLogTailBytes = 1 << 20
+
+// redactConfigValue decides whether a config key's value is secret.
+func redactConfigKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, needle := range []string{"secret", "token", "session", "salt", "passphrase", "priv", "lks", "tsec"} {
+		if strings.Contains(k, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactConfigTree(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, val := range t {
+			if redactConfigKey(key) {
+				t[key] = "[REDACTED]"
+			} else {
+				t[key] = redactConfigTree(val)
+			}
+		}
+		return t
+	case []interface{}:
+		for i, val := range t {
+			t[i] = redactConfigTree(val)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func (d *Service) diagRedactedConfig() []byte {
+	path := d.G().Env.GetConfigFilename()
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []byte(fmt.Sprintf("config unavailable: %s\n", err))
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return []byte(fmt.Sprintf("config unparsable: %s\n", err))
+	}
+	out, err := json.MarshalIndent(redactConfigTree(tree), "", "  ")
+	if err != nil {
+		return []byte(fmt.Sprintf("config re-marshal failed: %s\n", err))
+	}
+	return out
+}
+
+func (d *Service) diagLogTail() []byte {
+	path := d.G().Env.GetLogFile()
+	if path == "" {
+		return []byte("no log file configured (service logging to stderr)\n")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return []byte(fmt.Sprintf("log file unavailable: %s\n", err))
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return []byte(fmt.Sprintf("log file stat failed: %s\n", err))
+	}
+	offset := info.Size() - diagLogTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil {
+		return []byte(fmt.Sprintf("log file read failed: %s\n", err))
+	}
+	return buf
+}
+
+func (d *Service) diagMetrics() []byte {
+	var b bytes.Buffer
+	for _, sample := range d.G().Metrics.Snapshot() {
+		fmt.Fprintf(&b, "%s %v\n", sample.Name, sample.Value)
+	}
+	for _, e := range d.G().NetworkStats.Report() {
+		fmt.Fprintf(&b, "api %s count=%d bytesIn=%d totalMs=%d\n", e.Endpoint, e.Count, e.BytesIn, e.TotalMs)
+	}
+	return b.Bytes()
+}
+
+func (d *Service) diagDbStats() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "localdb main:  %v bytes (%s)\n", dirSize(d.G().Env.GetDbFilename()), d.G().Env.GetDbFilename())
+	fmt.Fprintf(&b, "localdb chat:  %v bytes (%s)\n", dirSize(d.G().Env.GetChatDbFilename()), d.G().Env.GetChatDbFilename())
+	counts := make(map[libkb.ObjType]int)
+	if err := d.G().LocalDb.ForEachRawKv(func(id libkb.DbKey, _ []byte) error {
+		counts[id.Typ]++
+		return nil
+	}); err != nil {
+		fmt.Fprintf(&b, "key walk failed: %s\n", err)
+	}
+	for typ, n := range counts {
+		fmt.Fprintf(&b, "keys typ=0x%02x count=%d\n", int(typ), n)
+	}
+	return b.Bytes()
+}
+
+func (d *Service) diagConnections() []byte {
+	var b bytes.Buffer
+	for _, det := range d.G().ConnectionManager.ListAllLabeledConnections() {
+		fmt.Fprintf(&b, "pid=%d client=%v version=%s desc=%s\n", det.Pid, det.ClientType, det.Version, det.Desc)
+	}
+	if b.Len() == 0 {
+		fmt.Fprintf(&b, "no labeled connections\n")
+	}
+	return b.Bytes()
+}
+
+func diagGoroutines() []byte {
+	var b bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&b, 2); err != nil {
+		fmt.Fprintf(&b, "goroutine dump failed: %s\n", err)
+	}
+	return b.Bytes()
+}
+
+// createDiagnosticsBundle assembles, compresses and seals the archive.
+// Returns the path written and the hex secretbox key.
+func (d *Service) createDiagnosticsBundle(outfile string) (path, key string, err error) {
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"goroutines.txt", diagGoroutines()},
+		{"metrics.txt", d.diagMetrics()},
+		{"dbstats.txt", d.diagDbStats()},
+		{"config.redacted.json", d.diagRedactedConfig()},
+		{"connections.txt", d.diagConnections()},
+		{"service.log.tail", d.diagLogTail()},
+	}
+
+	var tarBuf bytes.Buffer
+	gz := gzip.NewWriter(&tarBuf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    f.name,
+			Mode:    0600,
+			Size:    int64(len(f.data)),
+			ModTime: now,
+		}); err != nil {
+			return "", "", err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return "", "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", "", err
+	}
+
+	var sbKey [32]byte
+	var nonce [24]byte
+	if _, err := rand.Read(sbKey[:]); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", "", err
+	}
+	sealed := secretbox.Seal(nonce[:], tarBuf.Bytes(), &nonce, &sbKey)
+
+	if outfile == "" {
+		outfile = fmt.Sprintf("keybase-diagnostics-%s.tgz.sealed", now.Format("20060102-150405"))
+	}
+	if err := ioutil.WriteFile(outfile, sealed, 0600); err != nil {
+		return "", "", err
+	}
+	return outfile, hex.EncodeToString(sbKey[:]), nil
+}
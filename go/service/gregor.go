@@ -224,6 +224,8 @@ type gregorHandler struct {
 	broadcastCh chan gregor1.Message
 	replayCh    chan replayThreadArg
 
+	journal *gregorJournal
+
 	// Testing
 	testingEvents       *testingEvents
 	transportForTesting *connTransport
@@ -243,6 +245,7 @@ func newGregorHandler(g *globals.Context) *gregorHandler {
 		forceSessionCheck: false,
 		connectHappened:   make(chan struct{}),
 		replayCh:          make(chan replayThreadArg, 10),
+		journal:           newGregorJournal(g.ExternalG()),
 	}
 	return gh
 }
@@ -909,6 +912,7 @@ func (g *gregorHandler) broadcastMessageOnce(ctx context.Context, m gregor1.Mess
 		}
 
 		g.Debug(ctx, "broadcast: in-band message: msgID: %s Ctime: %s", msgID, ibm.Metadata().CTime())
+		g.journal.Record(m)
 		err = g.handleInBandMessage(ctx, g.GetIncomingClient(), ibm)
 
 		// Send message to local state machine
@@ -926,6 +930,7 @@ func (g *gregorHandler) broadcastMessageOnce(ctx context.Context, m gregor1.Mess
 	if obm != nil {
 		g.Debug(ctx, "broadcast: out-of-band message: uid: %s",
 			m.ToOutOfBandMessage().UID())
+		g.journal.Record(m)
 		if err := g.handleOutOfBandMessage(ctx, obm); err != nil {
 			g.Debug(ctx, "BroadcastMessage: error handling oobm: %s", err.Error())
 			return err
@@ -1881,6 +1886,15 @@ func (g *gregorRPCHandler) GetState(ctx context.Context) (res gregor1.State, err
 	return res, nil
 }
 
+func (g *gregorRPCHandler) ReplayJournal(ctx context.Context, since keybase1.Time) (res []keybase1.GregorJournalEvent, err error) {
+	defer g.G().CTraceTimed(ctx, "gregorRPCHandler#ReplayJournal", func() error { return err })()
+	uid := g.G().Env.GetUID()
+	if uid.IsNil() {
+		return nil, errors.New("not logged in")
+	}
+	return g.gh.journal.Replay(uid, since)
+}
+
 func (g *gregorRPCHandler) InjectItem(ctx context.Context, arg keybase1.InjectItemArg) (res gregor1.MsgID, err error) {
 	defer g.G().CTraceTimed(ctx, "gregorRPCHandler#InjectItem", func() error { return err })()
 	return g.gh.InjectItem(ctx, arg.Cat, []byte(arg.Body), arg.Dtime)
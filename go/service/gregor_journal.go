@@ -0,0 +1,196 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/gregor1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// gregorJournal keeps a durable record of every incoming gregor
+// message so GUI frontends recovering from a crash can replay what
+// they missed, and so "I never got that notification" reports can be
+// checked against what actually arrived.
+type gregorJournal struct {
+	libkb.Contextified
+	sync.Mutex
+	seq     int
+	appends int
+}
+
+const (
+	gregorJournalMaxAge    = 7 * 24 * time.Hour
+	gregorJournalMaxBody   = 4096
+	gregorJournalPruneMask = 63 // prune every 64 appends
+)
+
+func newGregorJournal(g *libkb.GlobalContext) *gregorJournal {
+	return &gregorJournal{Contextified: libkb.NewContextified(g)}
+}
+
+func (j *gregorJournal) dbKey(uid keybase1.UID, ctime time.Time, seq int) libkb.DbKey {
+	return libkb.DbKey{
+		Typ: libkb.DBGregorJournal,
+		Key: fmt.Sprintf("%s:%020d:%06d", uid, ctime.UnixNano(), seq),
+	}
+}
+
+// truncateJournalBody caps a body for storage. Bodies that are not
+// valid UTF-8 (msgpack payloads) are base64'd so the round trip
+// through the JSON db layer does not mangle them.
+func truncateJournalBody(body []byte) string {
+	if len(body) > gregorJournalMaxBody {
+		body = body[:gregorJournalMaxBody]
+	}
+	if !utf8.Valid(body) {
+		return "base64:" + base64.StdEncoding.EncodeToString(body)
+	}
+	return string(body)
+}
+
+// Record journals one incoming gregor message. Failures are logged
+// and swallowed: the journal must never get in the way of delivery.
+func (j *gregorJournal) Record(m gregor1.Message) {
+	uid := j.G().Env.GetUID()
+	if uid.IsNil() {
+		return
+	}
+	ev := keybase1.GregorJournalEvent{
+		Ctime: keybase1.ToTime(j.G().Clock().Now()),
+	}
+	if ibm := m.ToInBandMessage(); ibm != nil {
+		ev.Kind = "ibm"
+		if md := ibm.Metadata(); md != nil {
+			if msgID := md.MsgID(); msgID != nil {
+				ev.MsgID = msgID.String()
+			}
+		}
+		if upd := ibm.ToStateUpdateMessage(); upd != nil {
+			if item := upd.Creation(); item != nil {
+				if cat := item.Category(); cat != nil {
+					ev.Category = cat.String()
+				}
+				if body := item.Body(); body != nil {
+					ev.Body = truncateJournalBody(body.Bytes())
+				}
+			}
+		}
+	} else if obm := m.ToOutOfBandMessage(); obm != nil {
+		ev.Kind = "oobm"
+		if system := obm.System(); system != nil {
+			ev.System = system.String()
+		}
+		if body := obm.Body(); body != nil {
+			ev.Body = truncateJournalBody(body.Bytes())
+		}
+	} else {
+		return
+	}
+
+	j.Lock()
+	j.seq++
+	seq := j.seq
+	j.appends++
+	prune := j.appends&gregorJournalPruneMask == 0
+	j.Unlock()
+
+	key := j.dbKey(uid, keybase1.FromTime(ev.Ctime), seq)
+	if err := j.G().LocalDb.PutObj(key, nil, ev); err != nil {
+		j.G().Log.Debug("gregorJournal: record failed: %s", err)
+		return
+	}
+	if prune {
+		if err := j.pruneOld(); err != nil {
+			j.G().Log.Debug("gregorJournal: prune failed: %s", err)
+		}
+	}
+}
+
+// journalKeyTime parses the nanosecond timestamp out of a journal db
+// key of the form uid:ctime:seq. An empty uid matches any user's keys.
+func journalKeyTime(uid keybase1.UID, key string) (time.Time, bool) {
+	parts := strings.SplitN(key, ":", 3)
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	if !uid.IsNil() && parts[0] != string(uid) {
+		return time.Time{}, false
+	}
+	ns, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, ns), true
+}
+
+// pruneOld drops expired entries for all accounts on this device, not
+// just the current one, so switched-away accounts cannot grow the db
+// without bound.
+func (j *gregorJournal) pruneOld() error {
+	cutoff := j.G().Clock().Now().Add(-gregorJournalMaxAge)
+	var stale []libkb.DbKey
+	err := j.G().LocalDb.ForEachRawKv(func(id libkb.DbKey, _ []byte) error {
+		if id.Typ != libkb.DBGregorJournal {
+			return nil
+		}
+		if ctime, ok := journalKeyTime("", id.Key); ok && ctime.Before(cutoff) {
+			stale = append(stale, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range stale {
+		if err := j.G().LocalDb.Delete(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replay returns journaled events received at or after `since`,
+// oldest first.
+func (j *gregorJournal) Replay(uid keybase1.UID, since keybase1.Time) (res []keybase1.GregorJournalEvent, err error) {
+	sinceTime := keybase1.FromTime(since)
+	type keyedEvent struct {
+		key string
+		ev  keybase1.GregorJournalEvent
+	}
+	var events []keyedEvent
+	err = j.G().LocalDb.ForEachRawKv(func(id libkb.DbKey, value []byte) error {
+		if id.Typ != libkb.DBGregorJournal {
+			return nil
+		}
+		ctime, ok := journalKeyTime(uid, id.Key)
+		if !ok || ctime.Before(sinceTime) {
+			return nil
+		}
+		var ev keybase1.GregorJournalEvent
+		if found, err := j.G().LocalDb.GetInto(&ev, id); err != nil || !found {
+			return nil
+		}
+		events = append(events, keyedEvent{key: id.Key, ev: ev})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, k int) bool { return events[i].key < events[k].key })
+	res = make([]keybase1.GregorJournalEvent, len(events))
+	for i, e := range events {
+		res[i] = e.ev
+	}
+	return res, nil
+}
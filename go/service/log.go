@@ -7,6 +7,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/logger"
 	keybase1 "github.com/keybase/client/go/protocol/keybase1"
 	"github.com/keybase/go-framed-msgpack-rpc/rpc"
 )
@@ -15,14 +16,16 @@ import (
 type LogHandler struct {
 	*BaseHandler
 	logReg *logRegister
+	svc    *Service
 	libkb.Contextified
 }
 
 // NewLogHandler creates a LogHandler for the xp transport.
-func NewLogHandler(xp rpc.Transporter, logReg *logRegister, g *libkb.GlobalContext) *LogHandler {
+func NewLogHandler(xp rpc.Transporter, logReg *logRegister, d *Service, g *libkb.GlobalContext) *LogHandler {
 	return &LogHandler{
 		BaseHandler:  NewBaseHandler(g, xp),
 		logReg:       logReg,
+		svc:          d,
 		Contextified: libkb.NewContextified(g),
 	}
 }
@@ -41,3 +44,18 @@ func (h *LogHandler) RegisterLogger(_ context.Context, arg keybase1.RegisterLogg
 	err = h.logReg.RegisterLogger(arg, ui)
 	return err
 }
+
+func (h *LogHandler) SetLevel(_ context.Context, arg keybase1.SetLevelArg) error {
+	h.G().Log.Info("LogHandler::SetLevel: %s=%s", arg.Module, arg.Level)
+	return logger.SetModuleLevel(arg.Module, arg.Level)
+}
+
+func (h *LogHandler) CreateDiagnosticsBundle(_ context.Context, outfile string) (res keybase1.DiagnosticsBundleRes, err error) {
+	defer h.G().Trace("LogHandler::CreateDiagnosticsBundle", func() error { return err })()
+	res.Path, res.Key, err = h.svc.createDiagnosticsBundle(outfile)
+	return res, err
+}
+
+func (h *LogHandler) NetworkStats(_ context.Context) ([]keybase1.NetworkStatsEntry, error) {
+	return h.G().NetworkStats.Report(), nil
+}
@@ -100,6 +100,30 @@ func (h *LoginHandler) Unlock(ctx context.Context, sessionID int) error {
 	return engine.RunEngine2(m, eng)
 }
 
+// BotProvision mints a capability-scoped bot key and returns its
+// paper phrase.
+func (h *LoginHandler) BotProvision(ctx context.Context, arg keybase1.BotProvisionArg) (string, error) {
+	uis := libkb.UIs{
+		LogUI:     h.getLogUI(arg.SessionID),
+		SecretUI:  h.getSecretUI(arg.SessionID, h.G()),
+		SessionID: arg.SessionID,
+	}
+	eng := engine.NewBotProvision(h.G(), arg.Capabilities)
+	m := libkb.NewMetaContext(ctx, h.G()).WithUIs(uis)
+	if err := engine.RunEngine2(m, eng); err != nil {
+		return "", err
+	}
+	return eng.Passphrase(), nil
+}
+
+// LockSession drops in-memory secret key material without logging
+// out; `keybase unlock` (or any SecretUI prompt) restores it.
+func (h *LoginHandler) LockSession(ctx context.Context, sessionID int) error {
+	h.G().Log.CDebugf(ctx, "LockSession: locking in-memory secrets")
+	h.G().LockSecrets()
+	return nil
+}
+
 func (h *LoginHandler) UnlockWithPassphrase(ctx context.Context, arg keybase1.UnlockWithPassphraseArg) error {
 	uis := libkb.UIs{
 		LogUI:     h.getLogUI(arg.SessionID),
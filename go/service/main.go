@@ -23,6 +23,7 @@ import (
 	"github.com/keybase/client/go/chat"
 	"github.com/keybase/client/go/chat/attachments"
 	"github.com/keybase/client/go/chat/globals"
+	"github.com/keybase/client/go/chat/search"
 	"github.com/keybase/client/go/chat/storage"
 	"github.com/keybase/client/go/engine"
 	"github.com/keybase/client/go/ephemeral"
@@ -63,6 +64,8 @@ type Service struct {
 	home                 *home.Home
 	tlfUpgrader          *tlfupgrade.BackgroundTLFUpdater
 	avatarLoader         avatars.Source
+	autoLock             autoLocker
+	chatMaintenance      *chat.LocalMaintenance
 }
 
 type Shutdowner interface {
@@ -95,11 +98,14 @@ func (d *Service) GetStartChannel() <-chan struct{} {
 func (d *Service) RegisterProtocols(srv *rpc.Server, xp rpc.Transporter, connID libkb.ConnectionID, logReg *logRegister) (shutdowners []Shutdowner, err error) {
 	g := d.G()
 	cg := globals.NewContext(g, d.ChatG())
+	authz := newConnAuthz()
 	protocols := []rpc.Protocol{
+		keybase1.ApiTokenProtocol(NewApiTokenHandler(xp, g, authz)),
 		keybase1.AccountProtocol(NewAccountHandler(xp, g)),
 		keybase1.BTCProtocol(NewCryptocurrencyHandler(xp, g)),
 		keybase1.CryptocurrencyProtocol(NewCryptocurrencyHandler(xp, g)),
 		keybase1.ConfigProtocol(NewConfigHandler(xp, connID, g, d)),
+		keybase1.ContactsProtocol(NewContactsHandler(xp, g)),
 		keybase1.CryptoProtocol(NewCryptoHandler(g)),
 		keybase1.CtlProtocol(NewCtlHandler(xp, d, g)),
 		keybase1.DebuggingProtocol(NewDebuggingHandler(xp, g)),
@@ -111,13 +117,14 @@ func (d *Service) RegisterProtocols(srv *rpc.Server, xp rpc.Transporter, connID
 		keybase1.InstallProtocol(NewInstallHandler(xp, g)),
 		keybase1.KbfsProtocol(NewKBFSHandler(xp, g, d.ChatG())),
 		keybase1.KbfsMountProtocol(NewKBFSMountHandler(xp, g)),
-		keybase1.LogProtocol(NewLogHandler(xp, logReg, g)),
+		keybase1.LogProtocol(NewLogHandler(xp, logReg, d, g)),
 		keybase1.LoginProtocol(NewLoginHandler(xp, g)),
 		keybase1.NotifyCtlProtocol(NewNotifyCtlHandler(xp, connID, g)),
 		keybase1.PGPProtocol(NewPGPHandler(xp, connID, g)),
 		keybase1.PprofProtocol(NewPprofHandler(xp, g)),
 		keybase1.ReachabilityProtocol(newReachabilityHandler(xp, g, d.reachability)),
 		keybase1.RevokeProtocol(NewRevokeHandler(xp, g)),
+		keybase1.RemoteSignerProtocol(NewRemoteSignerHandler(xp, g)),
 		keybase1.ProveProtocol(NewProveHandler(xp, g)),
 		keybase1.SaltpackProtocol(NewSaltpackHandler(xp, g)),
 		keybase1.ScanProofsProtocol(NewScanProofsHandler(xp, g)),
@@ -147,7 +154,7 @@ func (d *Service) RegisterProtocols(srv *rpc.Server, xp rpc.Transporter, connID
 		stellar1.LocalProtocol(newWalletHandler(xp, g)),
 	}
 	for _, proto := range protocols {
-		if err = srv.Register(proto); err != nil {
+		if err = srv.Register(AuthorizingProtocol(authz, d.autoLock.touch, proto)); err != nil {
 			return
 		}
 	}
@@ -289,6 +296,13 @@ func (d *Service) Run() (err error) {
 	// until they get this notification (Type=notify, in systemd lingo).
 	systemd.NotifyStartupFinished()
 
+	d.startRemoteCtlListener()
+	d.startMetricsListener()
+	d.startNotifyBridge()
+	d.startAutoLock()
+	d.loadEnterprisePolicy()
+	d.startStellarScheduleSweeper()
+
 	d.G().ExitCode, err = d.ListenLoopWithStopper(l)
 
 	return err
@@ -341,6 +355,10 @@ func (d *Service) RunBackgroundOperations(uir *UIRouter) {
 	d.runBackgroundIdentifier()
 	d.runBackgroundPerUserKeyUpgrade()
 	d.runBackgroundPerUserKeyUpkeep()
+	d.runBackgroundPerUserKeyRollScheduled()
+	d.runBackgroundTrackFreshness()
+	d.runBackgroundPGPRefresh()
+	d.runBackgroundTeamMemberExpiry()
 	d.runBackgroundWalletInit()
 	d.runBackgroundWalletUpkeep()
 	d.runTLFUpgrade()
@@ -373,11 +391,18 @@ func (d *Service) startChatModules() {
 		g.ConvLoader.Start(context.Background(), uid)
 		g.FetchRetrier.Start(context.Background(), uid)
 		g.EphemeralPurger.Start(context.Background(), uid)
+		if d.chatMaintenance == nil {
+			d.chatMaintenance = chat.NewLocalMaintenance(g)
+		}
+		d.chatMaintenance.Start(context.Background(), uid)
 	}
 	d.purgeOldChatAttachmentData()
 }
 
 func (d *Service) stopChatModules() {
+	if d.chatMaintenance != nil {
+		<-d.chatMaintenance.Stop(context.Background())
+	}
 	<-d.ChatG().MessageDeliverer.Stop(context.Background())
 	<-d.ChatG().ConvLoader.Stop(context.Background())
 	<-d.ChatG().FetchRetrier.Stop(context.Background())
@@ -396,6 +421,7 @@ func (d *Service) createChatModules() {
 		boxer, chatStorage, ri)
 	chatStorage.SetAssetDeleter(g.ConvSource)
 	g.Searcher = chat.NewSearcher(g)
+	g.Indexer = search.NewIndexer(g)
 	g.ServerCacheVersions = storage.NewServerVersions(g)
 
 	// Syncer and retriers
@@ -518,7 +544,7 @@ func (d *Service) startupGregor() {
 		d.gregor.Init()
 		d.reachability = newReachability(d.G(), d.gregor)
 		d.gregor.setReachability(d.reachability)
-		d.G().ConnectivityMonitor = d.reachability
+		d.G().ConnectivityMonitor = libkb.NewConnectivityMonitorWithBreaker(d.G().APICircuitBreaker, d.reachability)
 
 		d.gregor.badger = d.badger
 		d.G().GregorDismisser = d.gregor
@@ -765,6 +791,89 @@ func (d *Service) runBackgroundPerUserKeyUpkeep() {
 	})
 }
 
+func (d *Service) runBackgroundPerUserKeyRollScheduled() {
+	eng := engine.NewPerUserKeyRollScheduledBackground(d.G(), &engine.PerUserKeyRollScheduledBackgroundArgs{})
+	go func() {
+		m := libkb.NewMetaContextBackground(d.G())
+		err := engine.RunEngine2(m, eng)
+		if err != nil {
+			m.CWarningf("per-user-key background scheduled roll error: %v", err)
+		}
+	}()
+
+	d.G().PushShutdownHook(func() error {
+		d.G().Log.Debug("stopping per-user-key background scheduled roll")
+		eng.Shutdown()
+		return nil
+	})
+}
+
+func (d *Service) runBackgroundTrackFreshness() {
+	eng := engine.NewTrackFreshnessBackground(d.G(), &engine.TrackFreshnessBackgroundArgs{})
+	go func() {
+		m := libkb.NewMetaContextBackground(d.G())
+		err := engine.RunEngine2(m, eng)
+		if err != nil {
+			m.CWarningf("background track freshness error: %v", err)
+		}
+	}()
+
+	d.G().PushShutdownHook(func() error {
+		d.G().Log.Debug("stopping background track freshness")
+		eng.Shutdown()
+		return nil
+	})
+}
+
+func (d *Service) runBackgroundPGPRefresh() {
+	eng := engine.NewPGPRefreshBackground(d.G(), &engine.PGPRefreshBackgroundArgs{})
+	go func() {
+		m := libkb.NewMetaContextBackground(d.G())
+		err := engine.RunEngine2(m, eng)
+		if err != nil {
+			m.CWarningf("background PGP refresh error: %v", err)
+		}
+	}()
+
+	d.G().PushShutdownHook(func() error {
+		d.G().Log.Debug("stopping background PGP refresh")
+		eng.Shutdown()
+		return nil
+	})
+}
+
+func (d *Service) runBackgroundTeamMemberExpiry() {
+	stopCh := make(chan struct{})
+	d.G().PushShutdownHook(func() error {
+		close(stopCh)
+		return nil
+	})
+	go func() {
+		m := libkb.NewMetaContextBackground(d.G())
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(time.Hour):
+			}
+			if !d.G().ActiveDevice.Valid() {
+				continue
+			}
+			removed, warnings, err := teams.SweepExpiredMembers(m.Ctx(), d.G())
+			if err != nil {
+				m.CDebugf("team member expiry sweep error: %s", err)
+				continue
+			}
+			for _, exp := range removed {
+				m.CWarningf("Removed %s from %s: time-boxed membership expired", exp.Username, exp.Team)
+			}
+			for _, exp := range warnings {
+				m.CWarningf("Membership of %s in %s expires at %s", exp.Username, exp.Team, exp.ExpiresAt)
+			}
+		}
+	}()
+}
+
 func (d *Service) runBackgroundWalletInit() {
 	eng := engine.NewWalletInitBackground(d.G(), &engine.WalletInitBackgroundArgs{})
 	go func() {
@@ -810,6 +919,7 @@ func (d *Service) OnLogin() error {
 		d.runBackgroundIdentifierWithUID(uid)
 		d.runTLFUpgrade()
 		go d.identifySelf()
+		d.assertLockdownPolicy()
 	}
 	return nil
 }
@@ -0,0 +1,122 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// Optional Prometheus endpoint: when service.metrics_bind (or
+// KEYBASE_METRICS_BIND) is set, the service serves /metrics in the
+// Prometheus text format so admins can monitor a headless keybase
+// like any other daemon. The registry counters come from core
+// subsystems (sigchain loads, chat delivery, KBFS block dedup); API
+// latencies are rendered from the existing NetworkStatsRecorder and
+// LocalDb sizes are computed at scrape time.
+
+type metricsHandler struct {
+	libkb.Contextified
+}
+
+func dirSize(path string) float64 {
+	var total int64
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return float64(total)
+}
+
+// escapeLabelValue escapes a Prometheus label value.
+func escapeLabelValue(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return strings.Replace(s, `"`, `\"`, -1)
+}
+
+func sanitizeMetricName(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func (h metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/metrics" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	for _, sample := range h.G().Metrics.Snapshot() {
+		kind := "counter"
+		if sample.Gauge {
+			kind = "gauge"
+		}
+		name := sanitizeMetricName(sample.Name)
+		fmt.Fprintf(w, "# TYPE %s %s\n%s %v\n", name, kind, name, sample.Value)
+	}
+
+	// Per-endpoint API traffic from the network stats recorder.
+	for _, e := range h.G().NetworkStats.Report() {
+		label := escapeLabelValue(e.Endpoint)
+		fmt.Fprintf(w, "keybase_api_requests_total{endpoint=\"%s\"} %d\n", label, e.Count)
+		fmt.Fprintf(w, "keybase_api_bytes_in_total{endpoint=\"%s\"} %d\n", label, e.BytesIn)
+		fmt.Fprintf(w, "keybase_api_request_duration_ms_sum{endpoint=\"%s\"} %d\n", label, e.TotalMs)
+	}
+
+	// LocalDb sizes, computed per scrape.
+	fmt.Fprintf(w, "# TYPE keybase_localdb_bytes gauge\n")
+	fmt.Fprintf(w, "keybase_localdb_bytes{db=\"main\"} %v\n", dirSize(h.G().Env.GetDbFilename()))
+	fmt.Fprintf(w, "keybase_localdb_bytes{db=\"chat\"} %v\n", dirSize(h.G().Env.GetChatDbFilename()))
+}
+
+// startMetricsListener serves /metrics when configured. Binding
+// non-loopback addresses is refused: the endpoint is unauthenticated
+// by design (like node_exporter) and must stay local; remote scraping
+// goes through an ssh tunnel or a sidecar proxy.
+func (d *Service) startMetricsListener() {
+	addr := d.G().Env.GetMetricsBind()
+	if addr == "" {
+		return
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		d.G().Log.Warning("metrics: bad bind address %q: %s", addr, err)
+		return
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		d.G().Log.Warning("metrics: refusing non-loopback bind %q", addr)
+		return
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		d.G().Log.Warning("metrics: listen on %s failed: %s", addr, err)
+		return
+	}
+	d.G().Log.Info("metrics: serving Prometheus metrics at http://%s/metrics", l.Addr())
+	d.G().PushShutdownHook(func() error {
+		return l.Close()
+	})
+	go func() {
+		_ = http.Serve(l, metricsHandler{Contextified: libkb.NewContextified(d.G())})
+	}()
+}
@@ -0,0 +1,157 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/chat1"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+)
+
+// notifyBridge delivers chat and follower events to the OS
+// notification system for users running only the service + CLI, with
+// no Electron GUI to render them. Platform backends live in the
+// notify_bridge_<os>.go files; on Linux the freedesktop daemon gets
+// actionable notifications (inline reply posts straight back to the
+// conversation).
+type notifyBridge struct {
+	libkb.NoopNotifyListener
+	libkb.Contextified
+	sync.Mutex
+	notifier nativeNotifier
+
+	// Notification id -> conversation the reply action posts to.
+	pending map[uint32]replyTarget
+}
+
+type replyTarget struct {
+	name        string
+	channel     string
+	membersType chat1.ConversationMembersType
+	visibility  keybase1.TLFVisibility
+}
+
+// Cap on remembered reply targets; ignored notifications age out by
+// wholesale reset rather than growing forever.
+const maxPendingReplies = 512
+
+// nativeNotifier is one OS notification backend.
+type nativeNotifier interface {
+	// Notify shows a notification, optionally with a reply action,
+	// returning the OS notification id.
+	Notify(title, body string, withReply bool) (uint32, error)
+	// Listen delivers invoked reply actions (id, reply text) until
+	// the service shuts down. Backends without actions just block.
+	Listen(onReply func(id uint32, text string)) error
+}
+
+func newNotifyBridge(g *libkb.GlobalContext) *notifyBridge {
+	return &notifyBridge{
+		Contextified: libkb.NewContextified(g),
+		notifier:     newPlatformNotifier(g),
+		pending:      make(map[uint32]replyTarget),
+	}
+}
+
+// startNotifyBridge wires the bridge into the notify router when
+// native notifications are enabled.
+func (d *Service) startNotifyBridge() {
+	if !d.G().Env.GetNativeNotifications() {
+		return
+	}
+	bridge := newNotifyBridge(d.G())
+	if bridge.notifier == nil {
+		d.G().Log.Warning("notify-bridge: no native backend on this platform")
+		return
+	}
+	d.G().NotifyRouter.SetListener(bridge)
+	d.G().Log.Info("notify-bridge: native notifications enabled")
+	go func() {
+		if err := bridge.notifier.Listen(bridge.handleReply); err != nil {
+			d.G().Log.Warning("notify-bridge: action listener exited: %s", err)
+		}
+	}()
+}
+
+func (n *notifyBridge) NewChatActivity(uid keybase1.UID, activity chat1.ChatActivity,
+	source chat1.ChatActivitySource) {
+	typ, err := activity.ActivityType()
+	if err != nil || typ != chat1.ChatActivityType_INCOMING_MESSAGE {
+		return
+	}
+	in := activity.IncomingMessage()
+	if !in.DisplayDesktopNotification {
+		return
+	}
+	msg := in.Message
+	state, err := msg.State()
+	if err != nil || state != chat1.MessageUnboxedState_VALID {
+		return
+	}
+	valid := msg.Valid()
+	// Don't notify for our own messages echoing back.
+	if valid.SenderUsername == n.G().Env.GetUsername().String() {
+		return
+	}
+	title := fmt.Sprintf("Keybase: %s", valid.SenderUsername)
+	body := in.DesktopNotificationSnippet
+	if body == "" {
+		body = "New message"
+	}
+
+	withReply := in.Conv != nil
+	id, err := n.notifier.Notify(title, body, withReply)
+	if err != nil {
+		n.G().Log.Debug("notify-bridge: notify failed: %s", err)
+		return
+	}
+	if withReply {
+		n.Lock()
+		if len(n.pending) >= maxPendingReplies {
+			n.pending = make(map[uint32]replyTarget)
+		}
+		n.pending[id] = replyTarget{
+			name:        in.Conv.Name,
+			channel:     in.Conv.Channel,
+			membersType: in.Conv.MembersType,
+			visibility:  in.Conv.Visibility,
+		}
+		n.Unlock()
+	}
+}
+
+func (n *notifyBridge) TrackingChanged(uid keybase1.UID, username libkb.NormalizedUsername) {
+	if _, err := n.notifier.Notify("Keybase", fmt.Sprintf("Following relationship with %s changed", username), false); err != nil {
+		n.G().Log.Debug("notify-bridge: notify failed: %s", err)
+	}
+}
+
+// handleReply posts inline-reply text back to the conversation the
+// notification came from.
+func (n *notifyBridge) handleReply(id uint32, text string) {
+	n.Lock()
+	target, found := n.pending[id]
+	delete(n.pending, id)
+	n.Unlock()
+	if !found || text == "" {
+		return
+	}
+	if n.G().ChatHelper == nil {
+		return
+	}
+	var topicName *string
+	if target.channel != "" {
+		topicName = &target.channel
+	}
+	err := n.G().ChatHelper.SendTextByName(context.Background(), target.name, topicName,
+		target.membersType, keybase1.TLFIdentifyBehavior_CHAT_CLI, text)
+	if err != nil {
+		n.G().Log.Warning("notify-bridge: inline reply to %s failed: %s", target.name, err)
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// +build !linux,!darwin
+
+package service
+
+import "github.com/keybase/client/go/libkb"
+
+func newPlatformNotifier(g *libkb.GlobalContext) nativeNotifier {
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import "github.com/keybase/client/go/libkb"
+
+// loadEnterprisePolicy fetches and enforces the fleet policy at
+// startup. A policy that fails to verify is refused loudly; the
+// service then runs unmanaged rather than trusting it.
+func (d *Service) loadEnterprisePolicy() {
+	policy, err := libkb.LoadEnterprisePolicy(d.G())
+	if err != nil {
+		d.G().Log.Warning("enterprise policy: refusing to load: %s", err)
+		return
+	}
+	if policy == nil {
+		return
+	}
+	d.G().Log.Info("enterprise policy v%d active (exploding disallowed: %v, forced proxy: %q, min passphrase: %d, disabled features: %v, lockdown required: %v)",
+		policy.Version, libkb.PolicyDisallowsExploding(), policy.ForcedProxy,
+		libkb.PolicyMinPassphraseLength(), policy.DisabledFeatures, policy.RequireLockdown)
+	if policy.RequireLockdown {
+		d.assertLockdownPolicy()
+	}
+}
+
+// assertLockdownPolicy turns lockdown mode on server-side when the
+// policy demands it and a user is logged in. Called at policy load
+// and again from OnLogin, so fleet machines converge no matter which
+// happens first.
+func (d *Service) assertLockdownPolicy() {
+	if !libkb.PolicyRequiresLockdown() || !d.G().ActiveDevice.Valid() {
+		return
+	}
+	go func() {
+		arg := libkb.APIArg{
+			Endpoint:    "account/lockdown",
+			SessionType: libkb.APISessionTypeREQUIRED,
+			Args:        libkb.HTTPArgs{"enabled": libkb.B{Val: true}},
+		}
+		if _, err := d.G().API.Post(arg); err != nil {
+			d.G().Log.Warning("enterprise policy: could not assert lockdown mode: %s", err)
+			return
+		}
+		d.G().Log.Info("enterprise policy: lockdown mode asserted")
+	}()
+}
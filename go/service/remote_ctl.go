@@ -0,0 +1,40 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"crypto/tls"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// startRemoteCtlListener brings up the TCP listener for remote
+// headless control when service.remote_ctl_bind is configured.
+// Connections are mutually authenticated with TLS client certs from
+// `keybase ctl remote-keygen` and then served the normal RPC surface.
+func (d *Service) startRemoteCtlListener() {
+	addr := d.G().Env.GetRemoteCtlBind()
+	if addr == "" {
+		return
+	}
+	cfg, err := libkb.RemoteCtlServerTLSConfig(d.G())
+	if err != nil {
+		d.G().Log.Warning("remote-ctl: disabled: %s", err)
+		return
+	}
+	l, err := tls.Listen("tcp", addr, cfg)
+	if err != nil {
+		d.G().Log.Warning("remote-ctl: listen on %s failed: %s", addr, err)
+		return
+	}
+	d.G().Log.Info("remote-ctl: listening on %s (TLS, client certs required)", addr)
+	d.G().PushShutdownHook(func() error {
+		return l.Close()
+	})
+	go func() {
+		if err := d.ListenLoop(l); err != nil {
+			d.G().Log.Warning("remote-ctl: listen loop ended: %s", err)
+		}
+	}()
+}
@@ -0,0 +1,58 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/keybase/go-framed-msgpack-rpc/rpc"
+	"golang.org/x/net/context"
+)
+
+// RemoteSignerHandler is the signer side of remote signer mode: it
+// holds the real device key and signs sigchain payloads on behalf of
+// keyless clients. It refuses everything unless the operator has
+// enabled signing.serve_remote_clients, and never signs with anything
+// but this device's own signing key.
+type RemoteSignerHandler struct {
+	libkb.Contextified
+	*BaseHandler
+}
+
+func NewRemoteSignerHandler(xp rpc.Transporter, g *libkb.GlobalContext) *RemoteSignerHandler {
+	return &RemoteSignerHandler{
+		Contextified: libkb.NewContextified(g),
+		BaseHandler:  NewBaseHandler(g, xp),
+	}
+}
+
+func (h *RemoteSignerHandler) RemoteSign(ctx context.Context, arg keybase1.RemoteSignArg) (res keybase1.RemoteSignResult, err error) {
+	m := libkb.NewMetaContext(ctx, h.G())
+	defer m.CTraceTimed("RemoteSignerHandler#RemoteSign", func() error { return err })()
+
+	if enabled, _ := h.G().Env.GetConfig().GetBoolAtPath("signing.serve_remote_clients"); !enabled {
+		return res, errors.New("remote signing is not enabled on this device (signing.serve_remote_clients)")
+	}
+
+	key, err := h.G().ActiveDevice.SigningKey()
+	if err != nil {
+		return res, err
+	}
+	if !arg.Req.SigningKID.IsNil() && !key.GetKID().Equal(arg.Req.SigningKID) {
+		return res, fmt.Errorf("request names KID %s but this device signs with %s",
+			arg.Req.SigningKID, key.GetKID())
+	}
+
+	m.CWarningf("Remote signer: signing %d-byte payload (%s) for a remote client",
+		len(arg.Req.Payload), arg.Req.Reason)
+
+	sig, sigID, err := key.SignToString(arg.Req.Payload)
+	if err != nil {
+		return res, err
+	}
+	return keybase1.RemoteSignResult{Sig: sig, SigID: sigID}, nil
+}
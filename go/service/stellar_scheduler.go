@@ -0,0 +1,54 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/protocol/stellar1"
+	"github.com/keybase/client/go/stellar"
+	"github.com/keybase/client/go/stellar/remote"
+	"github.com/keybase/client/go/stellar/stellarcommon"
+)
+
+// The payment-schedule sweeper: fires due scheduled payments through
+// the normal CLI send path (spending policy included) once an hour
+// while logged in. Schedules themselves live in the local db via
+// stellar.SweepDueSchedules.
+
+const stellarScheduleSweepInterval = time.Hour
+
+func (d *Service) startStellarScheduleSweeper() {
+	go func() {
+		for {
+			time.Sleep(stellarScheduleSweepInterval)
+			if !d.G().ActiveDevice.Valid() {
+				continue
+			}
+			d.sweepStellarSchedules()
+		}
+	}()
+}
+
+func (d *Service) sweepStellarSchedules() {
+	m := libkb.NewMetaContextBackground(d.G())
+	sender := func(sched stellar.PaymentSchedule) error {
+		_, err := stellar.SendPaymentCLI(m, remote.NewRemoteNet(d.G()), stellar.SendPaymentArg{
+			From:       stellar1.AccountID(sched.From),
+			To:         stellarcommon.RecipientInput(sched.To),
+			Amount:     sched.Amount,
+			SecretNote: sched.Note,
+		})
+		return err
+	}
+	fired, err := stellar.SweepDueSchedules(d.G(), time.Now(), sender)
+	if err != nil {
+		d.G().Log.Debug("stellar schedule sweep failed: %s", err)
+		return
+	}
+	for _, sched := range fired {
+		d.G().Log.Info("scheduled payment %s fired: %s XLM to %s", sched.ID, sched.Amount, sched.To)
+	}
+}
@@ -560,6 +560,72 @@ func (h *TeamsHandler) TeamRotateKey(ctx context.Context, teamID keybase1.TeamID
 	return teams.RotateKey(ctx, h.G().ExternalG(), teamID)
 }
 
+func exportInviteLink(link teams.InviteLink) keybase1.TeamInviteLinkInfo {
+	return keybase1.TeamInviteLinkInfo{
+		Handle:        link.Handle,
+		Role:          link.Role,
+		MaxUses:       link.MaxUses,
+		RemainingUses: len(link.Tokens),
+		ExpiresAt:     keybase1.ToTime(link.ExpiresAt),
+		JoinedVia:     link.JoinedVia,
+	}
+}
+
+// TeamCreateInviteLink mints a multi-use invite link backed by a
+// batch of seitan tokens.
+func (h *TeamsHandler) TeamCreateInviteLink(ctx context.Context, arg keybase1.TeamCreateInviteLinkArg) (res keybase1.TeamInviteLinkInfo, err error) {
+	ctx = libkb.WithLogTag(ctx, "TM")
+	defer h.G().CTraceTimed(ctx, fmt.Sprintf("TeamCreateInviteLink(%s, %s)", arg.Name, arg.Handle), func() error { return err })()
+	link, err := teams.CreateInviteLink(ctx, h.G().ExternalG(), arg.Name, arg.Handle, arg.Role,
+		arg.MaxUses, keybase1.FromTime(arg.ExpiresAt))
+	if err != nil {
+		return res, err
+	}
+	return exportInviteLink(*link), nil
+}
+
+func (h *TeamsHandler) TeamListInviteLinks(ctx context.Context, arg keybase1.TeamListInviteLinksArg) (res []keybase1.TeamInviteLinkInfo, err error) {
+	ctx = libkb.WithLogTag(ctx, "TM")
+	defer h.G().CTraceTimed(ctx, fmt.Sprintf("TeamListInviteLinks(%s)", arg.Name), func() error { return err })()
+	links, err := teams.ListInviteLinks(h.G().ExternalG(), arg.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		res = append(res, exportInviteLink(link))
+	}
+	return res, nil
+}
+
+// TeamRevokeInviteLink cancels the link's unredeemed tokens and
+// returns who joined through it.
+func (h *TeamsHandler) TeamRevokeInviteLink(ctx context.Context, arg keybase1.TeamRevokeInviteLinkArg) (res []string, err error) {
+	ctx = libkb.WithLogTag(ctx, "TM")
+	defer h.G().CTraceTimed(ctx, fmt.Sprintf("TeamRevokeInviteLink(%s, %s)", arg.Name, arg.Handle), func() error { return err })()
+	return teams.RevokeInviteLink(ctx, h.G().ExternalG(), arg.Name, arg.Handle)
+}
+
+// TeamListPendingRoleChanges surfaces proposals awaiting a second
+// admin under the two-admin rule.
+func (h *TeamsHandler) TeamListPendingRoleChanges(ctx context.Context, sessionID int) (res []keybase1.PendingRoleChangeInfo, err error) {
+	ctx = libkb.WithLogTag(ctx, "TM")
+	defer h.G().CTraceTimed(ctx, "TeamListPendingRoleChanges", func() error { return err })()
+	pending, err := teams.ListPendingRoleChanges(h.G().ExternalG())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pending {
+		res = append(res, keybase1.PendingRoleChangeInfo{
+			Team:       p.Team,
+			Username:   p.Username,
+			Role:       p.Role,
+			ProposedBy: p.ProposedBy,
+			ProposedAt: keybase1.ToTime(p.ProposedAt),
+		})
+	}
+	return res, nil
+}
+
 func (h *TeamsHandler) TeamDebug(ctx context.Context, teamID keybase1.TeamID) (res keybase1.TeamDebugRes, err error) {
 	ctx = libkb.WithLogTag(ctx, "TM")
 	defer h.G().CTraceTimed(ctx, fmt.Sprintf("TeamDebug(%v)", teamID), func() error { return err })()
@@ -360,3 +360,7 @@ func (h *UserHandler) FindNextMerkleRootAfterReset(ctx context.Context, arg keyb
 	defer m.CTraceTimed("UserHandler#FindNextMerkleRootAfterReset", func() error { return err })()
 	return libkb.FindNextMerkleRootAfterReset(m, arg)
 }
+
+func (h *UserHandler) GetUPAKStats(_ context.Context, sessionID int) (keybase1.UPAKStats, error) {
+	return h.G().GetUPAKLoader().CacheStats(), nil
+}
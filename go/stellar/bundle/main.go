@@ -60,6 +60,26 @@ func AddAccount(bundle *stellar1.Bundle, secretKey stellar1.SecretKey, name stri
 	return bundle.CheckInvariants()
 }
 
+// AddWatchAccount adds a watch-only account (no secret key) to the
+// bundle. Mutates `bundle`.
+func AddWatchAccount(bundle *stellar1.Bundle, accountID stellar1.AccountID, name string) (err error) {
+	if bundle == nil {
+		return fmt.Errorf("nil bundle")
+	}
+	accountID, err = libkb.ParseStellarAccountID(string(accountID))
+	if err != nil {
+		return err
+	}
+	bundle.Accounts = append(bundle.Accounts, stellar1.BundleEntry{
+		AccountID: accountID,
+		Mode:      stellar1.AccountMode_WATCH,
+		IsPrimary: false,
+		Signers:   nil,
+		Name:      name,
+	})
+	return bundle.CheckInvariants()
+}
+
 // CreateNewAccount generates a Stellar key pair and adds it to the
 // bundle. Mutates `bundle`.
 func CreateNewAccount(bundle *stellar1.Bundle, name string, makePrimary bool) (pub stellar1.AccountID, err error) {
@@ -0,0 +1,134 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package stellar
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// A durable local scheduler for stellar payments: one-shot sends at a
+// future time, or weekly/monthly recurrences. Schedules survive
+// restarts in the local DB; a sweep on the owner's device executes due
+// payments through the normal payment path (with its dry-run balance
+// check) and records outcomes.
+
+type PaymentSchedule struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from"`   // account ID
+	To        string    `json:"to"`     // recipient (keybase user or address)
+	Amount    string    `json:"amount"` // XLM string amount
+	Note      string    `json:"note"`
+	NextRun   time.Time `json:"next_run"`
+	Recur     string    `json:"recur"` // "", "weekly", "monthly"
+	CreatedAt time.Time `json:"created_at"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+func scheduleDbKey(id string) libkb.DbKey {
+	return libkb.DbKey{Typ: libkb.DBStellarSchedules, Key: id}
+}
+
+// CreateSchedule validates and stores a schedule.
+func CreateSchedule(g *libkb.GlobalContext, sched PaymentSchedule) error {
+	if sched.ID == "" || sched.To == "" || sched.Amount == "" {
+		return fmt.Errorf("schedules need an id, recipient and amount")
+	}
+	switch sched.Recur {
+	case "", "weekly", "monthly":
+	default:
+		return fmt.Errorf("unknown recurrence %q: use weekly or monthly (or neither)", sched.Recur)
+	}
+	if sched.NextRun.Before(time.Now()) {
+		return fmt.Errorf("next run %s is in the past", sched.NextRun)
+	}
+	if existing, _ := GetSchedule(g, sched.ID); existing != nil {
+		return fmt.Errorf("schedule %q already exists", sched.ID)
+	}
+	sched.CreatedAt = time.Now()
+	return g.LocalDb.PutObj(scheduleDbKey(sched.ID), nil, sched)
+}
+
+// GetSchedule fetches one schedule.
+func GetSchedule(g *libkb.GlobalContext, id string) (*PaymentSchedule, error) {
+	var sched PaymentSchedule
+	found, err := g.LocalDb.GetInto(&sched, scheduleDbKey(id))
+	if err != nil || !found {
+		return nil, err
+	}
+	return &sched, nil
+}
+
+// ListSchedules returns every schedule on this device.
+func ListSchedules(g *libkb.GlobalContext) (ret []PaymentSchedule, err error) {
+	err = g.LocalDb.ForEachRawKv(func(id libkb.DbKey, value []byte) error {
+		if id.Typ != libkb.DBStellarSchedules {
+			return nil
+		}
+		var sched PaymentSchedule
+		if jerr := json.Unmarshal(value, &sched); jerr != nil {
+			return nil
+		}
+		ret = append(ret, sched)
+		return nil
+	})
+	return ret, err
+}
+
+// CancelSchedule removes a schedule.
+func CancelSchedule(g *libkb.GlobalContext, id string) error {
+	if existing, _ := GetSchedule(g, id); existing == nil {
+		return fmt.Errorf("no schedule %q", id)
+	}
+	return g.LocalDb.Delete(scheduleDbKey(id))
+}
+
+// advance computes the next run after firing; ok=false for one-shots,
+// which are done.
+func (s PaymentSchedule) advance() (next time.Time, ok bool) {
+	switch s.Recur {
+	case "weekly":
+		return s.NextRun.AddDate(0, 0, 7), true
+	case "monthly":
+		return s.NextRun.AddDate(0, 1, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// PaymentSender executes one payment; the service supplies the real
+// sender (which performs the dry-run balance check and notification).
+type PaymentSender func(sched PaymentSchedule) error
+
+// SweepDueSchedules fires every due schedule. Failures are recorded on
+// the schedule and retried next sweep; recurring schedules advance only
+// after success.
+func SweepDueSchedules(g *libkb.GlobalContext, now time.Time, send PaymentSender) (fired []PaymentSchedule, err error) {
+	schedules, err := ListSchedules(g)
+	if err != nil {
+		return nil, err
+	}
+	for _, sched := range schedules {
+		if sched.NextRun.After(now) {
+			continue
+		}
+		if serr := send(sched); serr != nil {
+			sched.LastError = serr.Error()
+			_ = g.LocalDb.PutObj(scheduleDbKey(sched.ID), nil, sched)
+			continue
+		}
+		fired = append(fired, sched)
+		if next, recurs := sched.advance(); recurs {
+			sched.NextRun = next
+			sched.LastError = ""
+			_ = g.LocalDb.PutObj(scheduleDbKey(sched.ID), nil, sched)
+		} else {
+			_ = g.LocalDb.Delete(scheduleDbKey(sched.ID))
+		}
+	}
+	return fired, nil
+}
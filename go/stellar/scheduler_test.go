@@ -0,0 +1,64 @@
+package stellar
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/externalstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentScheduler(t *testing.T) {
+	tc := externalstest.SetupTest(t, "sched", 1)
+	defer tc.Cleanup()
+
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, CreateSchedule(tc.G, PaymentSchedule{
+		ID: "rent", To: "landlord", Amount: "100", NextRun: future, Recur: "monthly",
+	}))
+	require.NoError(t, CreateSchedule(tc.G, PaymentSchedule{
+		ID: "oneoff", To: "friend", Amount: "5", NextRun: future,
+	}))
+	require.Error(t, CreateSchedule(tc.G, PaymentSchedule{
+		ID: "rent", To: "x", Amount: "1", NextRun: future,
+	})) // duplicate
+	require.Error(t, CreateSchedule(tc.G, PaymentSchedule{
+		ID: "past", To: "x", Amount: "1", NextRun: time.Now().Add(-time.Hour),
+	}))
+	require.Error(t, CreateSchedule(tc.G, PaymentSchedule{
+		ID: "odd", To: "x", Amount: "1", NextRun: future, Recur: "hourly",
+	}))
+
+	// Nothing due yet.
+	fired, err := SweepDueSchedules(tc.G, time.Now(), func(PaymentSchedule) error { return nil })
+	require.NoError(t, err)
+	require.Empty(t, fired)
+
+	// After the hour: both fire; the one-shot is gone, monthly advances.
+	later := future.Add(time.Minute)
+	fired, err = SweepDueSchedules(tc.G, later, func(PaymentSchedule) error { return nil })
+	require.NoError(t, err)
+	require.Len(t, fired, 2)
+
+	schedules, err := ListSchedules(tc.G)
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	require.Equal(t, "rent", schedules[0].ID)
+	require.True(t, schedules[0].NextRun.After(later))
+
+	// Failed sends stay queued with the error recorded.
+	schedules[0].NextRun = later.Add(-time.Minute)
+	require.NoError(t, tc.G.LocalDb.PutObj(scheduleDbKey("rent"), nil, schedules[0]))
+	fired, err = SweepDueSchedules(tc.G, later, func(PaymentSchedule) error {
+		return fmt.Errorf("insufficient balance")
+	})
+	require.NoError(t, err)
+	require.Empty(t, fired)
+	got, err := GetSchedule(tc.G, "rent")
+	require.NoError(t, err)
+	require.Contains(t, got.LastError, "insufficient balance")
+
+	require.NoError(t, CancelSchedule(tc.G, "rent"))
+	require.Error(t, CancelSchedule(tc.G, "rent"))
+}
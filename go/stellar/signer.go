@@ -0,0 +1,130 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package stellar
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/stellarnet"
+	"github.com/stellar/go/build"
+)
+
+// A signer abstraction so an account's secret can live off-device — on
+// a Ledger, say. Payment and trustline flows build the transaction
+// locally and ask the account's signer for signatures; the default
+// signer wraps the usual in-process seed, and hardware transports
+// register a factory (the transport code is platform-specific and not
+// every build links it).
+
+// TxSigner produces signatures for stellar transactions on behalf of
+// one account.
+type TxSigner interface {
+	// AccountID is the public account this signer speaks for.
+	AccountID() stellarnet.AddressStr
+	// SignTransaction signs the base64 transaction envelope, returning
+	// the signed envelope. May block on user presence (a button press
+	// on the device).
+	SignTransaction(envelopeB64 string) (signedB64 string, err error)
+	// Description names the signer for prompts ("Ledger Nano S").
+	Description() string
+}
+
+// HardwareWalletFactory opens the signer for an account whose secret
+// lives on a hardware device.
+type HardwareWalletFactory func(g *libkb.GlobalContext, accountID stellarnet.AddressStr) (TxSigner, error)
+
+var (
+	hwSignerMu      sync.Mutex
+	hwSignerFactory HardwareWalletFactory
+	// Accounts marked hardware-backed: accountID -> true. Persisted in
+	// config under stellar.hardware_accounts by the import flow.
+	hwAccounts = make(map[stellarnet.AddressStr]bool)
+)
+
+// RegisterHardwareWalletFactory installs the platform's transport.
+func RegisterHardwareWalletFactory(f HardwareWalletFactory) {
+	hwSignerMu.Lock()
+	defer hwSignerMu.Unlock()
+	hwSignerFactory = f
+}
+
+// MarkAccountHardwareBacked records that an account's secret is on a
+// device; signing for it must go through the hardware factory.
+func MarkAccountHardwareBacked(accountID stellarnet.AddressStr) {
+	hwSignerMu.Lock()
+	defer hwSignerMu.Unlock()
+	hwAccounts[accountID] = true
+}
+
+// IsAccountHardwareBacked reports whether signing must happen on-device.
+func IsAccountHardwareBacked(accountID stellarnet.AddressStr) bool {
+	hwSignerMu.Lock()
+	defer hwSignerMu.Unlock()
+	return hwAccounts[accountID]
+}
+
+// SignerForAccount returns the signer to use: the hardware signer for
+// marked accounts (failing clearly when no transport is linked), or
+// nil, meaning the caller should sign with the in-process seed as
+// before.
+// HardwarePaymentTransaction builds an unsigned payment (or
+// create_account when the destination is unfunded) from a
+// hardware-backed account, signs it through the account's TxSigner,
+// and returns the signed envelope plus hash for posting. The secret
+// never enters this process.
+func HardwarePaymentTransaction(signer TxSigner, from, to stellarnet.AddressStr,
+	amount, memoText string, funded bool, sp build.SequenceProvider) (signedB64, txHashHex string, err error) {
+	var op build.TransactionMutator = build.Payment(
+		build.Destination{AddressOrSeed: to.String()},
+		build.NativeAmount{Amount: amount},
+	)
+	if !funded {
+		op = build.CreateAccount(
+			build.Destination{AddressOrSeed: to.String()},
+			build.NativeAmount{Amount: amount},
+		)
+	}
+	tx, err := build.Transaction(
+		build.SourceAccount{AddressOrSeed: from.String()},
+		stellarnet.Network(),
+		build.AutoSequence{SequenceProvider: sp},
+		op,
+		build.MemoText{Value: memoText},
+	)
+	if err != nil {
+		return "", "", err
+	}
+	// Zero-seed Sign yields the unsigned envelope for the device.
+	txe, err := tx.Sign()
+	if err != nil {
+		return "", "", err
+	}
+	unsigned, err := txe.Base64()
+	if err != nil {
+		return "", "", err
+	}
+	signedB64, err = signer.SignTransaction(unsigned)
+	if err != nil {
+		return "", "", fmt.Errorf("hardware signer (%s): %s", signer.Description(), err)
+	}
+	txHashHex, err = tx.HashHex()
+	if err != nil {
+		return "", "", err
+	}
+	return signedB64, txHashHex, nil
+}
+
+func SignerForAccount(g *libkb.GlobalContext, accountID stellarnet.AddressStr) (TxSigner, error) {
+	hwSignerMu.Lock()
+	defer hwSignerMu.Unlock()
+	if !hwAccounts[accountID] {
+		return nil, nil
+	}
+	if hwSignerFactory == nil {
+		return nil, fmt.Errorf("account %s is hardware-backed but no hardware wallet driver is registered in this build", accountID)
+	}
+	return hwSignerFactory(g, accountID)
+}
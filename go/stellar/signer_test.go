@@ -0,0 +1,49 @@
+package stellar
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/stellarnet"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedger struct{ account stellarnet.AddressStr }
+
+func (f fakeLedger) AccountID() stellarnet.AddressStr { return f.account }
+func (f fakeLedger) Description() string              { return "fake ledger" }
+func (f fakeLedger) SignTransaction(envelopeB64 string) (string, error) {
+	return envelopeB64 + ":signed-on-device", nil
+}
+
+func TestHardwareSignerRouting(t *testing.T) {
+	defer func() {
+		hwSignerFactory = nil
+		hwAccounts = make(map[stellarnet.AddressStr]bool)
+	}()
+
+	soft := stellarnet.AddressStr("GSOFT")
+	hard := stellarnet.AddressStr("GHARD")
+
+	// Unmarked accounts keep in-process signing (nil signer, no error).
+	signer, err := SignerForAccount(nil, soft)
+	require.NoError(t, err)
+	require.Nil(t, signer)
+
+	// Marked but no driver: a clear error instead of a silent fallback
+	// to software signing.
+	MarkAccountHardwareBacked(hard)
+	_, err = SignerForAccount(nil, hard)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no hardware wallet driver")
+
+	// With a driver, signing routes on-device.
+	RegisterHardwareWalletFactory(func(_ *libkb.GlobalContext, accountID stellarnet.AddressStr) (TxSigner, error) {
+		return fakeLedger{account: accountID}, nil
+	})
+	signer, err = SignerForAccount(nil, hard)
+	require.NoError(t, err)
+	signed, err := signer.SignTransaction("deadbeef")
+	require.NoError(t, err)
+	require.Equal(t, "deadbeef:signed-on-device", signed)
+}
@@ -0,0 +1,128 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package stellar
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// Per-device spending policy: payments above a configured threshold
+// either sit in a timed delay window (cancellable) or require an
+// approval recorded by a second device, checked in the payment engine
+// before anything is signed.
+//
+//   "stellar": { "spending_policy": {
+//       "threshold_xlm": "100", "mode": "delay", "delay": "10m" } }
+//
+// Mode "second-device" reuses the high-link approval tokens: the other
+// device approves with operation "stellar-payment".
+
+type SpendingPolicy struct {
+	ThresholdXLM float64
+	Mode         string // "delay" or "second-device"
+	Delay        time.Duration
+}
+
+type pendingPayment struct {
+	Amount    string    `json:"amount"`
+	To        string    `json:"to"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const stellarApprovalOperation = "stellar-payment"
+
+// How long after its delay elapses a pending record stays valid as a
+// confirmation. Beyond this it is treated as abandoned and a fresh
+// window starts.
+const pendingPaymentConfirmWindow = time.Hour
+
+func loadSpendingPolicy(g *libkb.GlobalContext) (policy SpendingPolicy, ok bool) {
+	threshold, _ := g.Env.GetConfig().GetStringAtPath("stellar.spending_policy.threshold_xlm")
+	if threshold == "" {
+		return policy, false
+	}
+	var err error
+	if policy.ThresholdXLM, err = strconv.ParseFloat(threshold, 64); err != nil {
+		return policy, false
+	}
+	policy.Mode, _ = g.Env.GetConfig().GetStringAtPath("stellar.spending_policy.mode")
+	if delayStr, found := g.Env.GetConfig().GetStringAtPath("stellar.spending_policy.delay"); found {
+		policy.Delay, _ = time.ParseDuration(delayStr)
+	}
+	if policy.Delay == 0 {
+		policy.Delay = 10 * time.Minute
+	}
+	return policy, true
+}
+
+func pendingPaymentDbKey(to, amount string) libkb.DbKey {
+	return libkb.DbKey{Typ: libkb.DBStellarPendingPayment, Key: to + ":" + amount}
+}
+
+// CheckSpendingPolicy gates a payment of amountXLM before signing.
+// A nil return means proceed.
+func CheckSpendingPolicy(m libkb.MetaContext, to string, amountXLM string) error {
+	policy, ok := loadSpendingPolicy(m.G())
+	if !ok {
+		return nil
+	}
+	amount, err := strconv.ParseFloat(amountXLM, 64)
+	if err != nil || amount < policy.ThresholdXLM {
+		return nil
+	}
+
+	switch policy.Mode {
+	case "second-device":
+		if err := libkb.ConsumeHighLinkApproval(m, stellarApprovalOperation); err != nil {
+			return fmt.Errorf("payments over %.2f XLM need approval from a second device (operation %q)",
+				policy.ThresholdXLM, stellarApprovalOperation)
+		}
+		return nil
+	default: // delay window
+		var pending pendingPayment
+		key := pendingPaymentDbKey(to, amountXLM)
+		found, _ := m.G().LocalDb.GetInto(&pending, key)
+		now := m.G().Clock().Now()
+		if found {
+			elapsed := now.Sub(pending.CreatedAt)
+			switch {
+			case elapsed > policy.Delay+pendingPaymentConfirmWindow:
+				// Abandoned record: start a fresh window below rather
+				// than honoring a months-old approval.
+			case elapsed >= policy.Delay:
+				// The window has passed without cancellation: proceed
+				// and consume the pending record.
+				_ = m.G().LocalDb.Delete(key)
+				return nil
+			default:
+				return fmt.Errorf("payment of %s XLM to %s is in its cancellation window for another %s (cancel with `keybase wallet cancel-pending`)",
+					amountXLM, to, (policy.Delay - elapsed).Round(time.Second))
+			}
+		}
+		record := pendingPayment{Amount: amountXLM, To: to, CreatedAt: now}
+		if err := m.G().LocalDb.PutObj(key, nil, record); err != nil {
+			return err
+		}
+		return fmt.Errorf("payment of %s XLM exceeds your %.2f XLM threshold; re-run after %s to confirm, or cancel with `keybase wallet cancel-pending`",
+			amountXLM, policy.ThresholdXLM, policy.Delay)
+	}
+}
+
+// CancelPendingPayment aborts a delayed payment inside its window.
+func CancelPendingPayment(m libkb.MetaContext, to string, amountXLM string) error {
+	key := pendingPaymentDbKey(to, amountXLM)
+	var pending pendingPayment
+	found, err := m.G().LocalDb.GetInto(&pending, key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no pending payment of %s XLM to %s", amountXLM, to)
+	}
+	return m.G().LocalDb.Delete(key)
+}
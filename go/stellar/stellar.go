@@ -161,6 +161,21 @@ func ImportSecretKey(ctx context.Context, g *libkb.GlobalContext, secretKey stel
 	return remote.Post(ctx, g, nextBundle)
 }
 
+// WatchAccount adds a stellar address to the user's bundle as
+// watch-only: its balance and history show up in the wallet, but there
+// is no secret key, so it can never spend.
+func WatchAccount(ctx context.Context, g *libkb.GlobalContext, accountID stellar1.AccountID, accountName string) (err error) {
+	prevBundle, _, err := remote.Fetch(ctx, g)
+	if err != nil {
+		return err
+	}
+	nextBundle := bundle.Advance(prevBundle)
+	if err := bundle.AddWatchAccount(&nextBundle, accountID, accountName); err != nil {
+		return err
+	}
+	return remote.Post(ctx, g, nextBundle)
+}
+
 func ExportSecretKey(ctx context.Context, g *libkb.GlobalContext, accountID stellar1.AccountID) (res stellar1.SecretKey, err error) {
 	prevBundle, _, err := remote.Fetch(ctx, g)
 	if err != nil {
@@ -224,7 +239,15 @@ func LookupSender(ctx context.Context, g *libkb.GlobalContext, accountID stellar
 	if err != nil {
 		return stellar1.BundleEntry{}, err
 	}
+	if entry.Mode == stellar1.AccountMode_WATCH {
+		return stellar1.BundleEntry{}, fmt.Errorf("account %v is watch-only and cannot send", entry.AccountID)
+	}
 	if len(entry.Signers) == 0 {
+		// Hardware-backed accounts keep no seed in the bundle; the
+		// payment path routes them through their TxSigner.
+		if IsAccountHardwareBacked(stellarnet.AddressStr(entry.AccountID.String())) {
+			return entry, nil
+		}
 		return stellar1.BundleEntry{}, errors.New("no signer for bundle")
 	}
 	if len(entry.Signers) > 1 {
@@ -413,12 +436,23 @@ func SendPaymentGUI(m libkb.MetaContext, remoter remote.Remoter, sendArg SendPay
 func sendPayment(m libkb.MetaContext, remoter remote.Remoter, sendArg SendPaymentArg, isCLI bool) (res SendPaymentResult, err error) {
 	defer m.CTraceTimed("Stellar.SendPayment", func() error { return err })()
 
+	// enforce any per-device spending policy before anything is signed
+	if err = CheckSpendingPolicy(m, string(sendArg.To), sendArg.Amount); err != nil {
+		return res, err
+	}
+
 	// look up sender account
 	senderEntry, err := LookupSender(m.Ctx(), m.G(), sendArg.From)
 	if err != nil {
 		return res, err
 	}
-	senderSeed := senderEntry.Signers[0]
+	senderHW := IsAccountHardwareBacked(stellarnet.AddressStr(senderEntry.AccountID.String()))
+	var senderSeed stellar1.SecretKey
+	if len(senderEntry.Signers) > 0 {
+		senderSeed = senderEntry.Signers[0]
+	} else if !senderHW {
+		return res, errors.New("no signer for bundle")
+	}
 
 	// look up recipient
 	recipient, err := LookupRecipient(m, sendArg.To, isCLI)
@@ -429,14 +463,19 @@ func sendPayment(m libkb.MetaContext, remoter remote.Remoter, sendArg SendPaymen
 	m.CDebugf("using stellar network passphrase: %q", stellarnet.Network().Passphrase)
 
 	if recipient.AccountID == nil || sendArg.ForceRelay {
+		if senderHW {
+			return res, errors.New("relay payments from hardware-backed accounts are not supported")
+		}
 		return sendRelayPayment(m, remoter,
 			senderSeed, sendArg.FromSeqno, recipient, sendArg.Amount, sendArg.DisplayBalance,
 			sendArg.SecretNote, sendArg.PublicMemo, sendArg.QuickReturn)
 	}
 
-	senderSeed2, err := stellarnet.NewSeedStr(senderSeed.SecureNoLogString())
-	if err != nil {
-		return res, err
+	var senderSeed2 stellarnet.SeedStr
+	if !senderHW {
+		if senderSeed2, err = stellarnet.NewSeedStr(senderSeed.SecureNoLogString()); err != nil {
+			return res, err
+		}
 	}
 
 	post := stellar1.PaymentDirectPost{
@@ -460,7 +499,23 @@ func sendPayment(m libkb.MetaContext, remoter remote.Remoter, sendArg SendPaymen
 	if err != nil {
 		return res, fmt.Errorf("error checking destination account balance: %v", err)
 	}
-	if !funded {
+
+	// Hardware-backed sender accounts sign on the device; the seed
+	// never enters this process.
+	hwSigner, err := SignerForAccount(m.G(), stellarnet.AddressStr(senderEntry.AccountID.String()))
+	if err != nil {
+		return res, err
+	}
+	if hwSigner != nil {
+		signed, txHash, herr := HardwarePaymentTransaction(hwSigner,
+			stellarnet.AddressStr(senderEntry.AccountID.String()), *recipient.AccountID,
+			sendArg.Amount, sendArg.PublicMemo, funded, sp)
+		if herr != nil {
+			return res, herr
+		}
+		post.SignedTransaction = signed
+		txID = txHash
+	} else if !funded {
 		// if no balance, create_account operation
 		// we could check here to make sure that amount is at least 1XLM
 		// but for now, just let stellar-core tell us there was an error
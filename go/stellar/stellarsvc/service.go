@@ -58,9 +58,10 @@ type preambleArg struct {
 
 // Preamble
 // Example usage:
-//   ctx, err, fin := c.Preamble(...)
-//   defer fin()
-//   if err != nil { return err }
+//
+//	ctx, err, fin := c.Preamble(...)
+//	defer fin()
+//	if err != nil { return err }
 func (s *Server) Preamble(inCtx context.Context, opts preambleArg) (ctx context.Context, err error, fin func()) {
 	ctx = s.logTag(inCtx)
 	getFinalErr := func() error {
@@ -70,6 +71,10 @@ func (s *Server) Preamble(inCtx context.Context, opts preambleArg) (ctx context.
 		return *opts.Err
 	}
 	fin = s.G().CTraceTimed(ctx, opts.RPCName, getFinalErr)
+	if libkb.PolicyFeatureDisabled("wallet") {
+		err = fmt.Errorf("the wallet is disabled by your administrator's policy")
+		return ctx, err, fin
+	}
 	if !opts.AllowLoggedOut {
 		if err = s.assertLoggedIn(ctx); err != nil {
 			return ctx, err, fin
@@ -284,6 +289,141 @@ func (s *Server) WalletInitLocal(ctx context.Context) (err error) {
 	return err
 }
 
+// CancelPendingCLILocal aborts a payment that is still inside its
+// spending-policy delay window.
+func (s *Server) CancelPendingCLILocal(ctx context.Context, arg stellar1.CancelPendingCLILocalArg) (err error) {
+	ctx, err, fin := s.Preamble(ctx, preambleArg{
+		RPCName: "CancelPendingCLILocal",
+		Err:     &err,
+	})
+	defer fin()
+	if err != nil {
+		return err
+	}
+
+	return stellar.CancelPendingPayment(libkb.NewMetaContext(ctx, s.G()), arg.To, arg.Amount)
+}
+
+// WatchAccountLocal adds a stellar address as watch-only: no secret
+// key is stored, so the account can be monitored but never spent from.
+func (s *Server) WatchAccountLocal(ctx context.Context, arg stellar1.WatchAccountLocalArg) (err error) {
+	ctx, err, fin := s.Preamble(ctx, preambleArg{
+		RPCName:       "WatchAccountLocal",
+		Err:           &err,
+		RequireWallet: true,
+	})
+	defer fin()
+	if err != nil {
+		return err
+	}
+
+	return stellar.WatchAccount(ctx, s.G(), arg.AccountID, arg.Name)
+}
+
+// senderSeed fetches the in-process seed for one of the user's
+// accounts (hardware-backed accounts have none and are refused here).
+func (s *Server) senderSeed(ctx context.Context, accountID stellar1.AccountID) (stellarnet.SeedStr, error) {
+	entry, err := stellar.LookupSender(ctx, s.G(), accountID)
+	if err != nil {
+		return "", err
+	}
+	if len(entry.Signers) == 0 {
+		return "", fmt.Errorf("account %s has no local secret key", entry.AccountID)
+	}
+	return stellarnet.NewSeedStr(entry.Signers[0].SecureNoLogString())
+}
+
+// AddTrustlineLocal opens (or adjusts) a trustline to an issued asset.
+func (s *Server) AddTrustlineLocal(ctx context.Context, arg stellar1.AddTrustlineLocalArg) (txID string, err error) {
+	ctx, err, fin := s.Preamble(ctx, preambleArg{
+		RPCName:       "AddTrustlineLocal",
+		Err:           &err,
+		RequireWallet: true,
+	})
+	defer fin()
+	if err != nil {
+		return txID, err
+	}
+	seed, err := s.senderSeed(ctx, arg.AccountID)
+	if err != nil {
+		return txID, err
+	}
+	return stellar.AddTrustline(seed, stellar.Asset{Code: arg.Code, Issuer: arg.Issuer}, arg.Limit)
+}
+
+// RemoveTrustlineLocal closes a trustline (balance must be zero).
+func (s *Server) RemoveTrustlineLocal(ctx context.Context, arg stellar1.RemoveTrustlineLocalArg) (txID string, err error) {
+	ctx, err, fin := s.Preamble(ctx, preambleArg{
+		RPCName:       "RemoveTrustlineLocal",
+		Err:           &err,
+		RequireWallet: true,
+	})
+	defer fin()
+	if err != nil {
+		return txID, err
+	}
+	seed, err := s.senderSeed(ctx, arg.AccountID)
+	if err != nil {
+		return txID, err
+	}
+	return stellar.RemoveTrustline(seed, stellar.Asset{Code: arg.Code, Issuer: arg.Issuer})
+}
+
+// SendPathPaymentCLILocal performs a cross-asset payment bounded by
+// sendMax slippage.
+func (s *Server) SendPathPaymentCLILocal(ctx context.Context, arg stellar1.SendPathPaymentCLILocalArg) (txID string, err error) {
+	ctx, err, fin := s.Preamble(ctx, preambleArg{
+		RPCName:       "SendPathPaymentCLILocal",
+		Err:           &err,
+		RequireWallet: true,
+	})
+	defer fin()
+	if err != nil {
+		return txID, err
+	}
+	seed, err := s.senderSeed(ctx, arg.FromAccountID)
+	if err != nil {
+		return txID, err
+	}
+	return stellar.SendPathPayment(seed, stellar.PathPaymentArg{
+		To:         arg.Recipient,
+		SendAsset:  stellar.Asset{Code: arg.SendCode, Issuer: arg.SendIssuer},
+		SendMax:    arg.SendMax,
+		DestAsset:  stellar.Asset{Code: arg.DestCode, Issuer: arg.DestIssuer},
+		DestAmount: arg.DestAmount,
+	})
+}
+
+// AssetBalancesLocal returns all balances on the account, issued
+// assets included.
+func (s *Server) AssetBalancesLocal(ctx context.Context, accountID stellar1.AccountID) (res []stellar1.AssetBalanceLocal, err error) {
+	ctx, err, fin := s.Preamble(ctx, preambleArg{
+		RPCName: "AssetBalancesLocal",
+		Err:     &err,
+	})
+	defer fin()
+	if err != nil {
+		return nil, err
+	}
+	balances, err := stellar.AssetBalances(stellarnet.AddressStr(accountID.String()))
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range balances {
+		code := b.Asset.Code
+		if b.Asset.Type == "native" {
+			code = "XLM"
+		}
+		res = append(res, stellar1.AssetBalanceLocal{
+			Code:    code,
+			Issuer:  b.Asset.Issuer,
+			Balance: b.Balance,
+			Limit:   b.Limit,
+		})
+	}
+	return res, nil
+}
+
 func (s *Server) SetDisplayCurrency(ctx context.Context, arg stellar1.SetDisplayCurrencyArg) (err error) {
 	ctx, err, fin := s.Preamble(ctx, preambleArg{
 		RPCName:       fmt.Sprintf("SetDisplayCurrency(%s, %s)", arg.AccountID, arg.Currency),
@@ -350,9 +490,10 @@ func (s *Server) WalletGetAccountsCLILocal(ctx context.Context) (ret []stellar1.
 	for _, account := range currentBundle.Accounts {
 		accID := account.AccountID
 		acc := stellar1.OwnAccountCLILocal{
-			AccountID: accID,
-			IsPrimary: account.IsPrimary,
-			Name:      account.Name,
+			AccountID:   accID,
+			IsPrimary:   account.IsPrimary,
+			IsWatchOnly: account.Mode == stellar1.AccountMode_WATCH,
+			Name:        account.Name,
 		}
 
 		balances, err := s.remoter.Balances(ctx, accID)
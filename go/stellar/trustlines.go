@@ -0,0 +1,118 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package stellar
+
+import (
+	"fmt"
+
+	"github.com/keybase/stellarnet"
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+)
+
+// Multi-asset support: trustline management for issued assets, balances
+// beyond the native lumen, and path payments with a slippage bound.
+// Transactions are built with the horizon SDK and signed/submitted
+// through the same stellarnet plumbing the native-payment path uses.
+
+// Asset names an issued asset.
+type Asset struct {
+	Code   string
+	Issuer string
+}
+
+func (a Asset) String() string { return a.Code + "/" + a.Issuer }
+
+// AddTrustline opens (or adjusts) a trustline from the account to the
+// asset, with the given limit ("" = maximum).
+func AddTrustline(from stellarnet.SeedStr, asset Asset, limit string) (txID string, err error) {
+	muts := []build.TransactionMutator{
+		build.SourceAccount{AddressOrSeed: from.SecureNoLogString()},
+		stellarnet.Network(),
+		build.AutoSequence{SequenceProvider: stellarnet.Client()},
+	}
+	if limit == "" {
+		muts = append(muts, build.Trust(asset.Code, asset.Issuer))
+	} else {
+		muts = append(muts, build.Trust(asset.Code, asset.Issuer, build.Limit(limit)))
+	}
+	return signAndSubmit(from, muts)
+}
+
+// RemoveTrustline closes a trustline (the asset balance must be zero).
+func RemoveTrustline(from stellarnet.SeedStr, asset Asset) (txID string, err error) {
+	return signAndSubmit(from, []build.TransactionMutator{
+		build.SourceAccount{AddressOrSeed: from.SecureNoLogString()},
+		stellarnet.Network(),
+		build.AutoSequence{SequenceProvider: stellarnet.Client()},
+		build.RemoveTrust(asset.Code, asset.Issuer),
+	})
+}
+
+// PathPaymentArg describes a cross-asset send: deliver destAmount of
+// destAsset to the recipient, spending at most sendMax of the source
+// asset (the slippage bound).
+type PathPaymentArg struct {
+	To         string
+	SendAsset  Asset  // zero value = native XLM
+	SendMax    string // slippage limit in the source asset
+	DestAsset  Asset  // zero value = native XLM
+	DestAmount string
+}
+
+func paymentAsset(a Asset, amount string) build.PaymentMutator {
+	if a.Code == "" {
+		return build.NativeAmount{Amount: amount}
+	}
+	return build.CreditAmount{Code: a.Code, Issuer: a.Issuer, Amount: amount}
+}
+
+// SendPathPayment performs a path payment with the slippage bound.
+func SendPathPayment(from stellarnet.SeedStr, arg PathPaymentArg) (txID string, err error) {
+	if arg.SendMax == "" {
+		return "", fmt.Errorf("path payments need a send-max slippage bound")
+	}
+	var payWith build.PayWithPath
+	if arg.SendAsset.Code == "" {
+		payWith = build.PayWith(build.NativeAsset(), arg.SendMax)
+	} else {
+		payWith = build.PayWith(
+			build.CreditAsset(arg.SendAsset.Code, arg.SendAsset.Issuer), arg.SendMax)
+	}
+
+	dest := paymentAsset(arg.DestAsset, arg.DestAmount)
+	return signAndSubmit(from, []build.TransactionMutator{
+		build.SourceAccount{AddressOrSeed: from.SecureNoLogString()},
+		stellarnet.Network(),
+		build.AutoSequence{SequenceProvider: stellarnet.Client()},
+		build.Payment(
+			build.Destination{AddressOrSeed: arg.To},
+			dest,
+			payWith,
+		),
+	})
+}
+
+// AssetBalances returns every balance on the account, the native lumens
+// included, for multi-asset display.
+func AssetBalances(accountID stellarnet.AddressStr) ([]horizon.Balance, error) {
+	return stellarnet.NewAccount(accountID).Balances()
+}
+
+func signAndSubmit(from stellarnet.SeedStr, muts []build.TransactionMutator) (txID string, err error) {
+	tx, err := build.Transaction(muts...)
+	if err != nil {
+		return "", err
+	}
+	txe, err := tx.Sign(from.SecureNoLogString())
+	if err != nil {
+		return "", err
+	}
+	signed, err := txe.Base64()
+	if err != nil {
+		return "", err
+	}
+	_, txID, err = stellarnet.Submit(signed)
+	return txID, err
+}
@@ -329,6 +329,66 @@ func (s *stellarRetryClient) WalletInitLocal(ctx context.Context) (err error) {
 	return err
 }
 
+func (s *stellarRetryClient) CancelPendingCLILocal(ctx context.Context, arg stellar1.CancelPendingCLILocalArg) (err error) {
+	for i := 0; i < retryCount; i++ {
+		err = s.cli.CancelPendingCLILocal(ctx, arg)
+		if err == nil {
+			break
+		}
+	}
+	return err
+}
+
+func (s *stellarRetryClient) WatchAccountLocal(ctx context.Context, arg stellar1.WatchAccountLocalArg) (err error) {
+	for i := 0; i < retryCount; i++ {
+		err = s.cli.WatchAccountLocal(ctx, arg)
+		if err == nil {
+			break
+		}
+	}
+	return err
+}
+
+func (s *stellarRetryClient) AddTrustlineLocal(ctx context.Context, arg stellar1.AddTrustlineLocalArg) (res string, err error) {
+	for i := 0; i < retryCount; i++ {
+		res, err = s.cli.AddTrustlineLocal(ctx, arg)
+		if err == nil {
+			break
+		}
+	}
+	return res, err
+}
+
+func (s *stellarRetryClient) RemoveTrustlineLocal(ctx context.Context, arg stellar1.RemoveTrustlineLocalArg) (res string, err error) {
+	for i := 0; i < retryCount; i++ {
+		res, err = s.cli.RemoveTrustlineLocal(ctx, arg)
+		if err == nil {
+			break
+		}
+	}
+	return res, err
+}
+
+func (s *stellarRetryClient) SendPathPaymentCLILocal(ctx context.Context, arg stellar1.SendPathPaymentCLILocalArg) (res string, err error) {
+	for i := 0; i < retryCount; i++ {
+		res, err = s.cli.SendPathPaymentCLILocal(ctx, arg)
+		if err == nil {
+			break
+		}
+	}
+	return res, err
+}
+
+func (s *stellarRetryClient) AssetBalancesLocal(ctx context.Context, accountID stellar1.AccountID) (res []stellar1.AssetBalanceLocal, err error) {
+	for i := 0; i < retryCount; i++ {
+		res, err = s.cli.AssetBalancesLocal(ctx, accountID)
+		if err == nil {
+			break
+		}
+	}
+	return res, err
+}
+
 func (s *stellarRetryClient) WalletDumpLocal(ctx context.Context) (res stellar1.Bundle, err error) {
 	for i := 0; i < retryCount; i++ {
 		res, err = s.cli.WalletDumpLocal(ctx)
@@ -0,0 +1,180 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// Package teamkv is a small encrypted key-value store scoped to a team:
+// values are boxed to the per-team key (same crypto the encrypted git
+// metadata store uses), stored server-side as opaque ciphertext, and
+// versioned with compare-and-swap revisions so concurrent writers
+// detect each other. Bots and tooling share secrets and config through
+// it without standing up their own infrastructure.
+package teamkv
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/keybase/client/go/git"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// Entry is one decrypted KV pair.
+type Entry struct {
+	Key      string
+	Value    []byte
+	Revision int
+}
+
+func teamIDVis(ctx context.Context, g *libkb.GlobalContext, teamname string) (keybase1.TeamIDWithVisibility, error) {
+	teamer := git.NewTeamer(g)
+	return teamer.LookupOrCreate(ctx, keybase1.Folder{
+		Name:       teamname,
+		FolderType: keybase1.FolderType_TEAM,
+		Private:    true,
+	})
+}
+
+// Put stores a value under key. prevRevision 0 means "create"; any
+// other value is a compare-and-swap against the server's revision, so a
+// racing write surfaces as an error instead of silently clobbering.
+func Put(ctx context.Context, g *libkb.GlobalContext, teamname, key string, value []byte, prevRevision int) error {
+	idVis, err := teamIDVis(ctx, g, teamname)
+	if err != nil {
+		return err
+	}
+	boxed, err := git.NewCrypto(g).Box(ctx, value, idVis)
+	if err != nil {
+		return err
+	}
+
+	apiArg := libkb.APIArg{
+		Endpoint:    "team/kv/put",
+		SessionType: libkb.APISessionTypeREQUIRED,
+		NetContext:  ctx,
+		Args: libkb.HTTPArgs{
+			"team_id":            libkb.S{Val: string(idVis.TeamID)},
+			"key":                libkb.S{Val: key},
+			"encrypted_value":    libkb.S{Val: base64.StdEncoding.EncodeToString(boxed.E)},
+			"nonce":              libkb.S{Val: base64.StdEncoding.EncodeToString(boxed.N[:])},
+			"key_generation":     libkb.I{Val: int(boxed.Gen)},
+			"encryption_version": libkb.I{Val: boxed.V},
+			"prev_revision":      libkb.I{Val: prevRevision},
+		},
+	}
+	_, err = g.GetAPI().Post(apiArg)
+	return err
+}
+
+// serverEntry is the wire form of one row.
+type serverEntry struct {
+	Key            string `json:"key"`
+	EncryptedValue string `json:"encrypted_value"`
+	Nonce          string `json:"nonce"`
+	KeyGeneration  int    `json:"key_generation"`
+	Version        int    `json:"encryption_version"`
+	Revision       int    `json:"revision"`
+}
+
+func (e serverEntry) decrypt(ctx context.Context, g *libkb.GlobalContext,
+	idVis keybase1.TeamIDWithVisibility) (Entry, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(e.EncryptedValue)
+	if err != nil {
+		return Entry{}, err
+	}
+	nonceRaw, err := base64.StdEncoding.DecodeString(e.Nonce)
+	if err != nil {
+		return Entry{}, err
+	}
+	var nonce keybase1.BoxNonce
+	if copy(nonce[:], nonceRaw) != len(nonce) {
+		return Entry{}, fmt.Errorf("bad nonce length for key %q", e.Key)
+	}
+	plaintext, err := git.NewCrypto(g).Unbox(ctx, idVis, &keybase1.EncryptedGitMetadata{
+		V:   e.Version,
+		E:   ciphertext,
+		N:   nonce,
+		Gen: keybase1.PerTeamKeyGeneration(e.KeyGeneration),
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{Key: e.Key, Value: plaintext, Revision: e.Revision}, nil
+}
+
+// Get fetches and decrypts one key.
+func Get(ctx context.Context, g *libkb.GlobalContext, teamname, key string) (Entry, error) {
+	idVis, err := teamIDVis(ctx, g, teamname)
+	if err != nil {
+		return Entry{}, err
+	}
+	var res struct {
+		libkb.AppStatusEmbed
+		Entry serverEntry `json:"entry"`
+	}
+	err = g.GetAPI().GetDecode(libkb.APIArg{
+		Endpoint:    "team/kv/get",
+		SessionType: libkb.APISessionTypeREQUIRED,
+		NetContext:  ctx,
+		Args: libkb.HTTPArgs{
+			"team_id": libkb.S{Val: string(idVis.TeamID)},
+			"key":     libkb.S{Val: key},
+		},
+	}, &res)
+	if err != nil {
+		return Entry{}, err
+	}
+	return res.Entry.decrypt(ctx, g, idVis)
+}
+
+// List fetches and decrypts every key in the team's store.
+func List(ctx context.Context, g *libkb.GlobalContext, teamname string) ([]Entry, error) {
+	idVis, err := teamIDVis(ctx, g, teamname)
+	if err != nil {
+		return nil, err
+	}
+	var res struct {
+		libkb.AppStatusEmbed
+		Entries []serverEntry `json:"entries"`
+	}
+	err = g.GetAPI().GetDecode(libkb.APIArg{
+		Endpoint:    "team/kv/list",
+		SessionType: libkb.APISessionTypeREQUIRED,
+		NetContext:  ctx,
+		Args: libkb.HTTPArgs{
+			"team_id": libkb.S{Val: string(idVis.TeamID)},
+		},
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Entry, 0, len(res.Entries))
+	for _, se := range res.Entries {
+		entry, derr := se.decrypt(ctx, g, idVis)
+		if derr != nil {
+			g.Log.CDebugf(ctx, "teamkv.List: cannot decrypt %q: %s", se.Key, derr)
+			continue
+		}
+		ret = append(ret, entry)
+	}
+	return ret, nil
+}
+
+// Del removes a key, compare-and-swapped on revision.
+func Del(ctx context.Context, g *libkb.GlobalContext, teamname, key string, prevRevision int) error {
+	idVis, err := teamIDVis(ctx, g, teamname)
+	if err != nil {
+		return err
+	}
+	_, err = g.GetAPI().Post(libkb.APIArg{
+		Endpoint:    "team/kv/del",
+		SessionType: libkb.APISessionTypeREQUIRED,
+		NetContext:  ctx,
+		Args: libkb.HTTPArgs{
+			"team_id":       libkb.S{Val: string(idVis.TeamID)},
+			"key":           libkb.S{Val: key},
+			"prev_revision": libkb.I{Val: prevRevision},
+		},
+	})
+	return err
+}
@@ -0,0 +1,157 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package teams
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// Opt-in two-admin rule for sensitive role changes: with
+// teams.two_admin_rule listing a team in config, promoting anyone to
+// admin or owner takes two distinct admins. The first admin's attempt
+// records a pending change; a second admin approves it, and only then
+// is the change link signed and posted. Enforced client-side in
+// EditMember.
+//
+// Pending records currently live in this service's local DB, so the
+// approving admin must act on the same service instance (shared admin
+// hosts, or an approval relayed by the operator). Both proposer and
+// approver must hold admin in the team; the proposing device is
+// recorded for the audit trail. Within one host the rule cannot
+// distinguish two admin accounts driven by one human from two humans —
+// making it fully distributed needs the pending record ferried between
+// admin devices (a structured chat message to the admins is the
+// natural transport) and is the known follow-on.
+
+type PendingRoleChange struct {
+	Team             string            `json:"team"`
+	Username         string            `json:"username"`
+	Role             keybase1.TeamRole `json:"role"`
+	ProposedBy       string            `json:"proposed_by"`
+	ProposedByDevice keybase1.DeviceID `json:"proposed_by_device"`
+	ProposedAt       time.Time         `json:"proposed_at"`
+}
+
+const pendingChangeTTL = 7 * 24 * time.Hour
+
+func pendingChangeDbKey(team, username string) libkb.DbKey {
+	return libkb.DbKey{Typ: libkb.DBTeamPendingChange, Key: team + ":" + username}
+}
+
+// twoAdminRuleEnabled reports whether the team opted into the rule.
+func twoAdminRuleEnabled(g *libkb.GlobalContext, teamname string) bool {
+	raw, err := g.Env.GetConfig().GetInterfaceAtPath("teams.two_admin_rule")
+	if err != nil {
+		return false
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range list {
+		if name, ok := item.(string); ok && name == teamname {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRoleChangeApproval gates a proposed role change under the
+// two-admin rule. It returns nil when the change may proceed (rule off,
+// role not sensitive, or a second admin already approved), and an
+// explanatory error otherwise, recording the proposal for approval.
+func checkRoleChangeApproval(ctx context.Context, g *libkb.GlobalContext,
+	teamname, username string, role keybase1.TeamRole) error {
+	return checkRoleChangeApprovalAs(ctx, g, g.Env.GetUsername().String(), teamname, username, role)
+}
+
+func checkRoleChangeApprovalAs(ctx context.Context, g *libkb.GlobalContext,
+	me, teamname, username string, role keybase1.TeamRole) error {
+	if !twoAdminRuleEnabled(g, teamname) {
+		return nil
+	}
+	if role != keybase1.TeamRole_ADMIN && role != keybase1.TeamRole_OWNER {
+		return nil
+	}
+	// Only admins of the team participate in the rule at all; this
+	// also stops an unrelated second account from "approving".
+	if err := assertIsTeamAdmin(ctx, g, teamname, me); err != nil {
+		return err
+	}
+
+	var pending PendingRoleChange
+	found, _ := g.LocalDb.GetInto(&pending, pendingChangeDbKey(teamname, username))
+	if found && time.Since(pending.ProposedAt) <= pendingChangeTTL && pending.Role == role {
+		if pending.ProposedBy != me {
+			// Second admin approving: consume the record and proceed.
+			_ = g.LocalDb.Delete(pendingChangeDbKey(teamname, username))
+			g.Log.CDebugf(ctx, "two-admin rule: change to %s/%s approved by %s (proposed by %s)",
+				teamname, username, me, pending.ProposedBy)
+			return nil
+		}
+		return fmt.Errorf("change already proposed by %s at %s; a different admin on a different device must approve it by re-running the command",
+			pending.ProposedBy, pending.ProposedAt.Format("2006-01-02 15:04"))
+	}
+
+	record := PendingRoleChange{
+		Team:             teamname,
+		Username:         username,
+		Role:             role,
+		ProposedBy:       me,
+		ProposedByDevice: g.ActiveDevice.DeviceID(),
+		ProposedAt:       time.Now(),
+	}
+	if err := g.LocalDb.PutObj(pendingChangeDbKey(teamname, username), nil, record); err != nil {
+		return err
+	}
+	return fmt.Errorf("the %s role change for %s is recorded as pending (two-admin rule); a second admin must re-run the command to approve", role, username)
+}
+
+// assertIsTeamAdmin verifies the given user holds admin or above in
+// the team. Swappable so unit tests without a team loader can stub
+// the load.
+var assertIsTeamAdmin = func(ctx context.Context, g *libkb.GlobalContext, teamname, username string) error {
+	team, err := Load(ctx, g, keybase1.LoadTeamArg{Name: teamname})
+	if err != nil {
+		return err
+	}
+	upak, _, err := g.GetUPAKLoader().LoadV2(
+		libkb.NewLoadUserArgWithMetaContext(libkb.NewMetaContext(ctx, g)).
+			WithName(username).WithPublicKeyOptional())
+	if err != nil {
+		return err
+	}
+	role, err := team.MemberRole(ctx, upak.Current.ToUserVersion())
+	if err != nil {
+		return err
+	}
+	if !role.IsAdminOrAbove() {
+		return fmt.Errorf("two-admin rule: %s is not an admin of %s", username, teamname)
+	}
+	return nil
+}
+
+// ListPendingRoleChanges returns proposals awaiting a second admin.
+func ListPendingRoleChanges(g *libkb.GlobalContext) (ret []PendingRoleChange, err error) {
+	err = g.LocalDb.ForEachRawKv(func(id libkb.DbKey, value []byte) error {
+		if id.Typ != libkb.DBTeamPendingChange {
+			return nil
+		}
+		var pending PendingRoleChange
+		if jerr := json.Unmarshal(value, &pending); jerr != nil {
+			return nil
+		}
+		if time.Since(pending.ProposedAt) <= pendingChangeTTL {
+			ret = append(ret, pending)
+		}
+		return nil
+	})
+	return ret, err
+}
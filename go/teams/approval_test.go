@@ -0,0 +1,63 @@
+package teams
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keybase/client/go/externalstest"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestTwoAdminRule(t *testing.T) {
+	tc := externalstest.SetupTest(t, "two_admin", 1)
+	defer tc.Cleanup()
+	ctx := context.Background()
+
+	// The unit environment has no team loader; stand in for the
+	// admin-role check, which has its own coverage via team loads in
+	// the integration tier.
+	restore := assertIsTeamAdmin
+	assertIsTeamAdmin = func(ctx context.Context, g *libkb.GlobalContext, teamname, username string) error {
+		return nil
+	}
+	defer func() { assertIsTeamAdmin = restore }()
+
+	// Rule off: changes flow.
+	require.NoError(t, checkRoleChangeApprovalAs(ctx, tc.G, "alice", "acme", "bob", keybase1.TeamRole_ADMIN))
+
+	cfg := tc.G.Env.GetConfigFilename()
+	require.NoError(t, os.MkdirAll(filepath.Dir(cfg), 0700))
+	require.NoError(t, ioutil.WriteFile(cfg, []byte(
+		`{"teams": {"two_admin_rule": ["acme"]}}`), 0600))
+	require.NoError(t, tc.G.ConfigReload())
+
+	// Non-sensitive roles are unaffected.
+	require.NoError(t, checkRoleChangeApprovalAs(ctx, tc.G, "alice", "acme", "bob", keybase1.TeamRole_WRITER))
+
+	// First admin's attempt records the proposal and blocks.
+	err := checkRoleChangeApprovalAs(ctx, tc.G, "alice", "acme", "bob", keybase1.TeamRole_ADMIN)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pending")
+
+	pending, err := ListPendingRoleChanges(tc.G)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	// The same admin re-running still blocks.
+	err = checkRoleChangeApprovalAs(ctx, tc.G, "alice", "acme", "bob", keybase1.TeamRole_ADMIN)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "different admin")
+
+	// A different admin approves: the change proceeds and the record is
+	// consumed.
+	require.NoError(t, checkRoleChangeApprovalAs(ctx, tc.G, "carol", "acme", "bob", keybase1.TeamRole_ADMIN))
+
+	pending, err = ListPendingRoleChanges(tc.G)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package teams
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/net/context"
+)
+
+// Opt-in per-team key escrow: each new per-team key generation is
+// additionally boxed to an admin-designated offline recovery public key
+// (an air-gapped nacl DH key), so an organization can recover team data
+// even if every admin device is lost. Configure with
+//
+//   "teams": { "escrow": { "acme": { "recovery_kid": "0121..." } } }
+//
+// Escrow boxes are uploaded alongside the rotation; the recovery key
+// never has to come online until the day it's needed.
+
+// escrowRecoveryKID returns the configured recovery key for a team, or
+// nil if escrow is off.
+func escrowRecoveryKID(g *libkb.GlobalContext, teamname string) keybase1.KID {
+	kidStr, _ := g.Env.GetConfig().GetStringAtPath("teams.escrow." + teamname + ".recovery_kid")
+	if kidStr == "" {
+		return keybase1.KID("")
+	}
+	return keybase1.KIDFromString(kidStr)
+}
+
+// EscrowBox is one generation's seed sealed to the recovery key.
+type EscrowBox struct {
+	Generation keybase1.PerTeamKeyGeneration `json:"generation"`
+	// Ephemeral sender public key and the sealed seed, base64.
+	EphemeralKey string `json:"ephemeral_key"`
+	Box          string `json:"box"`
+}
+
+// makeEscrowBox seals a per-team seed to the recovery public key using
+// an ephemeral sender key, so the box is decryptable offline with just
+// the recovery private key.
+func makeEscrowBox(seed keybase1.PerTeamKeySeed, gen keybase1.PerTeamKeyGeneration,
+	recoveryKID keybase1.KID) (*EscrowBox, error) {
+	genericKey, err := libkb.ImportKeypairFromKID(recoveryKID)
+	if err != nil {
+		return nil, err
+	}
+	dhKey, ok := genericKey.(libkb.NaclDHKeyPair)
+	if !ok {
+		return nil, fmt.Errorf("recovery KID %s is not a nacl DH key", recoveryKID)
+	}
+
+	ephPub, ephPriv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte // zero nonce: the ephemeral key is single-use
+	seedBytes := seed.ToBytes()
+	recoveryPub := [32]byte(dhKey.Public)
+	sealed := box.Seal(nil, seedBytes, &nonce, &recoveryPub, ephPriv)
+
+	return &EscrowBox{
+		Generation:   gen,
+		EphemeralKey: base64.StdEncoding.EncodeToString(ephPub[:]),
+		Box:          base64.StdEncoding.EncodeToString(sealed),
+	}, nil
+}
+
+// EscrowCurrentKey boxes the team's current per-team seed to the
+// configured recovery key and uploads it. Call after rotations on teams
+// with escrow enabled; it is a no-op when the team hasn't opted in.
+func EscrowCurrentKey(ctx context.Context, g *libkb.GlobalContext, teamname string) error {
+	recoveryKID := escrowRecoveryKID(g, teamname)
+	if recoveryKID.IsNil() {
+		return nil
+	}
+
+	team, err := Load(ctx, g, keybase1.LoadTeamArg{Name: teamname, ForceRepoll: true})
+	if err != nil {
+		return err
+	}
+	seed, err := team.SharedSecret(ctx)
+	if err != nil {
+		return err
+	}
+	escrow, err := makeEscrowBox(seed, team.Generation(), recoveryKID)
+	if err != nil {
+		return err
+	}
+
+	_, err = g.GetAPI().Post(libkb.APIArg{
+		Endpoint:    "team/escrow",
+		SessionType: libkb.APISessionTypeREQUIRED,
+		NetContext:  ctx,
+		Args: libkb.HTTPArgs{
+			"team_id":       libkb.S{Val: string(team.ID)},
+			"generation":    libkb.I{Val: int(escrow.Generation)},
+			"recovery_kid":  libkb.S{Val: recoveryKID.String()},
+			"ephemeral_key": libkb.S{Val: escrow.EphemeralKey},
+			"box":           libkb.S{Val: escrow.Box},
+		},
+	})
+	return err
+}
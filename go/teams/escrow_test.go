@@ -0,0 +1,48 @@
+package teams
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestMakeEscrowBox(t *testing.T) {
+	// An "air-gapped" recovery key pair.
+	recoveryPub, recoveryPriv, err := box.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	var pair libkb.NaclDHKeyPair
+	copy(pair.Public[:], recoveryPub[:])
+
+	var seed keybase1.PerTeamKeySeed
+	copy(seed[:], []byte("0123456789abcdef0123456789abcdef"))
+
+	escrow, err := makeEscrowBox(seed, 7, pair.GetKID())
+	require.NoError(t, err)
+	require.Equal(t, keybase1.PerTeamKeyGeneration(7), escrow.Generation)
+
+	// The offline recovery key (and only it) opens the box.
+	ephPub, err := base64.StdEncoding.DecodeString(escrow.EphemeralKey)
+	require.NoError(t, err)
+	sealed, err := base64.StdEncoding.DecodeString(escrow.Box)
+	require.NoError(t, err)
+	var ephKey [32]byte
+	copy(ephKey[:], ephPub)
+	var nonce [24]byte
+	opened, ok := box.Open(nil, sealed, &nonce, &ephKey, recoveryPriv)
+	require.True(t, ok)
+	require.Equal(t, seed[:], opened)
+
+	// A different key can't.
+	_, otherPriv, _ := box.GenerateKey(rand.Reader)
+	_, ok = box.Open(nil, sealed, &nonce, &ephKey, otherPriv)
+	require.False(t, ok)
+
+	// Non-DH KIDs are refused.
+	_, err = makeEscrowBox(seed, 1, keybase1.KID("0120deadbeef0a"))
+	require.Error(t, err)
+}
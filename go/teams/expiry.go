@@ -0,0 +1,89 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package teams
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+// Time-boxed memberships: an admin adds a member with an expiry
+// (contractor until a date); the expiry is recorded on the admin's
+// device, a background sweep posts the removal link once the time
+// passes, and a warning window flags upcoming expiries.
+
+type MemberExpiry struct {
+	Team      string    `json:"team"`
+	Username  string    `json:"username"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// expiryWarningWindow is how far ahead of the deadline expiries get
+// surfaced as warnings.
+const expiryWarningWindow = 72 * time.Hour
+
+func memberExpiryDbKey(team, username string) libkb.DbKey {
+	return libkb.DbKey{Typ: libkb.DBTeamMemberExpiry, Key: team + ":" + username}
+}
+
+// RecordMemberExpiry notes that username's membership in team lapses at
+// the given time. Recorded on this (admin) device; the sweep here posts
+// the removal.
+func RecordMemberExpiry(g *libkb.GlobalContext, team, username string, expiresAt time.Time) error {
+	if expiresAt.Before(time.Now()) {
+		return fmt.Errorf("expiry %s is in the past", expiresAt)
+	}
+	return g.LocalDb.PutObj(memberExpiryDbKey(team, username), nil, MemberExpiry{
+		Team:      team,
+		Username:  username,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// ListMemberExpiries returns all recorded expiries on this device.
+func ListMemberExpiries(g *libkb.GlobalContext) (ret []MemberExpiry, err error) {
+	err = g.LocalDb.ForEachRawKv(func(id libkb.DbKey, value []byte) error {
+		if id.Typ != libkb.DBTeamMemberExpiry {
+			return nil
+		}
+		var exp MemberExpiry
+		if jerr := json.Unmarshal(value, &exp); jerr != nil {
+			return nil
+		}
+		ret = append(ret, exp)
+		return nil
+	})
+	return ret, err
+}
+
+// SweepExpiredMembers removes members whose time has passed and reports
+// upcoming expiries inside the warning window. It runs on admin
+// devices; removals that fail (not an admin here, offline) stay
+// recorded and are retried on the next sweep.
+func SweepExpiredMembers(ctx context.Context, g *libkb.GlobalContext) (removed []MemberExpiry, warnings []MemberExpiry, err error) {
+	expiries, err := ListMemberExpiries(g)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	for _, exp := range expiries {
+		switch {
+		case now.After(exp.ExpiresAt):
+			if rerr := RemoveMember(ctx, g, exp.Team, exp.Username); rerr != nil {
+				g.Log.CDebugf(ctx, "SweepExpiredMembers: failed to remove %s from %s: %s",
+					exp.Username, exp.Team, rerr)
+				continue
+			}
+			_ = g.LocalDb.Delete(memberExpiryDbKey(exp.Team, exp.Username))
+			removed = append(removed, exp)
+		case exp.ExpiresAt.Sub(now) < expiryWarningWindow:
+			warnings = append(warnings, exp)
+		}
+	}
+	return removed, warnings, nil
+}
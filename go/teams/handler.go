@@ -60,6 +60,11 @@ func HandleRotateRequest(ctx context.Context, g *libkb.GlobalContext, msg keybas
 		if team.Generation() > msg.Generation {
 			g.Log.CDebugf(ctx, "current team generation %d > team.clkr generation %d, not rotating",
 				team.Generation(), msg.Generation)
+			// The generation already advanced (the sweep above, or a
+			// racing device, rotated); make sure it's escrowed too.
+			if eerr := EscrowCurrentKey(ctx, g, team.Name().String()); eerr != nil {
+				g.Log.CDebugf(ctx, "CLKR: escrow of advanced key failed: %s", eerr)
+			}
 			return nil
 		}
 
@@ -70,6 +75,15 @@ func HandleRotateRequest(ctx context.Context, g *libkb.GlobalContext, msg keybas
 		}
 
 		g.Log.CDebugf(ctx, "success rotating team %s (%s)", team.Name(), teamID)
+
+		// CLKR rotations (member reset/revoke) are the common way a
+		// new generation appears; escrow it to the offline recovery
+		// key just like explicit RotateKey does, or the latest
+		// generation is routinely unrecoverable. Failures don't undo
+		// the rotation.
+		if eerr := EscrowCurrentKey(ctx, g, team.Name().String()); eerr != nil {
+			g.Log.CDebugf(ctx, "CLKR: escrow of new key failed: %s", eerr)
+		}
 		return nil
 	})
 }
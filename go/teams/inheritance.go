@@ -0,0 +1,102 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package teams
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// Settings inheritance for subteams: instead of configuring every
+// subteam by hand, a subteam inherits openness/join-role (and showcase
+// defaults) from the nearest ancestor that set them, resolved
+// client-side at load time. A subteam listed in
+// teams.inheritance.no_inherit (config) opts out and uses only its own
+// explicit settings.
+
+// LevelSettings is one team's own (explicit) settings in the chain of
+// ancestors.
+type LevelSettings struct {
+	Name     string
+	Open     bool
+	JoinAs   keybase1.TeamRole
+	Showcase bool
+}
+
+// EffectiveSettings is the inheritance-resolved view for a subteam.
+type EffectiveSettings struct {
+	Open     bool
+	JoinAs   keybase1.TeamRole
+	Showcase bool
+	// Which ancestor each setting came from ("" = nothing set anywhere).
+	OpenSource     string
+	ShowcaseSource string
+}
+
+// mergeInheritedSettings resolves settings across an ancestry chain,
+// ordered root first, leaf last. A setting comes from the deepest level
+// that set it.
+func mergeInheritedSettings(chain []LevelSettings) (res EffectiveSettings) {
+	res.JoinAs = keybase1.TeamRole_READER
+	for _, level := range chain {
+		if level.Open {
+			res.Open = true
+			res.JoinAs = level.JoinAs
+			res.OpenSource = level.Name
+		}
+		if level.Showcase {
+			res.Showcase = true
+			res.ShowcaseSource = level.Name
+		}
+	}
+	return res
+}
+
+// inheritanceDisabled reports whether the team opted out via config.
+func inheritanceDisabled(g *libkb.GlobalContext, name string) bool {
+	raw, err := g.Env.GetConfig().GetInterfaceAtPath("teams.inheritance.no_inherit")
+	if err != nil {
+		return false
+	}
+	if list, ok := raw.([]interface{}); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok && s == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResolveInheritedSettings loads each ancestor of a subteam and merges
+// their settings; for top-level teams (or opted-out subteams) it
+// returns the team's own settings only.
+func ResolveInheritedSettings(ctx context.Context, g *libkb.GlobalContext, name string) (EffectiveSettings, error) {
+	ancestors := []string{name}
+	if !inheritanceDisabled(g, name) {
+		parts := strings.Split(name, ".")
+		ancestors = ancestors[:0]
+		for i := range parts {
+			ancestors = append(ancestors, strings.Join(parts[:i+1], "."))
+		}
+	}
+
+	var chain []LevelSettings
+	for _, ancestor := range ancestors {
+		team, err := Load(ctx, g, keybase1.LoadTeamArg{Name: ancestor})
+		if err != nil {
+			return EffectiveSettings{}, fmt.Errorf("loading ancestor %s: %s", ancestor, err)
+		}
+		chain = append(chain, LevelSettings{
+			Name:   ancestor,
+			Open:   team.IsOpen(),
+			JoinAs: team.OpenTeamJoinAs(),
+		})
+	}
+	return mergeInheritedSettings(chain), nil
+}
@@ -0,0 +1,43 @@
+package teams
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeInheritedSettings(t *testing.T) {
+	// Nothing set anywhere: closed, default join role.
+	res := mergeInheritedSettings([]LevelSettings{
+		{Name: "acme"}, {Name: "acme.eng"},
+	})
+	require.False(t, res.Open)
+	require.Equal(t, "", res.OpenSource)
+
+	// Parent open, leaf silent: inherited.
+	res = mergeInheritedSettings([]LevelSettings{
+		{Name: "acme", Open: true, JoinAs: keybase1.TeamRole_WRITER},
+		{Name: "acme.eng"},
+	})
+	require.True(t, res.Open)
+	require.Equal(t, keybase1.TeamRole_WRITER, res.JoinAs)
+	require.Equal(t, "acme", res.OpenSource)
+
+	// Leaf override wins over the ancestor.
+	res = mergeInheritedSettings([]LevelSettings{
+		{Name: "acme", Open: true, JoinAs: keybase1.TeamRole_WRITER},
+		{Name: "acme.eng", Open: true, JoinAs: keybase1.TeamRole_READER},
+	})
+	require.Equal(t, keybase1.TeamRole_READER, res.JoinAs)
+	require.Equal(t, "acme.eng", res.OpenSource)
+
+	// Showcase inherits independently.
+	res = mergeInheritedSettings([]LevelSettings{
+		{Name: "acme", Showcase: true},
+		{Name: "acme.eng", Open: true, JoinAs: keybase1.TeamRole_READER},
+	})
+	require.True(t, res.Showcase)
+	require.Equal(t, "acme", res.ShowcaseSource)
+	require.Equal(t, "acme.eng", res.OpenSource)
+}
@@ -0,0 +1,160 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package teams
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// Invite links with usage limits and role presets, built on seitan
+// tokens (which are single-use): a "link" with max-use N pre-generates
+// N tokens under one handle, records expiry and role, and keeps an
+// audit trail of which link each joining member used. List and revoke
+// operate on the whole handle.
+
+type InviteLink struct {
+	Handle    string            `json:"handle"`
+	Team      string            `json:"team"`
+	Role      keybase1.TeamRole `json:"role"`
+	Tokens    []string          `json:"tokens"` // unredeemed seitan tokens
+	MaxUses   int               `json:"max_uses"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	CreatedAt time.Time         `json:"created_at"`
+	CreatedBy string            `json:"created_by"`
+	JoinedVia []string          `json:"joined_via"` // usernames that redeemed
+}
+
+func inviteLinkDbKey(team, handle string) libkb.DbKey {
+	return libkb.DbKey{Typ: libkb.DBTeamInviteLinks, Key: team + ":" + handle}
+}
+
+// CreateInviteLink makes a limited-use invite link: maxUses seitan
+// tokens under one handle, each carrying the preset role.
+func CreateInviteLink(ctx context.Context, g *libkb.GlobalContext, teamname, handle string,
+	role keybase1.TeamRole, maxUses int, expiresAt time.Time) (*InviteLink, error) {
+	if maxUses < 1 {
+		return nil, fmt.Errorf("invite links need max-uses >= 1")
+	}
+	if handle == "" {
+		return nil, fmt.Errorf("invite links need a handle (a short label)")
+	}
+	if existing, _ := GetInviteLink(g, teamname, handle); existing != nil {
+		return nil, fmt.Errorf("an invite link named %q already exists for %s", handle, teamname)
+	}
+
+	link := &InviteLink{
+		Handle:    handle,
+		Team:      teamname,
+		Role:      role,
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+		CreatedBy: g.Env.GetUsername().String(),
+	}
+	label := keybase1.NewSeitanKeyLabelWithSms(keybase1.SeitanKeyLabelSms{F: "invite-link:" + handle})
+	for i := 0; i < maxUses; i++ {
+		ikey, err := CreateSeitanToken(ctx, g, teamname, role, label)
+		if err != nil {
+			return nil, fmt.Errorf("generating token %d/%d: %s", i+1, maxUses, err)
+		}
+		link.Tokens = append(link.Tokens, string(ikey))
+	}
+	if err := g.LocalDb.PutObj(inviteLinkDbKey(teamname, handle), nil, link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// GetInviteLink fetches one link's record.
+func GetInviteLink(g *libkb.GlobalContext, teamname, handle string) (*InviteLink, error) {
+	var link InviteLink
+	found, err := g.LocalDb.GetInto(&link, inviteLinkDbKey(teamname, handle))
+	if err != nil || !found {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ListInviteLinks returns the outstanding links for a team.
+func ListInviteLinks(g *libkb.GlobalContext, teamname string) (ret []InviteLink, err error) {
+	err = g.LocalDb.ForEachRawKv(func(id libkb.DbKey, value []byte) error {
+		if id.Typ != libkb.DBTeamInviteLinks {
+			return nil
+		}
+		var link InviteLink
+		if jerr := json.Unmarshal(value, &link); jerr != nil {
+			return nil
+		}
+		if link.Team == teamname {
+			ret = append(ret, link)
+		}
+		return nil
+	})
+	return ret, err
+}
+
+// IsExpired reports whether the link has lapsed or is used up.
+func (l *InviteLink) IsExpired(now time.Time) bool {
+	if !l.ExpiresAt.IsZero() && now.After(l.ExpiresAt) {
+		return true
+	}
+	return len(l.Tokens) == 0
+}
+
+// RecordJoinViaLink notes a redemption for the audit trail, consuming
+// one token slot.
+func RecordJoinViaLink(g *libkb.GlobalContext, teamname, handle, username string) error {
+	link, err := GetInviteLink(g, teamname, handle)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return fmt.Errorf("no invite link %q for %s", handle, teamname)
+	}
+	if len(link.Tokens) > 0 {
+		link.Tokens = link.Tokens[1:]
+	}
+	link.JoinedVia = append(link.JoinedVia, username)
+	return g.LocalDb.PutObj(inviteLinkDbKey(teamname, handle), nil, link)
+}
+
+// RevokeInviteLink cancels the link's unredeemed invitations and drops
+// the record (the audit trail of past joins is returned for logging).
+func RevokeInviteLink(ctx context.Context, g *libkb.GlobalContext, teamname, handle string) (joinedVia []string, err error) {
+	link, err := GetInviteLink(g, teamname, handle)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, fmt.Errorf("no invite link %q for %s", handle, teamname)
+	}
+
+	// Cancel every unredeemed invitation: the seitan invite ID derives
+	// from the token, so each outstanding token maps to its invite.
+	for _, token := range link.Tokens {
+		sikey, derr := SeitanIKey(token).GenerateSIKey()
+		if derr != nil {
+			continue
+		}
+		scInviteID, derr := sikey.GenerateTeamInviteID()
+		if derr != nil {
+			continue
+		}
+		inviteID := keybase1.TeamInviteID(scInviteID)
+		if cerr := CancelInviteByID(ctx, g, teamname, inviteID); cerr != nil {
+			g.Log.CDebugf(ctx, "RevokeInviteLink: cancel of invite %s failed: %s", inviteID, cerr)
+		}
+	}
+
+	if err := g.LocalDb.Delete(inviteLinkDbKey(teamname, handle)); err != nil {
+		return nil, err
+	}
+	return link.JoinedVia, nil
+}
@@ -0,0 +1,51 @@
+package teams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/externalstest"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteLinkBookkeeping(t *testing.T) {
+	tc := externalstest.SetupTest(t, "invite_links", 1)
+	defer tc.Cleanup()
+
+	// Seed a link record directly (token creation needs a live team).
+	link := InviteLink{
+		Handle:    "contractors",
+		Team:      "acme",
+		Role:      keybase1.TeamRole_WRITER,
+		Tokens:    []string{"tok1", "tok2", "tok3"},
+		MaxUses:   3,
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, tc.G.LocalDb.PutObj(inviteLinkDbKey("acme", "contractors"), nil, link))
+
+	links, err := ListInviteLinks(tc.G, "acme")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.False(t, links[0].IsExpired(time.Now()))
+
+	// Joins consume tokens and build the audit trail.
+	require.NoError(t, RecordJoinViaLink(tc.G, "acme", "contractors", "dana"))
+	require.NoError(t, RecordJoinViaLink(tc.G, "acme", "contractors", "eli"))
+	got, err := GetInviteLink(tc.G, "acme", "contractors")
+	require.NoError(t, err)
+	require.Equal(t, []string{"dana", "eli"}, got.JoinedVia)
+	require.Len(t, got.Tokens, 1)
+
+	// Used up or past expiry reads as expired.
+	require.NoError(t, RecordJoinViaLink(tc.G, "acme", "contractors", "fay"))
+	got, _ = GetInviteLink(tc.G, "acme", "contractors")
+	require.True(t, got.IsExpired(time.Now()))
+	require.True(t, link.IsExpired(time.Now().Add(2*time.Hour)))
+
+	// Other teams see nothing.
+	links, err = ListInviteLinks(tc.G, "other")
+	require.NoError(t, err)
+	require.Empty(t, links)
+}
@@ -908,6 +908,19 @@ func (l *TeamLoader) load2DecideRepoll(ctx context.Context, arg load2ArgT, fromC
 		repoll = true
 	}
 
+	// Offline staleness budget: when the network is down and a fully
+	// verified cached chain exists within team.staleness_budget, serve
+	// it rather than hard-failing, so chat and KBFS keep working
+	// read-only. Explicit ForceRepoll still insists on the network.
+	if repoll && !arg.forceRepoll && fromCache != nil &&
+		l.G().ConnectivityMonitor.IsConnected(ctx) == libkb.ConnectivityMonitorNo {
+		budget := l.G().Env.GetTeamStalenessBudget()
+		if budget > 0 && l.G().Clock().Now().Sub(fromCache.CachedAt.Time()) <= budget {
+			l.G().Log.CDebugf(ctx, "load2DecideRepoll: offline; serving cached team inside staleness budget %v", budget)
+			repoll = false
+		}
+	}
+
 	return false, repoll
 }
 
@@ -0,0 +1,116 @@
+// Copyright 2018 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package teams
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"golang.org/x/net/context"
+)
+
+// Member device posture requirements: admins declare constraints for a
+// team in config, clients evaluate every member's public key family
+// during team load, and violations surface in list-members and admin
+// notifications.
+//
+//   "teams": { "posture": { "acme": {
+//       "require_puk": true,
+//       "max_devices": 10,
+//       "forbid_paper_only": true } } }
+
+type PostureRequirements struct {
+	RequirePUK      bool `json:"require_puk"`
+	MaxDevices      int  `json:"max_devices"`
+	ForbidPaperOnly bool `json:"forbid_paper_only"`
+}
+
+type PostureViolation struct {
+	Username string
+	Problem  string
+}
+
+// posturePolicyFor reads the team's posture requirements from config.
+func posturePolicyFor(g *libkb.GlobalContext, teamname string) (req PostureRequirements, ok bool) {
+	raw, err := g.Env.GetConfig().GetInterfaceAtPath("teams.posture." + teamname)
+	if err != nil {
+		return req, false
+	}
+	blob, err := json.Marshal(raw)
+	if err != nil {
+		return req, false
+	}
+	if err := json.Unmarshal(blob, &req); err != nil {
+		return req, false
+	}
+	return req, req.RequirePUK || req.MaxDevices > 0 || req.ForbidPaperOnly
+}
+
+// evaluateMemberPosture checks one member's public key family against
+// the requirements.
+func evaluateMemberPosture(req PostureRequirements, username string,
+	upk keybase1.UserPlusKeysV2) (violations []PostureViolation) {
+	devices := 0
+	paperDevices := 0
+	for _, key := range upk.DeviceKeys {
+		if key.Base.Revocation != nil || !key.Base.IsSibkey {
+			continue
+		}
+		devices++
+		if key.DeviceType == libkb.DeviceTypePaper {
+			paperDevices++
+		}
+	}
+
+	if req.RequirePUK && len(upk.PerUserKeys) == 0 {
+		violations = append(violations, PostureViolation{
+			Username: username, Problem: "no per-user key",
+		})
+	}
+	if req.MaxDevices > 0 && devices > req.MaxDevices {
+		violations = append(violations, PostureViolation{
+			Username: username,
+			Problem:  fmt.Sprintf("%d active devices (limit %d)", devices, req.MaxDevices),
+		})
+	}
+	if req.ForbidPaperOnly && devices > 0 && devices == paperDevices {
+		violations = append(violations, PostureViolation{
+			Username: username, Problem: "only paper keys remain active",
+		})
+	}
+	return violations
+}
+
+// CheckTeamPosture evaluates every member's devices against the team's
+// configured posture requirements.
+func CheckTeamPosture(ctx context.Context, g *libkb.GlobalContext, teamname string) ([]PostureViolation, error) {
+	req, ok := posturePolicyFor(g, teamname)
+	if !ok {
+		return nil, nil
+	}
+	team, err := Load(ctx, g, keybase1.LoadTeamArg{Name: teamname})
+	if err != nil {
+		return nil, err
+	}
+	members, err := team.Members()
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []PostureViolation
+	for _, uv := range members.AllUserVersions() {
+		upak, _, err := g.GetUPAKLoader().LoadV2(
+			libkb.NewLoadUserArgWithMetaContext(libkb.NewMetaContext(ctx, g)).
+				WithUID(uv.Uid).WithPublicKeyOptional())
+		if err != nil {
+			g.Log.CDebugf(ctx, "CheckTeamPosture: cannot load %s: %s", uv.Uid, err)
+			continue
+		}
+		violations = append(violations,
+			evaluateMemberPosture(req, upak.Current.Username, upak.Current)...)
+	}
+	return violations, nil
+}
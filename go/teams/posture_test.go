@@ -0,0 +1,65 @@
+package teams
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol/keybase1"
+	"github.com/stretchr/testify/require"
+)
+
+func postureKey(device string, revoked, sibkey bool) keybase1.PublicKeyV2NaCl {
+	key := keybase1.PublicKeyV2NaCl{DeviceType: device}
+	key.Base.IsSibkey = sibkey
+	if revoked {
+		rev := keybase1.SignatureMetadata{}
+		key.Base.Revocation = &rev
+	}
+	return key
+}
+
+func TestEvaluateMemberPosture(t *testing.T) {
+	req := PostureRequirements{RequirePUK: true, MaxDevices: 2, ForbidPaperOnly: true}
+
+	// Healthy member: desktop + paper, has PUK.
+	healthy := keybase1.UserPlusKeysV2{
+		PerUserKeys: []keybase1.PerUserKey{{Gen: 1}},
+		DeviceKeys: map[keybase1.KID]keybase1.PublicKeyV2NaCl{
+			"d1": postureKey(libkb.DeviceTypeDesktop, false, true),
+			"p1": postureKey(libkb.DeviceTypePaper, false, true),
+		},
+	}
+	require.Empty(t, evaluateMemberPosture(req, "ok", healthy))
+
+	// No PUK.
+	noPUK := healthy
+	noPUK.PerUserKeys = nil
+	v := evaluateMemberPosture(req, "nopuk", noPUK)
+	require.Len(t, v, 1)
+	require.Contains(t, v[0].Problem, "per-user key")
+
+	// Too many devices.
+	crowded := keybase1.UserPlusKeysV2{
+		PerUserKeys: []keybase1.PerUserKey{{Gen: 1}},
+		DeviceKeys: map[keybase1.KID]keybase1.PublicKeyV2NaCl{
+			"d1": postureKey(libkb.DeviceTypeDesktop, false, true),
+			"d2": postureKey(libkb.DeviceTypeDesktop, false, true),
+			"d3": postureKey(libkb.DeviceTypeMobile, false, true),
+		},
+	}
+	v = evaluateMemberPosture(req, "crowded", crowded)
+	require.Len(t, v, 1)
+	require.Contains(t, v[0].Problem, "limit 2")
+
+	// Paper-only membership; revoked devices don't count.
+	paperOnly := keybase1.UserPlusKeysV2{
+		PerUserKeys: []keybase1.PerUserKey{{Gen: 1}},
+		DeviceKeys: map[keybase1.KID]keybase1.PublicKeyV2NaCl{
+			"d1": postureKey(libkb.DeviceTypeDesktop, true, true),
+			"p1": postureKey(libkb.DeviceTypePaper, false, true),
+		},
+	}
+	v = evaluateMemberPosture(req, "paperonly", paperOnly)
+	require.Len(t, v, 1)
+	require.Contains(t, v[0].Problem, "paper")
+}
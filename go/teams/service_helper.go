@@ -512,6 +512,11 @@ func AddEmailsBulk(ctx context.Context, g *libkb.GlobalContext, teamname, emails
 }
 
 func EditMember(ctx context.Context, g *libkb.GlobalContext, teamname, username string, role keybase1.TeamRole) error {
+	// Sensitive promotions can require a second admin; see approval.go.
+	if err := checkRoleChangeApproval(ctx, g, teamname, username, role); err != nil {
+		return err
+	}
+
 	uv, err := loadUserVersionByUsername(ctx, g, username, true /* useTracking */)
 	if err == errInviteRequired {
 		g.Log.CDebugf(ctx, "team %s: edit member %s, member is an invite link", teamname, username)
@@ -1529,7 +1534,15 @@ func RotateKey(ctx context.Context, g *libkb.GlobalContext, teamID keybase1.Team
 			return err
 		}
 
-		return team.Rotate(ctx)
+		if err := team.Rotate(ctx); err != nil {
+			return err
+		}
+		// Escrow the fresh generation to the offline recovery key when
+		// the team has opted in; failures don't undo the rotation.
+		if eerr := EscrowCurrentKey(ctx, g, team.Name().String()); eerr != nil {
+			g.Log.CDebugf(ctx, "RotateKey: escrow of new key failed: %s", eerr)
+		}
+		return nil
 	})
 }
 